@@ -0,0 +1,116 @@
+// Command rpsls-rulesets imports and exports rulesets as JSON or YAML documents, using the database configured for the
+// current environment, which can't be the memory one:
+//
+//	rpsls-rulesets export [-format json|yaml] <ruleset ID>
+//	rpsls-rulesets import [-replace] <file>
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"rpsls/rpslsapi"
+	"rpsls/rpslsapi/storage"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	rpslsapi.LoadConfig()
+	// the memory driver would import into a database dropped as soon as the command exits
+	if rpslsapi.Config.DB.Driver == "memory" {
+		fmt.Fprintln(os.Stderr, "rpsls-rulesets needs a persistent database, DB_DRIVER can't be memory")
+		os.Exit(1)
+	}
+	stores, cleanup := storage.NewDatabaseStores()
+	defer cleanup()
+	service := rpslsapi.NewRulesetService(stores.Ruleset, stores.Choice, rpslsapi.NewRuleGraphLock())
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = export(service, os.Args[2:])
+	case "import":
+		err = importFile(service, os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		printError(err)
+		cleanup()
+		os.Exit(1)
+	}
+}
+
+func export(service rpslsapi.RulesetService, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", string(rpslsapi.YAMLFormat), "document format: json or yaml")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+	}
+
+	document, err := service.ExportRuleset(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return rpslsapi.EncodeRulesetDocument(os.Stdout, document, rpslsapi.DocumentFormat(*format))
+}
+
+func importFile(service rpslsapi.RulesetService, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	replace := flags.Bool("replace", false, "replace the ruleset if it already exists")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	document, err := rpslsapi.DecodeRulesetDocument(file)
+	if err != nil {
+		return err
+	}
+	ruleset, err := service.ImportRuleset(document, *replace)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("imported ruleset %s (%s): %d choices, %d rules\n", ruleset.ID, ruleset.Name,
+		len(document.Choices), len(document.Beats))
+	return nil
+}
+
+func printError(err error) {
+	var documentErr *rpslsapi.RulesetDocumentError
+	var ruleGraphErr *rpslsapi.RuleGraphError
+	switch {
+	case errors.As(err, &documentErr):
+		fmt.Fprintln(os.Stderr, rpslsapi.ErrInvalidRuleset)
+		for _, problem := range documentErr.Problems {
+			fmt.Fprintf(os.Stderr, "  %s\n", problem)
+		}
+	case errors.As(err, &ruleGraphErr):
+		fmt.Fprintln(os.Stderr, rpslsapi.ErrInconsistentRuleGraph)
+		for _, issue := range ruleGraphErr.Report.Issues {
+			fmt.Fprintf(os.Stderr, "  %s: %s\n", issue.Severity, issue.Message)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	fmt.Fprintln(os.Stderr, "  rpsls-rulesets export [-format json|yaml] <ruleset ID>")
+	fmt.Fprintln(os.Stderr, "  rpsls-rulesets import [-replace] <file>")
+	os.Exit(2)
+}
@@ -0,0 +1,25 @@
+// Command rpsls-stubbot serves a stub bot, to register with POST /bots when developing against the bot protocol:
+//
+//	rpsls-stubbot [-addr :3100] [-delay 0s]
+package main
+
+import (
+	"flag"
+	stdhttp "net/http"
+
+	"github.com/rs/zerolog/log"
+	"rpsls/rpslsapi/http"
+	"rpsls/rpslsapi/logger"
+)
+
+func main() {
+	addr := flag.String("addr", ":3100", "address to listen on")
+	delay := flag.Duration("delay", 0, "how long to wait before answering, to simulate slow bots")
+	flag.Parse()
+
+	logger.SetUp()
+	log.Info().Str("addr", *addr).Msg("serving stub bot")
+	if err := stdhttp.ListenAndServe(*addr, http.StubBot{Delay: *delay}); err != nil {
+		log.Fatal().Err(err).Msg("Startup failed")
+	}
+}
@@ -0,0 +1,55 @@
+// Command rpsls-users appoints the admins among the registered users, using the database configured for the current
+// environment, which can't be the memory one:
+//
+//	rpsls-users grant-admin <username>
+//	rpsls-users revoke-admin <username>
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"rpsls/rpslsapi"
+	"rpsls/rpslsapi/storage"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		usage()
+	}
+
+	var role rpslsapi.Role
+	switch os.Args[1] {
+	case "grant-admin":
+		role = rpslsapi.AdminRole
+	case "revoke-admin":
+		role = rpslsapi.PlayerRole
+	default:
+		usage()
+	}
+
+	rpslsapi.LoadConfig()
+	// the memory driver would update a database dropped as soon as the command exits
+	if rpslsapi.Config.DB.Driver == "memory" {
+		fmt.Fprintln(os.Stderr, "rpsls-users needs a persistent database, DB_DRIVER can't be memory")
+		os.Exit(1)
+	}
+	stores, cleanup := storage.NewDatabaseStores()
+	defer cleanup()
+	service := rpslsapi.NewUserService(stores.User)
+
+	username := os.Args[2]
+	if err := service.SetRole(username, role); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		cleanup()
+		os.Exit(1)
+	}
+	fmt.Printf("%s is now a %s, from their next login\n", username, role)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	fmt.Fprintln(os.Stderr, "  rpsls-users grant-admin <username>")
+	fmt.Fprintln(os.Stderr, "  rpsls-users revoke-admin <username>")
+	os.Exit(2)
+}
@@ -0,0 +1,2 @@
+MATCH (r:Ruleset)
+DETACH DELETE r;
@@ -0,0 +1,4 @@
+CREATE (rpsls:Ruleset { id: "rpsls", name: "Rock Paper Scissors Lizard Spock" })
+WITH rpsls
+MATCH (c:Choice)
+CREATE (c)-[:PART_OF]->(rpsls);
@@ -0,0 +1,3 @@
+MATCH (rps:Ruleset { id: "rps" })
+OPTIONAL MATCH (c:Choice)-[:PART_OF]->(rps)
+DETACH DELETE c, rps;
@@ -0,0 +1,7 @@
+CREATE (rps:Ruleset { id: "rps", name: "Rock Paper Scissors" }),
+(rock:Choice { name: "Rock" })-[:PART_OF]->(rps),
+(paper:Choice { name: "Paper" })-[:PART_OF]->(rps),
+(scissors:Choice { name: "Scissors" })-[:PART_OF]->(rps),
+(rock)-[:BEATS {with: "crushes"}]->(scissors),
+(paper)-[:BEATS {with: "covers"}]->(rock),
+(scissors)-[:BEATS {with: "cuts"}]->(paper);
@@ -0,0 +1 @@
+DROP INDEX translation_language IF EXISTS;
@@ -0,0 +1 @@
+CREATE INDEX translation_language IF NOT EXISTS FOR (t:Translation) ON (t.language);
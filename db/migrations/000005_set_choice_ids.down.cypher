@@ -0,0 +1,2 @@
+MATCH (c:Choice)
+REMOVE c.id;
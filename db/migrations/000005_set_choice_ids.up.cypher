@@ -0,0 +1,5 @@
+MATCH (c:Choice)-[:PART_OF]->(r:Ruleset)
+WITH r.id + '-' + toLower(replace(c.name, ' ', '-')) AS slug, collect(c) AS choices
+UNWIND range(0, size(choices) - 1) AS i
+WITH choices[i] AS c, CASE i WHEN 0 THEN slug ELSE slug + '-' + toString(id(choices[i])) END AS slug
+SET c.id = slug;
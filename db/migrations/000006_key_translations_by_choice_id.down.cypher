@@ -0,0 +1,3 @@
+MATCH (c:Choice), (t:Translation { kind: 'choice' })
+WHERE t.key = c.id
+SET t.key = toString(id(c));
@@ -0,0 +1 @@
+DROP CONSTRAINT choice_id IF EXISTS;
@@ -0,0 +1 @@
+CREATE CONSTRAINT choice_id IF NOT EXISTS ON (c:Choice) ASSERT c.id IS UNIQUE;
@@ -0,0 +1 @@
+DROP INDEX history_round_user_played_at IF EXISTS;
@@ -0,0 +1 @@
+CREATE INDEX history_round_user_played_at IF NOT EXISTS FOR (r:HistoryRound) ON (r.user_id, r.played_at);
@@ -0,0 +1 @@
+DROP CONSTRAINT round_audit_id IF EXISTS;
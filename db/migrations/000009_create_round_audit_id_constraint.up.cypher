@@ -0,0 +1 @@
+CREATE CONSTRAINT round_audit_id IF NOT EXISTS ON (a:RoundAudit) ASSERT a.id IS UNIQUE;
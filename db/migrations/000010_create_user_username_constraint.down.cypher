@@ -0,0 +1 @@
+DROP CONSTRAINT user_username IF EXISTS;
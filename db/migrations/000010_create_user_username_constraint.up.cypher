@@ -0,0 +1 @@
+CREATE CONSTRAINT user_username IF NOT EXISTS ON (u:User) ASSERT u.username IS UNIQUE;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS beats;
+DROP TABLE IF EXISTS choices;
+DROP TABLE IF EXISTS rulesets;
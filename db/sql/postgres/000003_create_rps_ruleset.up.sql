@@ -0,0 +1,18 @@
+INSERT INTO rulesets (id, name) VALUES ('rps', 'Rock Paper Scissors');
+
+INSERT INTO choices (name, ruleset) VALUES
+('Rock', 'rps'),
+('Paper', 'rps'),
+('Scissors', 'rps');
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'crushes' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rps' AND winner.name = 'Rock' AND loser.ruleset = 'rps' AND loser.name = 'Scissors';
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'covers' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rps' AND winner.name = 'Paper' AND loser.ruleset = 'rps' AND loser.name = 'Rock';
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'cuts' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rps' AND winner.name = 'Scissors' AND loser.ruleset = 'rps' AND loser.name = 'Paper';
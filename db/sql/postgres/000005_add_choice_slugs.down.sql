@@ -0,0 +1,6 @@
+UPDATE translations SET key = CAST(choices.id AS TEXT) FROM choices
+WHERE translations.kind = 'choice' AND translations.key = choices.slug;
+
+DROP INDEX IF EXISTS choices_slug;
+
+ALTER TABLE choices DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,13 @@
+ALTER TABLE choices ADD COLUMN slug TEXT;
+
+UPDATE choices SET slug = ruleset || '-' || lower(replace(name, ' ', '-'));
+
+UPDATE choices SET slug = slug || '-' || id
+WHERE EXISTS (SELECT 1 FROM choices other WHERE other.slug = choices.slug AND other.id < choices.id);
+
+ALTER TABLE choices ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX choices_slug ON choices (slug);
+
+UPDATE translations SET key = choices.slug FROM choices
+WHERE translations.kind = 'choice' AND translations.key = CAST(choices.id AS TEXT);
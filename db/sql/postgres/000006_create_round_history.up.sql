@@ -0,0 +1,13 @@
+CREATE TABLE round_history (
+    id        TEXT   NOT NULL PRIMARY KEY,
+    user_id   TEXT   NOT NULL,
+    played_at BIGINT NOT NULL,
+    ruleset   TEXT   NOT NULL,
+    player    TEXT   NOT NULL,
+    computer  TEXT   NOT NULL,
+    results   TEXT   NOT NULL,
+    action    TEXT   NOT NULL,
+    series    TEXT   NOT NULL
+);
+
+CREATE INDEX round_history_user_played_at ON round_history (user_id, played_at, id);
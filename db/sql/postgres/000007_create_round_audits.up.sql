@@ -0,0 +1,9 @@
+CREATE TABLE round_audits (
+    id         TEXT   NOT NULL PRIMARY KEY,
+    request_id TEXT   NOT NULL,
+    user_id    TEXT   NOT NULL,
+    played_at  BIGINT NOT NULL,
+    record     TEXT   NOT NULL
+);
+
+CREATE INDEX round_audits_request_id ON round_audits (request_id);
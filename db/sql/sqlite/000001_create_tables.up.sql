@@ -0,0 +1,20 @@
+CREATE TABLE rulesets (
+    id   TEXT PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+CREATE TABLE choices (
+    id      INTEGER PRIMARY KEY AUTOINCREMENT,
+    name    TEXT NOT NULL,
+    ruleset TEXT NOT NULL REFERENCES rulesets (id) ON DELETE CASCADE,
+    UNIQUE (ruleset, name)
+);
+
+CREATE TABLE beats (
+    winner INTEGER NOT NULL REFERENCES choices (id) ON DELETE CASCADE,
+    loser  INTEGER NOT NULL REFERENCES choices (id) ON DELETE CASCADE,
+    verb   TEXT NOT NULL,
+    PRIMARY KEY (winner, loser, verb)
+);
+
+CREATE INDEX beats_loser ON beats (loser);
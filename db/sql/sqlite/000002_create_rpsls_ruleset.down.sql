@@ -0,0 +1,3 @@
+DELETE FROM beats WHERE winner IN (SELECT id FROM choices WHERE ruleset = 'rpsls');
+DELETE FROM choices WHERE ruleset = 'rpsls';
+DELETE FROM rulesets WHERE id = 'rpsls';
@@ -0,0 +1,48 @@
+INSERT INTO rulesets (id, name) VALUES ('rpsls', 'Rock Paper Scissors Lizard Spock');
+
+INSERT INTO choices (name, ruleset) VALUES
+('Rock', 'rpsls'),
+('Paper', 'rpsls'),
+('Scissors', 'rpsls'),
+('Lizard', 'rpsls'),
+('Spock', 'rpsls');
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'crushes' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rpsls' AND winner.name = 'Rock' AND loser.ruleset = 'rpsls' AND loser.name = 'Scissors';
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'crushes' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rpsls' AND winner.name = 'Rock' AND loser.ruleset = 'rpsls' AND loser.name = 'Lizard';
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'covers' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rpsls' AND winner.name = 'Paper' AND loser.ruleset = 'rpsls' AND loser.name = 'Rock';
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'disproves' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rpsls' AND winner.name = 'Paper' AND loser.ruleset = 'rpsls' AND loser.name = 'Spock';
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'cuts' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rpsls' AND winner.name = 'Scissors' AND loser.ruleset = 'rpsls' AND loser.name = 'Paper';
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'decapitates' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rpsls' AND winner.name = 'Scissors' AND loser.ruleset = 'rpsls' AND loser.name = 'Lizard';
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'eats' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rpsls' AND winner.name = 'Lizard' AND loser.ruleset = 'rpsls' AND loser.name = 'Paper';
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'poisons' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rpsls' AND winner.name = 'Lizard' AND loser.ruleset = 'rpsls' AND loser.name = 'Spock';
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'smashes' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rpsls' AND winner.name = 'Spock' AND loser.ruleset = 'rpsls' AND loser.name = 'Scissors';
+
+INSERT INTO beats (winner, loser, verb)
+SELECT winner.id, loser.id, 'vaporizes' FROM choices winner, choices loser
+WHERE winner.ruleset = 'rpsls' AND winner.name = 'Spock' AND loser.ruleset = 'rpsls' AND loser.name = 'Rock';
@@ -0,0 +1,3 @@
+DELETE FROM beats WHERE winner IN (SELECT id FROM choices WHERE ruleset = 'rps');
+DELETE FROM choices WHERE ruleset = 'rps';
+DELETE FROM rulesets WHERE id = 'rps';
@@ -0,0 +1,9 @@
+CREATE TABLE translations (
+    kind     TEXT NOT NULL,
+    key      TEXT NOT NULL,
+    language TEXT NOT NULL,
+    text     TEXT NOT NULL,
+    PRIMARY KEY (kind, key, language)
+);
+
+CREATE INDEX translations_language ON translations (language);
@@ -0,0 +1,6 @@
+UPDATE translations SET key = (SELECT CAST(id AS TEXT) FROM choices WHERE choices.slug = translations.key)
+WHERE kind = 'choice' AND EXISTS (SELECT 1 FROM choices WHERE choices.slug = translations.key);
+
+DROP INDEX IF EXISTS choices_slug;
+
+ALTER TABLE choices DROP COLUMN slug;
@@ -0,0 +1,11 @@
+ALTER TABLE choices ADD COLUMN slug TEXT;
+
+UPDATE choices SET slug = ruleset || '-' || lower(replace(name, ' ', '-'));
+
+UPDATE choices SET slug = slug || '-' || id
+WHERE EXISTS (SELECT 1 FROM choices other WHERE other.slug = choices.slug AND other.id < choices.id);
+
+CREATE UNIQUE INDEX choices_slug ON choices (slug);
+
+UPDATE translations SET key = (SELECT slug FROM choices WHERE CAST(choices.id AS TEXT) = translations.key)
+WHERE kind = 'choice' AND EXISTS (SELECT 1 FROM choices WHERE CAST(choices.id AS TEXT) = translations.key);
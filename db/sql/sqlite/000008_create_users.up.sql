@@ -0,0 +1,6 @@
+CREATE TABLE users (
+    id            TEXT   NOT NULL PRIMARY KEY,
+    username      TEXT   NOT NULL UNIQUE,
+    password_hash TEXT   NOT NULL,
+    created_at    BIGINT NOT NULL
+);
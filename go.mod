@@ -8,10 +8,16 @@ require (
 	github.com/go-chi/cors v1.2.0
 	github.com/go-chi/render v1.0.1
 	github.com/go-redis/redis/v8 v8.9.0
+	github.com/golang-jwt/jwt/v4 v4.4.3
 	github.com/golang-migrate/migrate/v4 v4.14.1
 	github.com/google/wire v0.4.0
+	github.com/gorilla/websocket v1.5.0
 	github.com/joho/godotenv v1.3.0
+	github.com/lib/pq v1.8.0
+	github.com/mattn/go-sqlite3 v1.14.8
 	github.com/neo4j/neo4j-go-driver/v4 v4.3.0
 	github.com/rs/zerolog v1.20.0
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/crypto v0.0.0-20200709230013-948cd5f35899
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,302 @@
+package rpslsapi
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrArcadeSessionNotFound = errors.New("arcade session not found")
+var ErrArcadeSessionOver = errors.New("arcade session already over")
+var ErrNotArcadeSessionOwner = errors.New("not the owner of the arcade session")
+var ErrInvalidArcadeSession = errors.New("arcade sessions need a player name of up to 50 characters and between 1 " +
+	"and 9 lives")
+
+// defaultArcadeLives and maxArcadeLives bound the number of lives arcade sessions start with
+const (
+	defaultArcadeLives = 3
+	maxArcadeLives     = 9
+)
+
+// arcadeWinPoints are scored by every won round, times the multiplier of the winning streak it extends
+const arcadeWinPoints = 100
+
+// maxArcadeMultiplier caps the multiplier built by consecutive wins
+const maxArcadeMultiplier = 5
+
+// highScoreTableSize is how many of the best final scores of each ruleset make it to its high scores
+const highScoreTableSize = 10
+
+// arcadeRetention is how long arcade sessions are kept, so players can come back to them
+const arcadeRetention = 24 * time.Hour
+
+type ArcadeSettings struct {
+	// Player is the name the final score is submitted to the high scores with
+	Player  string `json:"player"`
+	Ruleset string `json:"ruleset"`
+	// Lives is the number of rounds the player may lose, 3 if zero
+	Lives int `json:"lives"`
+	// Strategy is the name or difficulty of the strategy the computer plays with, the default one of the user if
+	// empty
+	Strategy string `json:"strategy,omitempty"`
+	// UserID is the ID of the authenticated user starting the session, who owns it, empty for anonymous players
+	UserID string `json:"-"`
+}
+
+// ArcadeSession is a run of rounds against the computer lasting until the player lost all their lives. Won rounds
+// score points, multiplied by the number of consecutive wins up to maxArcadeMultiplier, while lost ones cost a life
+// and break the streak. Tied rounds change neither.
+type ArcadeSession struct {
+	ID string `json:"id"`
+	// Owner is the ID of the user who started the session, the only one who may see and play it, sessions started
+	// anonymously being left to anonymous players
+	Owner    string `json:"owner,omitempty"`
+	Player   string `json:"player"`
+	Ruleset  string `json:"ruleset"`
+	Strategy string `json:"strategy,omitempty"`
+	// Lives is the number of lives left
+	Lives      int  `json:"lives"`
+	Score      int  `json:"score"`
+	Streak     int  `json:"streak"`
+	Multiplier int  `json:"multiplier"`
+	BestStreak int  `json:"best_streak"`
+	Over       bool `json:"over"`
+	// Rank is the rank of the final score in the high scores of the ruleset, if it made it there
+	Rank   int            `json:"rank,omitempty"`
+	Rounds []RoundResults `json:"rounds"`
+}
+
+// HighScore is the final score of an arcade session in the high scores of its ruleset
+type HighScore struct {
+	Player     string    `json:"player"`
+	Score      int       `json:"score"`
+	Session    string    `json:"session"`
+	Ruleset    string    `json:"ruleset"`
+	Rounds     int       `json:"rounds"`
+	BestStreak int       `json:"best_streak"`
+	AchievedAt time.Time `json:"achieved_at"`
+}
+
+type ArcadeService interface {
+	StartSession(settings *ArcadeSettings) (*ArcadeSession, error)
+	// Session returns the session to the user owning it, or ErrNotArcadeSessionOwner
+	Session(id, userID string) (*ArcadeSession, error)
+	// Play plays a round of the session with the ruleset and strategy of the session, saving it like RoundService.Play
+	// does once it is added to the session, and submitting the final score to the high scores once the last life is
+	// lost. Only the owner may play the session.
+	Play(id string, settings *RoundSettings) (*ArcadeSession, error)
+	// HighScores returns the high scores of the ruleset, best first
+	HighScores(ruleset string) ([]HighScore, error)
+}
+
+type ArcadeStore interface {
+	// CreateSession stores a new session, which is dropped once the retention has passed
+	CreateSession(session *ArcadeSession, retention time.Duration) error
+	// Session returns the session or ErrArcadeSessionNotFound
+	Session(id string) (*ArcadeSession, error)
+	UpdateSession(id string, update func(session *ArcadeSession) error) (*ArcadeSession, error)
+	// SubmitHighScore adds the score to the high scores of its ruleset through InsertHighScore, and returns its rank
+	// among them or 0 if it didn't make it
+	SubmitHighScore(score *HighScore, size int) (int, error)
+	// HighScores returns the high scores of the ruleset as kept by InsertHighScore
+	HighScores(ruleset string, size int) ([]HighScore, error)
+}
+
+type ArcadeServiceImpl struct {
+	arcadeStore       ArcadeStore
+	roundStore        RoundStore
+	choiceService     ChoiceService
+	scoreboardService ScoreboardService
+	strategyService   StrategyService
+	historyService    HistoryService
+	auditService      AuditService
+	now               func() time.Time
+}
+
+func NewArcadeService(arcadeStore ArcadeStore, roundStore RoundStore, choiceService ChoiceService,
+	scoreboardService ScoreboardService, strategyService StrategyService, historyService HistoryService,
+	auditService AuditService) ArcadeService {
+	return ArcadeServiceImpl{
+		arcadeStore:       arcadeStore,
+		roundStore:        roundStore,
+		choiceService:     choiceService,
+		scoreboardService: scoreboardService,
+		strategyService:   strategyService,
+		historyService:    historyService,
+		auditService:      auditService,
+		now:               time.Now,
+	}
+}
+
+func (as ArcadeServiceImpl) StartSession(settings *ArcadeSettings) (*ArcadeSession, error) {
+	lives := settings.Lives
+	if lives == 0 {
+		lives = defaultArcadeLives
+	}
+	if settings.Player == "" || len(settings.Player) > maxPlayerNameLength || lives < 0 || lives > maxArcadeLives {
+		return nil, ErrInvalidArcadeSession
+	}
+	ruleset := RulesetOrDefault(settings.Ruleset)
+	if _, err := as.choiceService.Choices(ruleset); err != nil {
+		return nil, err
+	}
+	strategy := ""
+	if settings.Strategy != "" {
+		info, err := as.strategyService.Strategy(settings.Strategy)
+		if err != nil {
+			return nil, err
+		}
+		strategy = info.Name
+	}
+
+	session := &ArcadeSession{
+		ID:         newToken(8),
+		Owner:      settings.UserID,
+		Player:     settings.Player,
+		Ruleset:    ruleset,
+		Strategy:   strategy,
+		Lives:      lives,
+		Multiplier: 1,
+		Rounds:     []RoundResults{},
+	}
+	if err := as.arcadeStore.CreateSession(session, arcadeRetention); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (as ArcadeServiceImpl) Session(id, userID string) (*ArcadeSession, error) {
+	session, err := as.arcadeStore.Session(id)
+	if err != nil {
+		return nil, err
+	}
+	if session.Owner != userID {
+		return nil, ErrNotArcadeSessionOwner
+	}
+	return session, nil
+}
+
+func (as ArcadeServiceImpl) Play(id string, settings *RoundSettings) (*ArcadeSession, error) {
+	session, err := as.Session(id, settings.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Over {
+		return nil, ErrArcadeSessionOver
+	}
+
+	// the round is decided before the update, which may be retried, and only saved once the update added it to the
+	// session, so that a round dropped because the session ended meanwhile leaves no trace
+	userID := settings.UserID
+	settings = &RoundSettings{Player: settings.Player, Ruleset: session.Ruleset, Strategy: session.Strategy,
+		RequestID: settings.RequestID, UserID: userID}
+	round, move, err := playAgainstComputer(as.roundStore, as.choiceService, as.strategyService, userID, settings)
+	if err != nil {
+		return nil, err
+	}
+	round.ID = newToken(8)
+
+	overNow := false
+	session, err = as.arcadeStore.UpdateSession(id, func(session *ArcadeSession) error {
+		if session.Over {
+			return ErrArcadeSessionOver
+		}
+		session.record(round)
+		overNow = session.Over
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := as.auditService.Record(newRoundAudit(settings.RequestID, userID, as.now(), round, move)); err != nil {
+		return nil, err
+	}
+	if userID != "" {
+		if err := as.scoreboardService.Append(userID, round); err != nil {
+			log.Info().Msg("failed to save round to scoreboard")
+		}
+		recordHistory(as.historyService, userID, round, "")
+	}
+
+	if overNow {
+		return as.submitHighScore(session), nil
+	}
+	return session, nil
+}
+
+func (as ArcadeServiceImpl) HighScores(ruleset string) ([]HighScore, error) {
+	return as.arcadeStore.HighScores(RulesetOrDefault(ruleset), highScoreTableSize)
+}
+
+// submitHighScore submits the final score of the session, returning the session with its rank. Failures are only
+// logged, the session being over anyway.
+func (as ArcadeServiceImpl) submitHighScore(session *ArcadeSession) *ArcadeSession {
+	rank, err := as.arcadeStore.SubmitHighScore(&HighScore{
+		Player:     session.Player,
+		Score:      session.Score,
+		Session:    session.ID,
+		Ruleset:    session.Ruleset,
+		Rounds:     len(session.Rounds),
+		BestStreak: session.BestStreak,
+		AchievedAt: as.now().UTC(),
+	}, highScoreTableSize)
+	if err != nil {
+		log.Info().Err(err).Msg("failed to submit arcade score to high scores")
+		return session
+	}
+	if rank == 0 {
+		return session
+	}
+
+	ranked, err := as.arcadeStore.UpdateSession(session.ID, func(session *ArcadeSession) error {
+		session.Rank = rank
+		return nil
+	})
+	if err != nil {
+		log.Info().Err(err).Msg("failed to save the high score rank of arcade session")
+		session.Rank = rank
+		return session
+	}
+	return ranked
+}
+
+// record scores the round, ending the session once the last life is lost
+func (as *ArcadeSession) record(round *RoundResults) {
+	as.Rounds = append(as.Rounds, *round)
+	switch ResultsLabel(round.Results) {
+	case Win:
+		as.Streak++
+		if as.Streak > as.BestStreak {
+			as.BestStreak = as.Streak
+		}
+		as.Multiplier = as.Streak
+		if as.Multiplier > maxArcadeMultiplier {
+			as.Multiplier = maxArcadeMultiplier
+		}
+		as.Score += arcadeWinPoints * as.Multiplier
+	case Lose:
+		as.Lives--
+		as.Streak = 0
+		as.Multiplier = 1
+		as.Over = as.Lives == 0
+	}
+}
+
+// InsertHighScore adds the score to the high scores, sorted best first with ties in submission order, and keeps the
+// best size ones. It returns the resulting high scores and the rank of the score among them, 0 if it didn't make it.
+func InsertHighScore(highScores []HighScore, score *HighScore, size int) ([]HighScore, int) {
+	position := sort.Search(len(highScores), func(i int) bool { return highScores[i].Score < score.Score })
+	if position >= size {
+		return highScores, 0
+	}
+	inserted := make([]HighScore, 0, len(highScores)+1)
+	inserted = append(inserted, highScores[:position]...)
+	inserted = append(inserted, *score)
+	inserted = append(inserted, highScores[position:]...)
+	if len(inserted) > size {
+		inserted = inserted[:size]
+	}
+	return inserted, position + 1
+}
@@ -0,0 +1,393 @@
+package rpslsapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type ArcadeStoreMock struct {
+	mock.Mock
+}
+
+func (asm *ArcadeStoreMock) CreateSession(session *ArcadeSession, retention time.Duration) error {
+	args := asm.Called(session, retention)
+	return args.Error(0)
+}
+
+func (asm *ArcadeStoreMock) Session(id string) (*ArcadeSession, error) {
+	args := asm.Called(id)
+	return args.Get(0).(*ArcadeSession), args.Error(1)
+}
+
+// UpdateSession applies the update to the session the mock returns
+func (asm *ArcadeStoreMock) UpdateSession(id string, update func(session *ArcadeSession) error) (*ArcadeSession,
+	error) {
+	args := asm.Called(id)
+	session := args.Get(0).(*ArcadeSession)
+	if err := update(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (asm *ArcadeStoreMock) SubmitHighScore(score *HighScore, size int) (int, error) {
+	args := asm.Called(score, size)
+	return args.Int(0), args.Error(1)
+}
+
+func (asm *ArcadeStoreMock) HighScores(ruleset string, size int) ([]HighScore, error) {
+	args := asm.Called(ruleset, size)
+	return args.Get(0).([]HighScore), args.Error(1)
+}
+
+func TestArcadeSession_record(t *testing.T) {
+	testCases := []struct {
+		name               string
+		lives              int
+		results            []ResultsLabel
+		expectedScore      int
+		expectedLives      int
+		expectedStreak     int
+		expectedMultiplier int
+		expectedBestStreak int
+		expectedOver       bool
+	}{
+		{
+			name:               "consecutive wins build the multiplier",
+			lives:              3,
+			results:            []ResultsLabel{Win, Win, Win},
+			expectedScore:      100 + 200 + 300,
+			expectedLives:      3,
+			expectedStreak:     3,
+			expectedMultiplier: 3,
+			expectedBestStreak: 3,
+		},
+		{
+			name:               "the multiplier is capped",
+			lives:              3,
+			results:            []ResultsLabel{Win, Win, Win, Win, Win, Win, Win},
+			expectedScore:      100 + 200 + 300 + 400 + 500 + 500 + 500,
+			expectedLives:      3,
+			expectedStreak:     7,
+			expectedMultiplier: 5,
+			expectedBestStreak: 7,
+		},
+		{
+			name:               "ties neither break the streak nor cost a life",
+			lives:              3,
+			results:            []ResultsLabel{Win, Tie, Win},
+			expectedScore:      100 + 200,
+			expectedLives:      3,
+			expectedStreak:     2,
+			expectedMultiplier: 2,
+			expectedBestStreak: 2,
+		},
+		{
+			name:               "losses cost a life and reset the multiplier",
+			lives:              3,
+			results:            []ResultsLabel{Win, Win, Lose, Win},
+			expectedScore:      100 + 200 + 100,
+			expectedLives:      2,
+			expectedStreak:     1,
+			expectedMultiplier: 1,
+			expectedBestStreak: 2,
+		},
+		{
+			name:               "the session is over once the last life is lost",
+			lives:              2,
+			results:            []ResultsLabel{Lose, Win, Lose},
+			expectedScore:      100,
+			expectedMultiplier: 1,
+			expectedBestStreak: 1,
+			expectedOver:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		session := &ArcadeSession{Lives: tc.lives, Multiplier: 1}
+		for _, results := range tc.results {
+			session.record(&RoundResults{Results: string(results)})
+		}
+
+		require.Equal(t, tc.expectedScore, session.Score, tc.name)
+		require.Equal(t, tc.expectedLives, session.Lives, tc.name)
+		require.Equal(t, tc.expectedStreak, session.Streak, tc.name)
+		require.Equal(t, tc.expectedMultiplier, session.Multiplier, tc.name)
+		require.Equal(t, tc.expectedBestStreak, session.BestStreak, tc.name)
+		require.Equal(t, tc.expectedOver, session.Over, tc.name)
+		require.Len(t, session.Rounds, len(tc.results), tc.name)
+	}
+}
+
+func TestArcadeService_StartSession(t *testing.T) {
+	testCases := []struct {
+		name             string
+		settings         ArcadeSettings
+		expectedLives    int
+		expectedStrategy string
+		expectedError    error
+	}{
+		{
+			name:          "success: start the session with the default lives, owned by the user",
+			settings:      ArcadeSettings{Player: "ada", Ruleset: "rpsls", UserID: "user"},
+			expectedLives: defaultArcadeLives,
+		},
+		{
+			name:             "success: store the name of the strategy selected by difficulty",
+			settings:         ArcadeSettings{Player: "ada", Ruleset: "rpsls", Lives: 5, Strategy: "expert"},
+			expectedLives:    5,
+			expectedStrategy: "markov",
+		},
+		{
+			name:          "failure: if the player has no name, return ErrInvalidArcadeSession",
+			settings:      ArcadeSettings{Ruleset: "rpsls"},
+			expectedError: ErrInvalidArcadeSession,
+		},
+		{
+			name:          "failure: if there are too many lives, return ErrInvalidArcadeSession",
+			settings:      ArcadeSettings{Player: "ada", Ruleset: "rpsls", Lives: 10},
+			expectedError: ErrInvalidArcadeSession,
+		},
+		{
+			name:          "failure: if the ruleset doesn't exist, return ErrRulesetNotFound",
+			settings:      ArcadeSettings{Player: "ada", Ruleset: "missing"},
+			expectedError: ErrRulesetNotFound,
+		},
+		{
+			name:          "failure: if the strategy is unknown, return ErrStrategyNotFound",
+			settings:      ArcadeSettings{Player: "ada", Ruleset: "rpsls", Strategy: "impossible"},
+			expectedError: ErrStrategyNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := ArcadeStoreMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		strategyMock := StrategyServiceMock{}
+		service := NewArcadeService(&storeMock, &RoundStoreMock{}, &choiceServiceMock, &ScoreboardServiceMock{},
+			&strategyMock, &HistoryServiceMock{}, &AuditServiceMock{})
+		strategyMock.On("Strategy", "expert").Return(&StrategyInfo{Name: "markov", Difficulty: "expert"}, nil)
+		strategyMock.On("Strategy", "impossible").Return((*StrategyInfo)(nil), ErrStrategyNotFound)
+		choiceServiceMock.On("Choices", "rpsls").Return(baseChoices, nil)
+		choiceServiceMock.On("Choices", "missing").Return([]Choice(nil), ErrRulesetNotFound)
+		storeMock.On("CreateSession", mock.Anything, arcadeRetention).Return(nil)
+
+		session, err := service.StartSession(&tc.settings)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			storeMock.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Len(t, session.ID, 16, tc.name)
+		require.Equal(t, tc.settings.UserID, session.Owner, tc.name)
+		require.Equal(t, tc.expectedLives, session.Lives, tc.name)
+		require.Equal(t, tc.expectedStrategy, session.Strategy, tc.name)
+		require.Equal(t, 1, session.Multiplier, tc.name)
+		require.False(t, session.Over, tc.name)
+	}
+}
+
+func TestArcadeService_Play(t *testing.T) {
+	rock, paper, scissors := &baseChoices[0], &baseChoices[1], &baseChoices[2]
+
+	testCases := []struct {
+		name           string
+		session        *ArcadeSession
+		storedSession  *ArcadeSession
+		computer       *Choice
+		player         string
+		auditError     error
+		rank           int
+		submitError    error
+		expectedScore  int
+		expectedOver   bool
+		expectedRank   int
+		expectedSubmit bool
+		expectedError  error
+	}{
+		{
+			name: "success: score the round played with the ruleset and strategy of the session",
+			session: &ArcadeSession{ID: "arcade", Owner: "user", Ruleset: "rpsls", Strategy: "markov", Lives: 1,
+				Multiplier: 1},
+			computer:      scissors,
+			expectedScore: 100,
+		},
+		{
+			name: "success: submit the final score and keep its rank once the last life is lost",
+			session: &ArcadeSession{ID: "arcade", Owner: "user", Player: "ada", Ruleset: "rpsls", Lives: 1, Score: 700,
+				Multiplier: 1},
+			computer:       paper,
+			rank:           2,
+			expectedScore:  700,
+			expectedOver:   true,
+			expectedRank:   2,
+			expectedSubmit: true,
+		},
+		{
+			name:           "success: the session is over even if the score can't be submitted",
+			session:        &ArcadeSession{ID: "arcade", Owner: "user", Ruleset: "rpsls", Lives: 1, Multiplier: 1},
+			computer:       paper,
+			submitError:    errors.New("unknown error"),
+			expectedOver:   true,
+			expectedSubmit: true,
+		},
+		{
+			name:          "failure: if the session is over, return ErrArcadeSessionOver",
+			session:       &ArcadeSession{ID: "arcade", Owner: "user", Ruleset: "rpsls", Over: true},
+			computer:      scissors,
+			expectedError: ErrArcadeSessionOver,
+		},
+		{
+			name:          "failure: if the session ended meanwhile, return ErrArcadeSessionOver without an audit",
+			session:       &ArcadeSession{ID: "arcade", Owner: "user", Ruleset: "rpsls", Lives: 1, Multiplier: 1},
+			storedSession: &ArcadeSession{ID: "arcade", Owner: "user", Ruleset: "rpsls", Over: true},
+			computer:      scissors,
+			expectedError: ErrArcadeSessionOver,
+		},
+		{
+			name:          "failure: if the session belongs to another user, return ErrNotArcadeSessionOwner",
+			session:       &ArcadeSession{ID: "arcade", Owner: "other", Ruleset: "rpsls", Lives: 1, Multiplier: 1},
+			computer:      scissors,
+			expectedError: ErrNotArcadeSessionOwner,
+		},
+		{
+			name:          "failure: if the round fails, return its error",
+			session:       &ArcadeSession{ID: "arcade", Owner: "user", Ruleset: "rpsls", Lives: 1, Multiplier: 1},
+			computer:      scissors,
+			player:        "unknown",
+			expectedError: ErrChoiceNotFound,
+		},
+		{
+			name:          "failure: if the round can't be audited, return the error without adding it to the history",
+			session:       &ArcadeSession{ID: "arcade", Owner: "user", Ruleset: "rpsls", Lives: 1, Multiplier: 1},
+			computer:      scissors,
+			auditError:    errors.New("audit store down"),
+			expectedError: errors.New("audit store down"),
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := ArcadeStoreMock{}
+		roundStoreMock := RoundStoreMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		scoreboardMock := ScoreboardServiceMock{}
+		strategyMock := StrategyServiceMock{}
+		historyMock := HistoryServiceMock{}
+		auditMock := AuditServiceMock{}
+		service := NewArcadeService(&storeMock, &roundStoreMock, &choiceServiceMock, &scoreboardMock, &strategyMock,
+			&historyMock, &auditMock)
+		storeMock.On("Session", "arcade").Return(tc.session, nil)
+		storedSession := tc.session
+		if tc.storedSession != nil {
+			storedSession = tc.storedSession
+		}
+		storeMock.On("UpdateSession", "arcade").Return(storedSession, nil)
+		storeMock.On("SubmitHighScore", mock.Anything, highScoreTableSize).Return(tc.rank, tc.submitError)
+		choiceServiceMock.On("Choice", rock.ID).Return(rock, nil)
+		choiceServiceMock.On("Choice", "unknown").Return((*Choice)(nil), ErrChoiceNotFound)
+		strategyMock.On("ComputerChoice", "user", "rpsls", tc.session.Strategy).
+			Return(&ComputerMove{Choice: tc.computer}, nil)
+		roundStoreMock.On("SimulateRound", rock.ID, scissors.ID).
+			Return(&Round{WinnerID: rock.ID, LoserID: scissors.ID, Action: "crushes"}, nil)
+		roundStoreMock.On("SimulateRound", rock.ID, paper.ID).
+			Return(&Round{WinnerID: paper.ID, LoserID: rock.ID, Action: "covers"}, nil)
+		scoreboardMock.On("Append", "user", mock.Anything).Return(nil)
+		historyMock.On("Record", "user", mock.Anything, "").Return(nil)
+		auditMock.On("Record", mock.Anything).Return(tc.auditError)
+		player := rock.ID
+		if tc.player != "" {
+			player = tc.player
+		}
+
+		session, err := service.Play("arcade", &RoundSettings{Player: player, RequestID: "request", UserID: "user"})
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			if tc.auditError == nil {
+				auditMock.AssertNotCalled(t, "Record", mock.Anything)
+			}
+			scoreboardMock.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+			historyMock.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedScore, session.Score, tc.name)
+		require.Equal(t, tc.expectedOver, session.Over, tc.name)
+		require.Equal(t, tc.expectedRank, session.Rank, tc.name)
+		round := session.Rounds[len(session.Rounds)-1]
+		audit := auditMock.Calls[0].Arguments.Get(0).(*RoundAudit)
+		require.Equal(t, round.ID, audit.ID, tc.name)
+		require.Equal(t, "request", audit.RequestID, tc.name)
+		require.Equal(t, tc.computer.ID, audit.Computer, tc.name)
+		scoreboardMock.AssertNumberOfCalls(t, "Append", 1)
+		historyMock.AssertNumberOfCalls(t, "Record", 1)
+		if !tc.expectedSubmit {
+			storeMock.AssertNotCalled(t, "SubmitHighScore", mock.Anything, mock.Anything)
+			continue
+		}
+		score := storeMock.Calls[2].Arguments.Get(0).(*HighScore)
+		require.Equal(t, tc.session.Player, score.Player, tc.name)
+		require.Equal(t, tc.expectedScore, score.Score, tc.name)
+		require.Equal(t, "arcade", score.Session, tc.name)
+		require.Equal(t, 1, score.Rounds, tc.name)
+	}
+}
+
+func TestInsertHighScore(t *testing.T) {
+	highScores := []HighScore{{Session: "first", Score: 900}, {Session: "second", Score: 500},
+		{Session: "third", Score: 500}}
+
+	testCases := []struct {
+		name             string
+		score            int
+		size             int
+		expectedRank     int
+		expectedSessions []string
+	}{
+		{
+			name:             "a better score is ranked first",
+			score:            1000,
+			size:             10,
+			expectedRank:     1,
+			expectedSessions: []string{"new", "first", "second", "third"},
+		},
+		{
+			name:             "ties are ranked in submission order",
+			score:            500,
+			size:             10,
+			expectedRank:     4,
+			expectedSessions: []string{"first", "second", "third", "new"},
+		},
+		{
+			name:             "only the best scores are kept",
+			score:            600,
+			size:             3,
+			expectedRank:     2,
+			expectedSessions: []string{"first", "new", "second"},
+		},
+		{
+			name:             "a score below a full table doesn't make it",
+			score:            100,
+			size:             3,
+			expectedSessions: []string{"first", "second", "third"},
+		},
+	}
+
+	for _, tc := range testCases {
+		inserted, rank := InsertHighScore(highScores, &HighScore{Session: "new", Score: tc.score}, tc.size)
+
+		require.Equal(t, tc.expectedRank, rank, tc.name)
+		sessions := make([]string, len(inserted))
+		for i := range inserted {
+			sessions[i] = inserted[i].Session
+		}
+		require.Equal(t, tc.expectedSessions, sessions, tc.name)
+		require.Equal(t, "third", highScores[2].Session, tc.name)
+	}
+}
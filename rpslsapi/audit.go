@@ -0,0 +1,156 @@
+package rpslsapi
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrAuditNotFound = errors.New("round audit not found")
+
+// RoundAudit is the immutable record of a round played against the computer, holding what is needed to replay it
+type RoundAudit struct {
+	// ID is the ID of the round, as in the history of the user
+	ID string `json:"id"`
+	// RequestID is the ID of the HTTP request the round was played in
+	RequestID string    `json:"request_id"`
+	UserID    string    `json:"user_id"`
+	PlayedAt  time.Time `json:"played_at"`
+	Ruleset   string    `json:"ruleset"`
+	Player    string    `json:"player"`
+	Computer  string    `json:"computer"`
+	Strategy  string    `json:"strategy"`
+	// RandomValues holds the raw values drawn from the randomizer to pick the computer choice
+	RandomValues []int `json:"random_values"`
+	// Choices is the snapshot of the choices of the ruleset the computer choice was picked among
+	Choices []Choice `json:"choices"`
+	// Rule is the BEATS relationship which decided the round, nil on ties
+	Rule    *Rule  `json:"rule,omitempty"`
+	Results string `json:"results"`
+	// Commitment is the commitment the round was played against, if any
+	Commitment string `json:"commitment,omitempty"`
+}
+
+// AuditReplay is the outcome of replaying an audited round from its record
+type AuditReplay struct {
+	Audit *RoundAudit `json:"audit"`
+	// Computer is the choice the recorded random value picks among the snapshot, only set for rounds played by the
+	// random strategy or in daily challenges, the other ones depending on more than the record
+	Computer string `json:"computer,omitempty"`
+	// Results is the outcome of the recorded choices under the recorded rule
+	Results string `json:"results"`
+	// CurrentRule is the BEATS relationship between the recorded choices under the current rules, which may have
+	// changed since the round was played
+	CurrentRule *Rule `json:"current_rule,omitempty"`
+	// Confirmed tells whether the replay led to the recorded computer choice and results
+	Confirmed bool `json:"confirmed"`
+}
+
+type AuditService interface {
+	Record(audit *RoundAudit) error
+	Audit(id string) (*RoundAudit, error)
+	Replay(id string) (*AuditReplay, error)
+}
+
+// AuditStore keeps round audits for good, without ever updating them
+type AuditStore interface {
+	SaveAudit(audit *RoundAudit) error
+	// Audit returns the audit of the round or ErrAuditNotFound
+	Audit(id string) (*RoundAudit, error)
+}
+
+type AuditServiceImpl struct {
+	auditStore AuditStore
+	roundStore RoundStore
+}
+
+func NewAuditService(auditStore AuditStore, roundStore RoundStore) AuditService {
+	return AuditServiceImpl{auditStore: auditStore, roundStore: roundStore}
+}
+
+func (as AuditServiceImpl) Record(audit *RoundAudit) error {
+	return as.auditStore.SaveAudit(audit)
+}
+
+func (as AuditServiceImpl) Audit(id string) (*RoundAudit, error) {
+	return as.auditStore.Audit(id)
+}
+
+func (as AuditServiceImpl) Replay(id string) (*AuditReplay, error) {
+	audit, err := as.auditStore.Audit(id)
+	if err != nil {
+		return nil, err
+	}
+
+	replay := &AuditReplay{Audit: audit, Results: audit.replayResults()}
+	replay.Confirmed = replay.Results == audit.Results
+	if (audit.Strategy == defaultStrategy || audit.Strategy == dailyStrategy) && len(audit.RandomValues) == 1 {
+		choice, err := pickRandom(audit.Choices, fixedRandomizer(audit.RandomValues[0]))
+		if err != nil {
+			return nil, err
+		}
+		replay.Computer = choice.ID
+		replay.Confirmed = replay.Confirmed && choice.ID == audit.Computer
+	}
+
+	if audit.Player != audit.Computer {
+		round, err := as.roundStore.SimulateRound(audit.Player, audit.Computer)
+		if err != nil && err != ErrRuleNotFound {
+			return nil, err
+		}
+		if err == nil {
+			replay.CurrentRule = &Rule{WinnerID: round.WinnerID, LoserID: round.LoserID, Action: round.Action}
+		}
+	}
+	return replay, nil
+}
+
+// newRoundAudit records a round played against the computer choice picked by the move
+func newRoundAudit(requestID, userID string, playedAt time.Time, round *RoundResults,
+	move *ComputerMove) *RoundAudit {
+	audit := &RoundAudit{
+		ID:           round.ID,
+		RequestID:    requestID,
+		UserID:       userID,
+		PlayedAt:     playedAt.UTC(),
+		Ruleset:      round.Ruleset,
+		Player:       round.Player,
+		Computer:     round.Computer,
+		Strategy:     move.Strategy,
+		RandomValues: move.RandomValues,
+		Choices:      move.Choices,
+		Results:      round.Results,
+	}
+	switch ResultsLabel(round.Results) {
+	case Win:
+		audit.Rule = &Rule{WinnerID: round.Player, LoserID: round.Computer, Action: round.Action}
+	case Lose:
+		audit.Rule = &Rule{WinnerID: round.Computer, LoserID: round.Player, Action: round.Action}
+	}
+	if round.Reveal != nil {
+		audit.Commitment = round.Reveal.Commitment
+	}
+	return audit
+}
+
+// replayResults decides the round again from the recorded choices and rule, returning an empty string if the rule
+// doesn't relate them
+func (ra *RoundAudit) replayResults() string {
+	switch {
+	case ra.Player == ra.Computer:
+		return string(Tie)
+	case ra.Rule == nil:
+		return ""
+	case ra.Rule.WinnerID == ra.Player && ra.Rule.LoserID == ra.Computer:
+		return string(Win)
+	case ra.Rule.WinnerID == ra.Computer && ra.Rule.LoserID == ra.Player:
+		return string(Lose)
+	}
+	return ""
+}
+
+// fixedRandomizer draws a recorded random value again
+type fixedRandomizer int
+
+func (fr fixedRandomizer) RandomInt() (int, error) {
+	return int(fr), nil
+}
@@ -0,0 +1,128 @@
+package rpslsapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type AuditServiceMock struct {
+	mock.Mock
+}
+
+func (asm *AuditServiceMock) Record(audit *RoundAudit) error {
+	args := asm.Called(audit)
+	return args.Error(0)
+}
+
+func (asm *AuditServiceMock) Audit(id string) (*RoundAudit, error) {
+	args := asm.Called(id)
+	return args.Get(0).(*RoundAudit), args.Error(1)
+}
+
+func (asm *AuditServiceMock) Replay(id string) (*AuditReplay, error) {
+	args := asm.Called(id)
+	return args.Get(0).(*AuditReplay), args.Error(1)
+}
+
+type AuditStoreMock struct {
+	mock.Mock
+}
+
+func (asm *AuditStoreMock) SaveAudit(audit *RoundAudit) error {
+	args := asm.Called(audit)
+	return args.Error(0)
+}
+
+func (asm *AuditStoreMock) Audit(id string) (*RoundAudit, error) {
+	args := asm.Called(id)
+	return args.Get(0).(*RoundAudit), args.Error(1)
+}
+
+func TestNewRoundAudit(t *testing.T) {
+	playedAt := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	move := &ComputerMove{Choice: &baseChoices[3], Strategy: "random", Choices: baseChoices, RandomValues: []int{8}}
+	round := &RoundResults{ID: "round", Results: string(Lose), Player: "rpsls-paper", Computer: "rpsls-lizard",
+		Ruleset: "rpsls", Action: "eats", Reveal: &CommitmentReveal{Commitment: "hash"}}
+
+	audit := newRoundAudit("request", "user", playedAt, round, move)
+
+	require.Equal(t, &RoundAudit{ID: "round", RequestID: "request", UserID: "user", PlayedAt: playedAt,
+		Ruleset: "rpsls", Player: "rpsls-paper", Computer: "rpsls-lizard", Strategy: "random",
+		RandomValues: []int{8}, Choices: baseChoices, Results: string(Lose), Commitment: "hash",
+		Rule: &Rule{WinnerID: "rpsls-lizard", LoserID: "rpsls-paper", Action: "eats"}}, audit)
+}
+
+func TestAuditService_Replay(t *testing.T) {
+	eats := &Rule{WinnerID: "rpsls-lizard", LoserID: "rpsls-paper", Action: "eats"}
+	audit := RoundAudit{ID: "round", Ruleset: "rpsls", Player: "rpsls-paper", Computer: "rpsls-lizard",
+		Strategy: "random", RandomValues: []int{8}, Choices: baseChoices, Rule: eats, Results: string(Lose)}
+	tampered := audit
+	tampered.Results = string(Win)
+	learnt := audit
+	learnt.Strategy = "markov"
+	learnt.RandomValues = []int{}
+	drawnElsewhere := audit
+	drawnElsewhere.RandomValues = []int{7}
+
+	testCases := []struct {
+		name           string
+		audit          RoundAudit
+		currentRule    *Round
+		currentError   error
+		expectedReplay *AuditReplay
+		expectedError  error
+	}{
+		{
+			name:        "success: confirm a random round, whose rule still holds",
+			audit:       audit,
+			currentRule: &Round{WinnerID: "rpsls-lizard", LoserID: "rpsls-paper", Action: "eats"},
+			expectedReplay: &AuditReplay{Computer: "rpsls-lizard", Results: string(Lose), CurrentRule: eats,
+				Confirmed: true},
+		},
+		{
+			name:           "success: confirm the results of a round played by another strategy, whose rule is gone",
+			audit:          learnt,
+			currentError:   ErrRuleNotFound,
+			expectedReplay: &AuditReplay{Results: string(Lose), Confirmed: true},
+		},
+		{
+			name:           "success: refute results which don't follow from the rule",
+			audit:          tampered,
+			currentRule:    &Round{WinnerID: "rpsls-lizard", LoserID: "rpsls-paper", Action: "eats"},
+			expectedReplay: &AuditReplay{Computer: "rpsls-lizard", Results: string(Lose), CurrentRule: eats},
+		},
+		{
+			name:           "success: refute a computer choice which the random value doesn't pick",
+			audit:          drawnElsewhere,
+			currentRule:    &Round{WinnerID: "rpsls-lizard", LoserID: "rpsls-paper", Action: "eats"},
+			expectedReplay: &AuditReplay{Computer: "rpsls-scissors", Results: string(Lose), CurrentRule: eats},
+		},
+		{
+			name:          "failure: if the rules can't be read, return the error",
+			audit:         audit,
+			currentError:  unknownDBError,
+			expectedError: unknownDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := AuditStoreMock{}
+		roundStoreMock := RoundStoreMock{}
+		service := NewAuditService(&storeMock, &roundStoreMock)
+		storeMock.On("Audit", "round").Return(&tc.audit, nil)
+		roundStoreMock.On("SimulateRound", "rpsls-paper", "rpsls-lizard").Return(tc.currentRule, tc.currentError)
+
+		replay, err := service.Replay("round")
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		tc.expectedReplay.Audit = &tc.audit
+		require.Equal(t, tc.expectedReplay, replay, tc.name)
+	}
+}
@@ -0,0 +1,202 @@
+package rpslsapi
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrBotNotFound = errors.New("bot not found")
+var ErrBotAlreadyExists = errors.New("bot already exists")
+var ErrInvalidBot = errors.New("bots need a lowercase name of letters, digits and dashes, which isn't the one of a " +
+	"built-in strategy, and an http or https URL on a public host")
+
+// privateNetworks are the IPv4 and IPv6 private ranges, loopback, link-local and unspecified addresses being checked
+// with the methods of net.IP
+var privateNetworks = []*net.IPNet{
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("fc00::/7"),
+}
+
+// maxBotNameLength bounds the length of bot names
+const maxBotNameLength = 50
+
+var botNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Bot is an opponent played by an external HTTP endpoint. Bots are selected like the built-in strategies, by name.
+type Bot struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// BotRequest is the round context posted to bots, which answer with a BotResponse before the deadline
+type BotRequest struct {
+	Ruleset string `json:"ruleset"`
+	// Choices holds the IDs of the choices of the ruleset, one of which must be played
+	Choices []string `json:"choices"`
+	// History holds the previous rounds of the opponent in the ruleset, most recent first
+	History  []BotRound `json:"history"`
+	Deadline time.Time  `json:"deadline"`
+}
+
+// BotRound is a previous round of the opponent of a bot, whose results are from the opponent's perspective
+type BotRound struct {
+	Results  string `json:"results"`
+	Opponent string `json:"opponent"`
+	Computer string `json:"computer"`
+}
+
+type BotResponse struct {
+	Choice string `json:"choice"`
+}
+
+type BotClient interface {
+	// Choose posts the request to the bot and returns its response, failing if none came before the deadline
+	Choose(bot *Bot, request *BotRequest) (*BotResponse, error)
+}
+
+type BotService interface {
+	RegisterBot(bot *Bot) (*Bot, error)
+	Bots() ([]Bot, error)
+	Bot(name string) (*Bot, error)
+	DeleteBot(name string) error
+}
+
+type BotStore interface {
+	// CreateBot stores a new bot, or returns ErrBotAlreadyExists if the name is taken
+	CreateBot(bot *Bot) error
+	// Bots returns the registered bots sorted by name
+	Bots() ([]Bot, error)
+	// Bot returns the bot or ErrBotNotFound
+	Bot(name string) (*Bot, error)
+	// DeleteBot removes the bot or returns ErrBotNotFound
+	DeleteBot(name string) error
+}
+
+type BotServiceImpl struct {
+	botStore BotStore
+}
+
+func NewBotService(botStore BotStore) BotService {
+	return BotServiceImpl{botStore: botStore}
+}
+
+func (bs BotServiceImpl) RegisterBot(bot *Bot) (*Bot, error) {
+	if err := validateBot(bot); err != nil {
+		return nil, err
+	}
+	if err := bs.botStore.CreateBot(bot); err != nil {
+		return nil, err
+	}
+	return bot, nil
+}
+
+func (bs BotServiceImpl) Bots() ([]Bot, error) {
+	return bs.botStore.Bots()
+}
+
+func (bs BotServiceImpl) Bot(name string) (*Bot, error) {
+	return bs.botStore.Bot(strings.ToLower(name))
+}
+
+func (bs BotServiceImpl) DeleteBot(name string) error {
+	return bs.botStore.DeleteBot(strings.ToLower(name))
+}
+
+// validateBot checks the bot can be called and selected without shadowing a built-in strategy
+func validateBot(bot *Bot) error {
+	if len(bot.Name) > maxBotNameLength || !botNamePattern.MatchString(bot.Name) {
+		return ErrInvalidBot
+	}
+	if _, err := findStrategy(bot.Name); err == nil {
+		return ErrInvalidBot
+	}
+	botURL, err := url.Parse(bot.URL)
+	if err != nil || (botURL.Scheme != "http" && botURL.Scheme != "https") || botURL.Host == "" {
+		return ErrInvalidBot
+	}
+	if Config.BotPrivateHosts {
+		return nil
+	}
+	host := strings.ToLower(botURL.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrInvalidBot
+	}
+	if ip := net.ParseIP(host); ip != nil && CheckBotIP(ip) != nil {
+		return ErrInvalidBot
+	}
+	return nil
+}
+
+// CheckBotIP rejects the loopback, private and link-local addresses, which would let bots reach the internal services
+// of the server, unless Config.BotPrivateHosts allows them. Host names are checked once resolved, when bots are called.
+func CheckBotIP(ip net.IP) error {
+	if Config.BotPrivateHosts {
+		return nil
+	}
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified() {
+		return fmt.Errorf("bot address %s isn't public", ip)
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return fmt.Errorf("bot address %s isn't public", ip)
+		}
+	}
+	return nil
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// BotStrategy asks a bot for the computer choice, playing at random like ChoiceService.RandomChoice if the bot fails,
+// doesn't answer in time or answers with a choice outside the ruleset
+type BotStrategy struct {
+	Bot     *Bot
+	Client  BotClient
+	Timeout time.Duration
+}
+
+func (bs BotStrategy) Choose(input *StrategyInput) (*Choice, error) {
+	request := &BotRequest{
+		Choices:  make([]string, len(input.Choices)),
+		History:  make([]BotRound, len(input.History)),
+		Deadline: time.Now().Add(bs.Timeout),
+	}
+	for i, choice := range input.Choices {
+		request.Choices[i] = choice.ID
+		request.Ruleset = choice.Ruleset
+	}
+	for i, round := range input.History {
+		request.History[i] = BotRound{Results: round.Results, Opponent: round.Player, Computer: round.Computer}
+	}
+
+	response, err := bs.Client.Choose(bs.Bot, request)
+	if err != nil {
+		log.Warn().Err(err).Str("bot", bs.Bot.Name).Msg("bot failed to choose, playing at random")
+		return RandomStrategy{}.Choose(input)
+	}
+	for i := range input.Choices {
+		if input.Choices[i].ID == response.Choice {
+			return &input.Choices[i], nil
+		}
+	}
+	log.Warn().Str("bot", bs.Bot.Name).Str("choice", response.Choice).
+		Msg("bot chose outside of the ruleset, playing at random")
+	return RandomStrategy{}.Choose(input)
+}
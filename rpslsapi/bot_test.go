@@ -0,0 +1,165 @@
+package rpslsapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type BotStoreMock struct {
+	mock.Mock
+}
+
+type BotClientMock struct {
+	mock.Mock
+}
+
+func (bsm *BotStoreMock) CreateBot(bot *Bot) error {
+	args := bsm.Called(bot)
+	return args.Error(0)
+}
+
+func (bsm *BotStoreMock) Bots() ([]Bot, error) {
+	args := bsm.Called()
+	return args.Get(0).([]Bot), args.Error(1)
+}
+
+func (bsm *BotStoreMock) Bot(name string) (*Bot, error) {
+	args := bsm.Called(name)
+	return args.Get(0).(*Bot), args.Error(1)
+}
+
+func (bsm *BotStoreMock) DeleteBot(name string) error {
+	args := bsm.Called(name)
+	return args.Error(0)
+}
+
+func (bcm *BotClientMock) Choose(bot *Bot, request *BotRequest) (*BotResponse, error) {
+	args := bcm.Called(bot, request)
+	return args.Get(0).(*BotResponse), args.Error(1)
+}
+
+var stubBot = &Bot{Name: "stub", URL: "http://bots.example.com/choose"}
+
+func TestBotService_RegisterBot(t *testing.T) {
+	testCases := []struct {
+		name          string
+		bot           Bot
+		storeError    error
+		expectedError error
+	}{
+		{
+			name: "success: register the bot",
+			bot:  *stubBot,
+		},
+		{
+			name:          "failure: if the name is taken, return ErrBotAlreadyExists",
+			bot:           *stubBot,
+			storeError:    ErrBotAlreadyExists,
+			expectedError: ErrBotAlreadyExists,
+		},
+		{
+			name:          "failure: if the name isn't a lowercase slug, return ErrInvalidBot",
+			bot:           Bot{Name: "Stub Bot", URL: stubBot.URL},
+			expectedError: ErrInvalidBot,
+		},
+		{
+			name:          "failure: if the name is a difficulty level, return ErrInvalidBot",
+			bot:           Bot{Name: "hard", URL: stubBot.URL},
+			expectedError: ErrInvalidBot,
+		},
+		{
+			name:          "failure: if the URL isn't an http URL, return ErrInvalidBot",
+			bot:           Bot{Name: "stub", URL: "ftp://bots.example.com/choose"},
+			expectedError: ErrInvalidBot,
+		},
+		{
+			name:          "failure: if the host is localhost, return ErrInvalidBot",
+			bot:           Bot{Name: "stub", URL: "http://localhost:3100/choose"},
+			expectedError: ErrInvalidBot,
+		},
+		{
+			name:          "failure: if the host is a loopback address, return ErrInvalidBot",
+			bot:           Bot{Name: "stub", URL: "http://[::1]:3100/choose"},
+			expectedError: ErrInvalidBot,
+		},
+		{
+			name:          "failure: if the host is a private address, return ErrInvalidBot",
+			bot:           Bot{Name: "stub", URL: "http://10.0.0.7/choose"},
+			expectedError: ErrInvalidBot,
+		},
+		{
+			name:          "failure: if the host is a link-local address, return ErrInvalidBot",
+			bot:           Bot{Name: "stub", URL: "http://169.254.169.254/latest/meta-data"},
+			expectedError: ErrInvalidBot,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := BotStoreMock{}
+		service := NewBotService(&storeMock)
+		storeMock.On("CreateBot", mock.Anything).Return(tc.storeError)
+
+		bot, err := service.RegisterBot(&tc.bot)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, &tc.bot, bot, tc.name)
+	}
+}
+
+func TestBotStrategy_Choose(t *testing.T) {
+	history := playedRounds(Lose, "rpsls-paper", "rpsls-spock", "rpsls-rock")
+
+	testCases := []struct {
+		name             string
+		response         *BotResponse
+		clientError      error
+		expectedChoiceID string
+	}{
+		{
+			name:             "success: play the choice of the bot",
+			response:         &BotResponse{Choice: "rpsls-lizard"},
+			expectedChoiceID: "rpsls-lizard",
+		},
+		{
+			name:             "success: play at random if the bot doesn't answer in time",
+			clientError:      errors.New("context deadline exceeded"),
+			expectedChoiceID: "rpsls-scissors",
+		},
+		{
+			name:             "success: play at random if the bot picks a choice outside of the ruleset",
+			response:         &BotResponse{Choice: "rps-rock"},
+			expectedChoiceID: "rpsls-scissors",
+		},
+	}
+
+	for _, tc := range testCases {
+		clientMock := BotClientMock{}
+		randomizerMock := RandomizerMock{}
+		strategy := BotStrategy{Bot: stubBot, Client: &clientMock, Timeout: time.Second}
+		clientMock.On("Choose", stubBot, mock.Anything).Return(tc.response, tc.clientError)
+		randomizerMock.On("RandomInt").Return(7, nil)
+
+		before := time.Now()
+		choice, err := strategy.Choose(&StrategyInput{Choices: baseChoices, Rules: baseRules, History: history,
+			Randomizer: &randomizerMock})
+
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedChoiceID, choice.ID, tc.name)
+		request := clientMock.Calls[0].Arguments.Get(1).(*BotRequest)
+		require.Equal(t, "rpsls", request.Ruleset, tc.name)
+		require.Len(t, request.Choices, len(baseChoices), tc.name)
+		require.Equal(t, []BotRound{
+			{Results: string(Lose), Opponent: "rpsls-rock", Computer: "rpsls-paper"},
+			{Results: string(Lose), Opponent: "rpsls-spock", Computer: "rpsls-paper"},
+		}, request.History, tc.name)
+		require.False(t, request.Deadline.Before(before.Add(time.Second)), tc.name)
+	}
+}
@@ -1,55 +1,340 @@
 package rpslsapi
 
-import "errors"
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+)
 
 var ErrChoiceNotFound = errors.New("choice not found")
+var ErrChoiceAlreadyExists = errors.New("choice already exists")
+var ErrInvalidChoice = errors.New("invalid choice")
 
+var choiceIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Choice is identified by a slug, e.g. "rpsls-rock", which stays the same when the choice is renamed and when its
+// ruleset is re-created from the same names
 type Choice struct {
-	ID   int64  `db:"id" json:"id"`
-	Name string `json:"name"`
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Ruleset string `json:"ruleset"`
+}
+
+// ChoiceDefinition describes a new choice along with the rules relating it to the existing ones. The ID is derived
+// from the ruleset and the name through NewChoiceID unless one is given.
+type ChoiceDefinition struct {
+	ID       string         `json:"id,omitempty"`
+	Name     string         `json:"name"`
+	Ruleset  string         `json:"ruleset"`
+	Beats    []Relationship `json:"beats"`
+	BeatenBy []Relationship `json:"beaten_by"`
 }
 
+// ChoiceService manages choices and rules. Methods taking a ruleset ID fall back to the default ruleset if it is empty.
 type ChoiceService interface {
-	Choices() ([]Choice, error)
-	RandomChoice() (*Choice, error)
-	Choice(id int64) (*Choice, error)
+	Choices(ruleset string) ([]Choice, error)
+	RandomChoice(ruleset string) (*Choice, error)
+	Choice(id string) (*Choice, error)
+	CreateChoice(definition *ChoiceDefinition) (*Choice, error)
+	UpdateChoice(choice *Choice) (*Choice, error)
+	DeleteChoice(id string) error
+	Rules(ruleset string) ([]Rule, error)
+	SaveRule(rule *Rule) error
+	DeleteRule(winnerID, loserID string) error
+	ValidateRules(ruleset string) (*RuleGraphReport, error)
+	RenderRules(ruleset string, format GraphFormat) ([]byte, error)
 }
 
 type ChoiceStore interface {
-	Choices() ([]Choice, error)
-	Choice(id int64) (*Choice, error)
+	// Choices returns the choices of the ruleset, or ErrRulesetNotFound
+	Choices(ruleset string) ([]Choice, error)
+	Choice(id string) (*Choice, error)
+	// CreateChoice stores the choice with the ID of the definition, failing with ErrChoiceAlreadyExists if it is taken
+	CreateChoice(definition *ChoiceDefinition) (*Choice, error)
+	UpdateChoice(choice *Choice) (*Choice, error)
+	DeleteChoice(id string) error
+	Rules(ruleset string) ([]Rule, error)
+	// SaveRule creates the BEATS relationship described by the rule, replacing any existing one between both choices.
+	// Both choices must belong to the same ruleset, otherwise ErrChoiceNotFound is returned.
+	SaveRule(rule *Rule) error
+	DeleteRule(winnerID, loserID string) error
+	// ImportRuleset creates the ruleset described by a validated document along with its choices and rules, atomically.
+	// An existing ruleset with the same ID is deleted first if replace is set, otherwise ErrRulesetAlreadyExists is
+	// returned.
+	ImportRuleset(document *RulesetDocument, replace bool) (*Ruleset, error)
+}
+
+// RuleGraphLock serializes the writes to choices, rules and rulesets made through the services sharing it, so that
+// each write changing a rule graph is validated against the graph left by the previous one rather than against the
+// same stale graph. It only covers a single process: instances sharing a database may still commit conflicting edits.
+type RuleGraphLock struct {
+	sync.Mutex
+}
+
+func NewRuleGraphLock() *RuleGraphLock {
+	return &RuleGraphLock{}
 }
 
 type ChoiceServiceImpl struct {
 	store      ChoiceStore
 	randomizer RandomizerService
+	lock       *RuleGraphLock
 }
 
-func NewChoiceService(store ChoiceStore, randomizer RandomizerService) ChoiceService {
-	return ChoiceServiceImpl{store: store, randomizer: randomizer}
+func NewChoiceService(store ChoiceStore, randomizer RandomizerService, lock *RuleGraphLock) ChoiceService {
+	return ChoiceServiceImpl{store: store, randomizer: randomizer, lock: lock}
 }
 
-func (cs ChoiceServiceImpl) Choices() ([]Choice, error) {
-	choices, err := cs.store.Choices()
+func (cs ChoiceServiceImpl) Choices(ruleset string) ([]Choice, error) {
+	choices, err := cs.store.Choices(RulesetOrDefault(ruleset))
 	if err == nil && choices == nil {
 		choices = []Choice{}
 	}
 	return choices, err
 }
 
-func (cs ChoiceServiceImpl) Choice(id int64) (*Choice, error) {
+func (cs ChoiceServiceImpl) Choice(id string) (*Choice, error) {
 	return cs.store.Choice(id)
 }
 
-func (cs ChoiceServiceImpl) RandomChoice() (*Choice, error) {
+func (cs ChoiceServiceImpl) RandomChoice(ruleset string) (*Choice, error) {
 	randomInt, err := cs.randomizer.RandomInt()
 	if err != nil {
 		return nil, err
 	}
-	choices, err := cs.Choices()
+	choices, err := cs.Choices(ruleset)
 	if err != nil {
 		return nil, err
 	}
+	if len(choices) == 0 {
+		return nil, ErrChoiceNotFound
+	}
 
 	return &choices[randomInt%len(choices)], nil
 }
+
+func (cs ChoiceServiceImpl) CreateChoice(definition *ChoiceDefinition) (*Choice, error) {
+	definition.Name = strings.TrimSpace(definition.Name)
+	if definition.Name == "" {
+		return nil, ErrInvalidChoice
+	}
+	definition.Ruleset = RulesetOrDefault(definition.Ruleset)
+	definition.ID = strings.TrimSpace(definition.ID)
+	if definition.ID == "" {
+		definition.ID = NewChoiceID(definition.Ruleset, definition.Name)
+	} else if !choiceIDPattern.MatchString(definition.ID) {
+		return nil, ErrInvalidChoice
+	}
+
+	related := make(map[string]bool, len(definition.Beats)+len(definition.BeatenBy))
+	for _, relationships := range [][]Relationship{definition.Beats, definition.BeatenBy} {
+		for _, relationship := range relationships {
+			if related[relationship.ChoiceID] || strings.TrimSpace(relationship.Action) == "" {
+				return nil, ErrInvalidRule
+			}
+			related[relationship.ChoiceID] = true
+		}
+	}
+
+	var newRules []Rule
+	for _, relationship := range definition.Beats {
+		newRules = append(newRules, Rule{WinnerID: definition.ID, LoserID: relationship.ChoiceID, Action: relationship.Action})
+	}
+	for _, relationship := range definition.BeatenBy {
+		newRules = append(newRules, Rule{WinnerID: relationship.ChoiceID, LoserID: definition.ID, Action: relationship.Action})
+	}
+
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	err := cs.checkRuleGraph(definition.Ruleset, func(choices []Choice, rules []Rule) ([]Choice, []Rule) {
+		proposedChoices := append(append([]Choice{}, choices...), Choice{ID: definition.ID, Name: definition.Name})
+		return proposedChoices, append(append([]Rule{}, rules...), newRules...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.store.CreateChoice(definition)
+}
+
+func (cs ChoiceServiceImpl) UpdateChoice(choice *Choice) (*Choice, error) {
+	choice.Name = strings.TrimSpace(choice.Name)
+	if choice.Name == "" {
+		return nil, ErrInvalidChoice
+	}
+
+	return cs.store.UpdateChoice(choice)
+}
+
+func (cs ChoiceServiceImpl) DeleteChoice(id string) error {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	choice, err := cs.store.Choice(id)
+	if err != nil {
+		return err
+	}
+
+	err = cs.checkRuleGraph(choice.Ruleset, func(choices []Choice, rules []Rule) ([]Choice, []Rule) {
+		var remainingChoices []Choice
+		for _, remaining := range choices {
+			if remaining.ID != id {
+				remainingChoices = append(remainingChoices, remaining)
+			}
+		}
+		return remainingChoices, filterRules(rules, func(rule Rule) bool {
+			return rule.WinnerID != id && rule.LoserID != id
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return cs.store.DeleteChoice(id)
+}
+
+func (cs ChoiceServiceImpl) Rules(ruleset string) ([]Rule, error) {
+	rules, err := cs.store.Rules(RulesetOrDefault(ruleset))
+	if err == nil && rules == nil {
+		rules = []Rule{}
+	}
+	return rules, err
+}
+
+func (cs ChoiceServiceImpl) SaveRule(rule *Rule) error {
+	rule.Action = strings.TrimSpace(rule.Action)
+	if rule.WinnerID == rule.LoserID || rule.Action == "" {
+		return ErrInvalidRule
+	}
+
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	winner, err := cs.store.Choice(rule.WinnerID)
+	if err != nil {
+		return err
+	}
+	loser, err := cs.store.Choice(rule.LoserID)
+	if err != nil {
+		return err
+	}
+	if winner.Ruleset != loser.Ruleset {
+		return ErrInvalidRule
+	}
+
+	err = cs.checkRuleGraph(winner.Ruleset, func(choices []Choice, rules []Rule) ([]Choice, []Rule) {
+		pair := newChoicePair(rule.WinnerID, rule.LoserID)
+		return choices, append(filterRules(rules, func(existing Rule) bool {
+			return newChoicePair(existing.WinnerID, existing.LoserID) != pair
+		}), *rule)
+	})
+	if err != nil {
+		return err
+	}
+
+	return cs.store.SaveRule(rule)
+}
+
+func (cs ChoiceServiceImpl) DeleteRule(winnerID, loserID string) error {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	winner, err := cs.store.Choice(winnerID)
+	if err == ErrChoiceNotFound {
+		return ErrRuleNotFound
+	} else if err != nil {
+		return err
+	}
+
+	err = cs.checkRuleGraph(winner.Ruleset, func(choices []Choice, rules []Rule) ([]Choice, []Rule) {
+		return choices, filterRules(rules, func(rule Rule) bool {
+			return rule.WinnerID != winnerID || rule.LoserID != loserID
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return cs.store.DeleteRule(winnerID, loserID)
+}
+
+func (cs ChoiceServiceImpl) ValidateRules(ruleset string) (*RuleGraphReport, error) {
+	ruleset = RulesetOrDefault(ruleset)
+	choices, err := cs.store.Choices(ruleset)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := cs.store.Rules(ruleset)
+	if err != nil {
+		return nil, err
+	}
+
+	return ValidateRuleGraph(choices, rules), nil
+}
+
+func (cs ChoiceServiceImpl) RenderRules(ruleset string, format GraphFormat) ([]byte, error) {
+	ruleset = RulesetOrDefault(ruleset)
+	choices, err := cs.store.Choices(ruleset)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := cs.store.Rules(ruleset)
+	if err != nil {
+		return nil, err
+	}
+
+	var rendered bytes.Buffer
+	if err := RenderRuleGraph(&rendered, ruleset, choices, rules, format); err != nil {
+		return nil, err
+	}
+	return rendered.Bytes(), nil
+}
+
+// checkRuleGraph validates the ruleset graph resulting from applying change to the stored one, failing with a
+// RuleGraphError if the change introduces errors that were not already there. It must be called with the rule graph
+// lock held until the change is stored. A cached graph is dropped first, since it may miss the writes of other
+// instances.
+func (cs ChoiceServiceImpl) checkRuleGraph(ruleset string,
+	change func(choices []Choice, rules []Rule) ([]Choice, []Rule)) error {
+	if cache, ok := cs.store.(OutcomeCache); ok {
+		cache.Invalidate(ruleset)
+	}
+	choices, err := cs.store.Choices(ruleset)
+	if err != nil {
+		return err
+	}
+	rules, err := cs.store.Rules(ruleset)
+	if err != nil {
+		return err
+	}
+
+	current := ValidateRuleGraph(choices, rules)
+	proposed := ValidateRuleGraph(change(choices, rules))
+	if len(proposed.introducedErrors(current)) > 0 {
+		return &RuleGraphError{Report: proposed}
+	}
+	return nil
+}
+
+func filterRules(rules []Rule, keep func(rule Rule) bool) []Rule {
+	var kept []Rule
+	for _, rule := range rules {
+		if keep(rule) {
+			kept = append(kept, rule)
+		}
+	}
+	return kept
+}
+
+// NewChoiceID derives the ID of a choice from its ruleset and name, e.g. "rpsls-rock" for Rock in RPSLS. Names without
+// any ASCII letter or digit get a hash instead.
+func NewChoiceID(ruleset, name string) string {
+	slug := strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		sum := sha1.Sum([]byte(name))
+		slug = hex.EncodeToString(sum[:4])
+	}
+	return ruleset + "-" + slug
+}
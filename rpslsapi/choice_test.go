@@ -2,6 +2,7 @@ package rpslsapi
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -16,16 +17,51 @@ type RandomizerMock struct {
 	mock.Mock
 }
 
-func (csm *ChoiceStoreMock) Choices() ([]Choice, error) {
-	args := csm.Called()
+func (csm *ChoiceStoreMock) Choices(ruleset string) ([]Choice, error) {
+	args := csm.Called(ruleset)
 	return args.Get(0).([]Choice), args.Error(1)
 }
 
-func (csm *ChoiceStoreMock) Choice(id int64) (*Choice, error) {
+func (csm *ChoiceStoreMock) Choice(id string) (*Choice, error) {
 	args := csm.Called(id)
 	return args.Get(0).(*Choice), args.Error(1)
 }
 
+func (csm *ChoiceStoreMock) CreateChoice(definition *ChoiceDefinition) (*Choice, error) {
+	args := csm.Called(definition)
+	return args.Get(0).(*Choice), args.Error(1)
+}
+
+func (csm *ChoiceStoreMock) UpdateChoice(choice *Choice) (*Choice, error) {
+	args := csm.Called(choice)
+	return args.Get(0).(*Choice), args.Error(1)
+}
+
+func (csm *ChoiceStoreMock) DeleteChoice(id string) error {
+	args := csm.Called(id)
+	return args.Error(0)
+}
+
+func (csm *ChoiceStoreMock) Rules(ruleset string) ([]Rule, error) {
+	args := csm.Called(ruleset)
+	return args.Get(0).([]Rule), args.Error(1)
+}
+
+func (csm *ChoiceStoreMock) SaveRule(rule *Rule) error {
+	args := csm.Called(rule)
+	return args.Error(0)
+}
+
+func (csm *ChoiceStoreMock) DeleteRule(winnerID, loserID string) error {
+	args := csm.Called(winnerID, loserID)
+	return args.Error(0)
+}
+
+func (csm *ChoiceStoreMock) ImportRuleset(document *RulesetDocument, replace bool) (*Ruleset, error) {
+	args := csm.Called(document, replace)
+	return args.Get(0).(*Ruleset), args.Error(1)
+}
+
 func (rm *RandomizerMock) RandomInt() (int, error) {
 	args := rm.Called()
 	return args.Get(0).(int), args.Error(1)
@@ -33,28 +69,56 @@ func (rm *RandomizerMock) RandomInt() (int, error) {
 
 var baseChoices = []Choice{
 	{
-		ID:   1,
-		Name: "rock",
+		ID:      "rpsls-rock",
+		Name:    "rock",
+		Ruleset: "rpsls",
 	},
 	{
-		ID:   2,
-		Name: "paper",
+		ID:      "rpsls-paper",
+		Name:    "paper",
+		Ruleset: "rpsls",
 	},
 	{
-		ID:   3,
-		Name: "scissors",
+		ID:      "rpsls-scissors",
+		Name:    "scissors",
+		Ruleset: "rpsls",
 	},
 	{
-		ID:   4,
-		Name: "lizard",
+		ID:      "rpsls-lizard",
+		Name:    "lizard",
+		Ruleset: "rpsls",
 	},
 	{
-		ID:   5,
-		Name: "spock",
+		ID:      "rpsls-spock",
+		Name:    "spock",
+		Ruleset: "rpsls",
 	},
 }
 
+// baseRules are the RPSLS rules between baseChoices
+var baseRules = []Rule{
+	{WinnerID: "rpsls-rock", LoserID: "rpsls-scissors", Action: "crushes"},
+	{WinnerID: "rpsls-rock", LoserID: "rpsls-lizard", Action: "crushes"},
+	{WinnerID: "rpsls-paper", LoserID: "rpsls-rock", Action: "covers"},
+	{WinnerID: "rpsls-paper", LoserID: "rpsls-spock", Action: "disproves"},
+	{WinnerID: "rpsls-scissors", LoserID: "rpsls-paper", Action: "cuts"},
+	{WinnerID: "rpsls-scissors", LoserID: "rpsls-lizard", Action: "decapitates"},
+	{WinnerID: "rpsls-lizard", LoserID: "rpsls-paper", Action: "eats"},
+	{WinnerID: "rpsls-lizard", LoserID: "rpsls-spock", Action: "poisons"},
+	{WinnerID: "rpsls-spock", LoserID: "rpsls-scissors", Action: "smashes"},
+	{WinnerID: "rpsls-spock", LoserID: "rpsls-rock", Action: "vaporizes"},
+}
+
 var unknownDBError = errors.New("unknown DB error")
+
+// mockChoiceLookups makes the store mock find every choice in baseChoices by its ID
+func mockChoiceLookups(storeMock *ChoiceStoreMock) {
+	for i := range baseChoices {
+		storeMock.On("Choice", baseChoices[i].ID).Return(&baseChoices[i], nil)
+	}
+	storeMock.On("Choice", mock.Anything).Return((*Choice)(nil), ErrChoiceNotFound)
+}
+
 var unknownRandomizerError = errors.New("unknown randomizer error")
 
 func TestChoiceService_Choices(t *testing.T) {
@@ -93,11 +157,11 @@ func TestChoiceService_Choices(t *testing.T) {
 	}
 
 	storeMock := ChoiceStoreMock{}
-	service := NewChoiceService(&storeMock, nil)
+	service := NewChoiceService(&storeMock, nil, NewRuleGraphLock())
 
 	for _, tc := range testCases {
-		storeMock.On("Choices").Return(tc.existingChoices, tc.storeError).Once()
-		choices, err := service.Choices()
+		storeMock.On("Choices", mock.Anything).Return(tc.existingChoices, tc.storeError).Once()
+		choices, err := service.Choices("")
 
 		if tc.expectedError != nil {
 			require.NotNil(t, t, err)
@@ -105,9 +169,9 @@ func TestChoiceService_Choices(t *testing.T) {
 		} else {
 			require.NotNil(t, choices)
 			require.Equal(t, len(tc.expectedChoices), len(choices))
-			storeMock.AssertCalled(t, "Choices")
+			storeMock.AssertCalled(t, "Choices", mock.Anything)
 
-			retChoicesMap := make(map[int64]Choice, len(choices))
+			retChoicesMap := make(map[string]Choice, len(choices))
 			for i := range choices {
 				retChoice := choices[i]
 				retChoicesMap[retChoice.ID] = retChoice
@@ -128,33 +192,33 @@ func TestChoiceService_Choices(t *testing.T) {
 func TestChoiceService_Choice(t *testing.T) {
 	testCases := []struct {
 		name           string
-		givenID        int64
+		givenID        string
 		expectedChoice *Choice
 		expectedError  error
 	}{
 		{
 			name:           "success: return found choice",
-			givenID:        1,
+			givenID:        "rpsls-rock",
 			expectedChoice: &baseChoices[0],
 			expectedError:  nil,
 		},
 		{
 			name:          "failure: if choice not found, return error",
-			givenID:       2,
+			givenID:       "rpsls-paper",
 			expectedError: ErrChoiceNotFound,
 		},
 		{
 			name:          "failure: if store returns unknown error, propagate it",
-			givenID:       3,
+			givenID:       "rpsls-scissors",
 			expectedError: unknownDBError,
 		},
 	}
 
 	storeMock := ChoiceStoreMock{}
-	service := NewChoiceService(&storeMock, nil)
-	storeMock.On("Choice", int64(1)).Return(&baseChoices[0], nil)
-	storeMock.On("Choice", int64(2)).Return((*Choice)(nil), ErrChoiceNotFound)
-	storeMock.On("Choice", int64(3)).Return((*Choice)(nil), unknownDBError)
+	service := NewChoiceService(&storeMock, nil, NewRuleGraphLock())
+	storeMock.On("Choice", "rpsls-rock").Return(&baseChoices[0], nil)
+	storeMock.On("Choice", "rpsls-paper").Return((*Choice)(nil), ErrChoiceNotFound)
+	storeMock.On("Choice", "rpsls-scissors").Return((*Choice)(nil), unknownDBError)
 
 	for _, tc := range testCases {
 		choice, err := service.Choice(tc.givenID)
@@ -199,12 +263,12 @@ func TestChoiceService_RandomChoice(t *testing.T) {
 
 	storeMock := ChoiceStoreMock{}
 	randomizerMock := RandomizerMock{}
-	service := NewChoiceService(&storeMock, &randomizerMock)
+	service := NewChoiceService(&storeMock, &randomizerMock, NewRuleGraphLock())
 
 	for _, tc := range testCases {
-		storeMock.On("Choices").Return(baseChoices, tc.storeError).Once()
+		storeMock.On("Choices", mock.Anything).Return(baseChoices, tc.storeError).Once()
 		randomizerMock.On("RandomInt").Return(tc.randomInt, tc.randomizerError).Once()
-		choice, err := service.RandomChoice()
+		choice, err := service.RandomChoice("")
 
 		if tc.expectedError != nil {
 			require.NotNil(t, t, err)
@@ -226,3 +290,267 @@ func TestChoiceService_RandomChoice(t *testing.T) {
 		}
 	}
 }
+
+func TestChoiceService_CreateChoice(t *testing.T) {
+	testCases := []struct {
+		name          string
+		definition    *ChoiceDefinition
+		storeError    error
+		expectedID    string
+		expectedError error
+	}{
+		{
+			name: "success: create choice with its rules",
+			definition: &ChoiceDefinition{
+				Name:    " kitten ",
+				Ruleset: "rpsls",
+				Beats: []Relationship{
+					{ChoiceID: "rpsls-paper", Action: "scratches"},
+					{ChoiceID: "rpsls-lizard", Action: "eats"},
+					{ChoiceID: "rpsls-spock", Action: "mesmerizes"},
+				},
+				BeatenBy: []Relationship{{ChoiceID: "rpsls-rock", Action: "crushes"}, {ChoiceID: "rpsls-scissors", Action: "cuts"}},
+			},
+			expectedID: "rpsls-kitten",
+		},
+		{
+			name: "success: keep the given ID",
+			definition: &ChoiceDefinition{
+				ID:      " rpsls-cat ",
+				Name:    "kitten",
+				Ruleset: "rpsls",
+				Beats: []Relationship{
+					{ChoiceID: "rpsls-paper", Action: "scratches"},
+					{ChoiceID: "rpsls-lizard", Action: "eats"},
+					{ChoiceID: "rpsls-spock", Action: "mesmerizes"},
+				},
+				BeatenBy: []Relationship{{ChoiceID: "rpsls-rock", Action: "crushes"}, {ChoiceID: "rpsls-scissors", Action: "cuts"}},
+			},
+			expectedID: "rpsls-cat",
+		},
+		{
+			name:          "failure: if the ID is not a slug, return ErrInvalidChoice",
+			definition:    &ChoiceDefinition{ID: "Kitten!", Name: "kitten"},
+			expectedError: ErrInvalidChoice,
+		},
+		{
+			name: "failure: if the choice is not related to every other one, return ErrInconsistentRuleGraph",
+			definition: &ChoiceDefinition{
+				Name:     "kitten",
+				Beats:    []Relationship{{ChoiceID: "rpsls-paper", Action: "scratches"}},
+				BeatenBy: []Relationship{{ChoiceID: "rpsls-rock", Action: "crushes"}},
+			},
+			expectedError: ErrInconsistentRuleGraph,
+		},
+		{
+			name:          "failure: if name is blank, return ErrInvalidChoice",
+			definition:    &ChoiceDefinition{Name: "  "},
+			expectedError: ErrInvalidChoice,
+		},
+		{
+			name: "failure: if a choice is related twice, return ErrInvalidRule",
+			definition: &ChoiceDefinition{
+				Name:     "kitten",
+				Beats:    []Relationship{{ChoiceID: "rpsls-paper", Action: "scratches"}},
+				BeatenBy: []Relationship{{ChoiceID: "rpsls-paper", Action: "covers"}},
+			},
+			expectedError: ErrInvalidRule,
+		},
+		{
+			name: "failure: if an action is blank, return ErrInvalidRule",
+			definition: &ChoiceDefinition{
+				Name:  "kitten",
+				Beats: []Relationship{{ChoiceID: "rpsls-paper", Action: ""}},
+			},
+			expectedError: ErrInvalidRule,
+		},
+		{
+			name:          "failure: if store returns unknown error, propagate it",
+			definition:    &ChoiceDefinition{Name: "kitten"},
+			storeError:    unknownDBError,
+			expectedError: unknownDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := ChoiceStoreMock{}
+		service := NewChoiceService(&storeMock, nil, NewRuleGraphLock())
+		storeMock.On("Choices", mock.Anything).Return(baseChoices, tc.storeError)
+		storeMock.On("Rules", mock.Anything).Return(baseRules, nil)
+		storeMock.On("CreateChoice", tc.definition).Return(&Choice{ID: "rpsls-kitten", Name: "kitten"}, nil).Once()
+
+		choice, err := service.CreateChoice(tc.definition)
+
+		if tc.expectedError != nil {
+			require.NotNil(t, err)
+			require.True(t, errors.Is(err, tc.expectedError))
+			storeMock.AssertNotCalled(t, "CreateChoice", tc.definition)
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, &Choice{ID: "rpsls-kitten", Name: "kitten"}, choice)
+			require.Equal(t, "kitten", tc.definition.Name)
+			require.Equal(t, tc.expectedID, tc.definition.ID)
+			storeMock.AssertCalled(t, "CreateChoice", tc.definition)
+		}
+	}
+}
+
+func TestChoiceService_UpdateChoice(t *testing.T) {
+	testCases := []struct {
+		name          string
+		choice        *Choice
+		storeError    error
+		expectedError error
+	}{
+		{
+			name:   "success: update choice",
+			choice: &Choice{ID: "rpsls-rock", Name: "boulder"},
+		},
+		{
+			name:          "failure: if name is blank, return ErrInvalidChoice",
+			choice:        &Choice{ID: "rpsls-rock", Name: ""},
+			expectedError: ErrInvalidChoice,
+		},
+		{
+			name:          "failure: if store returns ErrChoiceNotFound, propagate it",
+			choice:        &Choice{ID: "rpsls-pebble", Name: "boulder"},
+			storeError:    ErrChoiceNotFound,
+			expectedError: ErrChoiceNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := ChoiceStoreMock{}
+		service := NewChoiceService(&storeMock, nil, NewRuleGraphLock())
+		storeMock.On("UpdateChoice", tc.choice).Return(tc.choice, tc.storeError).Once()
+
+		choice, err := service.UpdateChoice(tc.choice)
+
+		if tc.expectedError != nil {
+			require.NotNil(t, err)
+			require.EqualError(t, tc.expectedError, err.Error())
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, tc.choice, choice)
+			storeMock.AssertCalled(t, "UpdateChoice", tc.choice)
+		}
+	}
+}
+
+func TestChoiceService_SaveRule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rule          *Rule
+		storeError    error
+		expectedError error
+	}{
+		{
+			name: "success: save rule",
+			rule: &Rule{WinnerID: "rpsls-rock", LoserID: "rpsls-scissors", Action: "crushes"},
+		},
+		{
+			name:          "failure: if a choice beats itself, return ErrInvalidRule",
+			rule:          &Rule{WinnerID: "rpsls-rock", LoserID: "rpsls-rock", Action: "crushes"},
+			expectedError: ErrInvalidRule,
+		},
+		{
+			name:          "failure: if action is blank, return ErrInvalidRule",
+			rule:          &Rule{WinnerID: "rpsls-rock", LoserID: "rpsls-scissors", Action: " "},
+			expectedError: ErrInvalidRule,
+		},
+		{
+			name:          "failure: if the rule references an unknown choice, return ErrChoiceNotFound",
+			rule:          &Rule{WinnerID: "rpsls-rock", LoserID: "rpsls-missing-99", Action: "crushes"},
+			expectedError: ErrChoiceNotFound,
+		},
+		{
+			name:          "failure: if store returns unknown error, propagate it",
+			rule:          &Rule{WinnerID: "rpsls-rock", LoserID: "rpsls-scissors", Action: "crushes"},
+			storeError:    unknownDBError,
+			expectedError: unknownDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := ChoiceStoreMock{}
+		service := NewChoiceService(&storeMock, nil, NewRuleGraphLock())
+		storeMock.On("Choices", mock.Anything).Return(baseChoices, nil)
+		storeMock.On("Rules", mock.Anything).Return(baseRules, nil)
+		mockChoiceLookups(&storeMock)
+		storeMock.On("SaveRule", tc.rule).Return(tc.storeError).Once()
+
+		err := service.SaveRule(tc.rule)
+
+		if tc.expectedError != nil {
+			require.NotNil(t, err)
+			require.True(t, errors.Is(err, tc.expectedError))
+		} else {
+			require.NoError(t, err)
+			storeMock.AssertCalled(t, "SaveRule", tc.rule)
+		}
+	}
+}
+
+func TestChoiceService_DeleteRule(t *testing.T) {
+	storeMock := ChoiceStoreMock{}
+	service := NewChoiceService(&storeMock, nil, NewRuleGraphLock())
+	storeMock.On("Choices", mock.Anything).Return(baseChoices, nil)
+	storeMock.On("Rules", mock.Anything).Return(baseRules, nil)
+	storeMock.On("DeleteRule", "rpsls-rock", "rpsls-scissors").Return(nil)
+	mockChoiceLookups(&storeMock)
+
+	err := service.DeleteRule("rpsls-rock", "rpsls-scissors")
+
+	require.True(t, errors.Is(err, ErrInconsistentRuleGraph))
+	var ruleGraphErr *RuleGraphError
+	require.True(t, errors.As(err, &ruleGraphErr))
+	require.False(t, ruleGraphErr.Report.Valid)
+	storeMock.AssertNotCalled(t, "DeleteRule", "rpsls-rock", "rpsls-scissors")
+}
+
+func TestChoiceService_DeleteChoice(t *testing.T) {
+	storeMock := ChoiceStoreMock{}
+	service := NewChoiceService(&storeMock, nil, NewRuleGraphLock())
+	storeMock.On("Choices", mock.Anything).Return(baseChoices, nil)
+	storeMock.On("Rules", mock.Anything).Return(baseRules, nil)
+	storeMock.On("DeleteChoice", "rpsls-spock").Return(nil)
+	mockChoiceLookups(&storeMock)
+
+	err := service.DeleteChoice("rpsls-spock")
+
+	require.NoError(t, err)
+	storeMock.AssertCalled(t, "DeleteChoice", "rpsls-spock")
+}
+
+func TestChoiceService_RenderRules(t *testing.T) {
+	Config.DefaultRuleset = "rpsls"
+	storeMock := ChoiceStoreMock{}
+	service := NewChoiceService(&storeMock, nil, NewRuleGraphLock())
+	storeMock.On("Choices", "rpsls").Return(baseChoices, nil)
+	storeMock.On("Rules", "rpsls").Return(baseRules, nil)
+	storeMock.On("Choices", "missing").Return([]Choice(nil), ErrRulesetNotFound)
+
+	rendered, err := service.RenderRules("", DOTFormat)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(rendered), `digraph "rpsls" {`))
+	require.Equal(t, len(baseRules), strings.Count(string(rendered), " -> "))
+
+	_, err = service.RenderRules("missing", SVGFormat)
+	require.Equal(t, ErrRulesetNotFound, err)
+}
+
+func TestNewChoiceID(t *testing.T) {
+	testCases := []struct {
+		ruleset    string
+		name       string
+		expectedID string
+	}{
+		{ruleset: "rpsls", name: "Rock", expectedID: "rpsls-rock"},
+		{ruleset: "rps-101", name: " Big  Bad_Wolf! ", expectedID: "rps-101-big-bad-wolf"},
+		{ruleset: "emoji", name: "🪨", expectedID: "emoji-1ba561b0"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expectedID, NewChoiceID(tc.ruleset, tc.name), tc.name)
+	}
+}
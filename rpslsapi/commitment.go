@@ -0,0 +1,72 @@
+package rpslsapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var ErrCommitmentNotFound = errors.New("round commitment not found, expired or already used")
+
+// RoundCommitment binds the server to the computer choice of a round before the player picks theirs. Commitment is
+// the hex-encoded SHA-256 of the salt and the ID of the computer choice joined by a colon, both being revealed once
+// the round is played.
+type RoundCommitment struct {
+	Token      string    `json:"token"`
+	Ruleset    string    `json:"ruleset"`
+	Commitment string    `json:"commitment"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// CommitmentReveal discloses what a commitment was computed from, so clients can check it against the computer choice
+type CommitmentReveal struct {
+	Commitment string `json:"commitment"`
+	Salt       string `json:"salt"`
+	Computer   string `json:"computer"`
+}
+
+// CommittedRound is a commitment as stored until the round is played, along with the secrets it was computed from
+type CommittedRound struct {
+	RoundCommitment
+	Salt     string `json:"salt"`
+	Computer string `json:"computer"`
+	// UserID is the ID of the user who requested the commitment, the only one who may play it, empty for anonymous
+	// players
+	UserID string `json:"user_id,omitempty"`
+	// Move is how the computer choice was picked, recorded in the audit of the round once played
+	Move *ComputerMove `json:"move,omitempty"`
+}
+
+type CommitmentStore interface {
+	// SaveCommitment stores the committed round under its token, which expires once the TTL has passed
+	SaveCommitment(round *CommittedRound, ttl time.Duration) error
+	// TakeCommitment returns and deletes the committed round with the token, or returns ErrCommitmentNotFound
+	TakeCommitment(token string) (*CommittedRound, error)
+}
+
+// commit commits to the computer choice of a round picked in advance for the user
+func commit(move *ComputerMove, ruleset, userID string, ttl time.Duration, now time.Time) *CommittedRound {
+	salt := newToken(16)
+	return &CommittedRound{
+		RoundCommitment: RoundCommitment{
+			Token:      newToken(16),
+			Ruleset:    ruleset,
+			Commitment: commitmentHash(salt, move.Choice.ID),
+			ExpiresAt:  now.Add(ttl),
+		},
+		Salt:     salt,
+		Computer: move.Choice.ID,
+		UserID:   userID,
+		Move:     move,
+	}
+}
+
+func commitmentHash(salt, choiceID string) string {
+	hash := sha256.Sum256([]byte(salt + ":" + choiceID))
+	return hex.EncodeToString(hash[:])
+}
+
+func (cr *CommittedRound) reveal() *CommitmentReveal {
+	return &CommitmentReveal{Commitment: cr.Commitment, Salt: cr.Salt, Computer: cr.Computer}
+}
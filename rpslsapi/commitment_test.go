@@ -0,0 +1,140 @@
+package rpslsapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type CommitmentStoreMock struct {
+	mock.Mock
+}
+
+func (csm *CommitmentStoreMock) SaveCommitment(round *CommittedRound, ttl time.Duration) error {
+	args := csm.Called(round, ttl)
+	return args.Error(0)
+}
+
+func (csm *CommitmentStoreMock) TakeCommitment(token string) (*CommittedRound, error) {
+	args := csm.Called(token)
+	return args.Get(0).(*CommittedRound), args.Error(1)
+}
+
+func TestRoundService_Commit(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	storeMock := CommitmentStoreMock{}
+	strategyMock := StrategyServiceMock{}
+	service := RoundServiceImpl{strategyService: &strategyMock, commitmentStore: &storeMock,
+		commitmentTTL: time.Minute, now: func() time.Time { return now }}
+	move := &ComputerMove{Choice: &baseChoices[3], Strategy: "markov", Choices: baseChoices, RandomValues: []int{}}
+	strategyMock.On("ComputerChoice", "user", "rpsls", "markov").Return(move, nil)
+	storeMock.On("SaveCommitment", mock.Anything, time.Minute).Return(nil)
+
+	commitment, err := service.Commit("user", "rpsls", "markov")
+
+	require.NoError(t, err)
+	require.Equal(t, "rpsls", commitment.Ruleset)
+	require.Equal(t, now.Add(time.Minute), commitment.ExpiresAt)
+	saved := storeMock.Calls[0].Arguments.Get(0).(*CommittedRound)
+	require.Equal(t, commitment.Token, saved.Token)
+	require.Equal(t, baseChoices[3].ID, saved.Computer)
+	require.Equal(t, "user", saved.UserID)
+	require.Equal(t, move, saved.Move)
+	require.Len(t, saved.Salt, 32)
+	require.Equal(t, commitmentHash(saved.Salt, saved.Computer), commitment.Commitment)
+}
+
+func TestRoundService_PlayCommitted(t *testing.T) {
+	rock, scissors := &baseChoices[0], &baseChoices[2]
+	committed := &CommittedRound{
+		RoundCommitment: RoundCommitment{Token: "token", Ruleset: "rpsls",
+			Commitment: commitmentHash("salt", scissors.ID)},
+		Salt:     "salt",
+		Computer: scissors.ID,
+		UserID:   "user",
+	}
+
+	testCases := []struct {
+		name            string
+		playerChoice    *Choice
+		userID          string
+		choiceError     error
+		commitmentError error
+		expectedError   error
+	}{
+		{
+			name:         "success: play against the committed choice and reveal the salt",
+			playerChoice: rock,
+			userID:       "user",
+		},
+		{
+			name:          "failure: if the commitment belongs to another user, return ErrCommitmentNotFound",
+			playerChoice:  rock,
+			userID:        "other",
+			expectedError: ErrCommitmentNotFound,
+		},
+		{
+			name:          "failure: if the commitment isn't played by a user, return ErrCommitmentNotFound",
+			playerChoice:  rock,
+			expectedError: ErrCommitmentNotFound,
+		},
+		{
+			name:            "failure: if the commitment is unknown, expired or used, return ErrCommitmentNotFound",
+			playerChoice:    rock,
+			userID:          "user",
+			commitmentError: ErrCommitmentNotFound,
+			expectedError:   ErrCommitmentNotFound,
+		},
+		{
+			name:          "failure: if the choice doesn't exist, use up the commitment and return ErrChoiceNotFound",
+			playerChoice:  &Choice{ID: "rpsls-dynamite"},
+			userID:        "user",
+			choiceError:   ErrChoiceNotFound,
+			expectedError: ErrChoiceNotFound,
+		},
+		{
+			name:          "failure: if the choice belongs to another ruleset, return ErrChoiceNotFound",
+			playerChoice:  &Choice{ID: "rps-rock", Name: "Rock", Ruleset: "rps"},
+			userID:        "user",
+			expectedError: ErrChoiceNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		roundStoreMock := RoundStoreMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		scoreboardMock := ScoreboardServiceMock{}
+		storeMock := CommitmentStoreMock{}
+		strategyMock := StrategyServiceMock{}
+		historyMock := HistoryServiceMock{}
+		auditMock := AuditServiceMock{}
+		service := NewRoundService(&roundStoreMock, &choiceServiceMock, &scoreboardMock, &storeMock, &strategyMock,
+			&historyMock, &auditMock)
+		choiceServiceMock.On("Choice", tc.playerChoice.ID).Return(tc.playerChoice, tc.choiceError)
+		choiceServiceMock.On("Choice", scissors.ID).Return(scissors, nil)
+		storeMock.On("TakeCommitment", "token").Return(committed, tc.commitmentError)
+		roundStoreMock.On("SimulateRound", rock.ID, scissors.ID).
+			Return(&Round{WinnerID: rock.ID, LoserID: scissors.ID, Action: "crushes"}, nil)
+		scoreboardMock.On("Append", mock.Anything, mock.Anything).Return(nil)
+		historyMock.On("Record", mock.Anything, mock.Anything, "").Return(nil)
+		auditMock.On("Record", mock.Anything).Return(nil)
+
+		results, err := service.Play(&RoundSettings{Player: tc.playerChoice.ID, Commitment: "token",
+			UserID: tc.userID})
+
+		strategyMock.AssertNotCalled(t, "ComputerChoice", mock.Anything, mock.Anything, mock.Anything)
+		storeMock.AssertCalled(t, "TakeCommitment", "token")
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, string(Win), results.Results, tc.name)
+		require.Equal(t, scissors.ID, results.Computer, tc.name)
+		require.Equal(t, &CommitmentReveal{Commitment: committed.Commitment, Salt: "salt", Computer: scissors.ID},
+			results.Reveal, tc.name)
+		require.Equal(t, results.Reveal.Commitment, commitmentHash(results.Reveal.Salt, results.Computer), tc.name)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,8 +17,18 @@ type config struct {
 	Redis              RedisConfig
 	RandomNumberServer string
 	ScoreboardSize     int
+	DefaultRuleset     string
+	OutcomeCacheTTL    time.Duration
+	MatchTimeout       time.Duration // MatchTimeout is how long players have to join a match and to move
+	CommitmentTTL      time.Duration // CommitmentTTL is how long round commitments can be played against
+	BotTimeout         time.Duration // BotTimeout is how long bots have to choose before the computer plays at random
+	BotPrivateHosts    bool          // BotPrivateHosts allows bots on loopback, private and link-local hosts
+	MarkovOrder        int           // MarkovOrder is how many of the last moves of the player the Markov strategy uses
+	DailySeed          string        // DailySeed is the secret seeding the computer choices of the daily challenges
+	JWTSecret          string        // JWTSecret signs the tokens authenticating users
+	TokenTTL           time.Duration // TokenTTL is how long the tokens issued when users log in are valid
+	DevMode            bool          // DevMode lets the secrets be left out, random ones being generated on startup
 	Environment        string
-	DummyUserID        string // dummyUserID is a fixed userId to be used in single-player mode
 }
 
 type ServerConfig struct {
@@ -25,6 +36,7 @@ type ServerConfig struct {
 }
 
 type DatabaseConfig struct {
+	Driver   string
 	Uri      string
 	Username string
 	Password string
@@ -33,6 +45,7 @@ type DatabaseConfig struct {
 }
 
 type RedisConfig struct {
+	Driver   string
 	Addr     string
 	Password string
 	DB       int
@@ -50,6 +63,7 @@ func LoadConfig() {
 			Addr: os.Getenv("SERVER_ADDR"),
 		},
 		DB: DatabaseConfig{
+			Driver:   os.Getenv("DB_DRIVER"),
 			Uri:      os.Getenv("DB_URI"),
 			Username: os.Getenv("DB_USERNAME"),
 			Password: os.Getenv("DB_PASSWORD"),
@@ -57,14 +71,25 @@ func LoadConfig() {
 			Realm:    os.Getenv("DB_REALM"),
 		},
 		Redis: RedisConfig{
+			Driver:   os.Getenv("CACHE_DRIVER"),
 			Addr:     os.Getenv("REDIS_ADDR"),
 			Password: os.Getenv("REDIS_PASSWORD"),
 			DB:       intConfig("REDIS_DB"),
 		},
 		RandomNumberServer: os.Getenv("RANDOM_NUMBER_SERVER"),
 		ScoreboardSize:     intConfig("RPSLS_SCOREBOARD_SIZE"),
+		DefaultRuleset:     os.Getenv("RPSLS_DEFAULT_RULESET"),
+		OutcomeCacheTTL:    durationConfig("RPSLS_OUTCOME_CACHE_TTL"),
+		MatchTimeout:       durationConfig("RPSLS_MATCH_TIMEOUT"),
+		CommitmentTTL:      durationConfig("RPSLS_COMMITMENT_TTL"),
+		BotTimeout:         durationConfig("RPSLS_BOT_TIMEOUT"),
+		BotPrivateHosts:    boolConfig("RPSLS_BOT_PRIVATE_HOSTS"),
+		MarkovOrder:        intConfig("RPSLS_MARKOV_ORDER"),
+		DailySeed:          os.Getenv("RPSLS_DAILY_SEED"),
+		JWTSecret:          os.Getenv("RPSLS_JWT_SECRET"),
+		TokenTTL:           durationConfig("RPSLS_TOKEN_TTL"),
+		DevMode:            boolConfig("RPSLS_DEV_MODE"),
 		Environment:        env,
-		DummyUserID:        "a4868d93-2d71-4ce4-b48c-c70e6a043851",
 	}
 }
 
@@ -76,6 +101,23 @@ func intConfig(key string) int {
 	return value
 }
 
+func boolConfig(key string) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		panic(fmt.Errorf("env var %s must be a bool", key))
+	}
+	return value
+}
+
+// listConfig splits a comma-separated list, ignoring blank items
+func durationConfig(key string) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		panic(fmt.Errorf("env var %s must be a duration", key))
+	}
+	return value
+}
+
 func loadFiles(env string) {
 	_ = godotenv.Load(".env." + env + ".local")
 	if "test" != env {
@@ -0,0 +1,267 @@
+package rpslsapi
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrDailyAttemptNotFound = errors.New("daily challenge not played yet")
+var ErrAlreadyPlayedDaily = errors.New("daily challenge already played today")
+var ErrInvalidDailyPlay = errors.New("daily challenge plays need a player name of up to 50 characters and one move " +
+	"per round")
+var ErrInvalidDailyDate = errors.New("daily challenges are identified by a date formatted as 2006-01-02, which " +
+	"can't be in the future")
+
+// dailyDateLayout formats the date identifying a daily challenge, which starts at midnight UTC
+const dailyDateLayout = "2006-01-02"
+
+// dailyChallengeRounds is the number of moves of a daily challenge
+const dailyChallengeRounds = 10
+
+// dailyWinPoints and dailyTiePoints are scored by won and tied rounds of a daily challenge
+const (
+	dailyWinPoints = 2
+	dailyTiePoints = 1
+)
+
+// dailyLeaderboardSize is how many of the best scores of the day make it to its leaderboard
+const dailyLeaderboardSize = 10
+
+// dailyRetention is how long attempts and leaderboards of daily challenges are kept
+const dailyRetention = 30 * 24 * time.Hour
+
+// dailyStrategy is the strategy the audits of daily challenge rounds record, their computer choices being drawn from
+// the sequence of the day rather than by a strategy
+const dailyStrategy = "daily"
+
+// DailyChallenge is the challenge of a day, every player facing the same computer choices in the default ruleset
+type DailyChallenge struct {
+	Date    string `json:"date"`
+	Ruleset string `json:"ruleset"`
+	Rounds  int    `json:"rounds"`
+	// Attempt is the attempt of the user, once played
+	Attempt     *DailyAttempt `json:"attempt,omitempty"`
+	Leaderboard []DailyScore  `json:"leaderboard"`
+}
+
+// DailyPlay is the single attempt of a user at the challenge of the day, holding their moves for every round
+type DailyPlay struct {
+	// Player is the name the score is submitted to the leaderboard with
+	Player string   `json:"player"`
+	Moves  []string `json:"moves"`
+	// RequestID is the ID of the HTTP request the challenge is played in
+	RequestID string `json:"-"`
+}
+
+type DailyAttempt struct {
+	Date     string         `json:"date"`
+	UserID   string         `json:"-"`
+	Player   string         `json:"player"`
+	PlayedAt time.Time      `json:"played_at"`
+	Score    int            `json:"score"`
+	Wins     int            `json:"wins"`
+	Ties     int            `json:"ties"`
+	Losses   int            `json:"losses"`
+	Rounds   []RoundResults `json:"rounds"`
+	// Rank is the rank of the score in the leaderboard of the day, if it made it there
+	Rank int `json:"rank,omitempty"`
+}
+
+// DailyScore is the score of an attempt in the leaderboard of its day
+type DailyScore struct {
+	Player   string    `json:"player"`
+	Score    int       `json:"score"`
+	Wins     int       `json:"wins"`
+	Ties     int       `json:"ties"`
+	Losses   int       `json:"losses"`
+	PlayedAt time.Time `json:"played_at"`
+}
+
+type DailyService interface {
+	// Challenge returns the challenge of the date, today if empty, along with the attempt of the user if any
+	Challenge(userID, date string) (*DailyChallenge, error)
+	// Play plays the moves against the computer choices of the day, once per user and day, auditing every round once
+	// the attempt is saved
+	Play(userID string, play *DailyPlay) (*DailyChallenge, error)
+}
+
+type DailyStore interface {
+	// SaveAttempt stores the attempt and adds its score to the leaderboard of its day through InsertDailyScore,
+	// setting its rank, unless the user already played that day in which case it returns ErrAlreadyPlayedDaily. Both
+	// are dropped once the retention has passed.
+	SaveAttempt(attempt *DailyAttempt, size int, retention time.Duration) error
+	// Attempt returns the attempt of the user at the challenge of the date or ErrDailyAttemptNotFound
+	Attempt(date, userID string) (*DailyAttempt, error)
+	// Leaderboard returns the leaderboard of the date as kept by InsertDailyScore
+	Leaderboard(date string, size int) ([]DailyScore, error)
+}
+
+type DailyServiceImpl struct {
+	dailyStore    DailyStore
+	roundStore    RoundStore
+	choiceService ChoiceService
+	auditService  AuditService
+	seed          string
+	now           func() time.Time
+}
+
+// NewDailyService seeds the challenges with RPSLS_DAILY_SEED, panicking without one unless in dev mode, where a random
+// seed is used so challenges change whenever the process restarts
+func NewDailyService(dailyStore DailyStore, roundStore RoundStore, choiceService ChoiceService,
+	auditService AuditService) DailyService {
+	seed := Config.DailySeed
+	if seed == "" {
+		if !Config.DevMode {
+			panic(errors.New("env var RPSLS_DAILY_SEED must be set outside of dev mode"))
+		}
+		log.Warn().Msg("RPSLS_DAILY_SEED is not set, daily challenges will change once the server restarts")
+		seed = newToken(32)
+	}
+	return DailyServiceImpl{
+		dailyStore:    dailyStore,
+		roundStore:    roundStore,
+		choiceService: choiceService,
+		auditService:  auditService,
+		seed:          seed,
+		now:           time.Now,
+	}
+}
+
+func (ds DailyServiceImpl) Challenge(userID, date string) (*DailyChallenge, error) {
+	today := ds.now().UTC().Format(dailyDateLayout)
+	if date == "" {
+		date = today
+	}
+	// dates formatted alike sort chronologically
+	if _, err := time.Parse(dailyDateLayout, date); err != nil || date > today {
+		return nil, ErrInvalidDailyDate
+	}
+
+	attempt, err := ds.dailyStore.Attempt(date, userID)
+	if err != nil && err != ErrDailyAttemptNotFound {
+		return nil, err
+	}
+	return ds.challenge(date, attempt)
+}
+
+func (ds DailyServiceImpl) Play(userID string, play *DailyPlay) (*DailyChallenge, error) {
+	if play.Player == "" || len(play.Player) > maxPlayerNameLength || len(play.Moves) != dailyChallengeRounds {
+		return nil, ErrInvalidDailyPlay
+	}
+	now := ds.now().UTC()
+	date := now.Format(dailyDateLayout)
+	if _, err := ds.dailyStore.Attempt(date, userID); err != ErrDailyAttemptNotFound {
+		if err == nil {
+			err = ErrAlreadyPlayedDaily
+		}
+		return nil, err
+	}
+
+	ruleset := RulesetOrDefault("")
+	choices, err := ds.choiceService.Choices(ruleset)
+	if err != nil {
+		return nil, err
+	}
+	if len(choices) == 0 {
+		return nil, ErrChoiceNotFound
+	}
+	randomizer := NewDailyRandomizer(now, ds.seed)
+	attempt := &DailyAttempt{Date: date, UserID: userID, Player: play.Player, PlayedAt: now}
+	var audits []*RoundAudit
+	for _, move := range play.Moves {
+		playerChoice, err := ds.choiceService.Choice(move)
+		if err != nil {
+			return nil, err
+		}
+		if playerChoice.Ruleset != ruleset {
+			return nil, ErrChoiceNotFound
+		}
+		// the index drawn is recorded as the random value, which picks the same choice when the audit is replayed
+		index, err := randomizer.RandomIndex(len(choices))
+		if err != nil {
+			return nil, err
+		}
+		round, err := decideRound(ds.roundStore, playerChoice, &choices[index])
+		if err != nil {
+			return nil, err
+		}
+		round.ID = newToken(8)
+		attempt.record(round)
+		audits = append(audits, newRoundAudit(play.RequestID, userID, now, round, &ComputerMove{
+			Choice: &choices[index], Strategy: dailyStrategy, Choices: choices, RandomValues: []int{index}}))
+	}
+
+	// the rounds are only audited once the attempt is saved, so that a second attempt of the day leaves no trace
+	if err := ds.dailyStore.SaveAttempt(attempt, dailyLeaderboardSize, dailyRetention); err != nil {
+		return nil, err
+	}
+	for _, audit := range audits {
+		if err := ds.auditService.Record(audit); err != nil {
+			return nil, err
+		}
+	}
+	return ds.challenge(date, attempt)
+}
+
+func (ds DailyServiceImpl) challenge(date string, attempt *DailyAttempt) (*DailyChallenge, error) {
+	leaderboard, err := ds.dailyStore.Leaderboard(date, dailyLeaderboardSize)
+	if err != nil {
+		return nil, err
+	}
+	return &DailyChallenge{
+		Date:        date,
+		Ruleset:     RulesetOrDefault(""),
+		Rounds:      dailyChallengeRounds,
+		Attempt:     attempt,
+		Leaderboard: leaderboard,
+	}, nil
+}
+
+// record scores the round of the attempt
+func (da *DailyAttempt) record(round *RoundResults) {
+	da.Rounds = append(da.Rounds, *round)
+	switch ResultsLabel(round.Results) {
+	case Win:
+		da.Wins++
+		da.Score += dailyWinPoints
+	case Tie:
+		da.Ties++
+		da.Score += dailyTiePoints
+	case Lose:
+		da.Losses++
+	}
+}
+
+// score returns the entry of the attempt in the leaderboard of its day
+func (da *DailyAttempt) score() *DailyScore {
+	return &DailyScore{
+		Player:   da.Player,
+		Score:    da.Score,
+		Wins:     da.Wins,
+		Ties:     da.Ties,
+		Losses:   da.Losses,
+		PlayedAt: da.PlayedAt,
+	}
+}
+
+// InsertDailyScore adds the score of the attempt to the leaderboard, sorted best first with ties in submission order,
+// and keeps the best size ones. It returns the resulting leaderboard and sets the rank of the attempt, leaving it 0
+// if it didn't make it.
+func InsertDailyScore(leaderboard []DailyScore, attempt *DailyAttempt, size int) []DailyScore {
+	position := sort.Search(len(leaderboard), func(i int) bool { return leaderboard[i].Score < attempt.Score })
+	if position >= size {
+		return leaderboard
+	}
+	inserted := make([]DailyScore, 0, len(leaderboard)+1)
+	inserted = append(inserted, leaderboard[:position]...)
+	inserted = append(inserted, *attempt.score())
+	inserted = append(inserted, leaderboard[position:]...)
+	if len(inserted) > size {
+		inserted = inserted[:size]
+	}
+	attempt.Rank = position + 1
+	return inserted
+}
@@ -0,0 +1,290 @@
+package rpslsapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type DailyStoreMock struct {
+	mock.Mock
+}
+
+func (dsm *DailyStoreMock) SaveAttempt(attempt *DailyAttempt, size int, retention time.Duration) error {
+	args := dsm.Called(attempt, size, retention)
+	return args.Error(0)
+}
+
+func (dsm *DailyStoreMock) Attempt(date, userID string) (*DailyAttempt, error) {
+	args := dsm.Called(date, userID)
+	return args.Get(0).(*DailyAttempt), args.Error(1)
+}
+
+func (dsm *DailyStoreMock) Leaderboard(date string, size int) ([]DailyScore, error) {
+	args := dsm.Called(date, size)
+	return args.Get(0).([]DailyScore), args.Error(1)
+}
+
+func TestDailyRandomizer(t *testing.T) {
+	day := time.Date(2026, 10, 18, 7, 0, 0, 0, time.UTC)
+	draw := func(randomizer RandomizerService) []int {
+		values := make([]int, dailyChallengeRounds)
+		for i := range values {
+			values[i], _ = randomizer.RandomInt()
+			require.True(t, values[i] >= 1 && values[i] <= 100)
+		}
+		return values
+	}
+
+	sequence := draw(NewDailyRandomizer(day, "secret"))
+	require.Equal(t, sequence, draw(NewDailyRandomizer(day.Add(16*time.Hour), "secret")),
+		"the sequence is the same all day long")
+	require.NotEqual(t, sequence, draw(NewDailyRandomizer(day.AddDate(0, 0, 1), "secret")),
+		"the sequence changes every day")
+	require.NotEqual(t, sequence, draw(NewDailyRandomizer(day, "other")), "the sequence depends on the secret")
+}
+
+func TestNewDailyService(t *testing.T) {
+	testCases := []struct {
+		name          string
+		seed          string
+		devMode       bool
+		expectedPanic bool
+	}{
+		{
+			name: "success: seed challenges with the configured seed",
+			seed: "seed",
+		},
+		{
+			name:    "success: seed challenges with a random seed in dev mode",
+			devMode: true,
+		},
+		{
+			name:          "failure: if the seed is missing outside of dev mode, panic",
+			expectedPanic: true,
+		},
+	}
+
+	defer func() { Config.DailySeed, Config.DevMode = "", false }()
+	for _, tc := range testCases {
+		Config.DailySeed, Config.DevMode = tc.seed, tc.devMode
+
+		if tc.expectedPanic {
+			require.Panics(t, func() {
+				NewDailyService(&DailyStoreMock{}, &RoundStoreMock{}, &ChoiceServiceMock{}, &AuditServiceMock{})
+			}, tc.name)
+			continue
+		}
+		service := NewDailyService(&DailyStoreMock{}, &RoundStoreMock{}, &ChoiceServiceMock{},
+			&AuditServiceMock{}).(DailyServiceImpl)
+		require.NotEmpty(t, service.seed, tc.name)
+		if tc.seed != "" {
+			require.Equal(t, tc.seed, service.seed, tc.name)
+		}
+	}
+}
+
+func TestDailyService_Challenge(t *testing.T) {
+	Config.DefaultRuleset = "rpsls"
+	attempt := &DailyAttempt{Date: "2026-10-17", Player: "ada", Score: 12, Rank: 1}
+
+	testCases := []struct {
+		name            string
+		date            string
+		expectedDate    string
+		expectedAttempt *DailyAttempt
+		expectedError   error
+	}{
+		{
+			name:         "success: return the challenge of today",
+			expectedDate: "2026-10-18",
+		},
+		{
+			name:            "success: return a past challenge with the attempt of the user",
+			date:            "2026-10-17",
+			expectedDate:    "2026-10-17",
+			expectedAttempt: attempt,
+		},
+		{
+			name:          "failure: if the date is in the future, return ErrInvalidDailyDate",
+			date:          "2026-10-19",
+			expectedError: ErrInvalidDailyDate,
+		},
+		{
+			name:          "failure: if the date is malformed, return ErrInvalidDailyDate",
+			date:          "18/10/2026",
+			expectedError: ErrInvalidDailyDate,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := DailyStoreMock{}
+		now := time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)
+		service := DailyServiceImpl{dailyStore: &storeMock, roundStore: &RoundStoreMock{},
+			choiceService: &ChoiceServiceMock{}, now: func() time.Time { return now }}
+		storeMock.On("Attempt", "2026-10-18", "user").Return((*DailyAttempt)(nil), ErrDailyAttemptNotFound)
+		storeMock.On("Attempt", "2026-10-17", "user").Return(attempt, nil)
+		storeMock.On("Leaderboard", mock.Anything, dailyLeaderboardSize).Return([]DailyScore{}, nil)
+
+		challenge, err := service.Challenge("user", tc.date)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedDate, challenge.Date, tc.name)
+		require.Equal(t, "rpsls", challenge.Ruleset, tc.name)
+		require.Equal(t, dailyChallengeRounds, challenge.Rounds, tc.name)
+		require.Equal(t, tc.expectedAttempt, challenge.Attempt, tc.name)
+	}
+}
+
+func TestDailyService_Play(t *testing.T) {
+	Config.DefaultRuleset = "rpsls"
+	moves := make([]string, dailyChallengeRounds)
+	for i := range moves {
+		moves[i] = "rpsls-rock"
+	}
+
+	testCases := []struct {
+		name          string
+		play          DailyPlay
+		attemptError  error
+		saveError     error
+		auditError    error
+		expectedError error
+	}{
+		{
+			name:         "success: play every move against the computer choices of the day and audit the rounds",
+			play:         DailyPlay{Player: "ada", Moves: moves, RequestID: "request"},
+			attemptError: ErrDailyAttemptNotFound,
+		},
+		{
+			name:          "failure: if a move is missing, return ErrInvalidDailyPlay",
+			play:          DailyPlay{Player: "ada", Moves: moves[1:]},
+			expectedError: ErrInvalidDailyPlay,
+		},
+		{
+			name:          "failure: if the player has no name, return ErrInvalidDailyPlay",
+			play:          DailyPlay{Moves: moves},
+			expectedError: ErrInvalidDailyPlay,
+		},
+		{
+			name:          "failure: if the user already played today, return ErrAlreadyPlayedDaily",
+			play:          DailyPlay{Player: "ada", Moves: moves},
+			expectedError: ErrAlreadyPlayedDaily,
+		},
+		{
+			name:          "failure: if the user played meanwhile, return ErrAlreadyPlayedDaily",
+			play:          DailyPlay{Player: "ada", Moves: moves},
+			attemptError:  ErrDailyAttemptNotFound,
+			saveError:     ErrAlreadyPlayedDaily,
+			expectedError: ErrAlreadyPlayedDaily,
+		},
+		{
+			name:          "failure: if a round can't be audited, return the error",
+			play:          DailyPlay{Player: "ada", Moves: moves},
+			attemptError:  ErrDailyAttemptNotFound,
+			auditError:    errors.New("audit store down"),
+			expectedError: errors.New("audit store down"),
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := DailyStoreMock{}
+		roundStoreMock := RoundStoreMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		auditMock := AuditServiceMock{}
+		today := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+		service := DailyServiceImpl{dailyStore: &storeMock, roundStore: &roundStoreMock,
+			choiceService: &choiceServiceMock, auditService: &auditMock, now: func() time.Time { return today }}
+		storeMock.On("Attempt", "2026-10-18", "user").Return(&DailyAttempt{}, tc.attemptError)
+		storeMock.On("SaveAttempt", mock.Anything, dailyLeaderboardSize, dailyRetention).Return(tc.saveError)
+		storeMock.On("Leaderboard", "2026-10-18", dailyLeaderboardSize).Return([]DailyScore{}, nil)
+		choiceServiceMock.On("Choices", "rpsls").Return(baseChoices, nil)
+		choiceServiceMock.On("Choice", "rpsls-rock").Return(&baseChoices[0], nil)
+		roundStoreMock.On("SimulateRound", "rpsls-rock", mock.Anything).
+			Return(&Round{WinnerID: "rpsls-rock", Action: "crushes"}, nil)
+		auditMock.On("Record", mock.Anything).Return(tc.auditError)
+
+		challenge, err := service.Play("user", &tc.play)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			if tc.auditError == nil {
+				auditMock.AssertNotCalled(t, "Record", mock.Anything)
+			}
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		attempt := challenge.Attempt
+		require.Equal(t, "2026-10-18", attempt.Date, tc.name)
+		require.Equal(t, "user", attempt.UserID, tc.name)
+		require.Len(t, attempt.Rounds, dailyChallengeRounds, tc.name)
+		require.Equal(t, dailyChallengeRounds, attempt.Wins+attempt.Ties, tc.name)
+		require.Equal(t, dailyWinPoints*attempt.Wins+dailyTiePoints*attempt.Ties, attempt.Score, tc.name)
+
+		randomizer := NewDailyRandomizer(today, "")
+		auditMock.AssertNumberOfCalls(t, "Record", dailyChallengeRounds)
+		for i, round := range attempt.Rounds {
+			expected, _ := pickRandom(baseChoices, randomizer)
+			require.Equal(t, expected.ID, round.Computer, tc.name)
+			audit := auditMock.Calls[i].Arguments.Get(0).(*RoundAudit)
+			require.Equal(t, round.ID, audit.ID, tc.name)
+			require.Equal(t, "request", audit.RequestID, tc.name)
+			require.Equal(t, dailyStrategy, audit.Strategy, tc.name)
+			replayed, _ := pickRandom(audit.Choices, fixedRandomizer(audit.RandomValues[0]))
+			require.Equal(t, round.Computer, replayed.ID, tc.name)
+		}
+	}
+}
+
+func TestInsertDailyScore(t *testing.T) {
+	leaderboard := []DailyScore{{Player: "first", Score: 18}, {Player: "second", Score: 12},
+		{Player: "third", Score: 12}}
+
+	testCases := []struct {
+		name            string
+		score           int
+		size            int
+		expectedRank    int
+		expectedPlayers []string
+	}{
+		{
+			name:            "a better score is ranked first",
+			score:           20,
+			size:            10,
+			expectedRank:    1,
+			expectedPlayers: []string{"new", "first", "second", "third"},
+		},
+		{
+			name:            "ties are ranked in submission order",
+			score:           12,
+			size:            10,
+			expectedRank:    4,
+			expectedPlayers: []string{"first", "second", "third", "new"},
+		},
+		{
+			name:            "a score below a full leaderboard doesn't make it",
+			score:           4,
+			size:            3,
+			expectedPlayers: []string{"first", "second", "third"},
+		},
+	}
+
+	for _, tc := range testCases {
+		attempt := &DailyAttempt{Player: "new", Score: tc.score}
+		inserted := InsertDailyScore(leaderboard, attempt, tc.size)
+
+		require.Equal(t, tc.expectedRank, attempt.Rank, tc.name)
+		players := make([]string, len(inserted))
+		for i := range inserted {
+			players[i] = inserted[i].Player
+		}
+		require.Equal(t, tc.expectedPlayers, players, tc.name)
+	}
+}
@@ -0,0 +1,234 @@
+package rpslsapi
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidFreeForAll = errors.New("free-for-all rounds need between 3 and 32 players with distinct names and " +
+	"a scoring of points or elimination")
+
+// minFreeForAllPlayers and maxFreeForAllPlayers bound the number of players throwing in a free-for-all round
+const (
+	minFreeForAllPlayers = 3
+	maxFreeForAllPlayers = 32
+)
+
+type FreeForAllScoring string
+
+const (
+	// PointsScoring ranks players by the points they score against each opponent
+	PointsScoring FreeForAllScoring = "points"
+	// EliminationScoring eliminates every player whose choice is beaten by another one, the last ones standing
+	// winning the round. If every player would be eliminated, nobody is and the round is a stalemate.
+	EliminationScoring FreeForAllScoring = "elimination"
+)
+
+// PointsTable holds the points scored against each opponent, depending on the outcome against them
+type PointsTable struct {
+	Win  int `json:"win"`
+	Tie  int `json:"tie"`
+	Loss int `json:"loss"`
+}
+
+// defaultPoints scores a point per defeated opponent
+var defaultPoints = PointsTable{Win: 1}
+
+type FreeForAllPlayer struct {
+	Name   string `json:"name"`
+	Choice string `json:"choice"`
+}
+
+type FreeForAllSettings struct {
+	Ruleset string             `json:"ruleset"`
+	Players []FreeForAllPlayer `json:"players"`
+	// Scoring is PointsScoring if empty
+	Scoring FreeForAllScoring `json:"scoring"`
+	// Points are the points of PointsScoring, one per defeated opponent if nil
+	Points *PointsTable `json:"points,omitempty"`
+}
+
+// FreeForAllResults is the outcome of a round where every player faced every other one with the same throw
+type FreeForAllResults struct {
+	Ruleset string            `json:"ruleset"`
+	Scoring FreeForAllScoring `json:"scoring"`
+	Points  *PointsTable      `json:"points,omitempty"`
+	// Standings holds a row per player, sorted by rank
+	Standings []FreeForAllStanding `json:"standings"`
+	Winners   []string             `json:"winners"`
+	Stalemate bool                 `json:"stalemate,omitempty"`
+}
+
+type FreeForAllStanding struct {
+	Rank       int     `json:"rank"`
+	Player     string  `json:"player"`
+	Choice     *Choice `json:"choice"`
+	Wins       int     `json:"wins"`
+	Ties       int     `json:"ties"`
+	Losses     int     `json:"losses"`
+	Points     int     `json:"points"`
+	Eliminated bool    `json:"eliminated,omitempty"`
+	// Results holds the outcome against every opponent, in the order players were given
+	Results []FreeForAllOutcome `json:"results"`
+}
+
+// FreeForAllOutcome is the outcome of the throw of a player against the one of an opponent
+type FreeForAllOutcome struct {
+	Opponent    string `json:"opponent"`
+	Results     string `json:"results"`
+	Action      string `json:"action,omitempty"`
+	Description string `json:"description"`
+}
+
+type FreeForAllService interface {
+	Play(settings *FreeForAllSettings) (*FreeForAllResults, error)
+}
+
+type FreeForAllServiceImpl struct {
+	roundStore    RoundStore
+	choiceService ChoiceService
+}
+
+func NewFreeForAllService(roundStore RoundStore, choiceService ChoiceService) FreeForAllService {
+	return FreeForAllServiceImpl{roundStore: roundStore, choiceService: choiceService}
+}
+
+func (fs FreeForAllServiceImpl) Play(settings *FreeForAllSettings) (*FreeForAllResults, error) {
+	if err := validateFreeForAll(settings); err != nil {
+		return nil, err
+	}
+	ruleset := RulesetOrDefault(settings.Ruleset)
+
+	standings := make([]FreeForAllStanding, len(settings.Players))
+	for i, player := range settings.Players {
+		choice, err := fs.choiceService.Choice(player.Choice)
+		if err != nil {
+			return nil, err
+		}
+		if choice.Ruleset != ruleset {
+			return nil, ErrChoiceNotFound
+		}
+		standings[i] = FreeForAllStanding{Player: player.Name, Choice: choice, Results: []FreeForAllOutcome{}}
+	}
+
+	// players throwing the same choices get the same outcome, so each pair of choices is decided once
+	decided := map[[2]string]*RoundResults{}
+	for i := range standings {
+		for j := range standings {
+			if i == j {
+				continue
+			}
+			key := [2]string{standings[i].Choice.ID, standings[j].Choice.ID}
+			round, found := decided[key]
+			if !found {
+				var err error
+				round, err = decideRound(fs.roundStore, standings[i].Choice, standings[j].Choice)
+				if err != nil {
+					return nil, err
+				}
+				decided[key] = round
+			}
+			standings[i].record(standings[j].Player, round)
+		}
+	}
+
+	results := &FreeForAllResults{Ruleset: ruleset, Scoring: settings.Scoring, Standings: standings}
+	if results.Scoring == "" {
+		results.Scoring = PointsScoring
+	}
+	if results.Scoring == PointsScoring {
+		points := defaultPoints
+		if settings.Points != nil {
+			points = *settings.Points
+		}
+		results.Points = &points
+	}
+	results.rank()
+	return results, nil
+}
+
+func validateFreeForAll(settings *FreeForAllSettings) error {
+	if len(settings.Players) < minFreeForAllPlayers || len(settings.Players) > maxFreeForAllPlayers {
+		return ErrInvalidFreeForAll
+	}
+	switch settings.Scoring {
+	case "", PointsScoring, EliminationScoring:
+	default:
+		return ErrInvalidFreeForAll
+	}
+
+	for i, player := range settings.Players {
+		if player.Name == "" || len(player.Name) > maxPlayerNameLength {
+			return ErrInvalidFreeForAll
+		}
+		for _, other := range settings.Players[:i] {
+			if strings.EqualFold(player.Name, other.Name) {
+				return ErrInvalidFreeForAll
+			}
+		}
+	}
+	return nil
+}
+
+func (fs *FreeForAllStanding) record(opponent string, round *RoundResults) {
+	switch ResultsLabel(round.Results) {
+	case Win:
+		fs.Wins++
+	case Lose:
+		fs.Losses++
+	default:
+		fs.Ties++
+	}
+	fs.Results = append(fs.Results, FreeForAllOutcome{Opponent: opponent, Results: round.Results,
+		Action: round.Action, Description: round.Description})
+}
+
+// rank scores the players and sorts the standings, players with the same score sharing their rank
+func (fr *FreeForAllResults) rank() {
+	standings := fr.Standings
+	var better func(a, b *FreeForAllStanding) bool
+	if fr.Scoring == EliminationScoring {
+		for i := range standings {
+			standings[i].Eliminated = standings[i].Losses > 0
+		}
+		fr.Stalemate = true
+		for i := range standings {
+			fr.Stalemate = fr.Stalemate && standings[i].Eliminated
+		}
+		if fr.Stalemate {
+			for i := range standings {
+				standings[i].Eliminated = false
+			}
+		}
+		// the eliminated players are ranked by how many opponents they defeated
+		better = func(a, b *FreeForAllStanding) bool {
+			if a.Eliminated != b.Eliminated {
+				return !a.Eliminated
+			}
+			return a.Eliminated && a.Wins > b.Wins
+		}
+	} else {
+		for i := range standings {
+			standings[i].Points = standings[i].Wins*fr.Points.Win + standings[i].Ties*fr.Points.Tie +
+				standings[i].Losses*fr.Points.Loss
+		}
+		better = func(a, b *FreeForAllStanding) bool {
+			return a.Points > b.Points
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		return better(&standings[i], &standings[j])
+	})
+	fr.Winners = []string{}
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && !better(&standings[i-1], &standings[i]) {
+			standings[i].Rank = standings[i-1].Rank
+		}
+		if standings[i].Rank == 1 && !fr.Stalemate {
+			fr.Winners = append(fr.Winners, standings[i].Player)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package rpslsapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockBaseRounds makes the store mock decide rounds between baseChoices, in both orders, with baseRules
+func mockBaseRounds(storeMock *RoundStoreMock) {
+	for _, rule := range baseRules {
+		round := &Round{WinnerID: rule.WinnerID, LoserID: rule.LoserID, Action: rule.Action}
+		storeMock.On("SimulateRound", rule.WinnerID, rule.LoserID).Return(round, nil)
+		storeMock.On("SimulateRound", rule.LoserID, rule.WinnerID).Return(round, nil)
+	}
+}
+
+func freeForAllPlayers(choiceIDs ...string) []FreeForAllPlayer {
+	names := []string{"ann", "bob", "cid", "dee"}
+	players := make([]FreeForAllPlayer, len(choiceIDs))
+	for i, id := range choiceIDs {
+		players[i] = FreeForAllPlayer{Name: names[i], Choice: id}
+	}
+	return players
+}
+
+func TestFreeForAllService_Play(t *testing.T) {
+	testCases := []struct {
+		name              string
+		settings          FreeForAllSettings
+		expectedPlayers   []string
+		expectedRanks     []int
+		expectedPoints    []int
+		expectedWinners   []string
+		expectedStalemate bool
+		expectedError     error
+	}{
+		{
+			name: "success: score a point per defeated opponent",
+			settings: FreeForAllSettings{Ruleset: "rpsls",
+				Players: freeForAllPlayers("rpsls-rock", "rpsls-scissors", "rpsls-lizard", "rpsls-rock")},
+			expectedPlayers: []string{"ann", "dee", "bob", "cid"},
+			expectedRanks:   []int{1, 1, 3, 4},
+			expectedPoints:  []int{2, 2, 1, 0},
+			expectedWinners: []string{"ann", "dee"},
+		},
+		{
+			name: "success: score the configured points",
+			settings: FreeForAllSettings{Ruleset: "rpsls", Points: &PointsTable{Win: 3, Tie: 1},
+				Players: freeForAllPlayers("rpsls-rock", "rpsls-scissors", "rpsls-lizard", "rpsls-rock")},
+			expectedPlayers: []string{"ann", "dee", "bob", "cid"},
+			expectedRanks:   []int{1, 1, 3, 4},
+			expectedPoints:  []int{7, 7, 3, 0},
+			expectedWinners: []string{"ann", "dee"},
+		},
+		{
+			name: "success: eliminate the beaten players, ranking them by defeated opponents",
+			settings: FreeForAllSettings{Ruleset: "rpsls", Scoring: EliminationScoring,
+				Players: freeForAllPlayers("rpsls-lizard", "rpsls-scissors", "rpsls-rock", "rpsls-rock")},
+			expectedPlayers: []string{"cid", "dee", "bob", "ann"},
+			expectedRanks:   []int{1, 1, 3, 4},
+			expectedPoints:  []int{0, 0, 0, 0},
+			expectedWinners: []string{"cid", "dee"},
+		},
+		{
+			name: "success: if every player is beaten, eliminate nobody and call a stalemate",
+			settings: FreeForAllSettings{Ruleset: "rpsls", Scoring: EliminationScoring,
+				Players: freeForAllPlayers("rpsls-rock", "rpsls-paper", "rpsls-scissors")},
+			expectedPlayers:   []string{"ann", "bob", "cid"},
+			expectedRanks:     []int{1, 1, 1},
+			expectedPoints:    []int{0, 0, 0},
+			expectedWinners:   []string{},
+			expectedStalemate: true,
+		},
+		{
+			name:          "failure: if there are less than 3 players, return ErrInvalidFreeForAll",
+			settings:      FreeForAllSettings{Players: freeForAllPlayers("rpsls-rock", "rpsls-paper")},
+			expectedError: ErrInvalidFreeForAll,
+		},
+		{
+			name: "failure: if two players have the same name, return ErrInvalidFreeForAll",
+			settings: FreeForAllSettings{Players: append(freeForAllPlayers("rpsls-rock", "rpsls-paper"),
+				FreeForAllPlayer{Name: "Ann", Choice: "rpsls-spock"})},
+			expectedError: ErrInvalidFreeForAll,
+		},
+		{
+			name: "failure: if the scoring is unknown, return ErrInvalidFreeForAll",
+			settings: FreeForAllSettings{Scoring: "sudden-death",
+				Players: freeForAllPlayers("rpsls-rock", "rpsls-paper", "rpsls-spock")},
+			expectedError: ErrInvalidFreeForAll,
+		},
+		{
+			name: "failure: if a choice belongs to another ruleset, return ErrChoiceNotFound",
+			settings: FreeForAllSettings{Ruleset: "rps",
+				Players: freeForAllPlayers("rpsls-rock", "rpsls-paper", "rpsls-spock")},
+			expectedError: ErrChoiceNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := RoundStoreMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		service := NewFreeForAllService(&storeMock, &choiceServiceMock)
+		mockBaseRounds(&storeMock)
+		for i := range baseChoices {
+			choiceServiceMock.On("Choice", baseChoices[i].ID).Return(&baseChoices[i], nil)
+		}
+
+		results, err := service.Play(&tc.settings)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Len(t, results.Standings, len(tc.expectedPlayers), tc.name)
+		for i, standing := range results.Standings {
+			require.Equal(t, tc.expectedPlayers[i], standing.Player, tc.name)
+			require.Equal(t, tc.expectedRanks[i], standing.Rank, tc.name)
+			require.Equal(t, tc.expectedPoints[i], standing.Points, tc.name)
+			require.Len(t, standing.Results, len(tc.expectedPlayers)-1, tc.name)
+		}
+		require.Equal(t, tc.expectedWinners, results.Winners, tc.name)
+		require.Equal(t, tc.expectedStalemate, results.Stalemate, tc.name)
+	}
+}
+
+func TestFreeForAllService_PlayResultTable(t *testing.T) {
+	storeMock := RoundStoreMock{}
+	choiceServiceMock := ChoiceServiceMock{}
+	service := NewFreeForAllService(&storeMock, &choiceServiceMock)
+	mockBaseRounds(&storeMock)
+	for i := range baseChoices {
+		choiceServiceMock.On("Choice", baseChoices[i].ID).Return(&baseChoices[i], nil)
+	}
+
+	results, err := service.Play(&FreeForAllSettings{Ruleset: "rpsls",
+		Players: freeForAllPlayers("rpsls-spock", "rpsls-scissors", "rpsls-spock")})
+
+	require.NoError(t, err)
+	require.Equal(t, FreeForAllStanding{Rank: 1, Player: "ann", Choice: &baseChoices[4], Wins: 1, Ties: 1,
+		Points: 1, Results: []FreeForAllOutcome{
+			{Opponent: "bob", Results: string(Win), Action: "smashes", Description: "spock smashes scissors"},
+			{Opponent: "cid", Results: string(Tie), Description: "Both played spock"},
+		}}, results.Standings[0])
+	require.Equal(t, FreeForAllStanding{Rank: 3, Player: "bob", Choice: &baseChoices[2], Losses: 2,
+		Results: []FreeForAllOutcome{
+			{Opponent: "ann", Results: string(Lose), Action: "smashes", Description: "spock smashes scissors"},
+			{Opponent: "cid", Results: string(Lose), Action: "smashes", Description: "spock smashes scissors"},
+		}}, results.Standings[2])
+	storeMock.AssertNumberOfCalls(t, "SimulateRound", 2)
+}
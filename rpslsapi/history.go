@@ -0,0 +1,186 @@
+package rpslsapi
+
+import (
+	"encoding/base64"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrInvalidHistoryQuery = errors.New("invalid history query: the limit must be between 1 and 100, from must be " +
+	"before to and the cursor must come from a previous page")
+
+// defaultHistoryLimit and maxHistoryLimit bound the number of rounds in a page of history
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+// HistoryRound is a round played by a user against the computer, as kept in their history
+type HistoryRound struct {
+	ID       string    `json:"id"`
+	UserID   string    `json:"-"`
+	PlayedAt time.Time `json:"played_at"`
+	Ruleset  string    `json:"ruleset"`
+	Player   string    `json:"player"`
+	Computer string    `json:"computer"`
+	Results  string    `json:"results"`
+	Action   string    `json:"action,omitempty"`
+	// Series is the ID of the series the round was played in, if any
+	Series string `json:"series,omitempty"`
+}
+
+// HistoryRequest selects a page of history. From is inclusive and To exclusive, both being ignored if zero.
+type HistoryRequest struct {
+	Ruleset string
+	From    time.Time
+	To      time.Time
+	// Cursor is the NextCursor of the previous page, empty for the first one
+	Cursor string
+	Limit  int
+}
+
+type HistoryPage struct {
+	Rounds []HistoryRound `json:"rounds"`
+	// NextCursor is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// HistoryQuery is a HistoryRequest as run by stores, rounds being sorted by time then ID, most recent first
+type HistoryQuery struct {
+	UserID  string
+	Ruleset string
+	From    time.Time
+	To      time.Time
+	// Before is the last round of the previous page, the rounds sorted after it being returned if set
+	Before *HistoryRound
+	Limit  int
+}
+
+// UnixNanoBounds returns the bounds of the query for stores keeping times as nanoseconds since the epoch: rounds are
+// played from `from` and before `to`, and sorted after the round of the previous page played at `before` with
+// the ID `beforeID`. Unset bounds are the extreme values of int64.
+func (hq *HistoryQuery) UnixNanoBounds() (from, to, before int64, beforeID string) {
+	from, to, before = math.MinInt64, math.MaxInt64, math.MaxInt64
+	if !hq.From.IsZero() {
+		from = hq.From.UnixNano()
+	}
+	if !hq.To.IsZero() {
+		to = hq.To.UnixNano()
+	}
+	if hq.Before != nil {
+		before, beforeID = hq.Before.PlayedAt.UnixNano(), hq.Before.ID
+	}
+	return from, to, before, beforeID
+}
+
+type HistoryService interface {
+	// Record adds a round played by the user to their history, along with the ID of its series if any. Rounds without
+	// an ID are given one.
+	Record(userID string, round *RoundResults, series string) error
+	History(userID string, request *HistoryRequest) (*HistoryPage, error)
+}
+
+// HistoryStore keeps every round for good, unlike the scoreboard which only holds the most recent results
+type HistoryStore interface {
+	SaveRound(round *HistoryRound) error
+	// Rounds returns up to query.Limit rounds matching the query, most recent first
+	Rounds(query *HistoryQuery) ([]HistoryRound, error)
+}
+
+type HistoryServiceImpl struct {
+	historyStore HistoryStore
+	now          func() time.Time
+}
+
+func NewHistoryService(historyStore HistoryStore) HistoryService {
+	return HistoryServiceImpl{historyStore: historyStore, now: time.Now}
+}
+
+func (hs HistoryServiceImpl) Record(userID string, round *RoundResults, series string) error {
+	id := round.ID
+	if id == "" {
+		id = newToken(8)
+	}
+	return hs.historyStore.SaveRound(&HistoryRound{
+		ID:       id,
+		UserID:   userID,
+		PlayedAt: hs.now().UTC(),
+		Ruleset:  round.Ruleset,
+		Player:   round.Player,
+		Computer: round.Computer,
+		Results:  round.Results,
+		Action:   round.Action,
+		Series:   series,
+	})
+}
+
+func (hs HistoryServiceImpl) History(userID string, request *HistoryRequest) (*HistoryPage, error) {
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit < 0 || limit > maxHistoryLimit {
+		return nil, ErrInvalidHistoryQuery
+	}
+	if !request.From.IsZero() && !request.To.IsZero() && !request.From.Before(request.To) {
+		return nil, ErrInvalidHistoryQuery
+	}
+
+	query := &HistoryQuery{UserID: userID, Ruleset: request.Ruleset, From: request.From, To: request.To,
+		Limit: limit + 1}
+	if request.Cursor != "" {
+		before, err := decodeHistoryCursor(request.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.Before = before
+	}
+
+	// one more round than asked tells whether there is a next page
+	rounds, err := hs.historyStore.Rounds(query)
+	if err != nil {
+		return nil, err
+	}
+	if rounds == nil {
+		rounds = []HistoryRound{}
+	}
+	page := &HistoryPage{Rounds: rounds}
+	if len(rounds) > limit {
+		page.Rounds = rounds[:limit]
+		page.NextCursor = encodeHistoryCursor(&rounds[limit-1])
+	}
+	return page, nil
+}
+
+// recordHistory adds a round to the history of the user, only logging failures so the round is still played
+func recordHistory(historyService HistoryService, userID string, round *RoundResults, series string) {
+	if err := historyService.Record(userID, round, series); err != nil {
+		log.Info().Err(err).Msg("failed to save round to history")
+	}
+}
+
+// encodeHistoryCursor returns the position after the round, as an opaque string
+func encodeHistoryCursor(round *HistoryRound) string {
+	position := round.PlayedAt.UTC().Format(time.RFC3339Nano) + " " + round.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(position))
+}
+
+func decodeHistoryCursor(cursor string) (*HistoryRound, error) {
+	position, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidHistoryQuery
+	}
+	parts := strings.SplitN(string(position), " ", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, ErrInvalidHistoryQuery
+	}
+	playedAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrInvalidHistoryQuery
+	}
+	return &HistoryRound{PlayedAt: playedAt, ID: parts[1]}, nil
+}
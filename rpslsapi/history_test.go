@@ -0,0 +1,126 @@
+package rpslsapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type HistoryServiceMock struct {
+	mock.Mock
+}
+
+func (hsm *HistoryServiceMock) Record(userID string, round *RoundResults, series string) error {
+	args := hsm.Called(userID, round, series)
+	return args.Error(0)
+}
+
+func (hsm *HistoryServiceMock) History(userID string, request *HistoryRequest) (*HistoryPage, error) {
+	args := hsm.Called(userID, request)
+	return args.Get(0).(*HistoryPage), args.Error(1)
+}
+
+type HistoryStoreMock struct {
+	mock.Mock
+}
+
+func (hsm *HistoryStoreMock) SaveRound(round *HistoryRound) error {
+	args := hsm.Called(round)
+	return args.Error(0)
+}
+
+func (hsm *HistoryStoreMock) Rounds(query *HistoryQuery) ([]HistoryRound, error) {
+	args := hsm.Called(query)
+	return args.Get(0).([]HistoryRound), args.Error(1)
+}
+
+func TestHistoryService_Record(t *testing.T) {
+	storeMock := HistoryStoreMock{}
+	playedAt := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	service := HistoryServiceImpl{historyStore: &storeMock, now: func() time.Time { return playedAt }}
+	storeMock.On("SaveRound", mock.Anything).Return(nil)
+
+	round := &RoundResults{Results: string(Win), Player: "rpsls-rock", Computer: "rpsls-scissors", Ruleset: "rpsls",
+		Action: "crushes", Description: "Rock crushes Scissors"}
+	require.NoError(t, service.Record("user", round, "series"))
+
+	saved := storeMock.Calls[0].Arguments.Get(0).(*HistoryRound)
+	require.NotEmpty(t, saved.ID)
+	saved.ID = ""
+	require.Equal(t, &HistoryRound{UserID: "user", PlayedAt: playedAt, Ruleset: "rpsls", Player: "rpsls-rock",
+		Computer: "rpsls-scissors", Results: string(Win), Action: "crushes", Series: "series"}, saved)
+}
+
+func TestHistoryService_History(t *testing.T) {
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	rounds := []HistoryRound{
+		{ID: "c", PlayedAt: start.Add(2 * time.Minute), Results: string(Win)},
+		{ID: "b", PlayedAt: start.Add(time.Minute), Results: string(Tie)},
+		{ID: "a", PlayedAt: start, Results: string(Lose)},
+	}
+	cursor := encodeHistoryCursor(&rounds[1])
+	position := &HistoryRound{ID: "b", PlayedAt: rounds[1].PlayedAt}
+
+	testCases := []struct {
+		name          string
+		request       HistoryRequest
+		expectedQuery *HistoryQuery
+		storeRounds   []HistoryRound
+		expectedPage  *HistoryPage
+		expectedError error
+	}{
+		{
+			name:          "success: return the first page, with a cursor if more rounds follow",
+			request:       HistoryRequest{Limit: 2, Ruleset: "rpsls", From: start},
+			expectedQuery: &HistoryQuery{UserID: "user", Ruleset: "rpsls", From: start, Limit: 3},
+			storeRounds:   rounds,
+			expectedPage:  &HistoryPage{Rounds: rounds[:2], NextCursor: cursor},
+		},
+		{
+			name:          "success: return the next page, without a cursor if it is the last one",
+			request:       HistoryRequest{Limit: 2, Cursor: cursor},
+			expectedQuery: &HistoryQuery{UserID: "user", Before: position, Limit: 3},
+			storeRounds:   rounds[2:],
+			expectedPage:  &HistoryPage{Rounds: rounds[2:]},
+		},
+		{
+			name:          "success: return an empty page, 20 rounds being asked by default",
+			request:       HistoryRequest{},
+			expectedQuery: &HistoryQuery{UserID: "user", Limit: 21},
+			expectedPage:  &HistoryPage{Rounds: []HistoryRound{}},
+		},
+		{
+			name:          "failure: if the limit is above 100, return ErrInvalidHistoryQuery",
+			request:       HistoryRequest{Limit: 101},
+			expectedError: ErrInvalidHistoryQuery,
+		},
+		{
+			name:          "failure: if from isn't before to, return ErrInvalidHistoryQuery",
+			request:       HistoryRequest{From: start, To: start},
+			expectedError: ErrInvalidHistoryQuery,
+		},
+		{
+			name:          "failure: if the cursor is malformed, return ErrInvalidHistoryQuery",
+			request:       HistoryRequest{Cursor: "not a cursor"},
+			expectedError: ErrInvalidHistoryQuery,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := HistoryStoreMock{}
+		service := NewHistoryService(&storeMock)
+		storeMock.On("Rounds", tc.expectedQuery).Return(tc.storeRounds, nil)
+
+		page, err := service.History("user", &tc.request)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			storeMock.AssertNotCalled(t, "Rounds", mock.Anything)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedPage, page, tc.name)
+	}
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"rpsls/rpslsapi"
+)
+
+type ArcadeHandler struct {
+	service rpslsapi.ArcadeService
+}
+
+// ArcadeRound is the body of a round played in an arcade session, whose ruleset and strategy are the ones of the
+// session
+type ArcadeRound struct {
+	Player string `json:"player"`
+}
+
+func NewArcadeHandler(arcadeService rpslsapi.ArcadeService) ArcadeHandler {
+	return ArcadeHandler{service: arcadeService}
+}
+
+func (ah *ArcadeHandler) addRoutes(r chi.Router) {
+	r.Post("/", ah.handleStart)
+	r.Get("/high-scores", ah.handleGetHighScores)
+	r.Get("/{id}", ah.handleGet)
+	r.Post("/{id}/rounds", ah.handlePlay)
+}
+
+func (ah *ArcadeHandler) handleStart(w http.ResponseWriter, r *http.Request) {
+	var settings rpslsapi.ArcadeSettings
+	if !decodeJsonBody(&settings, w, r, "startArcadeSession") {
+		return
+	}
+
+	settings.UserID = userID(r)
+	session, err := ah.service.StartSession(&settings)
+	if err != nil {
+		writeServiceError(err, w, r, "startArcadeSession")
+		return
+	}
+
+	writeJsonResponse(session, http.StatusCreated, w, r, "startArcadeSession")
+}
+
+func (ah *ArcadeHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	session, err := ah.service.Session(chi.URLParam(r, "id"), userID(r))
+	if err != nil {
+		writeServiceError(err, w, r, "getArcadeSession")
+		return
+	}
+
+	writeJsonResponse(session, http.StatusOK, w, r, "getArcadeSession")
+}
+
+func (ah *ArcadeHandler) handlePlay(w http.ResponseWriter, r *http.Request) {
+	var round ArcadeRound
+	if !decodeJsonBody(&round, w, r, "playArcadeRound") {
+		return
+	}
+
+	settings := &rpslsapi.RoundSettings{Player: round.Player, RequestID: middleware.GetReqID(r.Context()),
+		UserID: userID(r)}
+	session, err := ah.service.Play(chi.URLParam(r, "id"), settings)
+	if err != nil {
+		writeServiceError(err, w, r, "playArcadeRound")
+		return
+	}
+
+	writeJsonResponse(session, http.StatusOK, w, r, "playArcadeRound")
+}
+
+func (ah *ArcadeHandler) handleGetHighScores(w http.ResponseWriter, r *http.Request) {
+	highScores, err := ah.service.HighScores(r.URL.Query().Get("ruleset"))
+	if err != nil {
+		writeServiceError(err, w, r, "getHighScores")
+		return
+	}
+
+	writeJsonResponse(highScores, http.StatusOK, w, r, "getHighScores")
+}
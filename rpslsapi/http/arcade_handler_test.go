@@ -0,0 +1,158 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type ArcadeServiceMock struct {
+	mock.Mock
+}
+
+func (asm *ArcadeServiceMock) StartSession(settings *rpslsapi.ArcadeSettings) (*rpslsapi.ArcadeSession, error) {
+	args := asm.Called(settings)
+	return args.Get(0).(*rpslsapi.ArcadeSession), args.Error(1)
+}
+
+func (asm *ArcadeServiceMock) Session(id, userID string) (*rpslsapi.ArcadeSession, error) {
+	args := asm.Called(id, userID)
+	return args.Get(0).(*rpslsapi.ArcadeSession), args.Error(1)
+}
+
+func (asm *ArcadeServiceMock) Play(id string, settings *rpslsapi.RoundSettings) (*rpslsapi.ArcadeSession, error) {
+	args := asm.Called(id, settings)
+	return args.Get(0).(*rpslsapi.ArcadeSession), args.Error(1)
+}
+
+func (asm *ArcadeServiceMock) HighScores(ruleset string) ([]rpslsapi.HighScore, error) {
+	args := asm.Called(ruleset)
+	return args.Get(0).([]rpslsapi.HighScore), args.Error(1)
+}
+
+var ongoingArcadeSession = &rpslsapi.ArcadeSession{
+	ID:         "arcade",
+	Player:     "ada",
+	Ruleset:    "rpsls",
+	Lives:      2,
+	Score:      300,
+	Streak:     2,
+	Multiplier: 2,
+	BestStreak: 2,
+	Rounds:     []rpslsapi.RoundResults{{Results: string(rpslsapi.Lose)}, {Results: string(rpslsapi.Win)}},
+}
+
+func TestStartArcadeSessionRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the started session",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "failure: if the session is invalid, return 422",
+			serviceError:   rpslsapi.ErrInvalidArcadeSession,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := ArcadeServiceMock{}
+		router := NewRouter(Handlers{Arcade: NewArcadeHandler(&serviceMock), Auth: newTestAuthHandler()})
+		settings := &rpslsapi.ArcadeSettings{Player: "ada", Ruleset: "rpsls", Lives: 3}
+		owned := *settings
+		owned.UserID = "user"
+		serviceMock.On("StartSession", &owned).Return(ongoingArcadeSession, tc.serviceError)
+
+		body, _ := json.Marshal(settings)
+		req := authorize(httptest.NewRequest("POST", "/arcade", bytes.NewBuffer(body)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		serviceMock.AssertExpectations(t)
+	}
+}
+
+func TestPlayArcadeRoundRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the session",
+			requestBody:    "{\"player\": \"rpsls-rock\"}",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the session doesn't exist, return 404",
+			requestBody:    "{\"player\": \"rpsls-rock\"}",
+			serviceError:   rpslsapi.ErrArcadeSessionNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failure: if the session is over, return 409",
+			requestBody:    "{\"player\": \"rpsls-rock\"}",
+			serviceError:   rpslsapi.ErrArcadeSessionOver,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failure: if the session belongs to another user, return 403",
+			requestBody:    "{\"player\": \"rpsls-rock\"}",
+			serviceError:   rpslsapi.ErrNotArcadeSessionOwner,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "failure: if a bad body is sent, return 422",
+			requestBody:    "{\"player\": 1",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := ArcadeServiceMock{}
+		router := NewRouter(Handlers{Arcade: NewArcadeHandler(&serviceMock)})
+		serviceMock.On("Play", "arcade", mock.Anything).Return(ongoingArcadeSession, tc.serviceError)
+
+		req := httptest.NewRequest("POST", "/arcade/arcade/rounds", bytes.NewBufferString(tc.requestBody))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedStatus == http.StatusOK {
+			var returnedBody *rpslsapi.ArcadeSession
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody), tc.name)
+			require.Equal(t, ongoingArcadeSession, returnedBody, tc.name)
+			settings := serviceMock.Calls[0].Arguments.Get(1).(*rpslsapi.RoundSettings)
+			require.Equal(t, "rpsls-rock", settings.Player, tc.name)
+			require.NotEmpty(t, settings.RequestID, tc.name)
+		}
+	}
+}
+
+func TestGetHighScoresRequest(t *testing.T) {
+	highScores := []rpslsapi.HighScore{{Player: "ada", Score: 1200, Session: "arcade", Ruleset: "rps"}}
+	serviceMock := ArcadeServiceMock{}
+	router := NewRouter(Handlers{Arcade: NewArcadeHandler(&serviceMock)})
+	serviceMock.On("HighScores", "rps").Return(highScores, nil)
+
+	req := httptest.NewRequest("GET", "/arcade/high-scores?ruleset=rps", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var returnedBody []rpslsapi.HighScore
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody))
+	require.Equal(t, highScores, returnedBody)
+}
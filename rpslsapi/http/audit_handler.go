@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"rpsls/rpslsapi"
+)
+
+type AuditHandler struct {
+	service rpslsapi.AuditService
+}
+
+func NewAuditHandler(auditService rpslsapi.AuditService) AuditHandler {
+	return AuditHandler{service: auditService}
+}
+
+func (ah *AuditHandler) addRoutes(r chi.Router) {
+	r.Use(requireAdmin)
+	r.Get("/{id}", ah.handleGetAudit)
+	r.Post("/{id}/replay", ah.handleReplay)
+}
+
+func (ah *AuditHandler) handleGetAudit(w http.ResponseWriter, r *http.Request) {
+	audit, err := ah.service.Audit(chi.URLParam(r, "id"))
+	if err != nil {
+		writeServiceError(err, w, r, "getAudit")
+		return
+	}
+
+	writeJsonResponse(audit, http.StatusOK, w, r, "getAudit")
+}
+
+func (ah *AuditHandler) handleReplay(w http.ResponseWriter, r *http.Request) {
+	replay, err := ah.service.Replay(chi.URLParam(r, "id"))
+	if err != nil {
+		writeServiceError(err, w, r, "replayAudit")
+		return
+	}
+
+	writeJsonResponse(replay, http.StatusOK, w, r, "replayAudit")
+}
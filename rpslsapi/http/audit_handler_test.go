@@ -0,0 +1,113 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type AuditServiceMock struct {
+	mock.Mock
+}
+
+func (asm *AuditServiceMock) Record(audit *rpslsapi.RoundAudit) error {
+	args := asm.Called(audit)
+	return args.Error(0)
+}
+
+func (asm *AuditServiceMock) Audit(id string) (*rpslsapi.RoundAudit, error) {
+	args := asm.Called(id)
+	return args.Get(0).(*rpslsapi.RoundAudit), args.Error(1)
+}
+
+func (asm *AuditServiceMock) Replay(id string) (*rpslsapi.AuditReplay, error) {
+	args := asm.Called(id)
+	return args.Get(0).(*rpslsapi.AuditReplay), args.Error(1)
+}
+
+var tiedAudit = &rpslsapi.RoundAudit{ID: "round", RequestID: "request", Ruleset: "rps", Player: "rps-rock",
+	Computer: "rps-rock", Strategy: "random", RandomValues: []int{3}, Results: string(rpslsapi.Tie)}
+
+func TestAuditRequests(t *testing.T) {
+	replay := &rpslsapi.AuditReplay{Audit: tiedAudit, Computer: "rps-rock", Results: string(rpslsapi.Tie),
+		Confirmed: true}
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		authorize      func(req *http.Request) *http.Request
+		mockMethod     string
+		mockResult     interface{}
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the audit",
+			method:         "GET",
+			path:           "/admin/audits/round",
+			authorize:      authorizeAdmin,
+			mockMethod:     "Audit",
+			mockResult:     tiedAudit,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "success: return the replay",
+			method:         "POST",
+			path:           "/admin/audits/round/replay",
+			authorize:      authorizeAdmin,
+			mockMethod:     "Replay",
+			mockResult:     replay,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the audit doesn't exist, return 404",
+			method:         "POST",
+			path:           "/admin/audits/round/replay",
+			authorize:      authorizeAdmin,
+			mockMethod:     "Replay",
+			mockResult:     (*rpslsapi.AuditReplay)(nil),
+			serviceError:   rpslsapi.ErrAuditNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failure: if the user isn't an admin, return 403",
+			method:         "GET",
+			path:           "/admin/audits/round",
+			authorize:      authorize,
+			mockMethod:     "Audit",
+			mockResult:     tiedAudit,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "failure: if the request is anonymous, return 401",
+			method:         "GET",
+			path:           "/admin/audits/round",
+			authorize:      func(req *http.Request) *http.Request { return req },
+			mockMethod:     "Audit",
+			mockResult:     tiedAudit,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := AuditServiceMock{}
+		router := NewRouter(Handlers{Audit: NewAuditHandler(&serviceMock), Auth: newTestAuthHandler()})
+		serviceMock.On(tc.mockMethod, "round").Return(tc.mockResult, tc.serviceError)
+
+		req := tc.authorize(httptest.NewRequest(tc.method, tc.path, nil))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedStatus == http.StatusOK {
+			expected, _ := json.Marshal(tc.mockResult)
+			require.JSONEq(t, string(expected), rr.Body.String(), tc.name)
+		}
+	}
+}
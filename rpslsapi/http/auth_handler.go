@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"rpsls/rpslsapi"
+)
+
+// bearerPrefix starts the Authorization header carrying a token issued by POST /auth/login
+const bearerPrefix = "Bearer "
+
+// identityKey is the request context key of the identity of the authenticated user
+type identityKey struct{}
+
+type AuthHandler struct {
+	service rpslsapi.UserService
+}
+
+func NewAuthHandler(userService rpslsapi.UserService) AuthHandler {
+	return AuthHandler{service: userService}
+}
+
+func (ah *AuthHandler) addRoutes(r chi.Router) {
+	r.Post("/register", ah.handleRegister)
+	r.Post("/login", ah.handleLogin)
+}
+
+func (ah *AuthHandler) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var credentials rpslsapi.Credentials
+	if !decodeJsonBody(&credentials, w, r, "register") {
+		return
+	}
+
+	user, err := ah.service.Register(&credentials)
+	if err != nil {
+		writeServiceError(err, w, r, "register")
+		return
+	}
+
+	writeJsonResponse(user, http.StatusCreated, w, r, "register")
+}
+
+func (ah *AuthHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var credentials rpslsapi.Credentials
+	if !decodeJsonBody(&credentials, w, r, "login") {
+		return
+	}
+
+	token, err := ah.service.Login(&credentials)
+	if err != nil {
+		writeServiceError(err, w, r, "login")
+		return
+	}
+
+	writeJsonResponse(token, http.StatusOK, w, r, "login")
+}
+
+// authenticate puts the identity of the user into the request context when the request carries a bearer token,
+// rejecting invalid tokens. Requests without one are anonymous.
+func (ah *AuthHandler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !strings.HasPrefix(header, bearerPrefix) {
+			writeServiceError(rpslsapi.ErrInvalidToken, w, r, "authenticate")
+			return
+		}
+
+		identity, err := ah.service.Authenticate(strings.TrimPrefix(header, bearerPrefix))
+		if err != nil {
+			writeServiceError(err, w, r, "authenticate")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityKey{}, identity)))
+	})
+}
+
+// requireUser rejects anonymous requests
+func requireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userID(r) == "" {
+			writeServiceError(rpslsapi.ErrUnauthenticated, w, r, "authenticate")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAdmin rejects anonymous requests and requests of users who aren't admins
+func requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity := requestIdentity(r)
+		if identity == nil {
+			writeServiceError(rpslsapi.ErrUnauthenticated, w, r, "authenticate")
+			return
+		}
+		if identity.Role != rpslsapi.AdminRole {
+			writeServiceError(rpslsapi.ErrNotAdmin, w, r, "authenticate")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// userID returns the ID of the authenticated user, or an empty string for anonymous requests
+func userID(r *http.Request) string {
+	if identity := requestIdentity(r); identity != nil {
+		return identity.UserID
+	}
+	return ""
+}
+
+// requestIdentity returns the identity of the authenticated user, or nil for anonymous requests
+func requestIdentity(r *http.Request) *rpslsapi.Identity {
+	identity, _ := r.Context().Value(identityKey{}).(*rpslsapi.Identity)
+	return identity
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+
+	"rpsls/rpslsapi"
+)
+
+// maxBotResponseSize bounds the bytes read from the responses of bots, which only hold a choice ID
+const maxBotResponseSize = 4 << 10
+
+type BotClient struct {
+	client *http.Client
+}
+
+// NewBotClient returns a client which only connects to the addresses allowed by rpslsapi.CheckBotIP, so that bots
+// whose host resolves to an internal address can't be called, and which doesn't go through proxies
+func NewBotClient() BotClient {
+	dialer := &net.Dialer{Control: checkBotAddress}
+	return BotClient{client: &http.Client{Transport: &http.Transport{DialContext: dialer.DialContext}}}
+}
+
+// checkBotAddress rejects connections to the addresses bots aren't allowed on, once their host name is resolved
+func checkBotAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("bot address %s isn't an IP", address)
+	}
+	return rpslsapi.CheckBotIP(ip)
+}
+
+// Choose posts the request to the bot, cancelling the call once the deadline of the request has passed
+func (bc BotClient) Choose(bot *rpslsapi.Bot, request *rpslsapi.BotRequest) (*rpslsapi.BotResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), request.Deadline)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, bot.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := bc.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bot answered with status %d", resp.StatusCode)
+	}
+
+	var botResponse rpslsapi.BotResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxBotResponseSize)).Decode(&botResponse); err != nil {
+		return nil, err
+	}
+	return &botResponse, nil
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestBotClient_Choose(t *testing.T) {
+	choices := []string{"rpsls-rock", "rpsls-paper", "rpsls-scissors"}
+	testCases := []struct {
+		name           string
+		delay          time.Duration
+		history        []rpslsapi.BotRound
+		privateHosts   bool
+		expectedChoice string
+		expectedError  bool
+	}{
+		{
+			name:           "success: return the first choice without history",
+			privateHosts:   true,
+			expectedChoice: "rpsls-rock",
+		},
+		{
+			name:           "success: return the choice following the last one of the opponent",
+			history:        []rpslsapi.BotRound{{Results: string(rpslsapi.Win), Opponent: "rpsls-scissors"}},
+			privateHosts:   true,
+			expectedChoice: "rpsls-rock",
+		},
+		{
+			name:          "failure: if the bot doesn't answer before the deadline, return error",
+			delay:         time.Second,
+			privateHosts:  true,
+			expectedError: true,
+		},
+		{
+			name:          "failure: if the bot is on a loopback address, return error without calling it",
+			delay:         time.Second,
+			expectedError: true,
+		},
+	}
+	defer func() { rpslsapi.Config.BotPrivateHosts = false }()
+
+	for _, tc := range testCases {
+		rpslsapi.Config.BotPrivateHosts = tc.privateHosts
+		server := httptest.NewServer(StubBot{Delay: tc.delay})
+		client := NewBotClient()
+		bot := &rpslsapi.Bot{Name: "stub", URL: server.URL}
+
+		started := time.Now()
+		response, err := client.Choose(bot, &rpslsapi.BotRequest{Ruleset: "rpsls", Choices: choices,
+			History: tc.history, Deadline: time.Now().Add(50 * time.Millisecond)})
+
+		server.Close()
+		if tc.expectedError {
+			require.Error(t, err, tc.name)
+			require.Less(t, int64(time.Since(started)), int64(tc.delay), tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedChoice, response.Choice, tc.name)
+	}
+}
+
+func TestBotClient_Choose_LargeResponse(t *testing.T) {
+	rpslsapi.Config.BotPrivateHosts = true
+	defer func() { rpslsapi.Config.BotPrivateHosts = false }()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{\"choice\": \"" + strings.Repeat("rock", maxBotResponseSize) + "\"}"))
+	}))
+	defer server.Close()
+
+	_, err := NewBotClient().Choose(&rpslsapi.Bot{Name: "chatty", URL: server.URL},
+		&rpslsapi.BotRequest{Ruleset: "rpsls", Deadline: time.Now().Add(time.Second)})
+
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"rpsls/rpslsapi"
+)
+
+type BotHandler struct {
+	service rpslsapi.BotService
+}
+
+func NewBotHandler(botService rpslsapi.BotService) BotHandler {
+	return BotHandler{service: botService}
+}
+
+func (bh *BotHandler) addRoutes(r chi.Router) {
+	r.Get("/", bh.handleList)
+	r.With(requireAdmin).Post("/", bh.handleRegister)
+	r.Get("/{name}", bh.handleGet)
+	r.With(requireAdmin).Delete("/{name}", bh.handleDelete)
+}
+
+func (bh *BotHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	bots, err := bh.service.Bots()
+	if err != nil {
+		writeServiceError(err, w, r, "listBots")
+		return
+	}
+
+	writeJsonResponse(bots, http.StatusOK, w, r, "listBots")
+}
+
+func (bh *BotHandler) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var bot rpslsapi.Bot
+	if !decodeJsonBody(&bot, w, r, "registerBot") {
+		return
+	}
+
+	registered, err := bh.service.RegisterBot(&bot)
+	if err != nil {
+		writeServiceError(err, w, r, "registerBot")
+		return
+	}
+
+	writeJsonResponse(registered, http.StatusCreated, w, r, "registerBot")
+}
+
+func (bh *BotHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	bot, err := bh.service.Bot(chi.URLParam(r, "name"))
+	if err != nil {
+		writeServiceError(err, w, r, "getBot")
+		return
+	}
+
+	writeJsonResponse(bot, http.StatusOK, w, r, "getBot")
+}
+
+func (bh *BotHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if err := bh.service.DeleteBot(chi.URLParam(r, "name")); err != nil {
+		writeServiceError(err, w, r, "deleteBot")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
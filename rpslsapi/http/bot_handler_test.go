@@ -0,0 +1,118 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type BotServiceMock struct {
+	mock.Mock
+}
+
+func (bsm *BotServiceMock) RegisterBot(bot *rpslsapi.Bot) (*rpslsapi.Bot, error) {
+	args := bsm.Called(bot)
+	return args.Get(0).(*rpslsapi.Bot), args.Error(1)
+}
+
+func (bsm *BotServiceMock) Bots() ([]rpslsapi.Bot, error) {
+	args := bsm.Called()
+	return args.Get(0).([]rpslsapi.Bot), args.Error(1)
+}
+
+func (bsm *BotServiceMock) Bot(name string) (*rpslsapi.Bot, error) {
+	args := bsm.Called(name)
+	return args.Get(0).(*rpslsapi.Bot), args.Error(1)
+}
+
+func (bsm *BotServiceMock) DeleteBot(name string) error {
+	args := bsm.Called(name)
+	return args.Error(0)
+}
+
+var stubBot = &rpslsapi.Bot{Name: "stub", URL: "http://localhost:3100"}
+
+func TestRegisterBotRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the registered bot",
+			requestBody:    "{\"name\": \"stub\", \"url\": \"http://localhost:3100\"}",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "failure: if the bot is invalid, return 422",
+			requestBody:    "{\"name\": \"stub\", \"url\": \"http://localhost:3100\"}",
+			serviceError:   rpslsapi.ErrInvalidBot,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if the name is taken, return 409",
+			requestBody:    "{\"name\": \"stub\", \"url\": \"http://localhost:3100\"}",
+			serviceError:   rpslsapi.ErrBotAlreadyExists,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failure: if a bad body is sent, return 422",
+			requestBody:    "{\"name\": 1",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := BotServiceMock{}
+		router := NewRouter(Handlers{Bot: NewBotHandler(&serviceMock), Auth: newTestAuthHandler()})
+		serviceMock.On("RegisterBot", stubBot).Return(stubBot, tc.serviceError)
+
+		req := authorizeAdmin(httptest.NewRequest("POST", "/bots", bytes.NewBufferString(tc.requestBody)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedStatus == http.StatusCreated {
+			var returnedBody *rpslsapi.Bot
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody), tc.name)
+			require.Equal(t, stubBot, returnedBody, tc.name)
+		}
+	}
+}
+
+func TestDeleteBotRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: delete the bot",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the bot doesn't exist, return 404",
+			serviceError:   rpslsapi.ErrBotNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := BotServiceMock{}
+		router := NewRouter(Handlers{Bot: NewBotHandler(&serviceMock), Auth: newTestAuthHandler()})
+		serviceMock.On("DeleteBot", "stub").Return(tc.serviceError)
+
+		req := authorizeAdmin(httptest.NewRequest("DELETE", "/bots/stub", nil))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+	}
+}
@@ -0,0 +1,30 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"rpsls/rpslsapi"
+)
+
+type CacheHandler struct {
+	cache rpslsapi.OutcomeCache
+}
+
+func NewCacheHandler(outcomeCache rpslsapi.OutcomeCache) CacheHandler {
+	return CacheHandler{cache: outcomeCache}
+}
+
+func (ch *CacheHandler) addRoutes(r chi.Router) {
+	r.Get("/stats", ch.handleStats)
+	r.With(requireAdmin).Delete("/", ch.handleInvalidate)
+}
+
+func (ch *CacheHandler) handleStats(w http.ResponseWriter, r *http.Request) {
+	writeJsonResponse(ch.cache.Stats(), http.StatusOK, w, r, "cacheStats")
+}
+
+func (ch *CacheHandler) handleInvalidate(w http.ResponseWriter, r *http.Request) {
+	ch.cache.Invalidate(r.URL.Query().Get("ruleset"))
+	w.WriteHeader(http.StatusOK)
+}
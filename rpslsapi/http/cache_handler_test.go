@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type OutcomeCacheMock struct {
+	mock.Mock
+}
+
+func (ocm *OutcomeCacheMock) Stats() rpslsapi.OutcomeCacheStats {
+	args := ocm.Called()
+	return args.Get(0).(rpslsapi.OutcomeCacheStats)
+}
+
+func (ocm *OutcomeCacheMock) Invalidate(ruleset string) {
+	ocm.Called(ruleset)
+}
+
+func TestCacheStatsRequest(t *testing.T) {
+	cacheMock := OutcomeCacheMock{}
+	router := NewRouter(Handlers{Cache: NewCacheHandler(&cacheMock)})
+	stats := rpslsapi.OutcomeCacheStats{
+		Hits:           9,
+		Misses:         1,
+		HitRatio:       0.9,
+		CachedRulesets: []string{"rpsls"},
+		TTL:            "5m0s",
+	}
+	cacheMock.On("Stats").Return(stats)
+
+	req := httptest.NewRequest("GET", "/cache/stats", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var returnedBody rpslsapi.OutcomeCacheStats
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody))
+	require.Equal(t, stats, returnedBody)
+}
+
+func TestCacheInvalidateRequest(t *testing.T) {
+	testCases := []struct {
+		name            string
+		url             string
+		expectedRuleset string
+	}{
+		{
+			name:            "success: invalidate a single ruleset",
+			url:             "/cache?ruleset=rps",
+			expectedRuleset: "rps",
+		},
+		{
+			name:            "success: invalidate every ruleset",
+			url:             "/cache",
+			expectedRuleset: "",
+		},
+	}
+
+	cacheMock := OutcomeCacheMock{}
+	router := NewRouter(Handlers{Cache: NewCacheHandler(&cacheMock), Auth: newTestAuthHandler()})
+
+	for _, tc := range testCases {
+		cacheMock.On("Invalidate", tc.expectedRuleset).Return().Once()
+
+		req := authorizeAdmin(httptest.NewRequest("DELETE", tc.url, nil))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code, tc.name)
+	}
+	cacheMock.AssertExpectations(t)
+}
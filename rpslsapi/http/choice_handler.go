@@ -1,6 +1,7 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi"
@@ -9,22 +10,41 @@ import (
 	"rpsls/rpslsapi/logger"
 )
 
+var graphContentTypes = map[rpslsapi.GraphFormat]string{
+	rpslsapi.DOTFormat:     "text/vnd.graphviz",
+	rpslsapi.MermaidFormat: "text/plain; charset=utf-8",
+	rpslsapi.SVGFormat:     "image/svg+xml",
+}
+
 type ChoiceHandler struct {
-	service rpslsapi.ChoiceService
+	service      rpslsapi.ChoiceService
+	translations rpslsapi.TranslationService
 }
 
-func NewChoiceHandler(choiceService rpslsapi.ChoiceService) ChoiceHandler {
-	return ChoiceHandler{service: choiceService}
+func NewChoiceHandler(choiceService rpslsapi.ChoiceService,
+	translationService rpslsapi.TranslationService) ChoiceHandler {
+	return ChoiceHandler{service: choiceService, translations: translationService}
 }
 
 func (ch *ChoiceHandler) addRoutes(r chi.Router) {
 	r.Get("/choices", ch.handleList)
+	r.With(requireAdmin).Post("/choices", ch.handleCreate)
+	r.With(requireAdmin).Put("/choices/{id}", ch.handleUpdate)
+	r.With(requireAdmin).Delete("/choices/{id}", ch.handleDelete)
 	r.Get("/choice", ch.handleRandom)
+	r.Get("/rules", ch.handleListRules)
+	r.Get("/rules/validation", ch.handleValidateRules)
+	r.Get("/rules/graph", ch.handleRenderRules)
+	r.With(requireAdmin).Put("/rules", ch.handleSaveRule)
+	r.With(requireAdmin).Delete("/rules/{winnerID}/{loserID}", ch.handleDeleteRule)
 }
 
 func (ch *ChoiceHandler) handleList(w http.ResponseWriter, r *http.Request) {
-	choices, err := ch.service.Choices()
-	if err != nil {
+	choices, err := ch.service.Choices(r.URL.Query().Get("ruleset"))
+	if err == rpslsapi.ErrRulesetNotFound {
+		writeServiceError(err, w, r, "listChoices")
+		return
+	} else if err != nil {
 		writeJsonResponse(ErrorResponse{Code: UnknownError, Message: "list choices failed"},
 			http.StatusInternalServerError, w, r, "listChoices")
 		logger.WithReqIdAndAction(log.Error().Stack().Err(err), r, "listChoices").
@@ -35,12 +55,16 @@ func (ch *ChoiceHandler) handleList(w http.ResponseWriter, r *http.Request) {
 	if choices == nil {
 		choices = []rpslsapi.Choice{}
 	}
-	writeJsonResponse(choices, http.StatusOK, w, r, "listChoices")
+	translator := negotiateTranslator(ch.translations, w, r, "listChoices")
+	writeJsonResponse(translator.Choices(choices), http.StatusOK, w, r, "listChoices")
 }
 
 func (ch *ChoiceHandler) handleRandom(w http.ResponseWriter, r *http.Request) {
-	randomChoice, err := ch.service.RandomChoice()
-	if err != nil {
+	randomChoice, err := ch.service.RandomChoice(r.URL.Query().Get("ruleset"))
+	if err == rpslsapi.ErrRulesetNotFound {
+		writeServiceError(err, w, r, "randomChoice")
+		return
+	} else if err != nil {
 		writeJsonResponse(ErrorResponse{Code: UnknownError, Message: "random choice failed"},
 			http.StatusInternalServerError, w, r, "randomChoice")
 		logger.WithReqIdAndAction(log.Error().Stack().Err(err), r, "randomChoice").
@@ -48,5 +72,116 @@ func (ch *ChoiceHandler) handleRandom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJsonResponse(randomChoice, http.StatusOK, w, r, "randomChoice")
+	translator := negotiateTranslator(ch.translations, w, r, "randomChoice")
+	writeJsonResponse(translator.Choice(randomChoice), http.StatusOK, w, r, "randomChoice")
+}
+
+func (ch *ChoiceHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var definition rpslsapi.ChoiceDefinition
+	if !decodeJsonBody(&definition, w, r, "createChoice") {
+		return
+	}
+
+	choice, err := ch.service.CreateChoice(&definition)
+	if err != nil {
+		writeServiceError(err, w, r, "createChoice")
+		return
+	}
+
+	writeJsonResponse(choice, http.StatusCreated, w, r, "createChoice")
+}
+
+func (ch *ChoiceHandler) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	var choice rpslsapi.Choice
+	if !decodeJsonBody(&choice, w, r, "updateChoice") {
+		return
+	}
+	choice.ID = chi.URLParam(r, "id")
+
+	updated, err := ch.service.UpdateChoice(&choice)
+	if err != nil {
+		writeServiceError(err, w, r, "updateChoice")
+		return
+	}
+
+	writeJsonResponse(updated, http.StatusOK, w, r, "updateChoice")
+}
+
+func (ch *ChoiceHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if err := ch.service.DeleteChoice(chi.URLParam(r, "id")); err != nil {
+		writeServiceError(err, w, r, "deleteChoice")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (ch *ChoiceHandler) handleListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := ch.service.Rules(r.URL.Query().Get("ruleset"))
+	if err != nil {
+		writeServiceError(err, w, r, "listRules")
+		return
+	}
+
+	writeJsonResponse(rules, http.StatusOK, w, r, "listRules")
+}
+
+func (ch *ChoiceHandler) handleValidateRules(w http.ResponseWriter, r *http.Request) {
+	report, err := ch.service.ValidateRules(r.URL.Query().Get("ruleset"))
+	if err != nil {
+		writeServiceError(err, w, r, "validateRules")
+		return
+	}
+
+	writeJsonResponse(report, http.StatusOK, w, r, "validateRules")
+}
+
+func (ch *ChoiceHandler) handleRenderRules(w http.ResponseWriter, r *http.Request) {
+	format := rpslsapi.GraphFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = rpslsapi.SVGFormat
+	}
+	contentType, found := graphContentTypes[format]
+	if !found {
+		writeJsonResponse(ErrorResponse{Code: UnprocessableBody, Message: fmt.Sprintf("unknown format %q", format)},
+			http.StatusUnprocessableEntity, w, r, "renderRules")
+		return
+	}
+
+	rendered, err := ch.service.RenderRules(r.URL.Query().Get("ruleset"), format)
+	if err != nil {
+		writeServiceError(err, w, r, "renderRules")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(rendered); err != nil {
+		logger.WithReqIdAndAction(log.Error().Stack().Err(err), r, "renderRules").
+			Msg("failed to write response")
+	}
+}
+
+func (ch *ChoiceHandler) handleSaveRule(w http.ResponseWriter, r *http.Request) {
+	var rule rpslsapi.Rule
+	if !decodeJsonBody(&rule, w, r, "saveRule") {
+		return
+	}
+
+	if err := ch.service.SaveRule(&rule); err != nil {
+		writeServiceError(err, w, r, "saveRule")
+		return
+	}
+
+	writeJsonResponse(rule, http.StatusOK, w, r, "saveRule")
+}
+
+func (ch *ChoiceHandler) handleDeleteRule(w http.ResponseWriter, r *http.Request) {
+	winnerID, loserID := chi.URLParam(r, "winnerID"), chi.URLParam(r, "loserID")
+	if err := ch.service.DeleteRule(winnerID, loserID); err != nil {
+		writeServiceError(err, w, r, "deleteRule")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
 }
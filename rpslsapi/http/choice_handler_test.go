@@ -1,6 +1,7 @@
 package http
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -16,40 +17,80 @@ type ChoiceServiceMock struct {
 	mock.Mock
 }
 
-func (csm *ChoiceServiceMock) Choices() ([]rpslsapi.Choice, error) {
-	args := csm.Called()
+func (csm *ChoiceServiceMock) Choices(ruleset string) ([]rpslsapi.Choice, error) {
+	args := csm.Called(ruleset)
 	return args.Get(0).([]rpslsapi.Choice), args.Error(1)
 }
 
-func (csm *ChoiceServiceMock) Choice(id int64) (*rpslsapi.Choice, error) {
+func (csm *ChoiceServiceMock) Choice(id string) (*rpslsapi.Choice, error) {
 	args := csm.Called(id)
 	return args.Get(0).(*rpslsapi.Choice), args.Error(1)
 }
 
-func (csm *ChoiceServiceMock) RandomChoice() (*rpslsapi.Choice, error) {
-	args := csm.Called()
+func (csm *ChoiceServiceMock) RandomChoice(ruleset string) (*rpslsapi.Choice, error) {
+	args := csm.Called(ruleset)
 	return args.Get(0).(*rpslsapi.Choice), args.Error(1)
 }
 
+func (csm *ChoiceServiceMock) CreateChoice(definition *rpslsapi.ChoiceDefinition) (*rpslsapi.Choice, error) {
+	args := csm.Called(definition)
+	return args.Get(0).(*rpslsapi.Choice), args.Error(1)
+}
+
+func (csm *ChoiceServiceMock) UpdateChoice(choice *rpslsapi.Choice) (*rpslsapi.Choice, error) {
+	args := csm.Called(choice)
+	return args.Get(0).(*rpslsapi.Choice), args.Error(1)
+}
+
+func (csm *ChoiceServiceMock) DeleteChoice(id string) error {
+	args := csm.Called(id)
+	return args.Error(0)
+}
+
+func (csm *ChoiceServiceMock) Rules(ruleset string) ([]rpslsapi.Rule, error) {
+	args := csm.Called(ruleset)
+	return args.Get(0).([]rpslsapi.Rule), args.Error(1)
+}
+
+func (csm *ChoiceServiceMock) SaveRule(rule *rpslsapi.Rule) error {
+	args := csm.Called(rule)
+	return args.Error(0)
+}
+
+func (csm *ChoiceServiceMock) DeleteRule(winnerID, loserID string) error {
+	args := csm.Called(winnerID, loserID)
+	return args.Error(0)
+}
+
+func (csm *ChoiceServiceMock) ValidateRules(ruleset string) (*rpslsapi.RuleGraphReport, error) {
+	args := csm.Called(ruleset)
+	return args.Get(0).(*rpslsapi.RuleGraphReport), args.Error(1)
+}
+
+func (csm *ChoiceServiceMock) RenderRules(ruleset string, format rpslsapi.GraphFormat) ([]byte, error) {
+	args := csm.Called(ruleset, format)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
 var baseChoices = []rpslsapi.Choice{
 	{
-		ID:   1,
+		ID:   "rpsls-rock",
 		Name: "rock",
 	},
 	{
-		ID:   2,
+		ID:   "rpsls-paper",
 		Name: "paper",
 	},
 	{
-		ID:   3,
+		ID:   "rpsls-scissors",
 		Name: "scissors",
 	},
 	{
-		ID:   4,
+		ID:   "rpsls-lizard",
 		Name: "lizard",
 	},
 	{
-		ID:   5,
+		ID:   "rpsls-spock",
 		Name: "spock",
 	},
 }
@@ -74,6 +115,11 @@ func TestChoiceListRequest(t *testing.T) {
 			expectedChoices:    []rpslsapi.Choice{},
 			expectedStatus:     http.StatusOK,
 		},
+		{
+			name:           "failure: if the ruleset is missing, return 404",
+			serviceError:   rpslsapi.ErrRulesetNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
 		{
 			name:           "failure: if an unknown error happens, return 500",
 			serviceError:   errors.New("unknown error"),
@@ -82,10 +128,10 @@ func TestChoiceListRequest(t *testing.T) {
 	}
 
 	serviceMock := ChoiceServiceMock{}
-	router := NewRouter(NewChoiceHandler(&serviceMock), RoundHandler{}, ScoreboardHandler{})
+	router := NewRouter(Handlers{Choice: NewChoiceHandler(&serviceMock, &TranslationServiceMock{})})
 
 	for _, tc := range testCases {
-		serviceMock.On("Choices").Return(tc.choicesFromService, tc.serviceError).Once()
+		serviceMock.On("Choices", mock.Anything).Return(tc.choicesFromService, tc.serviceError).Once()
 
 		req := httptest.NewRequest("GET", "/choices", nil)
 		rr := httptest.NewRecorder()
@@ -112,7 +158,7 @@ func TestRandomChoiceRequest(t *testing.T) {
 	}{
 		{
 			name:              "success: return random choice",
-			choiceFromService: &rpslsapi.Choice{ID: 1, Name: "rock"},
+			choiceFromService: &rpslsapi.Choice{ID: "rpsls-rock", Name: "rock"},
 			expectedStatus:    http.StatusOK,
 		},
 		{
@@ -124,10 +170,10 @@ func TestRandomChoiceRequest(t *testing.T) {
 	}
 
 	serviceMock := ChoiceServiceMock{}
-	router := NewRouter(NewChoiceHandler(&serviceMock), RoundHandler{}, ScoreboardHandler{})
+	router := NewRouter(Handlers{Choice: NewChoiceHandler(&serviceMock, &TranslationServiceMock{})})
 
 	for _, tc := range testCases {
-		serviceMock.On("RandomChoice").Return(tc.choiceFromService, tc.serviceError).Once()
+		serviceMock.On("RandomChoice", mock.Anything).Return(tc.choiceFromService, tc.serviceError).Once()
 
 		req := httptest.NewRequest("GET", "/choice", nil)
 		rr := httptest.NewRecorder()
@@ -144,3 +190,329 @@ func TestRandomChoiceRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateChoiceRequest(t *testing.T) {
+	created := &rpslsapi.Choice{ID: "rpsls-kitten", Name: "kitten"}
+
+	testCases := []struct {
+		name           string
+		requestBody    []byte
+		serviceError   error
+		expectedChoice *rpslsapi.Choice
+		expectedStatus int
+	}{
+		{
+			name:           "success: return created choice",
+			requestBody:    []byte(`{"name": "kitten", "beats": [{"choice": "rpsls-paper", "action": "scratches"}]}`),
+			expectedChoice: created,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "failure: if the choice is invalid, return 422",
+			requestBody:    []byte(`{"name": ""}`),
+			serviceError:   rpslsapi.ErrInvalidChoice,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if a related choice is missing, return 404",
+			requestBody:    []byte(`{"name": "kitten", "beats": [{"choice": "rpsls-well", "action": "scratches"}]}`),
+			serviceError:   rpslsapi.ErrChoiceNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failure: if the name is taken, return 409",
+			requestBody:    []byte(`{"name": "rock"}`),
+			serviceError:   rpslsapi.ErrChoiceAlreadyExists,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failure: if a bad body is sent, return 422",
+			requestBody:    []byte(`{"name": `),
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	serviceMock := ChoiceServiceMock{}
+	router := NewRouter(Handlers{Choice: NewChoiceHandler(&serviceMock, &TranslationServiceMock{}),
+		Auth: newTestAuthHandler()})
+
+	for _, tc := range testCases {
+		serviceMock.On("CreateChoice", mock.Anything).Return(created, tc.serviceError).Once()
+
+		req := authorizeAdmin(httptest.NewRequest("POST", "/choices", bytes.NewBuffer(tc.requestBody)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code)
+		if tc.expectedChoice != nil {
+			var returnedBody *rpslsapi.Choice
+			err := json.Unmarshal(rr.Body.Bytes(), &returnedBody)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedChoice, returnedBody)
+		}
+	}
+}
+
+func TestUpdateChoiceRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return updated choice",
+			path:           "/choices/rpsls-rock",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the choice is missing, return 404",
+			path:           "/choices/rpsls-well",
+			serviceError:   rpslsapi.ErrChoiceNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	serviceMock := ChoiceServiceMock{}
+	router := NewRouter(Handlers{Choice: NewChoiceHandler(&serviceMock, &TranslationServiceMock{}),
+		Auth: newTestAuthHandler()})
+
+	for _, tc := range testCases {
+		serviceMock.On("UpdateChoice", mock.Anything).
+			Return(&rpslsapi.Choice{ID: "rpsls-rock", Name: "boulder"}, tc.serviceError).Once()
+
+		req := authorizeAdmin(httptest.NewRequest("PUT", tc.path, bytes.NewBufferString(`{"name": "boulder"}`)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code)
+	}
+}
+
+func TestDeleteChoiceRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return 200",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the choice is missing, return 404",
+			serviceError:   rpslsapi.ErrChoiceNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failure: if an unknown error happens, return 500",
+			serviceError:   errors.New("unknown error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	serviceMock := ChoiceServiceMock{}
+	router := NewRouter(Handlers{Choice: NewChoiceHandler(&serviceMock, &TranslationServiceMock{}),
+		Auth: newTestAuthHandler()})
+
+	for _, tc := range testCases {
+		serviceMock.On("DeleteChoice", "rpsls-scissors").Return(tc.serviceError).Once()
+
+		req := authorizeAdmin(httptest.NewRequest("DELETE", "/choices/rpsls-scissors", nil))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code)
+	}
+}
+
+func TestSaveRuleRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return saved rule",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the rule is invalid, return 422",
+			serviceError:   rpslsapi.ErrInvalidRule,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if the rule graph would become inconsistent, return 422",
+			serviceError:   &rpslsapi.RuleGraphError{Report: &rpslsapi.RuleGraphReport{}},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if a choice is missing, return 404",
+			serviceError:   rpslsapi.ErrChoiceNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	serviceMock := ChoiceServiceMock{}
+	router := NewRouter(Handlers{Choice: NewChoiceHandler(&serviceMock, &TranslationServiceMock{}),
+		Auth: newTestAuthHandler()})
+	rule := rpslsapi.Rule{WinnerID: "rpsls-rock", LoserID: "rpsls-scissors", Action: "crushes"}
+	body, _ := json.Marshal(rule)
+
+	for _, tc := range testCases {
+		serviceMock.On("SaveRule", &rule).Return(tc.serviceError).Once()
+
+		req := authorizeAdmin(httptest.NewRequest("PUT", "/rules", bytes.NewBuffer(body)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code)
+	}
+}
+
+func TestDeleteRuleRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return 200",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the rule is missing, return 404",
+			serviceError:   rpslsapi.ErrRuleNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	serviceMock := ChoiceServiceMock{}
+	router := NewRouter(Handlers{Choice: NewChoiceHandler(&serviceMock, &TranslationServiceMock{}),
+		Auth: newTestAuthHandler()})
+
+	for _, tc := range testCases {
+		serviceMock.On("DeleteRule", "rpsls-rock", "rpsls-scissors").Return(tc.serviceError).Once()
+
+		req := authorizeAdmin(httptest.NewRequest("DELETE", "/rules/rpsls-rock/rpsls-scissors", nil))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code)
+	}
+}
+
+func TestValidateRulesRequest(t *testing.T) {
+	report := &rpslsapi.RuleGraphReport{
+		Valid:   false,
+		Choices: 2,
+		Issues: []rpslsapi.RuleIssue{
+			{
+				Kind:      rpslsapi.MissingRule,
+				Severity:  rpslsapi.SeverityError,
+				ChoiceIDs: []string{"rpsls-rock", "rpsls-paper"},
+				Message:   "rock and paper have no BEATS relationship",
+			},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		reportFromService *rpslsapi.RuleGraphReport
+		serviceError      error
+		expectedReport    *rpslsapi.RuleGraphReport
+		expectedStatus    int
+	}{
+		{
+			name:              "success: return report",
+			reportFromService: report,
+			expectedReport:    report,
+			expectedStatus:    http.StatusOK,
+		},
+		{
+			name:           "failure: if an unknown error happens, return 500",
+			serviceError:   errors.New("unknown error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	serviceMock := ChoiceServiceMock{}
+	router := NewRouter(Handlers{Choice: NewChoiceHandler(&serviceMock, &TranslationServiceMock{})})
+
+	for _, tc := range testCases {
+		serviceMock.On("ValidateRules", mock.Anything).Return(tc.reportFromService, tc.serviceError).Once()
+
+		req := httptest.NewRequest("GET", "/rules/validation", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code)
+		if tc.expectedReport != nil {
+			var returnedBody *rpslsapi.RuleGraphReport
+			err := json.Unmarshal(rr.Body.Bytes(), &returnedBody)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedReport, returnedBody)
+		}
+	}
+}
+
+func TestRenderRulesRequest(t *testing.T) {
+	testCases := []struct {
+		name                string
+		url                 string
+		expectedFormat      rpslsapi.GraphFormat
+		serviceError        error
+		expectedStatus      int
+		expectedContentType string
+	}{
+		{
+			name:                "success: render SVG by default",
+			url:                 "/rules/graph",
+			expectedFormat:      rpslsapi.SVGFormat,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "image/svg+xml",
+		},
+		{
+			name:                "success: render DOT",
+			url:                 "/rules/graph?format=dot&ruleset=rps",
+			expectedFormat:      rpslsapi.DOTFormat,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/vnd.graphviz",
+		},
+		{
+			name:                "success: render Mermaid",
+			url:                 "/rules/graph?format=mermaid",
+			expectedFormat:      rpslsapi.MermaidFormat,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:           "failure: if the format is unknown, return 422",
+			url:            "/rules/graph?format=png",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if the ruleset doesn't exist, return 404",
+			url:            "/rules/graph?ruleset=missing",
+			expectedFormat: rpslsapi.SVGFormat,
+			serviceError:   rpslsapi.ErrRulesetNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := ChoiceServiceMock{}
+		router := NewRouter(Handlers{Choice: NewChoiceHandler(&serviceMock, &TranslationServiceMock{})})
+		serviceMock.On("RenderRules", mock.Anything, tc.expectedFormat).Return([]byte("rendered"), tc.serviceError)
+
+		req := httptest.NewRequest("GET", tc.url, nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedContentType != "" {
+			require.Equal(t, tc.expectedContentType, rr.Header().Get("Content-Type"), tc.name)
+			require.Equal(t, "rendered", rr.Body.String(), tc.name)
+		}
+	}
+}
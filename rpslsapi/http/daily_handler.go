@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"rpsls/rpslsapi"
+)
+
+type DailyHandler struct {
+	service rpslsapi.DailyService
+}
+
+func NewDailyHandler(dailyService rpslsapi.DailyService) DailyHandler {
+	return DailyHandler{service: dailyService}
+}
+
+func (dh *DailyHandler) addRoutes(r chi.Router) {
+	r.Use(requireUser)
+	r.Get("/", dh.handleGetChallenge)
+	r.Post("/play", dh.handlePlay)
+}
+
+func (dh *DailyHandler) handleGetChallenge(w http.ResponseWriter, r *http.Request) {
+	challenge, err := dh.service.Challenge(userID(r), r.URL.Query().Get("date"))
+	if err != nil {
+		writeServiceError(err, w, r, "getDailyChallenge")
+		return
+	}
+
+	writeJsonResponse(challenge, http.StatusOK, w, r, "getDailyChallenge")
+}
+
+func (dh *DailyHandler) handlePlay(w http.ResponseWriter, r *http.Request) {
+	var play rpslsapi.DailyPlay
+	if !decodeJsonBody(&play, w, r, "playDailyChallenge") {
+		return
+	}
+	play.RequestID = middleware.GetReqID(r.Context())
+
+	challenge, err := dh.service.Play(userID(r), &play)
+	if err != nil {
+		writeServiceError(err, w, r, "playDailyChallenge")
+		return
+	}
+
+	writeJsonResponse(challenge, http.StatusOK, w, r, "playDailyChallenge")
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type DailyServiceMock struct {
+	mock.Mock
+}
+
+func (dsm *DailyServiceMock) Challenge(userID, date string) (*rpslsapi.DailyChallenge, error) {
+	args := dsm.Called(userID, date)
+	return args.Get(0).(*rpslsapi.DailyChallenge), args.Error(1)
+}
+
+func (dsm *DailyServiceMock) Play(userID string, play *rpslsapi.DailyPlay) (*rpslsapi.DailyChallenge, error) {
+	args := dsm.Called(userID, play)
+	return args.Get(0).(*rpslsapi.DailyChallenge), args.Error(1)
+}
+
+var dailyChallenge = &rpslsapi.DailyChallenge{
+	Date:        "2026-10-18",
+	Ruleset:     "rpsls",
+	Rounds:      10,
+	Leaderboard: []rpslsapi.DailyScore{{Player: "ada", Score: 14, Wins: 6, Ties: 2, Losses: 2}},
+}
+
+func TestGetDailyChallengeRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		date           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the challenge of today",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "success: return the challenge of the date",
+			query:          "?date=2026-10-17",
+			date:           "2026-10-17",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the date is invalid, return 422",
+			query:          "?date=tomorrow",
+			date:           "tomorrow",
+			serviceError:   rpslsapi.ErrInvalidDailyDate,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := DailyServiceMock{}
+		router := NewRouter(Handlers{Daily: NewDailyHandler(&serviceMock), Auth: newTestAuthHandler()})
+		serviceMock.On("Challenge", "user", tc.date).Return(dailyChallenge, tc.serviceError)
+
+		req := authorize(httptest.NewRequest("GET", "/daily"+tc.query, nil))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedStatus == http.StatusOK {
+			var returnedBody *rpslsapi.DailyChallenge
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody), tc.name)
+			require.Equal(t, dailyChallenge, returnedBody, tc.name)
+		}
+	}
+}
+
+func TestPlayDailyChallengeRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the challenge with the attempt",
+			requestBody:    "{\"player\": \"ada\", \"moves\": [\"rpsls-rock\"]}",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the user already played today, return 409",
+			requestBody:    "{\"player\": \"ada\", \"moves\": [\"rpsls-rock\"]}",
+			serviceError:   rpslsapi.ErrAlreadyPlayedDaily,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failure: if moves are missing, return 422",
+			requestBody:    "{\"player\": \"ada\", \"moves\": [\"rpsls-rock\"]}",
+			serviceError:   rpslsapi.ErrInvalidDailyPlay,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if a bad body is sent, return 422",
+			requestBody:    "{\"moves\": \"rpsls-rock\"}",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := DailyServiceMock{}
+		router := NewRouter(Handlers{Daily: NewDailyHandler(&serviceMock), Auth: newTestAuthHandler()})
+		serviceMock.On("Play", "user", mock.Anything).Return(dailyChallenge, tc.serviceError)
+
+		req := authorize(httptest.NewRequest("POST", "/daily/play", bytes.NewBufferString(tc.requestBody)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedStatus == http.StatusOK {
+			play := serviceMock.Calls[0].Arguments.Get(1).(*rpslsapi.DailyPlay)
+			require.Equal(t, []string{"rpsls-rock"}, play.Moves, tc.name)
+			require.NotEmpty(t, play.RequestID, tc.name)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"rpsls/rpslsapi"
+)
+
+type FreeForAllHandler struct {
+	service rpslsapi.FreeForAllService
+}
+
+func NewFreeForAllHandler(freeForAllService rpslsapi.FreeForAllService) FreeForAllHandler {
+	return FreeForAllHandler{service: freeForAllService}
+}
+
+func (fh *FreeForAllHandler) addRoutes(r chi.Router) {
+	r.Post("/", fh.handlePlay)
+}
+
+func (fh *FreeForAllHandler) handlePlay(w http.ResponseWriter, r *http.Request) {
+	var settings rpslsapi.FreeForAllSettings
+	if !decodeJsonBody(&settings, w, r, "playFreeForAll") {
+		return
+	}
+
+	results, err := fh.service.Play(&settings)
+	if err != nil {
+		writeServiceError(err, w, r, "playFreeForAll")
+		return
+	}
+
+	writeJsonResponse(results, http.StatusOK, w, r, "playFreeForAll")
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type FreeForAllServiceMock struct {
+	mock.Mock
+}
+
+func (fsm *FreeForAllServiceMock) Play(settings *rpslsapi.FreeForAllSettings) (*rpslsapi.FreeForAllResults, error) {
+	args := fsm.Called(settings)
+	return args.Get(0).(*rpslsapi.FreeForAllResults), args.Error(1)
+}
+
+const freeForAllBody = "{\"scoring\": \"elimination\", \"players\": [{\"name\": \"ann\", \"choice\": \"rpsls-rock\"}]}"
+
+func TestPlayFreeForAllRequest(t *testing.T) {
+	results := &rpslsapi.FreeForAllResults{Ruleset: "rpsls", Scoring: rpslsapi.EliminationScoring,
+		Standings: []rpslsapi.FreeForAllStanding{{Rank: 1, Player: "ann", Wins: 2,
+			Results: []rpslsapi.FreeForAllOutcome{{Opponent: "bob", Results: string(rpslsapi.Win)}}}},
+		Winners: []string{"ann"}}
+
+	testCases := []struct {
+		name           string
+		requestBody    string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the result table",
+			requestBody:    freeForAllBody,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the round is invalid, return 422",
+			requestBody:    freeForAllBody,
+			serviceError:   rpslsapi.ErrInvalidFreeForAll,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if a choice doesn't exist, return 404",
+			requestBody:    freeForAllBody,
+			serviceError:   rpslsapi.ErrChoiceNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failure: if a bad body is sent, return 422",
+			requestBody:    "{\"players\": 1",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := FreeForAllServiceMock{}
+		router := NewRouter(Handlers{FreeForAll: NewFreeForAllHandler(&serviceMock)})
+		serviceMock.On("Play", &rpslsapi.FreeForAllSettings{Scoring: rpslsapi.EliminationScoring,
+			Players: []rpslsapi.FreeForAllPlayer{{Name: "ann", Choice: "rpsls-rock"}}}).Return(results, tc.serviceError)
+
+		req := httptest.NewRequest("POST", "/free-for-all", bytes.NewBufferString(tc.requestBody))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedStatus == http.StatusOK {
+			var returnedBody *rpslsapi.FreeForAllResults
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody), tc.name)
+			require.Equal(t, results, returnedBody, tc.name)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"rpsls/rpslsapi"
+)
+
+// historyDateLayout is accepted for the from and to parameters along with RFC 3339 times, as midnight UTC
+const historyDateLayout = "2006-01-02"
+
+type HistoryHandler struct {
+	service rpslsapi.HistoryService
+}
+
+func NewHistoryHandler(historyService rpslsapi.HistoryService) HistoryHandler {
+	return HistoryHandler{service: historyService}
+}
+
+func (hh *HistoryHandler) addRoutes(r chi.Router) {
+	r.Use(requireUser)
+	r.Get("/", hh.handleGetHistory)
+}
+
+func (hh *HistoryHandler) handleGetHistory(w http.ResponseWriter, r *http.Request) {
+	request, err := historyRequest(r.URL.Query())
+	if err != nil {
+		writeServiceError(err, w, r, "getHistory")
+		return
+	}
+
+	page, err := hh.service.History(userID(r), request)
+	if err != nil {
+		writeServiceError(err, w, r, "getHistory")
+		return
+	}
+
+	writeJsonResponse(page, http.StatusOK, w, r, "getHistory")
+}
+
+// historyRequest reads the ruleset, from, to, cursor and limit parameters, all of them being optional
+func historyRequest(query url.Values) (*rpslsapi.HistoryRequest, error) {
+	request := &rpslsapi.HistoryRequest{Ruleset: query.Get("ruleset"), Cursor: query.Get("cursor")}
+	var err error
+	if request.From, err = historyTime(query.Get("from")); err != nil {
+		return nil, err
+	}
+	if request.To, err = historyTime(query.Get("to")); err != nil {
+		return nil, err
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if request.Limit, err = strconv.Atoi(limit); err != nil || request.Limit == 0 {
+			return nil, rpslsapi.ErrInvalidHistoryQuery
+		}
+	}
+	return request, nil
+}
+
+func historyTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse(historyDateLayout, value); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, rpslsapi.ErrInvalidHistoryQuery
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type HistoryServiceMock struct {
+	mock.Mock
+}
+
+func (hsm *HistoryServiceMock) Record(userID string, round *rpslsapi.RoundResults, series string) error {
+	args := hsm.Called(userID, round, series)
+	return args.Error(0)
+}
+
+func (hsm *HistoryServiceMock) History(userID string,
+	request *rpslsapi.HistoryRequest) (*rpslsapi.HistoryPage, error) {
+	args := hsm.Called(userID, request)
+	return args.Get(0).(*rpslsapi.HistoryPage), args.Error(1)
+}
+
+func TestGetHistoryRequest(t *testing.T) {
+	page := &rpslsapi.HistoryPage{
+		Rounds: []rpslsapi.HistoryRound{{ID: "round", PlayedAt: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+			Ruleset: "rpsls", Player: "rpsls-rock", Computer: "rpsls-rock", Results: string(rpslsapi.Tie)}},
+		NextCursor: "cursor",
+	}
+
+	testCases := []struct {
+		name            string
+		query           string
+		expectedRequest *rpslsapi.HistoryRequest
+		serviceError    error
+		expectedStatus  int
+	}{
+		{
+			name:  "success: return the page",
+			query: "?ruleset=rpsls&from=2021-06-01&to=2021-06-02T10:00:00%2B02:00&cursor=cursor&limit=10",
+			expectedRequest: &rpslsapi.HistoryRequest{Ruleset: "rpsls", Cursor: "cursor", Limit: 10,
+				From: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2021, 6, 2, 8, 0, 0, 0, time.UTC)},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:            "success: return the first page without parameters",
+			expectedRequest: &rpslsapi.HistoryRequest{},
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:            "failure: if the service rejects the query, return 422",
+			query:           "?cursor=bad",
+			expectedRequest: &rpslsapi.HistoryRequest{Cursor: "bad"},
+			serviceError:    rpslsapi.ErrInvalidHistoryQuery,
+			expectedStatus:  http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if a date is malformed, return 422",
+			query:          "?from=yesterday",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if the limit isn't a number, return 422",
+			query:          "?limit=all",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := HistoryServiceMock{}
+		router := NewRouter(Handlers{History: NewHistoryHandler(&serviceMock), Auth: newTestAuthHandler()})
+		serviceMock.On("History", "user", mock.Anything).Return(page, tc.serviceError)
+
+		req := authorize(httptest.NewRequest("GET", "/history"+tc.query, nil))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedRequest == nil {
+			serviceMock.AssertNotCalled(t, "History", mock.Anything, mock.Anything)
+			continue
+		}
+		request := serviceMock.Calls[0].Arguments.Get(1).(*rpslsapi.HistoryRequest)
+		require.True(t, tc.expectedRequest.From.Equal(request.From), tc.name)
+		require.True(t, tc.expectedRequest.To.Equal(request.To), tc.name)
+		request.From, request.To = tc.expectedRequest.From, tc.expectedRequest.To
+		require.Equal(t, tc.expectedRequest, request, tc.name)
+		if tc.serviceError == nil {
+			var returnedBody *rpslsapi.HistoryPage
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody), tc.name)
+			require.Equal(t, page, returnedBody, tc.name)
+		}
+	}
+}
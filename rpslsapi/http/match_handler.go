@@ -0,0 +1,84 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"rpsls/rpslsapi"
+)
+
+// playerTokenHeader carries the token returned to a player when creating or joining a match
+const playerTokenHeader = "X-Player-Token"
+
+type MatchHandler struct {
+	service rpslsapi.MatchService
+}
+
+type MatchSettings struct {
+	Ruleset string `json:"ruleset"`
+}
+
+type Move struct {
+	Choice string `json:"choice"`
+}
+
+func NewMatchHandler(matchService rpslsapi.MatchService) MatchHandler {
+	return MatchHandler{service: matchService}
+}
+
+func (mh *MatchHandler) addRoutes(r chi.Router) {
+	r.Post("/", mh.handleCreate)
+	r.Get("/{id}", mh.handleGet)
+	r.Post("/{id}/join", mh.handleJoin)
+	r.Post("/{id}/moves", mh.handleMove)
+}
+
+func (mh *MatchHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var settings MatchSettings
+	if !decodeJsonBody(&settings, w, r, "createMatch") {
+		return
+	}
+
+	match, err := mh.service.CreateMatch(settings.Ruleset)
+	if err != nil {
+		writeServiceError(err, w, r, "createMatch")
+		return
+	}
+
+	writeJsonResponse(match, http.StatusCreated, w, r, "createMatch")
+}
+
+func (mh *MatchHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	match, err := mh.service.Match(chi.URLParam(r, "id"), r.Header.Get(playerTokenHeader))
+	if err != nil {
+		writeServiceError(err, w, r, "getMatch")
+		return
+	}
+
+	writeJsonResponse(match, http.StatusOK, w, r, "getMatch")
+}
+
+func (mh *MatchHandler) handleJoin(w http.ResponseWriter, r *http.Request) {
+	match, err := mh.service.JoinMatch(chi.URLParam(r, "id"))
+	if err != nil {
+		writeServiceError(err, w, r, "joinMatch")
+		return
+	}
+
+	writeJsonResponse(match, http.StatusOK, w, r, "joinMatch")
+}
+
+func (mh *MatchHandler) handleMove(w http.ResponseWriter, r *http.Request) {
+	var move Move
+	if !decodeJsonBody(&move, w, r, "moveInMatch") {
+		return
+	}
+
+	match, err := mh.service.Move(chi.URLParam(r, "id"), r.Header.Get(playerTokenHeader), move.Choice)
+	if err != nil {
+		writeServiceError(err, w, r, "moveInMatch")
+		return
+	}
+
+	writeJsonResponse(match, http.StatusOK, w, r, "moveInMatch")
+}
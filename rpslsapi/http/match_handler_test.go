@@ -0,0 +1,168 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type MatchServiceMock struct {
+	mock.Mock
+}
+
+func (msm *MatchServiceMock) CreateMatch(ruleset string) (*rpslsapi.Match, error) {
+	args := msm.Called(ruleset)
+	return args.Get(0).(*rpslsapi.Match), args.Error(1)
+}
+
+func (msm *MatchServiceMock) JoinMatch(id string) (*rpslsapi.Match, error) {
+	args := msm.Called(id)
+	return args.Get(0).(*rpslsapi.Match), args.Error(1)
+}
+
+func (msm *MatchServiceMock) Match(id, token string) (*rpslsapi.Match, error) {
+	args := msm.Called(id, token)
+	return args.Get(0).(*rpslsapi.Match), args.Error(1)
+}
+
+func (msm *MatchServiceMock) Move(id, token, choiceID string) (*rpslsapi.Match, error) {
+	args := msm.Called(id, token, choiceID)
+	return args.Get(0).(*rpslsapi.Match), args.Error(1)
+}
+
+func (msm *MatchServiceMock) Abandon(id, token string) (*rpslsapi.Match, error) {
+	args := msm.Called(id, token)
+	return args.Get(0).(*rpslsapi.Match), args.Error(1)
+}
+
+var waitingMatch = &rpslsapi.Match{
+	ID:      "match",
+	Ruleset: "rpsls",
+	Status:  rpslsapi.MatchWaiting,
+	Host:    &rpslsapi.MatchPlayer{Token: "host"},
+}
+
+func TestCreateMatchRequest(t *testing.T) {
+	serviceMock := MatchServiceMock{}
+	router := NewRouter(Handlers{Match: NewMatchHandler(&serviceMock)})
+	serviceMock.On("CreateMatch", "rpsls").Return(waitingMatch, nil)
+
+	req := httptest.NewRequest("POST", "/matches", bytes.NewBufferString("{\"ruleset\": \"rpsls\"}"))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var returnedBody *rpslsapi.Match
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody))
+	require.Equal(t, waitingMatch.ID, returnedBody.ID)
+	require.Equal(t, "host", returnedBody.Host.Token)
+}
+
+func TestGetMatchRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		token          string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the match as seen by the player",
+			token:          "host",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "success: return the match to spectators",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the match doesn't exist, return 404",
+			serviceError:   rpslsapi.ErrMatchNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := MatchServiceMock{}
+		router := NewRouter(Handlers{Match: NewMatchHandler(&serviceMock)})
+		serviceMock.On("Match", "match", tc.token).Return(waitingMatch, tc.serviceError)
+
+		req := httptest.NewRequest("GET", "/matches/match", nil)
+		if tc.token != "" {
+			req.Header.Set(playerTokenHeader, tc.token)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		serviceMock.AssertExpectations(t)
+	}
+}
+
+func TestMoveInMatchRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the match",
+			requestBody:    "{\"choice\": \"rpsls-rock\"}",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the token doesn't belong to a player, return 403",
+			requestBody:    "{\"choice\": \"rpsls-rock\"}",
+			serviceError:   rpslsapi.ErrNotMatchPlayer,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "failure: if the player already moved, return 409",
+			requestBody:    "{\"choice\": \"rpsls-rock\"}",
+			serviceError:   rpslsapi.ErrAlreadyMoved,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failure: if the match is over, return 409",
+			requestBody:    "{\"choice\": \"rpsls-rock\"}",
+			serviceError:   rpslsapi.ErrMatchClosed,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failure: if a bad body is sent, return 422",
+			requestBody:    "{\"choice\": 1",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := MatchServiceMock{}
+		router := NewRouter(Handlers{Match: NewMatchHandler(&serviceMock)})
+		serviceMock.On("Move", "match", "host", "rpsls-rock").Return(waitingMatch, tc.serviceError)
+
+		req := httptest.NewRequest("POST", "/matches/match/moves", bytes.NewBufferString(tc.requestBody))
+		req.Header.Set(playerTokenHeader, "host")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+	}
+}
+
+func TestJoinMatchRequest(t *testing.T) {
+	serviceMock := MatchServiceMock{}
+	router := NewRouter(Handlers{Match: NewMatchHandler(&serviceMock)})
+	serviceMock.On("JoinMatch", "match").Return(waitingMatch, rpslsapi.ErrMatchFull)
+
+	req := httptest.NewRequest("POST", "/matches/match/join", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusConflict, rr.Code)
+}
@@ -0,0 +1,295 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+	"rpsls/rpslsapi"
+	"rpsls/rpslsapi/logger"
+)
+
+// errUnknownMessage is sent back for messages whose type isn't one of the matchmaking ones
+var errUnknownMessage = errors.New("unknown message type, expected join, resume, move, forfeit or leave")
+
+const (
+	// pingPeriod is how often connections are pinged, which must be less than pongWait
+	pingPeriod = 20 * time.Second
+	// pongWait is how long a connection may stay silent, pongs included, before it is considered lost
+	pongWait = 30 * time.Second
+	// writeWait is how long writing a message may take
+	writeWait = 10 * time.Second
+	// maxMessageSize bounds the size of the messages sent by clients
+	maxMessageSize = 1024
+)
+
+type MatchmakingHandler struct {
+	service  rpslsapi.MatchmakingService
+	upgrader websocket.Upgrader
+}
+
+// MatchmakingMessage is sent by clients over the matchmaking WebSocket. Join queues the client with the ruleset,
+// rating and band of a rpslsapi.MatchmakingRequest, resume binds the connection to a paired match with the token of
+// the client, move submits a choice in the match and forfeit abandons it. Leave leaves the queue or a match that is
+// over.
+type MatchmakingMessage struct {
+	Type    string `json:"type"`
+	Ruleset string `json:"ruleset,omitempty"`
+	Rating  int    `json:"rating,omitempty"`
+	Band    int    `json:"band,omitempty"`
+	Match   string `json:"match,omitempty"`
+	Token   string `json:"token,omitempty"`
+	Choice  string `json:"choice,omitempty"`
+}
+
+// MatchmakingReply answers a MatchmakingMessage: queued, moved, forfeited, left or error. Match events are pushed
+// as rpslsapi.MatchEvent.
+type MatchmakingReply struct {
+	Type  string          `json:"type"`
+	Match *rpslsapi.Match `json:"match,omitempty"`
+	Error *ErrorResponse  `json:"error,omitempty"`
+}
+
+func NewMatchmakingHandler(matchmakingService rpslsapi.MatchmakingService) MatchmakingHandler {
+	return MatchmakingHandler{service: matchmakingService, upgrader: websocket.Upgrader{CheckOrigin: checkOrigin}}
+}
+
+func (mh *MatchmakingHandler) addRoutes(r chi.Router) {
+	r.Get("/", mh.handleConnect)
+}
+
+func (mh *MatchmakingHandler) handleConnect(w http.ResponseWriter, r *http.Request) {
+	conn, err := mh.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already wrote the error response
+		logger.WithReqIdAndAction(log.Debug().Err(err), r, "matchmaking").Msg("failed to upgrade connection")
+		return
+	}
+
+	connection := &matchmakingConnection{
+		service:  mh.service,
+		conn:     conn,
+		request:  r,
+		outbound: make(chan interface{}),
+		done:     make(chan struct{}),
+	}
+	go connection.writeLoop()
+	connection.readLoop()
+}
+
+// matchmakingConnection serves a client, who holds at most one ticket at a time. Only the read loop handles the
+// ticket, while the write loop is the only one writing to the connection.
+type matchmakingConnection struct {
+	service  rpslsapi.MatchmakingService
+	conn     *websocket.Conn
+	request  *http.Request
+	ticket   *rpslsapi.MatchmakingTicket
+	outbound chan interface{}
+	// done is closed once the client is gone, stopping the write loop and the forwarding of events
+	done chan struct{}
+}
+
+// readLoop handles the messages of the client until the connection is closed or lost, then disconnects its ticket
+func (mc *matchmakingConnection) readLoop() {
+	defer func() {
+		close(mc.done)
+		if mc.ticket != nil {
+			mc.service.Disconnect(mc.ticket.ID)
+		}
+		mc.conn.Close()
+	}()
+
+	mc.conn.SetReadLimit(maxMessageSize)
+	_ = mc.conn.SetReadDeadline(time.Now().Add(pongWait))
+	mc.conn.SetPongHandler(func(string) error {
+		return mc.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		_, data, err := mc.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.WithReqIdAndAction(log.Debug().Err(err), mc.request, "matchmaking").
+					Msg("matchmaking connection lost")
+			}
+			return
+		}
+		_ = mc.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		var message MatchmakingMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			mc.push(MatchmakingReply{Type: "error", Error: &ErrorResponse{Code: UnprocessableBody,
+				Message: err.Error()}})
+			continue
+		}
+		mc.handle(&message)
+	}
+}
+
+func (mc *matchmakingConnection) handle(message *MatchmakingMessage) {
+	var err error
+	switch message.Type {
+	case "join":
+		err = mc.join(message)
+	case "resume":
+		err = mc.resume(message)
+	case "move":
+		err = mc.move(message)
+	case "forfeit":
+		err = mc.forfeit()
+	case "leave":
+		err = mc.leave()
+	default:
+		mc.push(MatchmakingReply{Type: "error", Error: &ErrorResponse{Code: InvalidEntity,
+			Message: errUnknownMessage.Error()}})
+		return
+	}
+	if err != nil {
+		mc.pushServiceError(err, message.Type)
+	}
+}
+
+func (mc *matchmakingConnection) join(message *MatchmakingMessage) error {
+	if err := mc.dropTicket(); err != nil {
+		return err
+	}
+	ticket, err := mc.service.Enqueue(&rpslsapi.MatchmakingRequest{Ruleset: message.Ruleset,
+		Rating: message.Rating, Band: message.Band})
+	if err != nil {
+		return err
+	}
+	// the reply goes out before the events of the ticket, which may already hold match_found
+	mc.push(MatchmakingReply{Type: "queued"})
+	mc.follow(ticket)
+	return nil
+}
+
+func (mc *matchmakingConnection) resume(message *MatchmakingMessage) error {
+	if err := mc.dropTicket(); err != nil {
+		return err
+	}
+	ticket, err := mc.service.Resume(message.Match, message.Token)
+	if err != nil {
+		return err
+	}
+	mc.follow(ticket)
+	return nil
+}
+
+func (mc *matchmakingConnection) move(message *MatchmakingMessage) error {
+	if mc.ticket == nil {
+		return rpslsapi.ErrTicketNotFound
+	}
+	match, err := mc.service.Move(mc.ticket.ID, message.Choice)
+	if err != nil {
+		return err
+	}
+	mc.push(MatchmakingReply{Type: "moved", Match: match})
+	return nil
+}
+
+func (mc *matchmakingConnection) forfeit() error {
+	if mc.ticket == nil {
+		return rpslsapi.ErrTicketNotFound
+	}
+	match, err := mc.service.Forfeit(mc.ticket.ID)
+	if err != nil {
+		return err
+	}
+	mc.push(MatchmakingReply{Type: "forfeited", Match: match})
+	return nil
+}
+
+func (mc *matchmakingConnection) leave() error {
+	if mc.ticket == nil {
+		return rpslsapi.ErrTicketNotFound
+	}
+	if err := mc.dropTicket(); err != nil {
+		return err
+	}
+	mc.push(MatchmakingReply{Type: "left"})
+	return nil
+}
+
+// dropTicket leaves the queue or the match that is over before the client joins or resumes another one. A ticket
+// taken over by another connection is already gone.
+func (mc *matchmakingConnection) dropTicket() error {
+	if mc.ticket == nil {
+		return nil
+	}
+	if err := mc.service.Leave(mc.ticket.ID); err != nil && err != rpslsapi.ErrTicketNotFound {
+		return err
+	}
+	mc.ticket = nil
+	return nil
+}
+
+// follow forwards the events of the ticket to the client until the ticket is dropped or the client is gone
+func (mc *matchmakingConnection) follow(ticket *rpslsapi.MatchmakingTicket) {
+	mc.ticket = ticket
+	go func() {
+		for event := range ticket.Events {
+			mc.push(event)
+		}
+	}()
+}
+
+func (mc *matchmakingConnection) push(message interface{}) {
+	select {
+	case mc.outbound <- message:
+	case <-mc.done:
+	}
+}
+
+func (mc *matchmakingConnection) pushServiceError(err error, action string) {
+	statusCode, response := serviceErrorResponse(err, action)
+	mc.push(MatchmakingReply{Type: "error", Error: &response})
+	if statusCode == http.StatusInternalServerError {
+		logger.WithReqIdAndAction(log.Error().Stack().Err(err), mc.request, "matchmaking").
+			Msg(action + " failed")
+	}
+}
+
+// writeLoop writes the outbound messages and pings the client, closing the connection if a write fails so the read
+// loop stops as well
+func (mc *matchmakingConnection) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case message := <-mc.outbound:
+			_ = mc.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := mc.conn.WriteJSON(message); err != nil {
+				mc.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			if err := mc.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				mc.conn.Close()
+				return
+			}
+		case <-mc.done:
+			return
+		}
+	}
+}
+
+// checkOrigin accepts connections from the origin serving the API or from the allowed origins, and from clients
+// that aren't browsers, which don't send an origin
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range allowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+	originURL, err := url.Parse(origin)
+	return err == nil && originURL.Host == r.Host
+}
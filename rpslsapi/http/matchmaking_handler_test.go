@@ -0,0 +1,196 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type MatchmakingServiceMock struct {
+	mock.Mock
+}
+
+func (msm *MatchmakingServiceMock) Enqueue(request *rpslsapi.MatchmakingRequest) (*rpslsapi.MatchmakingTicket,
+	error) {
+	args := msm.Called(request)
+	return args.Get(0).(*rpslsapi.MatchmakingTicket), args.Error(1)
+}
+
+func (msm *MatchmakingServiceMock) Resume(matchID, token string) (*rpslsapi.MatchmakingTicket, error) {
+	args := msm.Called(matchID, token)
+	return args.Get(0).(*rpslsapi.MatchmakingTicket), args.Error(1)
+}
+
+func (msm *MatchmakingServiceMock) Move(ticketID, choiceID string) (*rpslsapi.Match, error) {
+	args := msm.Called(ticketID, choiceID)
+	return args.Get(0).(*rpslsapi.Match), args.Error(1)
+}
+
+func (msm *MatchmakingServiceMock) Forfeit(ticketID string) (*rpslsapi.Match, error) {
+	args := msm.Called(ticketID)
+	return args.Get(0).(*rpslsapi.Match), args.Error(1)
+}
+
+func (msm *MatchmakingServiceMock) Leave(ticketID string) error {
+	args := msm.Called(ticketID)
+	return args.Error(0)
+}
+
+func (msm *MatchmakingServiceMock) Disconnect(ticketID string) {
+	msm.Called(ticketID)
+}
+
+func newMatchmakingServer(serviceMock *MatchmakingServiceMock) *httptest.Server {
+	return httptest.NewServer(NewRouter(Handlers{Matchmaking: NewMatchmakingHandler(serviceMock)}))
+}
+
+func dialMatchmaking(t *testing.T, server *httptest.Server) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/matchmaking", nil)
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	return conn
+}
+
+func TestMatchmakingJoin(t *testing.T) {
+	events := make(chan rpslsapi.MatchEvent, 1)
+	match := &rpslsapi.Match{ID: "match", Ruleset: "rpsls", Status: rpslsapi.MatchPlaying,
+		Host: &rpslsapi.MatchPlayer{Token: "host"}, Guest: &rpslsapi.MatchPlayer{}}
+	events <- rpslsapi.MatchEvent{Type: rpslsapi.MatchFound, Match: match, Side: rpslsapi.Host}
+	disconnected := make(chan struct{})
+
+	serviceMock := MatchmakingServiceMock{}
+	serviceMock.On("Enqueue", &rpslsapi.MatchmakingRequest{Ruleset: "rpsls", Rating: 1200, Band: 100}).
+		Return(&rpslsapi.MatchmakingTicket{ID: "ticket", Events: events}, nil)
+	serviceMock.On("Move", "ticket", "rpsls-rock").Return(match, nil)
+	serviceMock.On("Disconnect", "ticket").Run(func(mock.Arguments) { close(disconnected) })
+	server := newMatchmakingServer(&serviceMock)
+	defer server.Close()
+	conn := dialMatchmaking(t, server)
+
+	require.NoError(t, conn.WriteJSON(MatchmakingMessage{Type: "join", Ruleset: "rpsls", Rating: 1200, Band: 100}))
+	var reply MatchmakingReply
+	require.NoError(t, conn.ReadJSON(&reply))
+	require.Equal(t, MatchmakingReply{Type: "queued"}, reply)
+	var event rpslsapi.MatchEvent
+	require.NoError(t, conn.ReadJSON(&event))
+	require.Equal(t, rpslsapi.MatchEvent{Type: rpslsapi.MatchFound, Match: match, Side: rpslsapi.Host}, event)
+
+	require.NoError(t, conn.WriteJSON(MatchmakingMessage{Type: "move", Choice: "rpsls-rock"}))
+	require.NoError(t, conn.ReadJSON(&reply))
+	require.Equal(t, MatchmakingReply{Type: "moved", Match: match}, reply)
+
+	require.NoError(t, conn.Close())
+	select {
+	case <-disconnected:
+	case <-time.After(time.Second):
+		require.FailNow(t, "the ticket wasn't disconnected")
+	}
+}
+
+func TestMatchmakingErrors(t *testing.T) {
+	testCases := []struct {
+		name         string
+		message      string
+		serviceError error
+		expectedCode ErrorCode
+	}{
+		{
+			name:         "failure: if a malformed message is sent, reply with an unprocessable body error",
+			message:      "{\"type\": 1",
+			expectedCode: UnprocessableBody,
+		},
+		{
+			name:         "failure: if the message type is unknown, reply with an invalid entity error",
+			message:      "{\"type\": \"dance\"}",
+			expectedCode: InvalidEntity,
+		},
+		{
+			name:         "failure: if the request is invalid, reply with an invalid entity error",
+			message:      "{\"type\": \"join\", \"rating\": -1}",
+			serviceError: rpslsapi.ErrInvalidMatchmaking,
+			expectedCode: InvalidEntity,
+		},
+		{
+			name:         "failure: if the ruleset doesn't exist, reply with a not found error",
+			message:      "{\"type\": \"join\", \"ruleset\": \"chess\"}",
+			serviceError: rpslsapi.ErrRulesetNotFound,
+			expectedCode: EntityNotFound,
+		},
+		{
+			name:         "failure: if the client moves before joining, reply with a not found error",
+			message:      "{\"type\": \"move\", \"choice\": \"rpsls-rock\"}",
+			expectedCode: EntityNotFound,
+		},
+		{
+			name:         "failure: if the match to resume isn't played by the client, reply with a forbidden error",
+			message:      "{\"type\": \"resume\", \"match\": \"match\", \"token\": \"spectator\"}",
+			serviceError: rpslsapi.ErrNotMatchPlayer,
+			expectedCode: Forbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := MatchmakingServiceMock{}
+		serviceMock.On("Enqueue", mock.Anything).Return((*rpslsapi.MatchmakingTicket)(nil), tc.serviceError)
+		serviceMock.On("Resume", mock.Anything, mock.Anything).
+			Return((*rpslsapi.MatchmakingTicket)(nil), tc.serviceError)
+		server := newMatchmakingServer(&serviceMock)
+		conn := dialMatchmaking(t, server)
+
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(tc.message)), tc.name)
+		var reply MatchmakingReply
+		require.NoError(t, conn.ReadJSON(&reply), tc.name)
+		require.Equal(t, "error", reply.Type, tc.name)
+		require.Equal(t, tc.expectedCode, reply.Error.Code, tc.name)
+
+		conn.Close()
+		server.Close()
+	}
+}
+
+func TestMatchmakingOrigin(t *testing.T) {
+	testCases := []struct {
+		name           string
+		origin         string
+		expectedStatus int
+	}{
+		{
+			name:           "success: accept clients that aren't browsers",
+			expectedStatus: http.StatusSwitchingProtocols,
+		},
+		{
+			name:           "success: accept the allowed origins",
+			origin:         "https://codechallenge.boohma.com",
+			expectedStatus: http.StatusSwitchingProtocols,
+		},
+		{
+			name:           "failure: if the origin isn't allowed, return 403",
+			origin:         "https://example.com",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	server := newMatchmakingServer(&MatchmakingServiceMock{})
+	defer server.Close()
+	for _, tc := range testCases {
+		header := http.Header{}
+		if tc.origin != "" {
+			header.Set("Origin", tc.origin)
+		}
+
+		conn, resp, _ := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/matchmaking",
+			header)
+
+		require.Equal(t, tc.expectedStatus, resp.StatusCode, tc.name)
+		if conn != nil {
+			conn.Close()
+		}
+	}
+}
@@ -5,21 +5,24 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
 	"github.com/rs/zerolog/log"
 	"rpsls/rpslsapi"
 	"rpsls/rpslsapi/logger"
 )
 
 type RoundHandler struct {
-	service rpslsapi.RoundService
+	service      rpslsapi.RoundService
+	translations rpslsapi.TranslationService
 }
 
-func NewRoundHandler(roundService rpslsapi.RoundService) RoundHandler {
-	return RoundHandler{service: roundService}
+func NewRoundHandler(roundService rpslsapi.RoundService, translationService rpslsapi.TranslationService) RoundHandler {
+	return RoundHandler{service: roundService, translations: translationService}
 }
 
 func (ch *RoundHandler) addRoutes(r chi.Router) {
 	r.Post("/", ch.handlePlay)
+	r.Post("/commitments", ch.handleCommit)
 }
 
 func (ch *RoundHandler) handlePlay(w http.ResponseWriter, r *http.Request) {
@@ -31,24 +34,26 @@ func (ch *RoundHandler) handlePlay(w http.ResponseWriter, r *http.Request) {
 			Msg("failed to parse request")
 		return
 	}
+	settings.RequestID = middleware.GetReqID(r.Context())
+	settings.UserID = userID(r)
 
 	result, err := ch.service.Play(&settings)
 	if err != nil {
-		if err == rpslsapi.ErrChoiceNotFound {
-			writeJsonResponse(ErrorResponse{Code: EntityNotFound, Message: "choice not found"},
-				http.StatusNotFound, w, r, "playRound")
-			logger.WithReqIdAndAction(log.Debug(), r, "playRound").
-				Int64("id", settings.Player).
-				Msg("choice not found")
-			return
-		}
-
-		writeJsonResponse(ErrorResponse{Code: UnknownError, Message: "failed to play match"},
-			http.StatusInternalServerError, w, r, "playRound")
-		logger.WithReqIdAndAction(log.Error().Stack().Err(err), r, "playRound").
-			Msg("failed to play match")
+		writeServiceError(err, w, r, "playRound")
 		return
 	}
 
-	writeJsonResponse(result, http.StatusOK, w, r, "playRound")
+	translator := negotiateTranslator(ch.translations, w, r, "playRound")
+	writeJsonResponse(translator.Round(result), http.StatusOK, w, r, "playRound")
+}
+
+func (ch *RoundHandler) handleCommit(w http.ResponseWriter, r *http.Request) {
+	commitment, err := ch.service.Commit(userID(r), r.URL.Query().Get("ruleset"),
+		r.URL.Query().Get("strategy"))
+	if err != nil {
+		writeServiceError(err, w, r, "commitRound")
+		return
+	}
+
+	writeJsonResponse(commitment, http.StatusCreated, w, r, "commitRound")
 }
@@ -18,18 +18,28 @@ type RoundServiceMock struct {
 }
 
 func (rsm *RoundServiceMock) Play(settings *rpslsapi.RoundSettings) (*rpslsapi.RoundResults, error) {
-	args := rsm.Called()
+	args := rsm.Called(settings)
 	return args.Get(0).(*rpslsapi.RoundResults), args.Error(1)
 }
 
+func (rsm *RoundServiceMock) Commit(userID, ruleset, strategy string) (*rpslsapi.RoundCommitment, error) {
+	args := rsm.Called(userID, ruleset, strategy)
+	return args.Get(0).(*rpslsapi.RoundCommitment), args.Error(1)
+}
+
 func TestPlayRequest(t *testing.T) {
-	const existingChoiceID = int64(1)
-	const missingChoiceID = int64(2)
+	const existingChoiceID = "rpsls-rock"
+	const missingChoiceID = "rpsls-well"
 
 	results := &rpslsapi.RoundResults{
-		Results:  string(rpslsapi.Win),
-		Player:   existingChoiceID,
-		Computer: 3,
+		Results:        string(rpslsapi.Win),
+		Player:         existingChoiceID,
+		Computer:       "rpsls-scissors",
+		Ruleset:        "rpsls",
+		PlayerChoice:   &rpslsapi.Choice{ID: existingChoiceID, Name: "Rock", Ruleset: "rpsls"},
+		ComputerChoice: &rpslsapi.Choice{ID: "rpsls-scissors", Name: "Scissors", Ruleset: "rpsls"},
+		Action:         "crushes",
+		Description:    "Rock crushes Scissors",
 	}
 
 	testCases := []struct {
@@ -59,6 +69,24 @@ func TestPlayRequest(t *testing.T) {
 			serviceError:   errors.New("unknown error"),
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name:           "failure: if the commitment is unknown, return 404",
+			requestBody:    playRequestBody(existingChoiceID),
+			serviceError:   rpslsapi.ErrCommitmentNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failure: if the strategy is unknown, return 404",
+			requestBody:    playRequestBody(existingChoiceID),
+			serviceError:   rpslsapi.ErrStrategyNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failure: if no rule relates both choices, return 404",
+			requestBody:    playRequestBody(existingChoiceID),
+			serviceError:   rpslsapi.ErrRuleNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
 		{
 			name:           "failure: if a bad body is sent, return 422",
 			requestBody:    []byte("{\"player\": 123"),
@@ -67,10 +95,10 @@ func TestPlayRequest(t *testing.T) {
 	}
 
 	serviceMock := RoundServiceMock{}
-	router := NewRouter(ChoiceHandler{}, NewRoundHandler(&serviceMock), ScoreboardHandler{})
+	router := NewRouter(Handlers{Round: NewRoundHandler(&serviceMock, &TranslationServiceMock{})})
 
 	for _, tc := range testCases {
-		serviceMock.On("Play").Return(tc.resultsFromService, tc.serviceError).Once()
+		serviceMock.On("Play", mock.Anything).Return(tc.resultsFromService, tc.serviceError).Once()
 
 		req := httptest.NewRequest("POST", "/play", bytes.NewBuffer(tc.requestBody))
 		rr := httptest.NewRecorder()
@@ -82,12 +110,50 @@ func TestPlayRequest(t *testing.T) {
 			err := json.Unmarshal(rr.Body.Bytes(), &returnedBody)
 			require.NoError(t, err)
 			require.EqualValues(t, tc.expectedResults, returnedBody)
+			settings := serviceMock.Calls[len(serviceMock.Calls)-1].Arguments.Get(0).(*rpslsapi.RoundSettings)
+			require.NotEmpty(t, settings.RequestID)
 		}
 	}
 }
 
-func playRequestBody(choiceID int64) []byte {
+func playRequestBody(choiceID string) []byte {
 	roundSettings := rpslsapi.RoundSettings{Player: choiceID}
 	body, _ := json.Marshal(roundSettings)
 	return body
 }
+
+func TestCommitRequest(t *testing.T) {
+	commitment := &rpslsapi.RoundCommitment{Token: "token", Ruleset: "rps", Commitment: "hash"}
+	testCases := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the commitment",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "failure: if the ruleset doesn't exist, return 404",
+			serviceError:   rpslsapi.ErrRulesetNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := RoundServiceMock{}
+		router := NewRouter(Handlers{Round: NewRoundHandler(&serviceMock, &TranslationServiceMock{})})
+		serviceMock.On("Commit", "", "rps", "hard").Return(commitment, tc.serviceError)
+
+		req := httptest.NewRequest("POST", "/play/commitments?ruleset=rps&strategy=hard", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.serviceError == nil {
+			var returnedBody *rpslsapi.RoundCommitment
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody), tc.name)
+			require.Equal(t, commitment, returnedBody, tc.name)
+		}
+	}
+}
@@ -7,25 +7,71 @@ import (
 	"github.com/go-chi/render"
 )
 
+// allowedOrigins are the origins browsers may call the API from, besides the one serving it
+var allowedOrigins = []string{"https://codechallenge.boohma.com"}
+
 type Router struct {
 	chi.Router
 }
 
-func NewRouter(choiceHandler ChoiceHandler, roundHandler RoundHandler, scoreboardHandler ScoreboardHandler) Router {
+// Handlers are the handlers mounted by the router. Handlers left out are mounted with their zero value, which is
+// enough for tests exercising other routes.
+type Handlers struct {
+	Choice      ChoiceHandler
+	Round       RoundHandler
+	Scoreboard  ScoreboardHandler
+	Ruleset     RulesetHandler
+	Cache       CacheHandler
+	Translation TranslationHandler
+	Match       MatchHandler
+	Series      SeriesHandler
+	Tournament  TournamentHandler
+	Strategy    StrategyHandler
+	Bot         BotHandler
+	FreeForAll  FreeForAllHandler
+	History     HistoryHandler
+	Audit       AuditHandler
+	Arcade      ArcadeHandler
+	Daily       DailyHandler
+	Simulation  SimulationHandler
+	Matchmaking MatchmakingHandler
+	Auth        AuthHandler
+}
+
+func NewRouter(handlers Handlers) Router {
 	router := chi.NewRouter()
 
 	router.Use(middleware.Heartbeat("/ping"))
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.RequestID)
 	router.Use(cors.Handler(cors.Options{
-		AllowedOrigins: []string{"https://codechallenge.boohma.com"},
+		AllowedOrigins: allowedOrigins,
 		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Origin", "Accept", "Content-Type", "X-Requested-With", "Authorization",
+			playerTokenHeader},
 	}))
+	router.Use(handlers.Auth.authenticate)
 	router.Use(render.SetContentType(render.ContentTypeJSON))
 
-	router.Route("/", choiceHandler.addRoutes)
-	router.Route("/play", roundHandler.addRoutes)
-	router.Route("/scoreboard", scoreboardHandler.addRoutes)
+	router.Route("/", handlers.Choice.addRoutes)
+	router.Route("/play", handlers.Round.addRoutes)
+	router.Route("/scoreboard", handlers.Scoreboard.addRoutes)
+	router.Route("/rulesets", handlers.Ruleset.addRoutes)
+	router.Route("/cache", handlers.Cache.addRoutes)
+	router.Route("/translations", handlers.Translation.addRoutes)
+	router.Route("/matches", handlers.Match.addRoutes)
+	router.Route("/series", handlers.Series.addRoutes)
+	router.Route("/tournaments", handlers.Tournament.addRoutes)
+	router.Route("/strategies", handlers.Strategy.addRoutes)
+	router.Route("/bots", handlers.Bot.addRoutes)
+	router.Route("/free-for-all", handlers.FreeForAll.addRoutes)
+	router.Route("/history", handlers.History.addRoutes)
+	router.Route("/admin/audits", handlers.Audit.addRoutes)
+	router.Route("/arcade", handlers.Arcade.addRoutes)
+	router.Route("/daily", handlers.Daily.addRoutes)
+	router.Route("/simulate", handlers.Simulation.addRoutes)
+	router.Route("/matchmaking", handlers.Matchmaking.addRoutes)
+	router.Route("/auth", handlers.Auth.addRoutes)
 
 	return Router{router}
 }
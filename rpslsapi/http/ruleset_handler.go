@@ -0,0 +1,120 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog/log"
+	"rpsls/rpslsapi"
+	"rpsls/rpslsapi/logger"
+)
+
+var documentContentTypes = map[rpslsapi.DocumentFormat]string{
+	rpslsapi.JSONFormat: "application/json",
+	rpslsapi.YAMLFormat: "application/yaml",
+}
+
+type RulesetHandler struct {
+	service rpslsapi.RulesetService
+}
+
+func NewRulesetHandler(rulesetService rpslsapi.RulesetService) RulesetHandler {
+	return RulesetHandler{service: rulesetService}
+}
+
+func (rh *RulesetHandler) addRoutes(r chi.Router) {
+	r.Get("/", rh.handleList)
+	r.With(requireAdmin).Post("/", rh.handleCreate)
+	r.Get("/{id}", rh.handleGet)
+	r.With(requireAdmin).Delete("/{id}", rh.handleDelete)
+	r.Get("/{id}/export", rh.handleExport)
+	r.With(requireAdmin).Post("/import", rh.handleImport)
+}
+
+func (rh *RulesetHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	rulesets, err := rh.service.Rulesets()
+	if err != nil {
+		writeServiceError(err, w, r, "listRulesets")
+		return
+	}
+
+	writeJsonResponse(rulesets, http.StatusOK, w, r, "listRulesets")
+}
+
+func (rh *RulesetHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	ruleset, err := rh.service.Ruleset(chi.URLParam(r, "id"))
+	if err != nil {
+		writeServiceError(err, w, r, "getRuleset")
+		return
+	}
+
+	writeJsonResponse(ruleset, http.StatusOK, w, r, "getRuleset")
+}
+
+func (rh *RulesetHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var ruleset rpslsapi.Ruleset
+	if !decodeJsonBody(&ruleset, w, r, "createRuleset") {
+		return
+	}
+
+	created, err := rh.service.CreateRuleset(&ruleset)
+	if err != nil {
+		writeServiceError(err, w, r, "createRuleset")
+		return
+	}
+
+	writeJsonResponse(created, http.StatusCreated, w, r, "createRuleset")
+}
+
+func (rh *RulesetHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if err := rh.service.DeleteRuleset(chi.URLParam(r, "id")); err != nil {
+		writeServiceError(err, w, r, "deleteRuleset")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (rh *RulesetHandler) handleExport(w http.ResponseWriter, r *http.Request) {
+	format := rpslsapi.DocumentFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = rpslsapi.JSONFormat
+	}
+	contentType, found := documentContentTypes[format]
+	if !found {
+		writeJsonResponse(ErrorResponse{Code: UnprocessableBody, Message: fmt.Sprintf("unknown format %q", format)},
+			http.StatusUnprocessableEntity, w, r, "exportRuleset")
+		return
+	}
+
+	document, err := rh.service.ExportRuleset(chi.URLParam(r, "id"))
+	if err != nil {
+		writeServiceError(err, w, r, "exportRuleset")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, document.ID, format))
+	w.WriteHeader(http.StatusOK)
+	if err := rpslsapi.EncodeRulesetDocument(w, document, format); err != nil {
+		logger.WithReqIdAndAction(log.Error().Stack().Err(err), r, "exportRuleset").
+			Msg("failed to write response")
+	}
+}
+
+func (rh *RulesetHandler) handleImport(w http.ResponseWriter, r *http.Request) {
+	document, err := rpslsapi.DecodeRulesetDocument(r.Body)
+	if err != nil {
+		writeServiceError(err, w, r, "importRuleset")
+		return
+	}
+
+	ruleset, err := rh.service.ImportRuleset(document, r.URL.Query().Get("replace") == "true")
+	if err != nil {
+		writeServiceError(err, w, r, "importRuleset")
+		return
+	}
+
+	writeJsonResponse(ruleset, http.StatusCreated, w, r, "importRuleset")
+}
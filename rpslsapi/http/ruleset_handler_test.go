@@ -0,0 +1,279 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type RulesetServiceMock struct {
+	mock.Mock
+}
+
+func (rsm *RulesetServiceMock) Rulesets() ([]rpslsapi.Ruleset, error) {
+	args := rsm.Called()
+	return args.Get(0).([]rpslsapi.Ruleset), args.Error(1)
+}
+
+func (rsm *RulesetServiceMock) Ruleset(id string) (*rpslsapi.Ruleset, error) {
+	args := rsm.Called(id)
+	return args.Get(0).(*rpslsapi.Ruleset), args.Error(1)
+}
+
+func (rsm *RulesetServiceMock) CreateRuleset(ruleset *rpslsapi.Ruleset) (*rpslsapi.Ruleset, error) {
+	args := rsm.Called(ruleset)
+	return args.Get(0).(*rpslsapi.Ruleset), args.Error(1)
+}
+
+func (rsm *RulesetServiceMock) DeleteRuleset(id string) error {
+	args := rsm.Called(id)
+	return args.Error(0)
+}
+
+func (rsm *RulesetServiceMock) ExportRuleset(id string) (*rpslsapi.RulesetDocument, error) {
+	args := rsm.Called(id)
+	return args.Get(0).(*rpslsapi.RulesetDocument), args.Error(1)
+}
+
+func (rsm *RulesetServiceMock) ImportRuleset(document *rpslsapi.RulesetDocument, replace bool) (*rpslsapi.Ruleset,
+	error) {
+	args := rsm.Called(document, replace)
+	return args.Get(0).(*rpslsapi.Ruleset), args.Error(1)
+}
+
+var baseRulesets = []rpslsapi.Ruleset{
+	{ID: "rps", Name: "Rock Paper Scissors"},
+	{ID: "rpsls", Name: "Rock Paper Scissors Lizard Spock"},
+}
+
+func TestRulesetListRequest(t *testing.T) {
+	testCases := []struct {
+		name                string
+		rulesetsFromService []rpslsapi.Ruleset
+		serviceError        error
+		expectedRulesets    []rpslsapi.Ruleset
+		expectedStatus      int
+	}{
+		{
+			name:                "success: return rulesets",
+			rulesetsFromService: baseRulesets,
+			expectedRulesets:    baseRulesets,
+			expectedStatus:      http.StatusOK,
+		},
+		{
+			name:           "failure: if an unknown error happens, return 500",
+			serviceError:   errors.New("unknown error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	serviceMock := RulesetServiceMock{}
+	router := NewRouter(Handlers{Ruleset: NewRulesetHandler(&serviceMock)})
+
+	for _, tc := range testCases {
+		serviceMock.On("Rulesets").Return(tc.rulesetsFromService, tc.serviceError).Once()
+
+		req := httptest.NewRequest("GET", "/rulesets", nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code)
+		if tc.expectedRulesets != nil {
+			var returnedBody []rpslsapi.Ruleset
+			err := json.Unmarshal(rr.Body.Bytes(), &returnedBody)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedRulesets, returnedBody)
+		}
+	}
+}
+
+func TestGetRulesetRequest(t *testing.T) {
+	serviceMock := RulesetServiceMock{}
+	router := NewRouter(Handlers{Ruleset: NewRulesetHandler(&serviceMock)})
+	serviceMock.On("Ruleset", "rps").Return(&baseRulesets[0], nil)
+	serviceMock.On("Ruleset", "missing").Return((*rpslsapi.Ruleset)(nil), rpslsapi.ErrRulesetNotFound)
+
+	req := httptest.NewRequest("GET", "/rulesets/rps", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	req = httptest.NewRequest("GET", "/rulesets/missing", nil)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestCreateRulesetRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return created ruleset",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "failure: if the ruleset is invalid, return 422",
+			serviceError:   rpslsapi.ErrInvalidRuleset,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if the ruleset exists, return 409",
+			serviceError:   rpslsapi.ErrRulesetAlreadyExists,
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	serviceMock := RulesetServiceMock{}
+	router := NewRouter(Handlers{Ruleset: NewRulesetHandler(&serviceMock), Auth: newTestAuthHandler()})
+	body, _ := json.Marshal(baseRulesets[0])
+
+	for _, tc := range testCases {
+		serviceMock.On("CreateRuleset", mock.Anything).Return(&baseRulesets[0], tc.serviceError).Once()
+
+		req := authorizeAdmin(httptest.NewRequest("POST", "/rulesets", bytes.NewBuffer(body)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code)
+	}
+}
+
+var rpsDocument = rpslsapi.RulesetDocument{
+	Version: rpslsapi.RulesetDocumentVersion,
+	ID:      "rps",
+	Name:    "Rock Paper Scissors",
+	Choices: []string{"Rock", "Paper", "Scissors"},
+	Beats: []rpslsapi.BeatsEntry{
+		{Winner: "Rock", Loser: "Scissors", Verb: "crushes"},
+		{Winner: "Paper", Loser: "Rock", Verb: "covers"},
+		{Winner: "Scissors", Loser: "Paper", Verb: "cuts"},
+	},
+}
+
+func TestExportRulesetRequest(t *testing.T) {
+	testCases := []struct {
+		name                string
+		url                 string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "success: export as JSON by default",
+			url:                 "/rulesets/rps/export",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        `"verb": "crushes"`,
+		},
+		{
+			name:                "success: export as YAML",
+			url:                 "/rulesets/rps/export?format=yaml",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/yaml",
+			expectedBody:        "verb: crushes",
+		},
+		{
+			name:           "failure: if the format is unknown, return 422",
+			url:            "/rulesets/rps/export?format=xml",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if the ruleset doesn't exist, return 404",
+			url:            "/rulesets/missing/export",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	serviceMock := RulesetServiceMock{}
+	router := NewRouter(Handlers{Ruleset: NewRulesetHandler(&serviceMock)})
+	serviceMock.On("ExportRuleset", "rps").Return(&rpsDocument, nil)
+	serviceMock.On("ExportRuleset", "missing").Return((*rpslsapi.RulesetDocument)(nil), rpslsapi.ErrRulesetNotFound)
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest("GET", tc.url, nil)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedBody != "" {
+			require.Equal(t, tc.expectedContentType, rr.Header().Get("Content-Type"), tc.name)
+			require.Contains(t, rr.Body.String(), tc.expectedBody, tc.name)
+		}
+	}
+}
+
+func TestImportRulesetRequest(t *testing.T) {
+	testCases := []struct {
+		name            string
+		url             string
+		body            string
+		serviceError    error
+		expectedReplace bool
+		expectedStatus  int
+	}{
+		{
+			name:           "success: import a YAML document",
+			url:            "/rulesets/import",
+			body:           "id: rps\nname: Rock Paper Scissors\nchoices: [Rock]\n",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:            "success: replace an existing ruleset with a JSON document",
+			url:             "/rulesets/import?replace=true",
+			body:            `{"id": "rps", "name": "Rock Paper Scissors", "choices": ["Rock"]}`,
+			expectedReplace: true,
+			expectedStatus:  http.StatusCreated,
+		},
+		{
+			name:           "failure: if the document has unknown fields, return 422",
+			url:            "/rulesets/import",
+			body:           "id: rps\nrules: []\n",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if the document is invalid, return 422",
+			url:            "/rulesets/import",
+			body:           "id: rps\n",
+			serviceError:   &rpslsapi.RulesetDocumentError{Problems: []string{"name is required"}},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if the ruleset exists, return 409",
+			url:            "/rulesets/import",
+			body:           "id: rps\nname: Rock Paper Scissors\n",
+			serviceError:   rpslsapi.ErrRulesetAlreadyExists,
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := RulesetServiceMock{}
+		router := NewRouter(Handlers{Ruleset: NewRulesetHandler(&serviceMock), Auth: newTestAuthHandler()})
+		serviceMock.On("ImportRuleset", mock.Anything, tc.expectedReplace).
+			Return(&baseRulesets[0], tc.serviceError).Once()
+
+		req := authorizeAdmin(httptest.NewRequest("POST", tc.url, strings.NewReader(tc.body)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.serviceError != nil {
+			var returnedBody ErrorResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody), tc.name)
+			if _, isDocumentErr := tc.serviceError.(*rpslsapi.RulesetDocumentError); isDocumentErr {
+				require.Equal(t, []interface{}{"name is required"}, returnedBody.Details, tc.name)
+			}
+		}
+	}
+}
@@ -18,12 +18,13 @@ func NewScoreboardHandler(scoreboardService rpslsapi.ScoreboardService) Scoreboa
 }
 
 func (sh *ScoreboardHandler) addRoutes(r chi.Router) {
+	r.Use(requireUser)
 	r.Get("/", sh.handleScoreboard)
 	r.Delete("/", sh.handleClear)
 }
 
 func (sh *ScoreboardHandler) handleScoreboard(w http.ResponseWriter, r *http.Request) {
-	result, err := sh.service.Scoreboard(rpslsapi.Config.DummyUserID)
+	result, err := sh.service.Scoreboard(userID(r), r.URL.Query().Get("ruleset"))
 	if err != nil {
 		writeJsonResponse(ErrorResponse{Code: UnknownError, Message: "failed to get scoreboard"},
 			http.StatusInternalServerError, w, r, "getScoreboard")
@@ -36,7 +37,7 @@ func (sh *ScoreboardHandler) handleScoreboard(w http.ResponseWriter, r *http.Req
 }
 
 func (sh *ScoreboardHandler) handleClear(w http.ResponseWriter, r *http.Request) {
-	err := sh.service.Clear(rpslsapi.Config.DummyUserID)
+	err := sh.service.Clear(userID(r), r.URL.Query().Get("ruleset"))
 	if err != nil {
 		writeJsonResponse(ErrorResponse{Code: UnknownError, Message: "failed to get scoreboard"},
 			http.StatusInternalServerError, w, r, "getScoreboard")
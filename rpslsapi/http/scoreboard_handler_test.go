@@ -16,8 +16,8 @@ type ScoreboardServiceMock struct {
 	mock.Mock
 }
 
-func (ssm *ScoreboardServiceMock) Scoreboard(userID string) ([]rpslsapi.RoundResults, error) {
-	args := ssm.Called(userID)
+func (ssm *ScoreboardServiceMock) Scoreboard(userID, ruleset string) ([]rpslsapi.RoundResults, error) {
+	args := ssm.Called(userID, ruleset)
 	return args.Get(0).([]rpslsapi.RoundResults), args.Error(1)
 }
 
@@ -26,8 +26,8 @@ func (ssm *ScoreboardServiceMock) Append(userID string, results *rpslsapi.RoundR
 	return args.Error(0)
 }
 
-func (ssm *ScoreboardServiceMock) Clear(userID string) error {
-	args := ssm.Called(userID)
+func (ssm *ScoreboardServiceMock) Clear(userID, ruleset string) error {
+	args := ssm.Called(userID, ruleset)
 	return args.Error(0)
 }
 
@@ -35,13 +35,13 @@ func TestGetScoreboardRequest(t *testing.T) {
 	results := []rpslsapi.RoundResults{
 		{
 			Results:  string(rpslsapi.Tie),
-			Player:   1,
-			Computer: 1,
+			Player:   "rpsls-rock",
+			Computer: "rpsls-rock",
 		},
 		{
 			Results:  string(rpslsapi.Win),
-			Player:   2,
-			Computer: 5,
+			Player:   "rpsls-paper",
+			Computer: "rpsls-spock",
 		},
 	}
 
@@ -66,12 +66,12 @@ func TestGetScoreboardRequest(t *testing.T) {
 	}
 
 	serviceMock := ScoreboardServiceMock{}
-	router := NewRouter(ChoiceHandler{}, RoundHandler{}, NewScoreboardHandler(&serviceMock))
+	router := NewRouter(Handlers{Scoreboard: NewScoreboardHandler(&serviceMock), Auth: newTestAuthHandler()})
 
 	for _, tc := range testCases {
-		serviceMock.On("Scoreboard", mock.Anything).Return(tc.resultsFromService, tc.serviceError).Once()
+		serviceMock.On("Scoreboard", "user", mock.Anything).Return(tc.resultsFromService, tc.serviceError).Once()
 
-		req := httptest.NewRequest("GET", "/scoreboard", nil)
+		req := authorize(httptest.NewRequest("GET", "/scoreboard", nil))
 		rr := httptest.NewRecorder()
 
 		router.ServeHTTP(rr, req)
@@ -104,15 +104,30 @@ func TestClearScoreboardRequest(t *testing.T) {
 	}
 
 	serviceMock := ScoreboardServiceMock{}
-	router := NewRouter(ChoiceHandler{}, RoundHandler{}, NewScoreboardHandler(&serviceMock))
+	router := NewRouter(Handlers{Scoreboard: NewScoreboardHandler(&serviceMock), Auth: newTestAuthHandler()})
 
 	for _, tc := range testCases {
-		serviceMock.On("Clear", mock.Anything).Return(tc.serviceError).Once()
+		serviceMock.On("Clear", "user", mock.Anything).Return(tc.serviceError).Once()
 
-		req := httptest.NewRequest("DELETE", "/scoreboard", nil)
+		req := authorize(httptest.NewRequest("DELETE", "/scoreboard", nil))
 		rr := httptest.NewRecorder()
 
 		router.ServeHTTP(rr, req)
 		require.Equal(t, tc.expectedStatus, rr.Code)
 	}
 }
+
+func TestScoreboardRequestWithoutUser(t *testing.T) {
+	serviceMock := ScoreboardServiceMock{}
+	router := NewRouter(Handlers{Scoreboard: NewScoreboardHandler(&serviceMock), Auth: newTestAuthHandler()})
+
+	req := httptest.NewRequest("GET", "/scoreboard", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	var returnedBody ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody))
+	require.Equal(t, Unauthorized, returnedBody.Code)
+	serviceMock.AssertNotCalled(t, "Scoreboard", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"rpsls/rpslsapi"
+)
+
+type SeriesHandler struct {
+	service rpslsapi.SeriesService
+}
+
+// SeriesRound is the body of a round played in a series, whose ruleset is the one of the series
+type SeriesRound struct {
+	Player string `json:"player"`
+}
+
+func NewSeriesHandler(seriesService rpslsapi.SeriesService) SeriesHandler {
+	return SeriesHandler{service: seriesService}
+}
+
+func (sh *SeriesHandler) addRoutes(r chi.Router) {
+	r.Post("/", sh.handleCreate)
+	r.Get("/{id}", sh.handleGet)
+	r.Post("/{id}/rounds", sh.handlePlay)
+}
+
+func (sh *SeriesHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var settings rpslsapi.SeriesSettings
+	if !decodeJsonBody(&settings, w, r, "createSeries") {
+		return
+	}
+
+	settings.UserID = userID(r)
+	series, err := sh.service.CreateSeries(&settings)
+	if err != nil {
+		writeServiceError(err, w, r, "createSeries")
+		return
+	}
+
+	writeJsonResponse(series, http.StatusCreated, w, r, "createSeries")
+}
+
+func (sh *SeriesHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	series, err := sh.service.Series(chi.URLParam(r, "id"), userID(r))
+	if err != nil {
+		writeServiceError(err, w, r, "getSeries")
+		return
+	}
+
+	writeJsonResponse(series, http.StatusOK, w, r, "getSeries")
+}
+
+func (sh *SeriesHandler) handlePlay(w http.ResponseWriter, r *http.Request) {
+	var round SeriesRound
+	if !decodeJsonBody(&round, w, r, "playSeriesRound") {
+		return
+	}
+
+	settings := &rpslsapi.RoundSettings{Player: round.Player, RequestID: middleware.GetReqID(r.Context()),
+		UserID: userID(r)}
+	series, err := sh.service.Play(chi.URLParam(r, "id"), settings)
+	if err != nil {
+		writeServiceError(err, w, r, "playSeriesRound")
+		return
+	}
+
+	writeJsonResponse(series, http.StatusOK, w, r, "playSeriesRound")
+}
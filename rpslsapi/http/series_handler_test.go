@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type SeriesServiceMock struct {
+	mock.Mock
+}
+
+func (ssm *SeriesServiceMock) CreateSeries(settings *rpslsapi.SeriesSettings) (*rpslsapi.Series, error) {
+	args := ssm.Called(settings)
+	return args.Get(0).(*rpslsapi.Series), args.Error(1)
+}
+
+func (ssm *SeriesServiceMock) Series(id, userID string) (*rpslsapi.Series, error) {
+	args := ssm.Called(id, userID)
+	return args.Get(0).(*rpslsapi.Series), args.Error(1)
+}
+
+func (ssm *SeriesServiceMock) Play(id string, settings *rpslsapi.RoundSettings) (*rpslsapi.Series, error) {
+	args := ssm.Called(id, settings)
+	return args.Get(0).(*rpslsapi.Series), args.Error(1)
+}
+
+var ongoingSeries = &rpslsapi.Series{
+	ID:      "series",
+	Ruleset: "rpsls",
+	BestOf:  5,
+	Score:   rpslsapi.SeriesScore{Player: 1},
+	Rounds:  []rpslsapi.RoundResults{{Results: string(rpslsapi.Win)}},
+}
+
+func TestCreateSeriesRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the created series",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "failure: if the series is invalid, return 422",
+			serviceError:   rpslsapi.ErrInvalidSeries,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := SeriesServiceMock{}
+		router := NewRouter(Handlers{Series: NewSeriesHandler(&serviceMock), Auth: newTestAuthHandler()})
+		settings := &rpslsapi.SeriesSettings{BestOf: 5, Ruleset: "rpsls", ReplayTies: true}
+		owned := *settings
+		owned.UserID = "user"
+		serviceMock.On("CreateSeries", &owned).Return(ongoingSeries, tc.serviceError)
+
+		body, _ := json.Marshal(settings)
+		req := authorize(httptest.NewRequest("POST", "/series", bytes.NewBuffer(body)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		serviceMock.AssertExpectations(t)
+	}
+}
+
+func TestPlaySeriesRoundRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the series",
+			requestBody:    "{\"player\": \"rpsls-rock\"}",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the series doesn't exist, return 404",
+			requestBody:    "{\"player\": \"rpsls-rock\"}",
+			serviceError:   rpslsapi.ErrSeriesNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failure: if the series is finished, return 409",
+			requestBody:    "{\"player\": \"rpsls-rock\"}",
+			serviceError:   rpslsapi.ErrSeriesFinished,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failure: if the series belongs to another user, return 403",
+			requestBody:    "{\"player\": \"rpsls-rock\"}",
+			serviceError:   rpslsapi.ErrNotSeriesOwner,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "failure: if a bad body is sent, return 422",
+			requestBody:    "{\"player\": 1",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := SeriesServiceMock{}
+		router := NewRouter(Handlers{Series: NewSeriesHandler(&serviceMock)})
+		serviceMock.On("Play", "series", mock.Anything).Return(ongoingSeries, tc.serviceError)
+
+		req := httptest.NewRequest("POST", "/series/series/rounds", bytes.NewBufferString(tc.requestBody))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedStatus == http.StatusOK {
+			var returnedBody *rpslsapi.Series
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody), tc.name)
+			require.Equal(t, ongoingSeries, returnedBody, tc.name)
+			settings := serviceMock.Calls[0].Arguments.Get(1).(*rpslsapi.RoundSettings)
+			require.Equal(t, "rpsls-rock", settings.Player, tc.name)
+			require.NotEmpty(t, settings.RequestID, tc.name)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/rs/zerolog/log"
@@ -10,7 +11,9 @@ import (
 )
 
 type Server struct {
-	router *Router
+	router         *Router
+	choiceService  rpslsapi.ChoiceService
+	rulesetService rpslsapi.RulesetService
 }
 
 type ErrorCode int
@@ -19,18 +22,24 @@ const (
 	EntityNotFound ErrorCode = iota
 	UnprocessableBody
 	UnknownError
+	InvalidEntity
+	EntityConflict
+	Forbidden
+	Unauthorized
 )
 
 type ErrorResponse struct {
-	Code    ErrorCode `json:"code"`
-	Message string    `json:"message"`
+	Code    ErrorCode   `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
 }
 
-func NewServer(router Router) Server {
-	return Server{router: &router}
+func NewServer(router Router, choiceService rpslsapi.ChoiceService, rulesetService rpslsapi.RulesetService) Server {
+	return Server{router: &router, choiceService: choiceService, rulesetService: rulesetService}
 }
 
 func (s *Server) Start() {
+	s.validateRules()
 	if err := http.ListenAndServe(rpslsapi.Config.Server.Addr, s.router); err != nil {
 		log.Fatal().Err(err).Msg("Startup failed")
 	}
@@ -44,3 +53,100 @@ func writeJsonResponse(body interface{}, statusCode int, w http.ResponseWriter,
 			Msg("failed to write response")
 	}
 }
+
+// validateRules logs every issue found in the rule graph of each ruleset, so inconsistencies are noticed before rounds
+// are played
+func (s *Server) validateRules() {
+	rulesets, err := s.rulesetService.Rulesets()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list rulesets")
+		return
+	}
+
+	for _, ruleset := range rulesets {
+		report, err := s.choiceService.ValidateRules(ruleset.ID)
+		if err != nil {
+			log.Error().Err(err).Str("ruleset", ruleset.ID).Msg("failed to validate rules")
+			continue
+		}
+
+		for _, issue := range report.Issues {
+			event := log.Warn()
+			if issue.Severity == rpslsapi.SeverityError {
+				event = log.Error()
+			}
+			event.Str("ruleset", ruleset.ID).Str("kind", string(issue.Kind)).Strs("choices", issue.ChoiceIDs).
+				Msg(issue.Message)
+		}
+		if !report.Valid {
+			log.Error().Str("ruleset", ruleset.ID).Msg("rule graph is inconsistent, some rounds will fail")
+		}
+	}
+}
+
+// decodeJsonBody decodes the request body into v, writing a 422 response and returning false if it is malformed
+func decodeJsonBody(v interface{}, w http.ResponseWriter, r *http.Request, action string) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeJsonResponse(ErrorResponse{Code: UnprocessableBody, Message: err.Error()},
+			http.StatusUnprocessableEntity, w, r, action)
+		logger.WithReqIdAndAction(log.Debug().Err(err), r, action).
+			Msg("failed to parse request")
+		return false
+	}
+	return true
+}
+
+// writeServiceError maps the errors returned by services to their HTTP responses
+func writeServiceError(err error, w http.ResponseWriter, r *http.Request, action string) {
+	statusCode, response := serviceErrorResponse(err, action)
+	writeJsonResponse(response, statusCode, w, r, action)
+	if statusCode == http.StatusInternalServerError {
+		logger.WithReqIdAndAction(log.Error().Stack().Err(err), r, action).
+			Msg(action + " failed")
+		return
+	}
+	logger.WithReqIdAndAction(log.Debug(), r, action).Msg(err.Error())
+}
+
+// serviceErrorResponse returns the HTTP status code and the body of the response to an error returned by a service
+func serviceErrorResponse(err error, action string) (int, ErrorResponse) {
+	var ruleGraphErr *rpslsapi.RuleGraphError
+	if errors.As(err, &ruleGraphErr) {
+		return http.StatusUnprocessableEntity,
+			ErrorResponse{Code: InvalidEntity, Message: err.Error(), Details: ruleGraphErr.Report}
+	}
+
+	var documentErr *rpslsapi.RulesetDocumentError
+	if errors.As(err, &documentErr) {
+		return http.StatusUnprocessableEntity, ErrorResponse{Code: InvalidEntity,
+			Message: rpslsapi.ErrInvalidRuleset.Error(), Details: documentErr.Problems}
+	}
+
+	switch err {
+	case rpslsapi.ErrChoiceNotFound, rpslsapi.ErrRuleNotFound, rpslsapi.ErrRulesetNotFound,
+		rpslsapi.ErrTranslationNotFound, rpslsapi.ErrMatchNotFound, rpslsapi.ErrSeriesNotFound,
+		rpslsapi.ErrTournamentNotFound, rpslsapi.ErrCommitmentNotFound, rpslsapi.ErrStrategyNotFound,
+		rpslsapi.ErrBotNotFound, rpslsapi.ErrAuditNotFound, rpslsapi.ErrArcadeSessionNotFound,
+		rpslsapi.ErrTicketNotFound:
+		return http.StatusNotFound, ErrorResponse{Code: EntityNotFound, Message: err.Error()}
+	case rpslsapi.ErrInvalidChoice, rpslsapi.ErrInvalidRule, rpslsapi.ErrInvalidRuleset,
+		rpslsapi.ErrInvalidTranslation, rpslsapi.ErrInvalidSeries, rpslsapi.ErrInvalidTournament,
+		rpslsapi.ErrInvalidBot, rpslsapi.ErrInvalidFreeForAll, rpslsapi.ErrInvalidHistoryQuery,
+		rpslsapi.ErrInvalidArcadeSession, rpslsapi.ErrInvalidDailyPlay, rpslsapi.ErrInvalidDailyDate,
+		rpslsapi.ErrInvalidSimulation, rpslsapi.ErrInvalidMatchmaking, rpslsapi.ErrInvalidUser:
+		return http.StatusUnprocessableEntity, ErrorResponse{Code: InvalidEntity, Message: err.Error()}
+	case rpslsapi.ErrChoiceAlreadyExists, rpslsapi.ErrRulesetAlreadyExists, rpslsapi.ErrMatchFull,
+		rpslsapi.ErrMatchClosed, rpslsapi.ErrAlreadyMoved, rpslsapi.ErrSeriesFinished, rpslsapi.ErrTournamentClosed,
+		rpslsapi.ErrNotEnoughPlayers, rpslsapi.ErrPlayerNameTaken, rpslsapi.ErrNothingToPlay,
+		rpslsapi.ErrBotAlreadyExists, rpslsapi.ErrArcadeSessionOver, rpslsapi.ErrAlreadyPlayedDaily,
+		rpslsapi.ErrNotMatched, rpslsapi.ErrMatchInProgress, rpslsapi.ErrUsernameTaken:
+		return http.StatusConflict, ErrorResponse{Code: EntityConflict, Message: err.Error()}
+	case rpslsapi.ErrInvalidCredentials, rpslsapi.ErrInvalidToken, rpslsapi.ErrUnauthenticated:
+		return http.StatusUnauthorized, ErrorResponse{Code: Unauthorized, Message: err.Error()}
+	case rpslsapi.ErrNotMatchPlayer, rpslsapi.ErrNotTournamentPlayer, rpslsapi.ErrNotTournamentOrganizer,
+		rpslsapi.ErrNotAdmin, rpslsapi.ErrNotSeriesOwner, rpslsapi.ErrNotArcadeSessionOwner:
+		return http.StatusForbidden, ErrorResponse{Code: Forbidden, Message: err.Error()}
+	default:
+		return http.StatusInternalServerError, ErrorResponse{Code: UnknownError, Message: action + " failed"}
+	}
+}
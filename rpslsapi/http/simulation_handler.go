@@ -0,0 +1,36 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"rpsls/rpslsapi"
+)
+
+type SimulationHandler struct {
+	service rpslsapi.SimulationService
+}
+
+func NewSimulationHandler(simulationService rpslsapi.SimulationService) SimulationHandler {
+	return SimulationHandler{service: simulationService}
+}
+
+func (sh *SimulationHandler) addRoutes(r chi.Router) {
+	r.Use(requireAdmin)
+	r.Post("/", sh.handleSimulate)
+}
+
+func (sh *SimulationHandler) handleSimulate(w http.ResponseWriter, r *http.Request) {
+	var settings rpslsapi.SimulationSettings
+	if !decodeJsonBody(&settings, w, r, "simulate") {
+		return
+	}
+
+	report, err := sh.service.Simulate(&settings)
+	if err != nil {
+		writeServiceError(err, w, r, "simulate")
+		return
+	}
+
+	writeJsonResponse(report, http.StatusOK, w, r, "simulate")
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type SimulationServiceMock struct {
+	mock.Mock
+}
+
+func (ssm *SimulationServiceMock) Simulate(settings *rpslsapi.SimulationSettings) (*rpslsapi.SimulationReport,
+	error) {
+	args := ssm.Called(settings)
+	return args.Get(0).(*rpslsapi.SimulationReport), args.Error(1)
+}
+
+func TestSimulateRequest(t *testing.T) {
+	report := &rpslsapi.SimulationReport{
+		Ruleset:  "rps",
+		Player:   "random",
+		Computer: "markov",
+		Seed:     42,
+		Totals:   rpslsapi.SimulationStats{Rounds: 3, Wins: 1, Losses: 2, WinRate: 1.0 / 3, LossRate: 2.0 / 3},
+		Choices:  []rpslsapi.ChoiceStats{},
+		Matchups: []rpslsapi.MatchupStats{},
+	}
+
+	testCases := []struct {
+		name           string
+		requestBody    string
+		authorize      func(req *http.Request) *http.Request
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the report",
+			requestBody:    "{\"ruleset\": \"rps\", \"rounds\": 3, \"computer\": \"expert\"}",
+			authorize:      authorizeAdmin,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the user isn't an admin, return 403",
+			requestBody:    "{\"ruleset\": \"rps\", \"rounds\": 3, \"computer\": \"expert\"}",
+			authorize:      authorize,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "failure: if the request is anonymous, return 401",
+			requestBody:    "{\"ruleset\": \"rps\", \"rounds\": 3, \"computer\": \"expert\"}",
+			authorize:      func(req *http.Request) *http.Request { return req },
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "failure: if the simulation is invalid, return 422",
+			requestBody:    "{\"ruleset\": \"rps\", \"rounds\": 3, \"computer\": \"expert\"}",
+			authorize:      authorizeAdmin,
+			serviceError:   rpslsapi.ErrInvalidSimulation,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if the ruleset doesn't exist, return 404",
+			requestBody:    "{\"ruleset\": \"rps\", \"rounds\": 3, \"computer\": \"expert\"}",
+			authorize:      authorizeAdmin,
+			serviceError:   rpslsapi.ErrRulesetNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failure: if a bad body is sent, return 422",
+			requestBody:    "{\"rounds\": \"many\"}",
+			authorize:      authorizeAdmin,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := SimulationServiceMock{}
+		router := NewRouter(Handlers{Simulation: NewSimulationHandler(&serviceMock), Auth: newTestAuthHandler()})
+		settings := &rpslsapi.SimulationSettings{Ruleset: "rps", Rounds: 3, Computer: "expert"}
+		serviceMock.On("Simulate", settings).Return(report, tc.serviceError)
+
+		req := tc.authorize(httptest.NewRequest("POST", "/simulate", bytes.NewBufferString(tc.requestBody)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedStatus == http.StatusOK {
+			var returnedBody *rpslsapi.SimulationReport
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody), tc.name)
+			require.Equal(t, report, returnedBody, tc.name)
+		}
+	}
+}
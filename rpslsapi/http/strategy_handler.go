@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"rpsls/rpslsapi"
+)
+
+type StrategyHandler struct {
+	service rpslsapi.StrategyService
+}
+
+// StrategySelection is the body selecting a strategy by name or difficulty
+type StrategySelection struct {
+	Strategy string `json:"strategy"`
+}
+
+func NewStrategyHandler(strategyService rpslsapi.StrategyService) StrategyHandler {
+	return StrategyHandler{service: strategyService}
+}
+
+func (sh *StrategyHandler) addRoutes(r chi.Router) {
+	r.Get("/", sh.handleList)
+	r.With(requireUser).Get("/default", sh.handleGetDefault)
+	r.With(requireUser).Put("/default", sh.handleSetDefault)
+}
+
+func (sh *StrategyHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	strategies, err := sh.service.Strategies()
+	if err != nil {
+		writeServiceError(err, w, r, "getStrategies")
+		return
+	}
+
+	writeJsonResponse(strategies, http.StatusOK, w, r, "getStrategies")
+}
+
+func (sh *StrategyHandler) handleGetDefault(w http.ResponseWriter, r *http.Request) {
+	strategy, err := sh.service.DefaultStrategy(userID(r))
+	if err != nil {
+		writeServiceError(err, w, r, "getDefaultStrategy")
+		return
+	}
+
+	writeJsonResponse(strategy, http.StatusOK, w, r, "getDefaultStrategy")
+}
+
+func (sh *StrategyHandler) handleSetDefault(w http.ResponseWriter, r *http.Request) {
+	var selection StrategySelection
+	if !decodeJsonBody(&selection, w, r, "setDefaultStrategy") {
+		return
+	}
+
+	strategy, err := sh.service.SetDefaultStrategy(userID(r), selection.Strategy)
+	if err != nil {
+		writeServiceError(err, w, r, "setDefaultStrategy")
+		return
+	}
+
+	writeJsonResponse(strategy, http.StatusOK, w, r, "setDefaultStrategy")
+}
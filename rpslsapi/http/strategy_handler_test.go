@@ -0,0 +1,103 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type StrategyServiceMock struct {
+	mock.Mock
+}
+
+func (ssm *StrategyServiceMock) Strategies() ([]rpslsapi.StrategyInfo, error) {
+	args := ssm.Called()
+	return args.Get(0).([]rpslsapi.StrategyInfo), args.Error(1)
+}
+
+func (ssm *StrategyServiceMock) Strategy(nameOrDifficulty string) (*rpslsapi.StrategyInfo, error) {
+	args := ssm.Called(nameOrDifficulty)
+	return args.Get(0).(*rpslsapi.StrategyInfo), args.Error(1)
+}
+
+func (ssm *StrategyServiceMock) ComputerChoice(userID, ruleset, strategy string) (*rpslsapi.ComputerMove, error) {
+	args := ssm.Called(userID, ruleset, strategy)
+	return args.Get(0).(*rpslsapi.ComputerMove), args.Error(1)
+}
+
+func (ssm *StrategyServiceMock) DefaultStrategy(userID string) (*rpslsapi.StrategyInfo, error) {
+	args := ssm.Called(userID)
+	return args.Get(0).(*rpslsapi.StrategyInfo), args.Error(1)
+}
+
+func (ssm *StrategyServiceMock) SetDefaultStrategy(userID, strategy string) (*rpslsapi.StrategyInfo, error) {
+	args := ssm.Called(userID, strategy)
+	return args.Get(0).(*rpslsapi.StrategyInfo), args.Error(1)
+}
+
+var markovStrategy = &rpslsapi.StrategyInfo{Name: "markov", Difficulty: "expert",
+	Description: "Counters what the player played after their last moves in the past"}
+
+func TestGetStrategiesRequest(t *testing.T) {
+	serviceMock := StrategyServiceMock{}
+	router := NewRouter(Handlers{Strategy: NewStrategyHandler(&serviceMock)})
+	serviceMock.On("Strategies").Return([]rpslsapi.StrategyInfo{*markovStrategy}, nil)
+
+	req := httptest.NewRequest("GET", "/strategies", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var returnedBody []rpslsapi.StrategyInfo
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody))
+	require.Equal(t, []rpslsapi.StrategyInfo{*markovStrategy}, returnedBody)
+}
+
+func TestSetDefaultStrategyRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return the selected strategy",
+			requestBody:    "{\"strategy\": \"expert\"}",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the strategy is unknown, return 404",
+			requestBody:    "{\"strategy\": \"expert\"}",
+			serviceError:   rpslsapi.ErrStrategyNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failure: if a bad body is sent, return 422",
+			requestBody:    "{\"strategy\": 1",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := StrategyServiceMock{}
+		router := NewRouter(Handlers{Strategy: NewStrategyHandler(&serviceMock), Auth: newTestAuthHandler()})
+		serviceMock.On("SetDefaultStrategy", "user", "expert").Return(markovStrategy, tc.serviceError)
+
+		req := authorize(httptest.NewRequest("PUT", "/strategies/default", bytes.NewBufferString(tc.requestBody)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		if tc.expectedStatus == http.StatusOK {
+			var returnedBody *rpslsapi.StrategyInfo
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody), tc.name)
+			require.Equal(t, markovStrategy, returnedBody, tc.name)
+		}
+	}
+}
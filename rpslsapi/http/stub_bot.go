@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+// StubBot is a bot meant for local development and tests, which plays the choice following the last one of its
+// opponent in the choices of the ruleset, or the first choice without history. It waits for Delay before answering,
+// so slow bots can be simulated.
+type StubBot struct {
+	Delay time.Duration
+}
+
+func (sb StubBot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request rpslsapi.BotRequest
+	if !decodeJsonBody(&request, w, r, "stubBot") {
+		return
+	}
+	if len(request.Choices) == 0 {
+		writeJsonResponse(ErrorResponse{Code: InvalidEntity, Message: "no choices to pick from"},
+			http.StatusUnprocessableEntity, w, r, "stubBot")
+		return
+	}
+
+	select {
+	case <-time.After(sb.Delay):
+	case <-r.Context().Done():
+		return
+	}
+
+	choice := request.Choices[0]
+	if len(request.History) > 0 {
+		for i, id := range request.Choices {
+			if id == request.History[0].Opponent {
+				choice = request.Choices[(i+1)%len(request.Choices)]
+			}
+		}
+	}
+	writeJsonResponse(rpslsapi.BotResponse{Choice: choice}, http.StatusOK, w, r, "stubBot")
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"rpsls/rpslsapi"
+)
+
+type TournamentHandler struct {
+	service rpslsapi.TournamentService
+}
+
+type TournamentRegistration struct {
+	Name string `json:"name"`
+}
+
+func NewTournamentHandler(tournamentService rpslsapi.TournamentService) TournamentHandler {
+	return TournamentHandler{service: tournamentService}
+}
+
+func (th *TournamentHandler) addRoutes(r chi.Router) {
+	r.Post("/", th.handleCreate)
+	r.Get("/{id}", th.handleGet)
+	r.Post("/{id}/join", th.handleJoin)
+	r.Post("/{id}/moves", th.handleMove)
+	r.Post("/{id}/advance", th.handleAdvance)
+}
+
+func (th *TournamentHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var settings rpslsapi.TournamentSettings
+	if !decodeJsonBody(&settings, w, r, "createTournament") {
+		return
+	}
+
+	tournament, err := th.service.CreateTournament(&settings)
+	if err != nil {
+		writeServiceError(err, w, r, "createTournament")
+		return
+	}
+
+	writeJsonResponse(tournament, http.StatusCreated, w, r, "createTournament")
+}
+
+func (th *TournamentHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	tournament, err := th.service.Tournament(chi.URLParam(r, "id"), r.Header.Get(playerTokenHeader))
+	if err != nil {
+		writeServiceError(err, w, r, "getTournament")
+		return
+	}
+
+	writeJsonResponse(tournament, http.StatusOK, w, r, "getTournament")
+}
+
+func (th *TournamentHandler) handleJoin(w http.ResponseWriter, r *http.Request) {
+	var registration TournamentRegistration
+	if !decodeJsonBody(&registration, w, r, "joinTournament") {
+		return
+	}
+
+	tournament, err := th.service.JoinTournament(chi.URLParam(r, "id"), registration.Name)
+	if err != nil {
+		writeServiceError(err, w, r, "joinTournament")
+		return
+	}
+
+	writeJsonResponse(tournament, http.StatusOK, w, r, "joinTournament")
+}
+
+func (th *TournamentHandler) handleMove(w http.ResponseWriter, r *http.Request) {
+	var move Move
+	if !decodeJsonBody(&move, w, r, "moveInTournament") {
+		return
+	}
+
+	tournament, err := th.service.Move(chi.URLParam(r, "id"), r.Header.Get(playerTokenHeader), move.Choice)
+	if err != nil {
+		writeServiceError(err, w, r, "moveInTournament")
+		return
+	}
+
+	writeJsonResponse(tournament, http.StatusOK, w, r, "moveInTournament")
+}
+
+func (th *TournamentHandler) handleAdvance(w http.ResponseWriter, r *http.Request) {
+	tournament, err := th.service.Advance(chi.URLParam(r, "id"), r.Header.Get(playerTokenHeader))
+	if err != nil {
+		writeServiceError(err, w, r, "advanceTournament")
+		return
+	}
+
+	writeJsonResponse(tournament, http.StatusOK, w, r, "advanceTournament")
+}
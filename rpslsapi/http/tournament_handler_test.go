@@ -0,0 +1,159 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type TournamentServiceMock struct {
+	mock.Mock
+}
+
+func (tsm *TournamentServiceMock) CreateTournament(settings *rpslsapi.TournamentSettings) (*rpslsapi.Tournament,
+	error) {
+	args := tsm.Called(settings)
+	return args.Get(0).(*rpslsapi.Tournament), args.Error(1)
+}
+
+func (tsm *TournamentServiceMock) Tournament(id, token string) (*rpslsapi.Tournament, error) {
+	args := tsm.Called(id, token)
+	return args.Get(0).(*rpslsapi.Tournament), args.Error(1)
+}
+
+func (tsm *TournamentServiceMock) JoinTournament(id, name string) (*rpslsapi.Tournament, error) {
+	args := tsm.Called(id, name)
+	return args.Get(0).(*rpslsapi.Tournament), args.Error(1)
+}
+
+func (tsm *TournamentServiceMock) Move(id, token, choiceID string) (*rpslsapi.Tournament, error) {
+	args := tsm.Called(id, token, choiceID)
+	return args.Get(0).(*rpslsapi.Tournament), args.Error(1)
+}
+
+func (tsm *TournamentServiceMock) Advance(id, token string) (*rpslsapi.Tournament, error) {
+	args := tsm.Called(id, token)
+	return args.Get(0).(*rpslsapi.Tournament), args.Error(1)
+}
+
+var openTournament = &rpslsapi.Tournament{
+	ID:      "tournament",
+	Name:    "Office cup",
+	Ruleset: "rpsls",
+	Format:  rpslsapi.SingleElimination,
+	Status:  rpslsapi.TournamentRegistration,
+}
+
+func TestTournamentRequests(t *testing.T) {
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		requestBody    string
+		token          string
+		mockMethod     string
+		mockArguments  []interface{}
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:        "success: create the tournament",
+			method:      "POST",
+			path:        "/tournaments",
+			requestBody: "{\"name\": \"Office cup\", \"format\": \"single_elimination\"}",
+			mockMethod:  "CreateTournament",
+			mockArguments: []interface{}{
+				&rpslsapi.TournamentSettings{Name: "Office cup", Format: rpslsapi.SingleElimination}},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "failure: if the tournament is invalid, return 422",
+			method:         "POST",
+			path:           "/tournaments",
+			requestBody:    "{\"name\": \"\"}",
+			mockMethod:     "CreateTournament",
+			mockArguments:  []interface{}{&rpslsapi.TournamentSettings{}},
+			serviceError:   rpslsapi.ErrInvalidTournament,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "success: return the tournament as seen by the player",
+			method:         "GET",
+			path:           "/tournaments/tournament",
+			token:          "token",
+			mockMethod:     "Tournament",
+			mockArguments:  []interface{}{"tournament", "token"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the tournament doesn't exist, return 404",
+			method:         "GET",
+			path:           "/tournaments/tournament",
+			mockMethod:     "Tournament",
+			mockArguments:  []interface{}{"tournament", ""},
+			serviceError:   rpslsapi.ErrTournamentNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "failure: if the name is taken, return 409",
+			method:         "POST",
+			path:           "/tournaments/tournament/join",
+			requestBody:    "{\"name\": \"Ada\"}",
+			mockMethod:     "JoinTournament",
+			mockArguments:  []interface{}{"tournament", "Ada"},
+			serviceError:   rpslsapi.ErrPlayerNameTaken,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "failure: if the player has nothing to play, return 409",
+			method:         "POST",
+			path:           "/tournaments/tournament/moves",
+			requestBody:    "{\"choice\": \"rpsls-rock\"}",
+			token:          "token",
+			mockMethod:     "Move",
+			mockArguments:  []interface{}{"tournament", "token", "rpsls-rock"},
+			serviceError:   rpslsapi.ErrNothingToPlay,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "success: advance the tournament",
+			method:         "POST",
+			path:           "/tournaments/tournament/advance",
+			token:          "organizer",
+			mockMethod:     "Advance",
+			mockArguments:  []interface{}{"tournament", "organizer"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the token is not the one of the organizer, return 403",
+			method:         "POST",
+			path:           "/tournaments/tournament/advance",
+			token:          "token",
+			mockMethod:     "Advance",
+			mockArguments:  []interface{}{"tournament", "token"},
+			serviceError:   rpslsapi.ErrNotTournamentOrganizer,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := TournamentServiceMock{}
+		router := NewRouter(Handlers{Tournament: NewTournamentHandler(&serviceMock)})
+		serviceMock.On(tc.mockMethod, tc.mockArguments...).Return(openTournament, tc.serviceError)
+
+		req := httptest.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.requestBody))
+		if tc.token != "" {
+			req.Header.Set(playerTokenHeader, tc.token)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+		serviceMock.AssertExpectations(t)
+	}
+}
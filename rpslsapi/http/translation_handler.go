@@ -0,0 +1,127 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog/log"
+	"rpsls/rpslsapi"
+	"rpsls/rpslsapi/logger"
+)
+
+type TranslationHandler struct {
+	service rpslsapi.TranslationService
+}
+
+func NewTranslationHandler(translationService rpslsapi.TranslationService) TranslationHandler {
+	return TranslationHandler{service: translationService}
+}
+
+func (th *TranslationHandler) addRoutes(r chi.Router) {
+	r.Get("/", th.handleList)
+	r.With(requireAdmin).Put("/", th.handleSave)
+	r.With(requireAdmin).Delete("/{kind}/{language}/{key}", th.handleDelete)
+}
+
+func (th *TranslationHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	translations, err := th.service.Translations(r.URL.Query().Get("language"))
+	if err != nil {
+		writeServiceError(err, w, r, "listTranslations")
+		return
+	}
+
+	writeJsonResponse(translations, http.StatusOK, w, r, "listTranslations")
+}
+
+func (th *TranslationHandler) handleSave(w http.ResponseWriter, r *http.Request) {
+	var translation rpslsapi.Translation
+	if !decodeJsonBody(&translation, w, r, "saveTranslation") {
+		return
+	}
+
+	saved, err := th.service.SaveTranslation(&translation)
+	if err != nil {
+		writeServiceError(err, w, r, "saveTranslation")
+		return
+	}
+
+	writeJsonResponse(saved, http.StatusOK, w, r, "saveTranslation")
+}
+
+func (th *TranslationHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	// verbs may contain spaces, which reach the handler escaped
+	key, err := url.PathUnescape(chi.URLParam(r, "key"))
+	if err != nil {
+		writeServiceError(rpslsapi.ErrTranslationNotFound, w, r, "deleteTranslation")
+		return
+	}
+
+	kind := rpslsapi.TranslationKind(chi.URLParam(r, "kind"))
+	if err := th.service.DeleteTranslation(kind, key, chi.URLParam(r, "language")); err != nil {
+		writeServiceError(err, w, r, "deleteTranslation")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// negotiateTranslator returns the translator for the languages accepted by the request, or nil if it has no
+// Accept-Language header. Responses are translated on a best effort basis: if translations can't be loaded, the error
+// is logged and nil is returned, so they are sent untranslated.
+func negotiateTranslator(service rpslsapi.TranslationService, w http.ResponseWriter, r *http.Request,
+	action string) *rpslsapi.Translator {
+	w.Header().Add("Vary", "Accept-Language")
+	languages := acceptedLanguages(r.Header.Get("Accept-Language"))
+	if len(languages) == 0 {
+		return nil
+	}
+
+	translator, err := service.Translator(languages)
+	if err != nil {
+		logger.WithReqIdAndAction(log.Error().Stack().Err(err), r, action).
+			Msg("failed to load translations")
+		return nil
+	}
+	if language := translator.Language(); language != "" {
+		w.Header().Set("Content-Language", language)
+	}
+	return translator
+}
+
+// acceptedLanguages parses an Accept-Language header, returning its language tags sorted by decreasing quality. The
+// wildcard and tags with a quality of zero are left out.
+func acceptedLanguages(header string) []string {
+	type weightedLanguage struct {
+		tag     string
+		quality float64
+	}
+
+	var weighted []weightedLanguage
+	for _, entry := range strings.Split(header, ",") {
+		parts := strings.Split(entry, ";")
+		tag := strings.ToLower(strings.TrimSpace(parts[0]))
+		quality := 1.0
+		for _, parameter := range parts[1:] {
+			parameter = strings.TrimSpace(parameter)
+			if strings.HasPrefix(parameter, "q=") {
+				if q, err := strconv.ParseFloat(parameter[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if tag != "" && tag != "*" && quality > 0 {
+			weighted = append(weighted, weightedLanguage{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(weighted, func(i, j int) bool { return weighted[i].quality > weighted[j].quality })
+
+	languages := make([]string, 0, len(weighted))
+	for _, language := range weighted {
+		languages = append(languages, language.tag)
+	}
+	return languages
+}
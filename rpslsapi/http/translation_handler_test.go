@@ -0,0 +1,197 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+type TranslationServiceMock struct {
+	mock.Mock
+}
+
+func (tsm *TranslationServiceMock) Translations(language string) ([]rpslsapi.Translation, error) {
+	args := tsm.Called(language)
+	return args.Get(0).([]rpslsapi.Translation), args.Error(1)
+}
+
+func (tsm *TranslationServiceMock) SaveTranslation(translation *rpslsapi.Translation) (*rpslsapi.Translation,
+	error) {
+	args := tsm.Called(translation)
+	return args.Get(0).(*rpslsapi.Translation), args.Error(1)
+}
+
+func (tsm *TranslationServiceMock) DeleteTranslation(kind rpslsapi.TranslationKind, key, language string) error {
+	args := tsm.Called(kind, key, language)
+	return args.Error(0)
+}
+
+func (tsm *TranslationServiceMock) Translator(languages []string) (*rpslsapi.Translator, error) {
+	args := tsm.Called(languages)
+	return args.Get(0).(*rpslsapi.Translator), args.Error(1)
+}
+
+var ptTranslations = []rpslsapi.Translation{
+	{Kind: rpslsapi.ChoiceTranslation, Key: "rpsls-rock", Language: "pt", Text: "Pedra"},
+	{Kind: rpslsapi.ChoiceTranslation, Key: "rpsls-scissors", Language: "pt", Text: "Tesoura"},
+	{Kind: rpslsapi.VerbTranslation, Key: "crushes", Language: "pt", Text: "esmaga"},
+}
+
+func TestAcceptedLanguages(t *testing.T) {
+	testCases := []struct {
+		header            string
+		expectedLanguages []string
+	}{
+		{header: "", expectedLanguages: []string{}},
+		{header: "pt-BR", expectedLanguages: []string{"pt-br"}},
+		{header: "es;q=0.5, pt-BR, pt;q=0.9, *;q=0.1", expectedLanguages: []string{"pt-br", "pt", "es"}},
+		{header: "fr;q=0, en;q=bad", expectedLanguages: []string{"en"}},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expectedLanguages, acceptedLanguages(tc.header), tc.header)
+	}
+}
+
+func TestLocalizedChoiceListRequest(t *testing.T) {
+	serviceMock := ChoiceServiceMock{}
+	translationMock := TranslationServiceMock{}
+	router := NewRouter(Handlers{Choice: NewChoiceHandler(&serviceMock, &translationMock)})
+	serviceMock.On("Choices", mock.Anything).Return(baseChoices[:2], nil)
+	translationMock.On("Translator", []string{"pt-br", "en"}).Return(rpslsapi.NewTranslator(ptTranslations), nil)
+
+	req := httptest.NewRequest("GET", "/choices", nil)
+	req.Header.Set("Accept-Language", "pt-BR,en;q=0.5")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "pt", rr.Header().Get("Content-Language"))
+	require.Contains(t, rr.Header().Values("Vary"), "Accept-Language")
+	var returnedBody []rpslsapi.Choice
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody))
+	require.Equal(t, "Pedra", returnedBody[0].Name)
+	require.Equal(t, baseChoices[1].Name, returnedBody[1].Name)
+}
+
+func TestLocalizedPlayRequest(t *testing.T) {
+	results := &rpslsapi.RoundResults{
+		Results:        string(rpslsapi.Win),
+		PlayerChoice:   &rpslsapi.Choice{ID: "rpsls-rock", Name: "Rock", Ruleset: "rpsls"},
+		ComputerChoice: &rpslsapi.Choice{ID: "rpsls-scissors", Name: "Scissors", Ruleset: "rpsls"},
+		Action:         "crushes",
+		Description:    "Rock crushes Scissors",
+	}
+
+	testCases := []struct {
+		name                string
+		translatorError     error
+		expectedDescription string
+	}{
+		{
+			name:                "success: translate the results",
+			expectedDescription: "Pedra esmaga Tesoura",
+		},
+		{
+			name:                "success: if translations can't be loaded, return untranslated results",
+			translatorError:     errors.New("unknown error"),
+			expectedDescription: "Rock crushes Scissors",
+		},
+	}
+
+	for _, tc := range testCases {
+		serviceMock := RoundServiceMock{}
+		translationMock := TranslationServiceMock{}
+		router := NewRouter(Handlers{Round: NewRoundHandler(&serviceMock, &translationMock)})
+		serviceMock.On("Play", mock.Anything).Return(results, nil)
+		translationMock.On("Translator", []string{"pt"}).
+			Return(rpslsapi.NewTranslator(ptTranslations), tc.translatorError)
+
+		req := httptest.NewRequest("POST", "/play", bytes.NewBuffer(playRequestBody("rpsls-rock")))
+		req.Header.Set("Accept-Language", "pt")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code, tc.name)
+		var returnedBody rpslsapi.RoundResults
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody), tc.name)
+		require.Equal(t, tc.expectedDescription, returnedBody.Description, tc.name)
+	}
+}
+
+func TestTranslationListRequest(t *testing.T) {
+	serviceMock := TranslationServiceMock{}
+	router := NewRouter(Handlers{Translation: NewTranslationHandler(&serviceMock)})
+	serviceMock.On("Translations", "pt").Return(ptTranslations, nil)
+
+	req := httptest.NewRequest("GET", "/translations?language=pt", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var returnedBody []rpslsapi.Translation
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &returnedBody))
+	require.Equal(t, ptTranslations, returnedBody)
+}
+
+func TestSaveTranslationRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "success: return saved translation",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure: if the translation is invalid, return 422",
+			serviceError:   rpslsapi.ErrInvalidTranslation,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "failure: if the translated choice doesn't exist, return 404",
+			serviceError:   rpslsapi.ErrChoiceNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	serviceMock := TranslationServiceMock{}
+	router := NewRouter(Handlers{Translation: NewTranslationHandler(&serviceMock), Auth: newTestAuthHandler()})
+	body, _ := json.Marshal(ptTranslations[0])
+
+	for _, tc := range testCases {
+		serviceMock.On("SaveTranslation", &ptTranslations[0]).Return(&ptTranslations[0], tc.serviceError).Once()
+
+		req := authorizeAdmin(httptest.NewRequest("PUT", "/translations", bytes.NewBuffer(body)))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, tc.expectedStatus, rr.Code, tc.name)
+	}
+}
+
+func TestDeleteTranslationRequest(t *testing.T) {
+	serviceMock := TranslationServiceMock{}
+	router := NewRouter(Handlers{Translation: NewTranslationHandler(&serviceMock), Auth: newTestAuthHandler()})
+	serviceMock.On("DeleteTranslation", rpslsapi.VerbTranslation, "crushes", "pt").Return(nil)
+	serviceMock.On("DeleteTranslation", rpslsapi.VerbTranslation, "eats up", "pt").
+		Return(rpslsapi.ErrTranslationNotFound)
+
+	req := authorizeAdmin(httptest.NewRequest("DELETE", "/translations/verb/pt/crushes", nil))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	req = authorizeAdmin(httptest.NewRequest("DELETE", "/translations/verb/pt/eats%20up", nil))
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,306 @@
+package rpslsapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrMatchNotFound = errors.New("match not found")
+var ErrMatchFull = errors.New("match already has two players")
+var ErrMatchClosed = errors.New("match does not accept moves")
+var ErrAlreadyMoved = errors.New("choice already submitted")
+var ErrNotMatchPlayer = errors.New("not a player of the match")
+
+// matchRetention is how long matches are kept, so players can still poll them once they are over
+const matchRetention = 24 * time.Hour
+
+type MatchStatus string
+
+const (
+	// MatchWaiting matches wait for a second player to join
+	MatchWaiting MatchStatus = "waiting"
+	// MatchPlaying matches wait for both players to submit their choice
+	MatchPlaying  MatchStatus = "playing"
+	MatchFinished MatchStatus = "finished"
+	// MatchExpired matches timed out before anybody could win them
+	MatchExpired MatchStatus = "expired"
+)
+
+type MatchSide string
+
+const (
+	Host  MatchSide = "host"
+	Guest MatchSide = "guest"
+)
+
+// MatchPlayer is one side of a match. Token authenticates the requests of the player and is only shown to them,
+// while Choice stays hidden from the opponent until the match is over.
+type MatchPlayer struct {
+	Token  string  `json:"token,omitempty"`
+	Moved  bool    `json:"moved"`
+	Choice *Choice `json:"choice,omitempty"`
+}
+
+// MatchOutcome describes how a finished match was decided. Winner is empty on ties, and Forfeit is set when the
+// opponent of the winner didn't move in time.
+type MatchOutcome struct {
+	Winner      MatchSide `json:"winner,omitempty"`
+	Forfeit     bool      `json:"forfeit,omitempty"`
+	Action      string    `json:"action,omitempty"`
+	Description string    `json:"description"`
+}
+
+// Match is a round between two players who submit their choices without seeing each other's. Deadline is when the
+// current step, joining or moving, times out.
+type Match struct {
+	ID       string        `json:"id"`
+	Ruleset  string        `json:"ruleset"`
+	Status   MatchStatus   `json:"status"`
+	Host     *MatchPlayer  `json:"host"`
+	Guest    *MatchPlayer  `json:"guest,omitempty"`
+	Outcome  *MatchOutcome `json:"outcome,omitempty"`
+	Deadline time.Time     `json:"deadline"`
+}
+
+type MatchService interface {
+	// CreateMatch opens a match of the ruleset, returning it with the token of the host
+	CreateMatch(ruleset string) (*Match, error)
+	// JoinMatch makes the caller the guest of a waiting match, returning it with the token of the guest
+	JoinMatch(id string) (*Match, error)
+	// Match returns the match as seen by the player with the token, which may be empty for spectators
+	Match(id, token string) (*Match, error)
+	// Move submits the choice of the player with the token, resolving the match once both players moved
+	Move(id, token, choiceID string) (*Match, error)
+	// Abandon makes the player with the token forfeit the match, which their opponent wins if they joined it
+	Abandon(id, token string) (*Match, error)
+}
+
+type MatchStore interface {
+	// CreateMatch stores a new match, which is dropped once the retention has passed
+	CreateMatch(match *Match, retention time.Duration) error
+	// Match returns the match or ErrMatchNotFound
+	Match(id string) (*Match, error)
+	UpdateMatch(id string, update func(match *Match) error) (*Match, error)
+}
+
+type MatchServiceImpl struct {
+	matchStore    MatchStore
+	roundStore    RoundStore
+	choiceService ChoiceService
+	timeout       time.Duration
+	now           func() time.Time
+}
+
+func NewMatchService(matchStore MatchStore, roundStore RoundStore, choiceService ChoiceService) MatchService {
+	return MatchServiceImpl{
+		matchStore:    matchStore,
+		roundStore:    roundStore,
+		choiceService: choiceService,
+		timeout:       Config.MatchTimeout,
+		now:           time.Now,
+	}
+}
+
+func (ms MatchServiceImpl) CreateMatch(ruleset string) (*Match, error) {
+	ruleset = RulesetOrDefault(ruleset)
+	if _, err := ms.choiceService.Choices(ruleset); err != nil {
+		return nil, err
+	}
+
+	match := &Match{
+		ID:       newToken(8),
+		Ruleset:  ruleset,
+		Status:   MatchWaiting,
+		Host:     &MatchPlayer{Token: newToken(16)},
+		Deadline: ms.now().Add(ms.timeout),
+	}
+	if err := ms.matchStore.CreateMatch(match, matchRetention); err != nil {
+		return nil, err
+	}
+	return match.viewFor(match.Host.Token), nil
+}
+
+func (ms MatchServiceImpl) JoinMatch(id string) (*Match, error) {
+	token := newToken(16)
+	match, err := ms.matchStore.UpdateMatch(id, func(match *Match) error {
+		ms.expire(match)
+		switch match.Status {
+		case MatchWaiting:
+		case MatchExpired:
+			return ErrMatchClosed
+		default:
+			return ErrMatchFull
+		}
+
+		match.Guest = &MatchPlayer{Token: token}
+		match.Status = MatchPlaying
+		match.Deadline = ms.now().Add(ms.timeout)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match.viewFor(token), nil
+}
+
+func (ms MatchServiceImpl) Match(id, token string) (*Match, error) {
+	match, err := ms.matchStore.Match(id)
+	if err != nil {
+		return nil, err
+	}
+	ms.expire(match)
+	return match.viewFor(token), nil
+}
+
+func (ms MatchServiceImpl) Move(id, token, choiceID string) (*Match, error) {
+	choice, err := ms.choiceService.Choice(choiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := ms.matchStore.UpdateMatch(id, func(match *Match) error {
+		ms.expire(match)
+		player, _ := match.player(token)
+		switch {
+		case player == nil:
+			return ErrNotMatchPlayer
+		case match.Status != MatchPlaying:
+			return ErrMatchClosed
+		case player.Moved:
+			return ErrAlreadyMoved
+		case choice.Ruleset != match.Ruleset:
+			return ErrChoiceNotFound
+		}
+
+		player.Moved = true
+		player.Choice = choice
+		if match.Host.Moved && match.Guest.Moved {
+			return ms.resolve(match)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match.viewFor(token), nil
+}
+
+func (ms MatchServiceImpl) Abandon(id, token string) (*Match, error) {
+	match, err := ms.matchStore.UpdateMatch(id, func(match *Match) error {
+		ms.expire(match)
+		player, side := match.player(token)
+		switch {
+		case player == nil:
+			return ErrNotMatchPlayer
+		case match.Status != MatchWaiting && match.Status != MatchPlaying:
+			return ErrMatchClosed
+		case match.Status == MatchWaiting:
+			match.Status = MatchExpired
+			return nil
+		}
+
+		winner := Host
+		if side == Host {
+			winner = Guest
+		}
+		match.Status = MatchFinished
+		match.Outcome = &MatchOutcome{Winner: winner, Forfeit: true,
+			Description: fmt.Sprintf("%s abandoned the match", side)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match.viewFor(token), nil
+}
+
+// resolve decides a match both players moved in through the BEATS relationship between their choices
+func (ms MatchServiceImpl) resolve(match *Match) error {
+	host, guest := match.Host.Choice, match.Guest.Choice
+	match.Status = MatchFinished
+	if host.ID == guest.ID {
+		match.Outcome = &MatchOutcome{Description: describeTie(phrases[TiePhrase], host)}
+		return nil
+	}
+
+	round, err := ms.roundStore.SimulateRound(host.ID, guest.ID)
+	if err != nil {
+		return err
+	}
+	match.Outcome = &MatchOutcome{Winner: Host, Action: round.Action}
+	if round.WinnerID == host.ID {
+		match.Outcome.Description = describeRound(host, round.Action, guest)
+	} else {
+		match.Outcome.Winner = Guest
+		match.Outcome.Description = describeRound(guest, round.Action, host)
+	}
+	return nil
+}
+
+// expire closes a match whose deadline has passed. A player who moved wins against an opponent who didn't, otherwise
+// the match expires.
+func (ms MatchServiceImpl) expire(match *Match) {
+	if (match.Status != MatchWaiting && match.Status != MatchPlaying) || !ms.now().After(match.Deadline) {
+		return
+	}
+
+	if match.Status == MatchWaiting || match.Host.Moved == match.Guest.Moved {
+		match.Status = MatchExpired
+		return
+	}
+	winner, loser := Host, Guest
+	if match.Guest.Moved {
+		winner, loser = Guest, Host
+	}
+	match.Status = MatchFinished
+	match.Outcome = &MatchOutcome{Winner: winner, Forfeit: true,
+		Description: fmt.Sprintf("%s did not move in time", loser)}
+}
+
+// player returns the player with the token and its side, or nil if nobody in the match has it
+func (m *Match) player(token string) (*MatchPlayer, MatchSide) {
+	switch {
+	case token == "":
+		return nil, ""
+	case m.Host != nil && m.Host.Token == token:
+		return m.Host, Host
+	case m.Guest != nil && m.Guest.Token == token:
+		return m.Guest, Guest
+	}
+	return nil, ""
+}
+
+// viewFor returns a copy of the match as the player with the token may see it: without the token of the opponent
+// and, until the match is over, without the choice of the opponent
+func (m *Match) viewFor(token string) *Match {
+	view := *m
+	over := m.Status == MatchFinished || m.Status == MatchExpired
+	hide := func(player *MatchPlayer) *MatchPlayer {
+		if player == nil {
+			return nil
+		}
+		visible := *player
+		if player.Token != token {
+			visible.Token = ""
+			if !over {
+				visible.Choice = nil
+			}
+		}
+		return &visible
+	}
+	view.Host = hide(m.Host)
+	view.Guest = hide(m.Guest)
+	return &view
+}
+
+// newToken returns size random bytes encoded in hex
+func newToken(size int) string {
+	token := make([]byte, size)
+	if _, err := rand.Read(token); err != nil {
+		panic(fmt.Errorf("failed to generate random token: %w", err))
+	}
+	return hex.EncodeToString(token)
+}
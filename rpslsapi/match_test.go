@@ -0,0 +1,339 @@
+package rpslsapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MatchStoreMock struct {
+	mock.Mock
+}
+
+func (msm *MatchStoreMock) CreateMatch(match *Match, retention time.Duration) error {
+	args := msm.Called(match, retention)
+	return args.Error(0)
+}
+
+func (msm *MatchStoreMock) Match(id string) (*Match, error) {
+	args := msm.Called(id)
+	return args.Get(0).(*Match), args.Error(1)
+}
+
+// UpdateMatch applies the update to the match the mock returns
+func (msm *MatchStoreMock) UpdateMatch(id string, update func(match *Match) error) (*Match, error) {
+	args := msm.Called(id)
+	if err := args.Error(1); err != nil {
+		return nil, err
+	}
+	match := args.Get(0).(*Match)
+	if err := update(match); err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+var matchNow = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestMatchService(storeMock *MatchStoreMock, roundStoreMock *RoundStoreMock,
+	choiceServiceMock *ChoiceServiceMock) MatchServiceImpl {
+	return MatchServiceImpl{
+		matchStore:    storeMock,
+		roundStore:    roundStoreMock,
+		choiceService: choiceServiceMock,
+		timeout:       time.Minute,
+		now:           func() time.Time { return matchNow },
+	}
+}
+
+// playingMatch returns a match both players joined, where the given players already moved
+func playingMatch(hostChoice, guestChoice *Choice) *Match {
+	return &Match{
+		ID:       "match",
+		Ruleset:  "rpsls",
+		Status:   MatchPlaying,
+		Host:     &MatchPlayer{Token: "host", Moved: hostChoice != nil, Choice: hostChoice},
+		Guest:    &MatchPlayer{Token: "guest", Moved: guestChoice != nil, Choice: guestChoice},
+		Deadline: matchNow.Add(time.Minute),
+	}
+}
+
+func TestMatchService_CreateMatch(t *testing.T) {
+	storeMock := MatchStoreMock{}
+	choiceServiceMock := ChoiceServiceMock{}
+	service := newTestMatchService(&storeMock, &RoundStoreMock{}, &choiceServiceMock)
+	choiceServiceMock.On("Choices", "rpsls").Return(baseChoices, nil)
+	choiceServiceMock.On("Choices", "missing").Return([]Choice(nil), ErrRulesetNotFound)
+	storeMock.On("CreateMatch", mock.Anything, matchRetention).Return(nil)
+
+	match, err := service.CreateMatch("rpsls")
+	require.NoError(t, err)
+	require.Equal(t, MatchWaiting, match.Status)
+	require.Equal(t, "rpsls", match.Ruleset)
+	require.Len(t, match.ID, 16)
+	require.Len(t, match.Host.Token, 32)
+	require.Nil(t, match.Guest)
+	require.Equal(t, matchNow.Add(time.Minute), match.Deadline)
+	storeMock.AssertCalled(t, "CreateMatch", mock.Anything, matchRetention)
+
+	_, err = service.CreateMatch("missing")
+	require.Equal(t, ErrRulesetNotFound, err)
+}
+
+func TestMatchService_JoinMatch(t *testing.T) {
+	testCases := []struct {
+		name          string
+		match         *Match
+		expectedError error
+	}{
+		{
+			name: "success: join a waiting match",
+			match: &Match{ID: "match", Status: MatchWaiting, Host: &MatchPlayer{Token: "host"},
+				Deadline: matchNow.Add(time.Second)},
+		},
+		{
+			name:          "failure: if the match has two players, return ErrMatchFull",
+			match:         playingMatch(nil, nil),
+			expectedError: ErrMatchFull,
+		},
+		{
+			name: "failure: if nobody joined in time, return ErrMatchClosed",
+			match: &Match{ID: "match", Status: MatchWaiting, Host: &MatchPlayer{Token: "host"},
+				Deadline: matchNow.Add(-time.Second)},
+			expectedError: ErrMatchClosed,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := MatchStoreMock{}
+		service := newTestMatchService(&storeMock, &RoundStoreMock{}, &ChoiceServiceMock{})
+		storeMock.On("UpdateMatch", "match").Return(tc.match, nil)
+
+		match, err := service.JoinMatch("match")
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+		} else {
+			require.NoError(t, err, tc.name)
+			require.Equal(t, MatchPlaying, match.Status, tc.name)
+			require.Empty(t, match.Host.Token, tc.name)
+			require.Len(t, match.Guest.Token, 32, tc.name)
+			require.Equal(t, matchNow.Add(time.Minute), match.Deadline, tc.name)
+		}
+	}
+}
+
+func TestMatchService_Move(t *testing.T) {
+	rock, paper := &baseChoices[0], &baseChoices[1]
+
+	testCases := []struct {
+		name            string
+		match           *Match
+		token           string
+		choice          *Choice
+		expectedStatus  MatchStatus
+		expectedOutcome *MatchOutcome
+		expectedError   error
+	}{
+		{
+			name:           "success: first move waits for the opponent",
+			match:          playingMatch(nil, nil),
+			token:          "host",
+			choice:         paper,
+			expectedStatus: MatchPlaying,
+		},
+		{
+			name:            "success: second move decides the match",
+			match:           playingMatch(nil, paper),
+			token:           "host",
+			choice:          rock,
+			expectedStatus:  MatchFinished,
+			expectedOutcome: &MatchOutcome{Winner: Guest, Action: "covers", Description: "paper covers rock"},
+		},
+		{
+			name:            "success: same choices tie",
+			match:           playingMatch(rock, nil),
+			token:           "guest",
+			choice:          rock,
+			expectedStatus:  MatchFinished,
+			expectedOutcome: &MatchOutcome{Description: "Both played rock"},
+		},
+		{
+			name:          "failure: if the token is not one of the players, return ErrNotMatchPlayer",
+			match:         playingMatch(nil, nil),
+			token:         "spectator",
+			choice:        rock,
+			expectedError: ErrNotMatchPlayer,
+		},
+		{
+			name:          "failure: if the player already moved, return ErrAlreadyMoved",
+			match:         playingMatch(rock, nil),
+			token:         "host",
+			choice:        paper,
+			expectedError: ErrAlreadyMoved,
+		},
+		{
+			name:          "failure: if the choice belongs to another ruleset, return ErrChoiceNotFound",
+			match:         playingMatch(nil, nil),
+			token:         "host",
+			choice:        &Choice{ID: "rps-rock", Name: "Rock", Ruleset: "rps"},
+			expectedError: ErrChoiceNotFound,
+		},
+		{
+			name: "failure: if the match is waiting for a guest, return ErrMatchClosed",
+			match: &Match{ID: "match", Status: MatchWaiting, Host: &MatchPlayer{Token: "host"},
+				Deadline: matchNow.Add(time.Minute)},
+			token:         "host",
+			choice:        rock,
+			expectedError: ErrMatchClosed,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := MatchStoreMock{}
+		roundStoreMock := RoundStoreMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		service := newTestMatchService(&storeMock, &roundStoreMock, &choiceServiceMock)
+		storeMock.On("UpdateMatch", "match").Return(tc.match, nil)
+		choiceServiceMock.On("Choice", tc.choice.ID).Return(tc.choice, nil)
+		roundStoreMock.On("SimulateRound", mock.Anything, mock.Anything).
+			Return(&Round{WinnerID: paper.ID, LoserID: rock.ID, Action: "covers"}, nil)
+
+		match, err := service.Move("match", tc.token, tc.choice.ID)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+		} else {
+			require.NoError(t, err, tc.name)
+			require.Equal(t, tc.expectedStatus, match.Status, tc.name)
+			require.Equal(t, tc.expectedOutcome, match.Outcome, tc.name)
+			player, _ := match.player(tc.token)
+			require.Equal(t, tc.choice, player.Choice, tc.name)
+		}
+	}
+}
+
+func TestMatchService_Abandon(t *testing.T) {
+	rock := &baseChoices[0]
+	testCases := []struct {
+		name            string
+		match           *Match
+		token           string
+		expectedStatus  MatchStatus
+		expectedOutcome *MatchOutcome
+		expectedError   error
+	}{
+		{
+			name:           "success: the opponent wins the match",
+			match:          playingMatch(nil, nil),
+			token:          "guest",
+			expectedStatus: MatchFinished,
+			expectedOutcome: &MatchOutcome{Winner: Host, Forfeit: true,
+				Description: "guest abandoned the match"},
+		},
+		{
+			name:           "success: the opponent wins even if the player already moved",
+			match:          playingMatch(rock, nil),
+			token:          "host",
+			expectedStatus: MatchFinished,
+			expectedOutcome: &MatchOutcome{Winner: Guest, Forfeit: true,
+				Description: "host abandoned the match"},
+		},
+		{
+			name: "success: a match nobody joined expires",
+			match: &Match{ID: "match", Status: MatchWaiting, Host: &MatchPlayer{Token: "host"},
+				Deadline: matchNow.Add(time.Minute)},
+			token:          "host",
+			expectedStatus: MatchExpired,
+		},
+		{
+			name:          "failure: if the token is not one of the players, return ErrNotMatchPlayer",
+			match:         playingMatch(nil, nil),
+			token:         "spectator",
+			expectedError: ErrNotMatchPlayer,
+		},
+		{
+			name: "failure: if the match is over, return ErrMatchClosed",
+			match: &Match{ID: "match", Status: MatchFinished, Host: &MatchPlayer{Token: "host"},
+				Guest: &MatchPlayer{Token: "guest"}, Deadline: matchNow.Add(time.Minute)},
+			token:         "host",
+			expectedError: ErrMatchClosed,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := MatchStoreMock{}
+		service := newTestMatchService(&storeMock, &RoundStoreMock{}, &ChoiceServiceMock{})
+		storeMock.On("UpdateMatch", "match").Return(tc.match, nil)
+
+		match, err := service.Abandon("match", tc.token)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedStatus, match.Status, tc.name)
+		require.Equal(t, tc.expectedOutcome, match.Outcome, tc.name)
+	}
+}
+
+func TestMatchService_Match(t *testing.T) {
+	rock := &baseChoices[0]
+	testCases := []struct {
+		name            string
+		match           *Match
+		token           string
+		expectedStatus  MatchStatus
+		expectedOutcome *MatchOutcome
+		expectHidden    bool
+	}{
+		{
+			name:           "success: the choice of the opponent stays hidden",
+			match:          playingMatch(nil, rock),
+			token:          "host",
+			expectedStatus: MatchPlaying,
+			expectHidden:   true,
+		},
+		{
+			name:           "success: spectators see no choice",
+			match:          playingMatch(nil, rock),
+			expectedStatus: MatchPlaying,
+			expectHidden:   true,
+		},
+		{
+			name: "success: a player who moved in time wins by forfeit",
+			match: &Match{ID: "match", Status: MatchPlaying, Host: &MatchPlayer{Token: "host"},
+				Guest:    &MatchPlayer{Token: "guest", Moved: true, Choice: rock},
+				Deadline: matchNow.Add(-time.Second)},
+			token:          "host",
+			expectedStatus: MatchFinished,
+			expectedOutcome: &MatchOutcome{Winner: Guest, Forfeit: true,
+				Description: "host did not move in time"},
+		},
+		{
+			name: "success: the match expires if nobody moved in time",
+			match: &Match{ID: "match", Status: MatchPlaying, Host: &MatchPlayer{Token: "host"},
+				Guest: &MatchPlayer{Token: "guest"}, Deadline: matchNow.Add(-time.Second)},
+			token:          "host",
+			expectedStatus: MatchExpired,
+			expectHidden:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := MatchStoreMock{}
+		service := newTestMatchService(&storeMock, &RoundStoreMock{}, &ChoiceServiceMock{})
+		storeMock.On("Match", "match").Return(tc.match, nil)
+
+		match, err := service.Match("match", tc.token)
+
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedStatus, match.Status, tc.name)
+		require.Equal(t, tc.expectedOutcome, match.Outcome, tc.name)
+		require.Equal(t, tc.expectHidden, match.Guest.Choice == nil, tc.name)
+		require.Empty(t, match.Guest.Token, tc.name)
+	}
+}
@@ -0,0 +1,554 @@
+package rpslsapi
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrInvalidMatchmaking = errors.New("matchmaking ratings and rating bands can't be negative")
+var ErrTicketNotFound = errors.New("not in the matchmaking queue nor in a match")
+var ErrNotMatched = errors.New("not matched with an opponent yet")
+var ErrMatchInProgress = errors.New("match still in progress")
+
+// reconnectGrace is how long a player who lost their connection has to resume their match before abandoning it
+const reconnectGrace = 30 * time.Second
+
+// matchEventBuffer is how many events are kept for a ticket whose connection is slow to read them. Further events are
+// dropped, the last ones being repeated by match_over anyway.
+const matchEventBuffer = 16
+
+// deadlineSlack delays the check of a match after its deadline, which only expires it once passed
+const deadlineSlack = time.Second
+
+type MatchEventType string
+
+const (
+	// MatchFound events are sent to both players once paired, with the match as seen by each of them
+	MatchFound MatchEventType = "match_found"
+	// MatchResumed events are sent to a player who resumed their match from a new connection
+	MatchResumed         MatchEventType = "match_resumed"
+	OpponentMoved        MatchEventType = "opponent_moved"
+	OpponentDisconnected MatchEventType = "opponent_disconnected"
+	OpponentReconnected  MatchEventType = "opponent_reconnected"
+	// MatchOver events are sent to both players once the match is finished or expired
+	MatchOver MatchEventType = "match_over"
+)
+
+// MatchmakingRequest describes the opponents a player accepts. Two players are paired if they accept the ruleset of
+// each other, an empty one accepting any, and if their rating gap is within the band of both, a zero band accepting
+// any rating. Ratings are declared by the players.
+type MatchmakingRequest struct {
+	Ruleset string `json:"ruleset"`
+	Rating  int    `json:"rating"`
+	Band    int    `json:"band"`
+}
+
+// MatchEvent is pushed to a player as their match progresses. Match is the match as seen by them and Side their side
+// in it. ReconnectDeadline is when a disconnected opponent abandons the match if they don't resume it.
+type MatchEvent struct {
+	Type              MatchEventType `json:"type"`
+	Match             *Match         `json:"match"`
+	Side              MatchSide      `json:"side"`
+	ReconnectDeadline *time.Time     `json:"reconnect_deadline,omitempty"`
+}
+
+// MatchmakingTicket is the place of a player in the queue, then in their match. Events is closed once the ticket is
+// dropped.
+type MatchmakingTicket struct {
+	ID     string
+	Events <-chan MatchEvent
+}
+
+// MatchmakingService pairs players into matches and pushes the progress of the matches to them. Tickets and matches
+// being tracked in-process, players must stay connected to the same instance.
+type MatchmakingService interface {
+	// Enqueue pairs the player with the oldest queued player they are compatible with, or queues them
+	Enqueue(request *MatchmakingRequest) (*MatchmakingTicket, error)
+	// Resume returns a new ticket for the player with the token in a paired match, dropping their previous one
+	Resume(matchID, token string) (*MatchmakingTicket, error)
+	// Move submits the choice of the player of the ticket in their match
+	Move(ticketID, choiceID string) (*Match, error)
+	// Forfeit makes the player of the ticket abandon their match
+	Forfeit(ticketID string) (*Match, error)
+	// Leave drops the ticket of a queued player or of a player whose match is over
+	Leave(ticketID string) error
+	// Disconnect drops the ticket of a player who lost their connection. A player in a match has reconnectGrace to
+	// resume it before abandoning it.
+	Disconnect(ticketID string)
+}
+
+type MatchmakingServiceImpl struct {
+	matchService   MatchService
+	choiceService  ChoiceService
+	reconnectGrace time.Duration
+	now            func() time.Time
+	state          *matchmakingState
+}
+
+// matchmakingState holds the queue and the matches in progress. Its lock only guards them: the match service is
+// called and the events are sent once it is released, so a slow match store doesn't hold up the other players.
+type matchmakingState struct {
+	mu sync.Mutex
+	// queue holds the tickets waiting for an opponent, oldest first
+	queue   []*ticket
+	tickets map[string]*ticket
+	rooms   map[string]*matchRoom
+}
+
+type ticket struct {
+	id      string
+	request MatchmakingRequest
+	// mu guards sending on events against closing them, which happen on either side of the state lock
+	mu     sync.Mutex
+	events chan MatchEvent
+	closed bool
+	// room is the match of the ticket, nil while queued
+	room *matchRoom
+	side MatchSide
+}
+
+// matchRoom tracks a paired match and the tickets of its players, which are missing while they are disconnected
+type matchRoom struct {
+	matchID       string
+	tokens        map[MatchSide]string
+	tickets       map[MatchSide]*ticket
+	abandonTimers map[MatchSide]*time.Timer
+	deadlineTimer *time.Timer
+	over          bool
+}
+
+// notification is an event collected under the state lock and sent once it is released, with the match as seen by
+// the player loaded unless it is already known
+type notification struct {
+	player            *ticket
+	matchID           string
+	token             string
+	eventType         MatchEventType
+	match             *Match
+	reconnectDeadline *time.Time
+}
+
+func NewMatchmakingService(matchService MatchService, choiceService ChoiceService) MatchmakingService {
+	return MatchmakingServiceImpl{
+		matchService:   matchService,
+		choiceService:  choiceService,
+		reconnectGrace: reconnectGrace,
+		now:            time.Now,
+		state:          &matchmakingState{tickets: map[string]*ticket{}, rooms: map[string]*matchRoom{}},
+	}
+}
+
+func (ms MatchmakingServiceImpl) Enqueue(request *MatchmakingRequest) (*MatchmakingTicket, error) {
+	if request.Rating < 0 || request.Band < 0 {
+		return nil, ErrInvalidMatchmaking
+	}
+	if request.Ruleset != "" {
+		if _, err := ms.choiceService.Choices(request.Ruleset); err != nil {
+			return nil, err
+		}
+	}
+
+	ms.state.mu.Lock()
+	player := ms.newTicket(request)
+	for i, opponent := range ms.state.queue {
+		if !compatible(&opponent.request, request) {
+			continue
+		}
+		// the opponent leaves the queue while the match is opened, so nobody else is paired with them
+		ms.state.queue = append(ms.state.queue[:i:i], ms.state.queue[i+1:]...)
+		ms.state.mu.Unlock()
+		return ms.pair(opponent, player, i)
+	}
+	ms.state.queue = append(ms.state.queue, player)
+	ms.state.mu.Unlock()
+	return player.public(), nil
+}
+
+func (ms MatchmakingServiceImpl) Resume(matchID, token string) (*MatchmakingTicket, error) {
+	ms.state.mu.Lock()
+	room := ms.state.rooms[matchID]
+	if room == nil {
+		ms.state.mu.Unlock()
+		return ms.resumeOver(matchID, token)
+	}
+	side := room.side(token)
+	if side == "" {
+		ms.state.mu.Unlock()
+		return nil, ErrNotMatchPlayer
+	}
+
+	if previous := room.tickets[side]; previous != nil {
+		ms.drop(previous)
+	}
+	if timer := room.abandonTimers[side]; timer != nil {
+		timer.Stop()
+		delete(room.abandonTimers, side)
+	}
+	player := ms.newTicket(&MatchmakingRequest{})
+	ms.join(room, player, side)
+	var events []notification
+	ms.notify(&events, room, side, MatchResumed, nil)
+	ms.notify(&events, room, side.opponent(), OpponentReconnected, nil)
+	ms.state.mu.Unlock()
+	ms.deliver(events)
+	return player.public(), nil
+}
+
+// resumeOver returns a ticket receiving match_over for a match which is no longer in progress, since its room is
+// dropped once it is over. Matches that weren't paired through matchmaking can't be resumed.
+func (ms MatchmakingServiceImpl) resumeOver(matchID, token string) (*MatchmakingTicket, error) {
+	match, err := ms.matchService.Match(matchID, token)
+	if err != nil {
+		return nil, err
+	}
+	player, side := match.player(token)
+	if player == nil {
+		return nil, ErrNotMatchPlayer
+	}
+	if match.Status != MatchFinished && match.Status != MatchExpired {
+		return nil, ErrMatchNotFound
+	}
+
+	room := &matchRoom{matchID: matchID, tokens: map[MatchSide]string{side: token}, tickets: map[MatchSide]*ticket{},
+		over: true}
+	ms.state.mu.Lock()
+	resumed := ms.newTicket(&MatchmakingRequest{})
+	ms.join(room, resumed, side)
+	ms.state.mu.Unlock()
+	resumed.send(MatchEvent{Type: MatchOver, Match: match, Side: side})
+	return resumed.public(), nil
+}
+
+func (ms MatchmakingServiceImpl) Move(ticketID, choiceID string) (*Match, error) {
+	ms.state.mu.Lock()
+	player, err := ms.playing(ticketID)
+	if err != nil {
+		ms.state.mu.Unlock()
+		return nil, err
+	}
+	room, side := player.room, player.side
+	ms.state.mu.Unlock()
+
+	match, err := ms.matchService.Move(room.matchID, room.tokens[side], choiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []notification
+	ms.state.mu.Lock()
+	if match.Status == MatchFinished || match.Status == MatchExpired {
+		ms.finish(&events, room)
+	} else if !room.over {
+		ms.notify(&events, room, side.opponent(), OpponentMoved, nil)
+	}
+	ms.state.mu.Unlock()
+	ms.deliver(events)
+	return match, nil
+}
+
+func (ms MatchmakingServiceImpl) Forfeit(ticketID string) (*Match, error) {
+	ms.state.mu.Lock()
+	player, err := ms.playing(ticketID)
+	if err != nil {
+		ms.state.mu.Unlock()
+		return nil, err
+	}
+	room, side := player.room, player.side
+	ms.state.mu.Unlock()
+
+	match, err := ms.matchService.Abandon(room.matchID, room.tokens[side])
+	if err != nil {
+		return nil, err
+	}
+
+	var events []notification
+	ms.state.mu.Lock()
+	ms.finish(&events, room)
+	ms.state.mu.Unlock()
+	ms.deliver(events)
+	return match, nil
+}
+
+func (ms MatchmakingServiceImpl) Leave(ticketID string) error {
+	ms.state.mu.Lock()
+	defer ms.state.mu.Unlock()
+	player := ms.state.tickets[ticketID]
+	switch {
+	case player == nil:
+		return ErrTicketNotFound
+	case player.room != nil && !player.room.over:
+		return ErrMatchInProgress
+	}
+	ms.drop(player)
+	return nil
+}
+
+func (ms MatchmakingServiceImpl) Disconnect(ticketID string) {
+	ms.state.mu.Lock()
+	player := ms.state.tickets[ticketID]
+	if player == nil {
+		ms.state.mu.Unlock()
+		return
+	}
+	ms.drop(player)
+	room, side := player.room, player.side
+	if room == nil || room.over {
+		ms.state.mu.Unlock()
+		return
+	}
+
+	var events []notification
+	ms.leaveRoom(&events, room, side)
+	ms.state.mu.Unlock()
+	ms.deliver(events)
+}
+
+// leaveRoom tells the opponent that the player of the side is disconnected and gives them reconnectGrace to resume
+// the match before abandoning it
+func (ms MatchmakingServiceImpl) leaveRoom(events *[]notification, room *matchRoom, side MatchSide) {
+	deadline := ms.now().Add(ms.reconnectGrace).UTC()
+	ms.notify(events, room, side.opponent(), OpponentDisconnected, &deadline)
+	room.abandonTimers[side] = time.AfterFunc(ms.reconnectGrace, func() { ms.abandonDisconnected(room, side) })
+}
+
+// abandonDisconnected makes a player who didn't resume their match in time abandon it
+func (ms MatchmakingServiceImpl) abandonDisconnected(room *matchRoom, side MatchSide) {
+	ms.state.mu.Lock()
+	if room.over || room.tickets[side] != nil {
+		ms.state.mu.Unlock()
+		return
+	}
+	ms.state.mu.Unlock()
+
+	if _, err := ms.matchService.Abandon(room.matchID, room.tokens[side]); err != nil && err != ErrMatchClosed {
+		log.Error().Err(err).Str("match", room.matchID).Msg("failed to abandon match of disconnected player")
+		return
+	}
+
+	var events []notification
+	ms.state.mu.Lock()
+	ms.finish(&events, room)
+	ms.state.mu.Unlock()
+	ms.deliver(events)
+}
+
+// pair opens a match between the opponent taken from the position of the queue, who hosts it, and the player. The
+// opponent goes back to their position if the match can't be opened.
+func (ms MatchmakingServiceImpl) pair(opponent, player *ticket, position int) (*MatchmakingTicket, error) {
+	ruleset := opponent.request.Ruleset
+	if ruleset == "" {
+		ruleset = player.request.Ruleset
+	}
+	hosted, err := ms.matchService.CreateMatch(ruleset)
+	var joined *Match
+	if err == nil {
+		joined, err = ms.matchService.JoinMatch(hosted.ID)
+	}
+
+	ms.state.mu.Lock()
+	if err != nil {
+		delete(ms.state.tickets, player.id)
+		if ms.state.tickets[opponent.id] == opponent {
+			if position > len(ms.state.queue) {
+				position = len(ms.state.queue)
+			}
+			ms.state.queue = append(ms.state.queue[:position:position],
+				append([]*ticket{opponent}, ms.state.queue[position:]...)...)
+		}
+		ms.state.mu.Unlock()
+		return nil, err
+	}
+
+	room := &matchRoom{
+		matchID:       joined.ID,
+		tokens:        map[MatchSide]string{Host: hosted.Host.Token, Guest: joined.Guest.Token},
+		tickets:       map[MatchSide]*ticket{},
+		abandonTimers: map[MatchSide]*time.Timer{},
+	}
+	ms.state.rooms[room.matchID] = room
+	ms.join(room, player, Guest)
+	events := []notification{{player: player, eventType: MatchFound, match: joined}}
+	// an opponent who disconnected while the match was opened abandons it unless they resume it
+	if ms.state.tickets[opponent.id] == opponent {
+		ms.join(room, opponent, Host)
+		ms.notify(&events, room, Host, MatchFound, nil)
+	} else {
+		ms.leaveRoom(&events, room, Host)
+	}
+	room.deadlineTimer = time.AfterFunc(joined.Deadline.Sub(ms.now())+deadlineSlack, func() {
+		ms.checkDeadline(room)
+	})
+	ms.state.mu.Unlock()
+	ms.deliver(events)
+	return player.public(), nil
+}
+
+// checkDeadline finishes the room once its match is over, so players who stopped moving are told about it
+func (ms MatchmakingServiceImpl) checkDeadline(room *matchRoom) {
+	ms.state.mu.Lock()
+	over := room.over
+	ms.state.mu.Unlock()
+	if over {
+		return
+	}
+
+	match, err := ms.matchService.Match(room.matchID, "")
+	if err != nil {
+		log.Error().Err(err).Str("match", room.matchID).Msg("failed to check match deadline")
+		return
+	}
+
+	var events []notification
+	ms.state.mu.Lock()
+	if match.Status == MatchFinished || match.Status == MatchExpired {
+		ms.finish(&events, room)
+	} else if !room.over {
+		room.deadlineTimer = time.AfterFunc(match.Deadline.Sub(ms.now())+deadlineSlack, func() {
+			ms.checkDeadline(room)
+		})
+	}
+	ms.state.mu.Unlock()
+	ms.deliver(events)
+}
+
+// finish collects match_over for both players and stops tracking the room, unless it is already over. Tickets stay
+// until their players leave.
+func (ms MatchmakingServiceImpl) finish(events *[]notification, room *matchRoom) {
+	if room.over {
+		return
+	}
+	room.over = true
+	if room.deadlineTimer != nil {
+		room.deadlineTimer.Stop()
+	}
+	for _, timer := range room.abandonTimers {
+		timer.Stop()
+	}
+	delete(ms.state.rooms, room.matchID)
+	ms.notify(events, room, Host, MatchOver, nil)
+	ms.notify(events, room, Guest, MatchOver, nil)
+}
+
+// notify collects the event for the player of the side, if they are connected
+func (ms MatchmakingServiceImpl) notify(events *[]notification, room *matchRoom, side MatchSide,
+	eventType MatchEventType, reconnectDeadline *time.Time) {
+	player := room.tickets[side]
+	if player == nil {
+		return
+	}
+	*events = append(*events, notification{player: player, matchID: room.matchID, token: room.tokens[side],
+		eventType: eventType, reconnectDeadline: reconnectDeadline})
+}
+
+// deliver sends the collected events with the match as seen by their players. It must be called without holding the
+// state lock.
+func (ms MatchmakingServiceImpl) deliver(events []notification) {
+	for _, event := range events {
+		match := event.match
+		if match == nil {
+			var err error
+			match, err = ms.matchService.Match(event.matchID, event.token)
+			if err != nil {
+				log.Error().Err(err).Str("match", event.matchID).Str("event", string(event.eventType)).
+					Msg("failed to load match to notify player")
+				continue
+			}
+		}
+		event.player.send(MatchEvent{Type: event.eventType, Match: match, Side: event.player.side,
+			ReconnectDeadline: event.reconnectDeadline})
+	}
+}
+
+// playing returns the ticket if its player is in a match in progress
+func (ms MatchmakingServiceImpl) playing(ticketID string) (*ticket, error) {
+	player := ms.state.tickets[ticketID]
+	switch {
+	case player == nil:
+		return nil, ErrTicketNotFound
+	case player.room == nil:
+		return nil, ErrNotMatched
+	case player.room.over:
+		return nil, ErrMatchClosed
+	}
+	return player, nil
+}
+
+func (ms MatchmakingServiceImpl) newTicket(request *MatchmakingRequest) *ticket {
+	player := &ticket{id: newToken(8), request: *request, events: make(chan MatchEvent, matchEventBuffer)}
+	ms.state.tickets[player.id] = player
+	return player
+}
+
+func (ms MatchmakingServiceImpl) join(room *matchRoom, player *ticket, side MatchSide) {
+	player.room = room
+	player.side = side
+	room.tickets[side] = player
+}
+
+// drop forgets the ticket and closes its events
+func (ms MatchmakingServiceImpl) drop(player *ticket) {
+	delete(ms.state.tickets, player.id)
+	for i, queued := range ms.state.queue {
+		if queued == player {
+			ms.state.queue = append(ms.state.queue[:i:i], ms.state.queue[i+1:]...)
+			break
+		}
+	}
+	if player.room != nil && player.room.tickets[player.side] == player {
+		delete(player.room.tickets, player.side)
+	}
+	player.mu.Lock()
+	defer player.mu.Unlock()
+	player.closed = true
+	close(player.events)
+}
+
+// send pushes the event without blocking on a slow connection. Events of a dropped ticket are discarded.
+func (t *ticket) send(event MatchEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+	select {
+	case t.events <- event:
+	default:
+		log.Info().Str("ticket", t.id).Str("event", string(event.Type)).Msg("dropped match event of slow ticket")
+	}
+}
+
+func (t *ticket) public() *MatchmakingTicket {
+	return &MatchmakingTicket{ID: t.id, Events: t.events}
+}
+
+// side returns the side of the player with the token, or an empty one if nobody in the room has it
+func (mr *matchRoom) side(token string) MatchSide {
+	for side, sideToken := range mr.tokens {
+		if token != "" && token == sideToken {
+			return side
+		}
+	}
+	return ""
+}
+
+func (side MatchSide) opponent() MatchSide {
+	if side == Host {
+		return Guest
+	}
+	return Host
+}
+
+// compatible tells whether both requests accept each other
+func compatible(first, second *MatchmakingRequest) bool {
+	if first.Ruleset != "" && second.Ruleset != "" && first.Ruleset != second.Ruleset {
+		return false
+	}
+	gap := first.Rating - second.Rating
+	if gap < 0 {
+		gap = -gap
+	}
+	return (first.Band == 0 || gap <= first.Band) && (second.Band == 0 || gap <= second.Band)
+}
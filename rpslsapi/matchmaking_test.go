@@ -0,0 +1,395 @@
+package rpslsapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MatchServiceMock struct {
+	mock.Mock
+}
+
+func (msm *MatchServiceMock) CreateMatch(ruleset string) (*Match, error) {
+	args := msm.Called(ruleset)
+	return args.Get(0).(*Match), args.Error(1)
+}
+
+func (msm *MatchServiceMock) JoinMatch(id string) (*Match, error) {
+	args := msm.Called(id)
+	return args.Get(0).(*Match), args.Error(1)
+}
+
+func (msm *MatchServiceMock) Match(id, token string) (*Match, error) {
+	args := msm.Called(id, token)
+	return args.Get(0).(*Match), args.Error(1)
+}
+
+func (msm *MatchServiceMock) Move(id, token, choiceID string) (*Match, error) {
+	args := msm.Called(id, token, choiceID)
+	return args.Get(0).(*Match), args.Error(1)
+}
+
+func (msm *MatchServiceMock) Abandon(id, token string) (*Match, error) {
+	args := msm.Called(id, token)
+	return args.Get(0).(*Match), args.Error(1)
+}
+
+func newTestMatchmakingService(matchServiceMock *MatchServiceMock,
+	choiceServiceMock *ChoiceServiceMock) MatchmakingServiceImpl {
+	return MatchmakingServiceImpl{
+		matchService:   matchServiceMock,
+		choiceService:  choiceServiceMock,
+		reconnectGrace: 20 * time.Millisecond,
+		now:            func() time.Time { return matchNow },
+		state:          &matchmakingState{tickets: map[string]*ticket{}, rooms: map[string]*matchRoom{}},
+	}
+}
+
+// expectPairing sets up the match service to open the match returned by playingMatch
+func expectPairing(matchServiceMock *MatchServiceMock, ruleset string) {
+	match := playingMatch(nil, nil)
+	hosted := &Match{ID: match.ID, Ruleset: match.Ruleset, Status: MatchWaiting, Host: match.Host}
+	matchServiceMock.On("CreateMatch", ruleset).Return(hosted, nil).Once()
+	matchServiceMock.On("JoinMatch", "match").Return(match.viewFor("guest"), nil).Once()
+	matchServiceMock.On("Match", "match", "host").Return(match.viewFor("host"), nil).Once()
+}
+
+// pairedTickets returns the tickets of the host and the guest of the match returned by playingMatch
+func pairedTickets(t *testing.T, service MatchmakingServiceImpl,
+	matchServiceMock *MatchServiceMock) (*MatchmakingTicket, *MatchmakingTicket) {
+	expectPairing(matchServiceMock, "")
+	host, err := service.Enqueue(&MatchmakingRequest{})
+	require.NoError(t, err)
+	guest, err := service.Enqueue(&MatchmakingRequest{})
+	require.NoError(t, err)
+	require.Equal(t, MatchFound, nextEvent(t, host).Type)
+	require.Equal(t, MatchFound, nextEvent(t, guest).Type)
+	return host, guest
+}
+
+func nextEvent(t *testing.T, ticket *MatchmakingTicket) MatchEvent {
+	select {
+	case event, open := <-ticket.Events:
+		require.True(t, open, "events closed")
+		return event
+	case <-time.After(time.Second):
+		require.FailNow(t, "no event received")
+	}
+	return MatchEvent{}
+}
+
+func requireNoEvent(t *testing.T, ticket *MatchmakingTicket, name string) {
+	select {
+	case event := <-ticket.Events:
+		require.FailNow(t, "unexpected event "+string(event.Type), name)
+	default:
+	}
+}
+
+func TestMatchmakingService_Enqueue(t *testing.T) {
+	testCases := []struct {
+		name            string
+		first           MatchmakingRequest
+		second          MatchmakingRequest
+		expectedRuleset string
+		expectedPaired  bool
+	}{
+		{
+			name:           "success: pair players accepting any ruleset",
+			expectedPaired: true,
+		},
+		{
+			name:            "success: pair players of the same ruleset",
+			first:           MatchmakingRequest{Ruleset: "rpsls"},
+			second:          MatchmakingRequest{Ruleset: "rpsls"},
+			expectedRuleset: "rpsls",
+			expectedPaired:  true,
+		},
+		{
+			name:            "success: play the ruleset of the player who chose one",
+			second:          MatchmakingRequest{Ruleset: "rps"},
+			expectedRuleset: "rps",
+			expectedPaired:  true,
+		},
+		{
+			name:           "success: pair players whose rating gap is within both bands",
+			first:          MatchmakingRequest{Rating: 1200, Band: 100},
+			second:         MatchmakingRequest{Rating: 1300, Band: 200},
+			expectedPaired: true,
+		},
+		{
+			name:   "success: queue players of different rulesets",
+			first:  MatchmakingRequest{Ruleset: "rpsls"},
+			second: MatchmakingRequest{Ruleset: "rps"},
+		},
+		{
+			name:   "success: queue players whose rating gap exceeds the band of one of them",
+			first:  MatchmakingRequest{Rating: 1200},
+			second: MatchmakingRequest{Rating: 1500, Band: 200},
+		},
+	}
+
+	for _, tc := range testCases {
+		matchServiceMock := MatchServiceMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		service := newTestMatchmakingService(&matchServiceMock, &choiceServiceMock)
+		choiceServiceMock.On("Choices", mock.Anything).Return(baseChoices, nil)
+		if tc.expectedPaired {
+			expectPairing(&matchServiceMock, tc.expectedRuleset)
+		}
+
+		first, err := service.Enqueue(&tc.first)
+		require.NoError(t, err, tc.name)
+		second, err := service.Enqueue(&tc.second)
+		require.NoError(t, err, tc.name)
+
+		matchServiceMock.AssertExpectations(t)
+		if !tc.expectedPaired {
+			require.Len(t, service.state.queue, 2, tc.name)
+			requireNoEvent(t, first, tc.name)
+			requireNoEvent(t, second, tc.name)
+			continue
+		}
+		require.Empty(t, service.state.queue, tc.name)
+		hostEvent, guestEvent := nextEvent(t, first), nextEvent(t, second)
+		require.Equal(t, MatchEvent{Type: MatchFound, Match: playingMatch(nil, nil).viewFor("host"), Side: Host},
+			hostEvent, tc.name)
+		require.Equal(t, MatchEvent{Type: MatchFound, Match: playingMatch(nil, nil).viewFor("guest"), Side: Guest},
+			guestEvent, tc.name)
+	}
+}
+
+func TestMatchmakingService_Enqueue_Invalid(t *testing.T) {
+	testCases := []struct {
+		name          string
+		request       MatchmakingRequest
+		expectedError error
+	}{
+		{
+			name:          "failure: if the rating is negative, return ErrInvalidMatchmaking",
+			request:       MatchmakingRequest{Rating: -1},
+			expectedError: ErrInvalidMatchmaking,
+		},
+		{
+			name:          "failure: if the band is negative, return ErrInvalidMatchmaking",
+			request:       MatchmakingRequest{Band: -100},
+			expectedError: ErrInvalidMatchmaking,
+		},
+		{
+			name:          "failure: if the ruleset doesn't exist, return ErrRulesetNotFound",
+			request:       MatchmakingRequest{Ruleset: "chess"},
+			expectedError: ErrRulesetNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		choiceServiceMock := ChoiceServiceMock{}
+		service := newTestMatchmakingService(&MatchServiceMock{}, &choiceServiceMock)
+		choiceServiceMock.On("Choices", "chess").Return([]Choice{}, ErrRulesetNotFound)
+
+		_, err := service.Enqueue(&tc.request)
+
+		require.Equal(t, tc.expectedError, err, tc.name)
+		require.Empty(t, service.state.queue, tc.name)
+	}
+}
+
+func TestMatchmakingService_Enqueue_PairingError(t *testing.T) {
+	matchServiceMock := MatchServiceMock{}
+	service := newTestMatchmakingService(&matchServiceMock, &ChoiceServiceMock{})
+	matchServiceMock.On("CreateMatch", "").Return((*Match)(nil), errors.New("store down"))
+
+	first, err := service.Enqueue(&MatchmakingRequest{})
+	require.NoError(t, err)
+	_, err = service.Enqueue(&MatchmakingRequest{})
+	require.Error(t, err)
+
+	require.Len(t, service.state.queue, 1)
+	require.Equal(t, first.ID, service.state.queue[0].id)
+	require.Len(t, service.state.tickets, 1)
+}
+
+// TestMatchmakingService_Enqueue_Disconnect disconnects the opponent while the match is opened, which would deadlock
+// if the state was locked while calling the match service
+func TestMatchmakingService_Enqueue_Disconnect(t *testing.T) {
+	match := playingMatch(nil, nil)
+	hosted := &Match{ID: match.ID, Ruleset: match.Ruleset, Status: MatchWaiting, Host: match.Host}
+	abandoned := playingMatch(nil, nil)
+	abandoned.Status = MatchFinished
+	matchServiceMock := MatchServiceMock{}
+	service := newTestMatchmakingService(&matchServiceMock, &ChoiceServiceMock{})
+	started, release := make(chan struct{}), make(chan struct{})
+	matchServiceMock.On("CreateMatch", "").Return(hosted, nil).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	})
+	matchServiceMock.On("JoinMatch", "match").Return(match.viewFor("guest"), nil)
+	matchServiceMock.On("Match", "match", "guest").Return(match.viewFor("guest"), nil).Once()
+	matchServiceMock.On("Abandon", "match", "host").Return(abandoned.viewFor("host"), nil).Once()
+	matchServiceMock.On("Match", "match", "guest").Return(abandoned.viewFor("guest"), nil).Once()
+
+	host, err := service.Enqueue(&MatchmakingRequest{})
+	require.NoError(t, err)
+	var guest *MatchmakingTicket
+	paired := make(chan struct{})
+	go func() {
+		guest, err = service.Enqueue(&MatchmakingRequest{})
+		close(paired)
+	}()
+	<-started
+	service.Disconnect(host.ID)
+	close(release)
+	<-paired
+
+	require.NoError(t, err)
+
+	require.Equal(t, MatchEvent{Type: MatchFound, Match: match.viewFor("guest"), Side: Guest}, nextEvent(t, guest))
+	require.Equal(t, OpponentDisconnected, nextEvent(t, guest).Type)
+	require.Equal(t, MatchEvent{Type: MatchOver, Match: abandoned.viewFor("guest"), Side: Guest},
+		nextEvent(t, guest))
+	matchServiceMock.AssertExpectations(t)
+}
+
+func TestMatchmakingService_Move(t *testing.T) {
+	rock, scissors := &baseChoices[0], &baseChoices[2]
+	moved := playingMatch(rock, nil)
+	finished := playingMatch(rock, scissors)
+	finished.Status = MatchFinished
+	finished.Outcome = &MatchOutcome{Winner: Host, Action: "crushes", Description: "Rock crushes Scissors"}
+
+	matchServiceMock := MatchServiceMock{}
+	service := newTestMatchmakingService(&matchServiceMock, &ChoiceServiceMock{})
+	host, guest := pairedTickets(t, service, &matchServiceMock)
+
+	matchServiceMock.On("Move", "match", "host", rock.ID).Return(moved.viewFor("host"), nil).Once()
+	matchServiceMock.On("Match", "match", "guest").Return(moved.viewFor("guest"), nil).Once()
+	match, err := service.Move(host.ID, rock.ID)
+	require.NoError(t, err)
+	require.Equal(t, moved.viewFor("host"), match)
+	require.Equal(t, MatchEvent{Type: OpponentMoved, Match: moved.viewFor("guest"), Side: Guest}, nextEvent(t, guest))
+	requireNoEvent(t, host, "the mover isn't notified")
+
+	matchServiceMock.On("Move", "match", "guest", scissors.ID).Return(finished.viewFor("guest"), nil).Once()
+	matchServiceMock.On("Match", "match", "host").Return(finished.viewFor("host"), nil).Once()
+	matchServiceMock.On("Match", "match", "guest").Return(finished.viewFor("guest"), nil).Once()
+	_, err = service.Move(guest.ID, scissors.ID)
+	require.NoError(t, err)
+	require.Equal(t, MatchEvent{Type: MatchOver, Match: finished.viewFor("host"), Side: Host}, nextEvent(t, host))
+	require.Equal(t, MatchEvent{Type: MatchOver, Match: finished.viewFor("guest"), Side: Guest}, nextEvent(t, guest))
+
+	_, err = service.Move(guest.ID, rock.ID)
+	require.Equal(t, ErrMatchClosed, err)
+	require.NoError(t, service.Leave(guest.ID))
+	_, err = service.Move(guest.ID, rock.ID)
+	require.Equal(t, ErrTicketNotFound, err)
+}
+
+func TestMatchmakingService_Disconnect(t *testing.T) {
+	abandoned := playingMatch(nil, nil)
+	abandoned.Status = MatchFinished
+	abandoned.Outcome = &MatchOutcome{Winner: Host, Forfeit: true, Description: "guest abandoned the match"}
+
+	matchServiceMock := MatchServiceMock{}
+	service := newTestMatchmakingService(&matchServiceMock, &ChoiceServiceMock{})
+	host, guest := pairedTickets(t, service, &matchServiceMock)
+	matchServiceMock.On("Match", "match", "host").Return(playingMatch(nil, nil).viewFor("host"), nil).Once()
+	matchServiceMock.On("Abandon", "match", "guest").Return(abandoned.viewFor("guest"), nil).Once()
+	matchServiceMock.On("Match", "match", "host").Return(abandoned.viewFor("host"), nil).Once()
+
+	service.Disconnect(guest.ID)
+
+	_, open := <-guest.Events
+	require.False(t, open)
+	deadline := matchNow.Add(service.reconnectGrace)
+	require.Equal(t, MatchEvent{Type: OpponentDisconnected, Match: playingMatch(nil, nil).viewFor("host"), Side: Host,
+		ReconnectDeadline: &deadline}, nextEvent(t, host))
+	require.Equal(t, MatchEvent{Type: MatchOver, Match: abandoned.viewFor("host"), Side: Host}, nextEvent(t, host))
+	matchServiceMock.AssertExpectations(t)
+}
+
+func TestMatchmakingService_Resume(t *testing.T) {
+	matchServiceMock := MatchServiceMock{}
+	service := newTestMatchmakingService(&matchServiceMock, &ChoiceServiceMock{})
+	host, guest := pairedTickets(t, service, &matchServiceMock)
+	matchServiceMock.On("Match", "match", "host").Return(playingMatch(nil, nil).viewFor("host"), nil)
+	matchServiceMock.On("Match", "match", "guest").Return(playingMatch(nil, nil).viewFor("guest"), nil)
+
+	service.Disconnect(guest.ID)
+	require.Equal(t, OpponentDisconnected, nextEvent(t, host).Type)
+	_, err := service.Resume("match", "spectator")
+	require.Equal(t, ErrNotMatchPlayer, err)
+	resumed, err := service.Resume("match", "guest")
+	require.NoError(t, err)
+
+	require.Equal(t, MatchEvent{Type: MatchResumed, Match: playingMatch(nil, nil).viewFor("guest"), Side: Guest},
+		nextEvent(t, resumed))
+	require.Equal(t, OpponentReconnected, nextEvent(t, host).Type)
+	time.Sleep(2 * service.reconnectGrace)
+	matchServiceMock.AssertNotCalled(t, "Abandon", "match", "guest")
+	require.Equal(t, ErrMatchInProgress, service.Leave(resumed.ID))
+}
+
+func TestMatchmakingService_Resume_Over(t *testing.T) {
+	expired := playingMatch(nil, nil)
+	expired.Status = MatchExpired
+	testCases := []struct {
+		name          string
+		match         *Match
+		token         string
+		expectedError error
+	}{
+		{
+			name:  "success: a match which is over can be resumed to receive match_over",
+			match: expired.viewFor("host"),
+			token: "host",
+		},
+		{
+			name:          "failure: if the match wasn't paired through matchmaking, return ErrMatchNotFound",
+			match:         playingMatch(nil, nil).viewFor("host"),
+			token:         "host",
+			expectedError: ErrMatchNotFound,
+		},
+		{
+			name:          "failure: if the token is not one of the players, return ErrNotMatchPlayer",
+			match:         expired.viewFor(""),
+			expectedError: ErrNotMatchPlayer,
+		},
+	}
+
+	for _, tc := range testCases {
+		matchServiceMock := MatchServiceMock{}
+		service := newTestMatchmakingService(&matchServiceMock, &ChoiceServiceMock{})
+		matchServiceMock.On("Match", "match", tc.token).Return(tc.match, nil)
+
+		resumed, err := service.Resume("match", tc.token)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, MatchEvent{Type: MatchOver, Match: tc.match, Side: Host}, nextEvent(t, resumed), tc.name)
+		_, err = service.Move(resumed.ID, "rpsls-rock")
+		require.Equal(t, ErrMatchClosed, err, tc.name)
+	}
+}
+
+func TestMatchmakingService_Leave(t *testing.T) {
+	matchServiceMock := MatchServiceMock{}
+	service := newTestMatchmakingService(&matchServiceMock, &ChoiceServiceMock{})
+
+	queued, err := service.Enqueue(&MatchmakingRequest{})
+	require.NoError(t, err)
+	require.NoError(t, service.Leave(queued.ID))
+	require.Empty(t, service.state.queue)
+	_, open := <-queued.Events
+	require.False(t, open)
+	require.Equal(t, ErrTicketNotFound, service.Leave(queued.ID))
+
+	host, _ := pairedTickets(t, service, &matchServiceMock)
+	require.Equal(t, ErrMatchInProgress, service.Leave(host.ID))
+}
@@ -0,0 +1,21 @@
+package rpslsapi
+
+// OutcomeCacheStats reports how the in-process outcome matrix cache has been used since the server started. Misses
+// count loads of rulesets that were not cached, refreshes count reloads of entries older than the TTL.
+type OutcomeCacheStats struct {
+	Hits           uint64   `json:"hits"`
+	Misses         uint64   `json:"misses"`
+	Refreshes      uint64   `json:"refreshes"`
+	Invalidations  uint64   `json:"invalidations"`
+	HitRatio       float64  `json:"hit_ratio"`
+	CachedRulesets []string `json:"cached_rulesets"`
+	TTL            string   `json:"ttl"`
+}
+
+// OutcomeCache keeps the choices and rules of each ruleset in memory, so rounds are decided without querying the
+// database.
+type OutcomeCache interface {
+	Stats() OutcomeCacheStats
+	// Invalidate drops the cached matrix of the ruleset, or of every ruleset if it is empty
+	Invalidate(ruleset string)
+}
@@ -1,7 +1,13 @@
 package rpslsapi
 
 import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
 	"errors"
+	"math/big"
+	"math/rand"
+	"time"
 )
 
 var ErrRandomNumberGenerationFailed = errors.New("failed to generate a random number")
@@ -11,6 +17,13 @@ type RandomizerService interface {
 	RandomInt() (int, error)
 }
 
+// IndexRandomizer is implemented by the randomizers which can draw uniformly among any number of items, whereas a value
+// of RandomInt modulo the number of items favors the first ones whenever that number doesn't divide 100
+type IndexRandomizer interface {
+	// RandomIndex generates a random int [0, n)
+	RandomIndex(n int) (int, error)
+}
+
 type RandomNumberResponse struct {
 	RandomNumber int `json:"random_number"`
 }
@@ -19,6 +32,15 @@ type RandomizerClient interface {
 	RandomNumber() (*RandomNumberResponse, error)
 }
 
+// NewRandomizerService draws from the random number server, except with the memory DB driver, which draws in process
+// like the memory drivers keep everything in process, so that tests and local development need no other service
+func NewRandomizerService(client RandomizerClient) RandomizerService {
+	if Config.DB.Driver == "memory" {
+		return LocalRandomizer{}
+	}
+	return NewExternalRandomizerService(client)
+}
+
 type ExternalRandomizerService struct {
 	client RandomizerClient
 }
@@ -35,3 +57,40 @@ func (ers ExternalRandomizerService) RandomInt() (int, error) {
 
 	return randomNumberResponse.RandomNumber, nil
 }
+
+// LocalRandomizer draws random ints in process from a cryptographically secure source, in place of the random number
+// server
+type LocalRandomizer struct{}
+
+func (lr LocalRandomizer) RandomInt() (int, error) {
+	n, err := crand.Int(crand.Reader, big.NewInt(100))
+	if err != nil {
+		return 0, ErrRandomNumberGenerationFailed
+	}
+	return int(n.Int64()) + 1, nil
+}
+
+// SeededRandomizer draws a sequence of random ints determined by its seed, without calling the random number server
+type SeededRandomizer struct {
+	random *rand.Rand
+}
+
+func NewSeededRandomizer(seed int64) SeededRandomizer {
+	return SeededRandomizer{rand.New(rand.NewSource(seed))}
+}
+
+// NewDailyRandomizer draws the same sequence for everyone on a given day, so every player of the daily challenge faces
+// the same computer choices. The sequence is seeded with the UTC date of the day and a secret, without which it could
+// be computed in advance.
+func NewDailyRandomizer(day time.Time, secret string) SeededRandomizer {
+	sum := sha256.Sum256([]byte(secret + ":" + day.UTC().Format(dailyDateLayout)))
+	return NewSeededRandomizer(int64(binary.BigEndian.Uint64(sum[:8])))
+}
+
+func (sr SeededRandomizer) RandomInt() (int, error) {
+	return sr.random.Intn(100) + 1, nil
+}
+
+func (sr SeededRandomizer) RandomIndex(n int) (int, error) {
+	return sr.random.Intn(n), nil
+}
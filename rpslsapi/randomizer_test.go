@@ -2,6 +2,7 @@ package rpslsapi
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -52,3 +53,60 @@ func TestExternalRandomizerService_RandomInt(t *testing.T) {
 		}
 	}
 }
+
+func TestSeededRandomizer_PickRandom(t *testing.T) {
+	// 100 values modulo 51 choices would draw two of them half as often as the others
+	choices := make([]Choice, 51)
+	for i := range choices {
+		choices[i] = Choice{ID: fmt.Sprintf("choice-%d", i)}
+	}
+	randomizer := NewSeededRandomizer(7)
+
+	picks := map[string]int{}
+	for i := 0; i < 1000*len(choices); i++ {
+		choice, err := pickRandom(choices, randomizer)
+		require.NoError(t, err)
+		picks[choice.ID]++
+	}
+
+	for _, choice := range choices {
+		require.InDelta(t, 1000, picks[choice.ID], 150, choice.ID)
+	}
+}
+
+func TestNewRandomizerService(t *testing.T) {
+	testCases := []struct {
+		name               string
+		driver             string
+		expectedRandomizer RandomizerService
+	}{
+		{
+			name:               "success: draw from the random number server with a database",
+			driver:             "neo4j",
+			expectedRandomizer: ExternalRandomizerService{},
+		},
+		{
+			name:               "success: draw in process with the memory driver",
+			driver:             "memory",
+			expectedRandomizer: LocalRandomizer{},
+		},
+	}
+
+	defer func() { Config.DB.Driver = "" }()
+	for _, tc := range testCases {
+		Config.DB.Driver = tc.driver
+
+		randomizer := NewRandomizerService(nil)
+
+		require.IsType(t, tc.expectedRandomizer, randomizer, tc.name)
+	}
+}
+
+func TestLocalRandomizer_RandomInt(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		randomInt, err := LocalRandomizer{}.RandomInt()
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, randomInt, 1)
+		require.LessOrEqual(t, randomInt, 100)
+	}
+}
@@ -1,10 +1,17 @@
 package rpslsapi
 
-import "github.com/rs/zerolog/log"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
 
 type Round struct {
-	WinnerID int64
-	LoserID  int64
+	WinnerID string
+	LoserID  string
 	Action   string
 }
 
@@ -17,72 +24,282 @@ const (
 )
 
 type RoundSettings struct {
-	Player int64 `json:"player"`
+	Player  string `json:"player"`
+	Ruleset string `json:"ruleset"`
+	// Commitment is the token of a round commitment, whose computer choice and ruleset the round is played with
+	Commitment string `json:"commitment,omitempty"`
+	// Strategy is the name or difficulty of the strategy the computer plays with, the default one of the user if empty
+	Strategy string `json:"strategy,omitempty"`
+	// RequestID is the ID of the HTTP request the round is played in, which is recorded in its audit
+	RequestID string `json:"-"`
+	// UserID is the ID of the authenticated player, empty for anonymous players whose rounds are neither added to a
+	// scoreboard nor to the history
+	UserID string `json:"-"`
 }
 
+// RoundResults describes the outcome of a round from the player's perspective. Player and Computer hold the IDs of the
+// chosen choices, while PlayerChoice and ComputerChoice hold the full choices.
 type RoundResults struct {
-	Results  string `json:"results"`
-	Player   int64  `json:"player"`
-	Computer int64  `json:"computer"`
+	// ID is only set on rounds played against the computer, identifying them in the history and audits
+	ID             string  `json:"id,omitempty"`
+	Results        string  `json:"results"`
+	Player         string  `json:"player"`
+	Computer       string  `json:"computer"`
+	Ruleset        string  `json:"ruleset"`
+	PlayerChoice   *Choice `json:"player_choice,omitempty"`
+	ComputerChoice *Choice `json:"computer_choice,omitempty"`
+	// Action is the verb of the BEATS relationship that decided the round, empty on ties
+	Action string `json:"action,omitempty"`
+	// Description is a human-readable summary of the round, e.g. "Paper covers Rock"
+	Description string `json:"description,omitempty"`
+	// Series, Score and Rounds are only set on scoreboard entries recording a whole series, whose results are those
+	// of the series
+	Series string         `json:"series,omitempty"`
+	Score  *SeriesScore   `json:"score,omitempty"`
+	Rounds []RoundResults `json:"rounds,omitempty"`
+	// Reveal is only set on rounds played against a commitment
+	Reveal *CommitmentReveal `json:"reveal,omitempty"`
+}
+
+// UnmarshalJSON also accepts results stored before choices were identified by slugs, deriving the slugs of their
+// numeric player and computer IDs from the embedded choices.
+func (r *RoundResults) UnmarshalJSON(data []byte) error {
+	type plainResults RoundResults
+	err := json.Unmarshal(data, (*plainResults)(r))
+	if _, legacy := err.(*json.UnmarshalTypeError); !legacy {
+		return err
+	}
+
+	var stored struct {
+		plainResults
+		Player         json.Number   `json:"player"`
+		Computer       json.Number   `json:"computer"`
+		PlayerChoice   *legacyChoice `json:"player_choice,omitempty"`
+		ComputerChoice *legacyChoice `json:"computer_choice,omitempty"`
+	}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	*r = RoundResults(stored.plainResults)
+	r.PlayerChoice, r.Player = stored.PlayerChoice.upgrade(stored.Player)
+	r.ComputerChoice, r.Computer = stored.ComputerChoice.upgrade(stored.Computer)
+	return nil
+}
+
+// legacyChoice is a choice as stored while choices were identified by numeric database IDs
+type legacyChoice struct {
+	ID      json.Number `json:"id"`
+	Name    string      `json:"name"`
+	Ruleset string      `json:"ruleset"`
+}
+
+// upgrade returns the choice with its slug ID, or nil and the numeric ID as a string when the choice is unknown
+func (c *legacyChoice) upgrade(id json.Number) (*Choice, string) {
+	if c == nil {
+		return nil, id.String()
+	}
+	choice := &Choice{ID: legacyChoiceID(c.Ruleset, c.Name), Name: c.Name, Ruleset: c.Ruleset}
+	return choice, choice.ID
+}
+
+// legacyChoiceID derives the slug the migrations 000005 gave to a choice identified by a numeric ID, which keeps
+// punctuation that NewChoiceID drops. The migrations also suffix with its numeric ID the slug of all but one of the
+// choices of a ruleset whose names only differ by case, which can't be told from the stored results.
+func legacyChoiceID(ruleset, name string) string {
+	return ruleset + "-" + strings.ToLower(strings.ReplaceAll(name, " ", "-"))
 }
 
 type RoundService interface {
 	Play(settings *RoundSettings) (*RoundResults, error)
+	// Commit picks the computer choice of a round of the ruleset in advance with the strategy, or the default one of
+	// the user, returning a single-use commitment to it which only the user may play
+	Commit(userID, ruleset, strategy string) (*RoundCommitment, error)
 }
 
 type RoundStore interface {
-	SimulateRound(choice1ID, choice2ID int64) (*Round, error)
+	// SimulateRound returns the round decided by the BEATS relationship between both choices, or ErrRuleNotFound
+	SimulateRound(choice1ID, choice2ID string) (*Round, error)
 }
 
 type RoundServiceImpl struct {
 	roundStore        RoundStore
 	choiceService     ChoiceService
 	scoreboardService ScoreboardService
+	commitmentStore   CommitmentStore
+	strategyService   StrategyService
+	historyService    HistoryService
+	auditService      AuditService
+	commitmentTTL     time.Duration
+	now               func() time.Time
 }
 
-func NewRoundService(roundStore RoundStore, choiceService ChoiceService, scoreboardService ScoreboardService) RoundService {
-	return RoundServiceImpl{roundStore: roundStore, choiceService: choiceService, scoreboardService: scoreboardService}
+func NewRoundService(roundStore RoundStore, choiceService ChoiceService, scoreboardService ScoreboardService,
+	commitmentStore CommitmentStore, strategyService StrategyService, historyService HistoryService,
+	auditService AuditService) RoundService {
+	return RoundServiceImpl{
+		roundStore:        roundStore,
+		choiceService:     choiceService,
+		scoreboardService: scoreboardService,
+		commitmentStore:   commitmentStore,
+		strategyService:   strategyService,
+		historyService:    historyService,
+		auditService:      auditService,
+		commitmentTTL:     Config.CommitmentTTL,
+		now:               time.Now,
+	}
 }
 
 func (rs RoundServiceImpl) Play(settings *RoundSettings) (*RoundResults, error) {
-	playerChoice, err := rs.choiceService.Choice(settings.Player)
+	var result *RoundResults
+	var move *ComputerMove
+	var err error
+	if settings.Commitment != "" {
+		result, move, err = rs.playCommitted(settings)
+	} else {
+		result, move, err = playAgainstComputer(rs.roundStore, rs.choiceService, rs.strategyService,
+			settings.UserID, settings)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	computerChoice, err := rs.choiceService.RandomChoice()
+	result.ID = newToken(8)
+	if err := rs.saveRoundResults(settings, result, move); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (rs RoundServiceImpl) Commit(userID, ruleset, strategy string) (*RoundCommitment, error) {
+	ruleset = RulesetOrDefault(ruleset)
+	move, err := rs.strategyService.ComputerChoice(userID, ruleset, strategy)
 	if err != nil {
 		return nil, err
 	}
+	committed := commit(move, ruleset, userID, rs.commitmentTTL, rs.now())
+	if err := rs.commitmentStore.SaveCommitment(committed, rs.commitmentTTL); err != nil {
+		return nil, err
+	}
+	return &committed.RoundCommitment, nil
+}
 
+// playCommitted plays the choice of the player against the computer choice of a commitment, which is used up even if
+// the choice of the player turns out to be invalid. Commitments of other users fail like unknown ones, so that a
+// leaked token can't be played by anyone else.
+func (rs RoundServiceImpl) playCommitted(settings *RoundSettings) (*RoundResults, *ComputerMove, error) {
+	committed, err := rs.commitmentStore.TakeCommitment(settings.Commitment)
+	if err != nil {
+		return nil, nil, err
+	}
+	if committed.UserID != settings.UserID {
+		return nil, nil, ErrCommitmentNotFound
+	}
+	settings.Ruleset = committed.Ruleset
+	playerChoice, err := rs.choiceService.Choice(settings.Player)
+	if err != nil {
+		return nil, nil, err
+	}
+	if playerChoice.Ruleset != committed.Ruleset {
+		return nil, nil, ErrChoiceNotFound
+	}
+
+	computerChoice, err := rs.choiceService.Choice(committed.Computer)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := decideRound(rs.roundStore, playerChoice, computerChoice)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Reveal = committed.reveal()
+
+	// commitments made before moves were kept only know the computer choice
+	move := committed.Move
+	if move == nil {
+		move = &ComputerMove{Choice: computerChoice}
+	}
+	return result, move, nil
+}
+
+// playAgainstComputer plays the choice of the player against the one picked by the strategy of the round or of the
+// user, without recording the results. The move of the computer is returned along with the results.
+func playAgainstComputer(roundStore RoundStore, choiceService ChoiceService, strategyService StrategyService,
+	userID string, settings *RoundSettings) (*RoundResults, *ComputerMove, error) {
+	settings.Ruleset = RulesetOrDefault(settings.Ruleset)
+	playerChoice, err := choiceService.Choice(settings.Player)
+	if err != nil {
+		return nil, nil, err
+	}
+	if playerChoice.Ruleset != settings.Ruleset {
+		return nil, nil, ErrChoiceNotFound
+	}
+
+	move, err := strategyService.ComputerChoice(userID, settings.Ruleset, settings.Strategy)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := decideRound(roundStore, playerChoice, move.Choice)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, move, nil
+}
+
+// decideRound returns the results of a round between both choices of the same ruleset
+func decideRound(roundStore RoundStore, playerChoice, computerChoice *Choice) (*RoundResults, error) {
 	result := &RoundResults{
-		Player:   playerChoice.ID,
-		Computer: computerChoice.ID,
+		Player:         playerChoice.ID,
+		Computer:       computerChoice.ID,
+		Ruleset:        playerChoice.Ruleset,
+		PlayerChoice:   playerChoice,
+		ComputerChoice: computerChoice,
 	}
 	if playerChoice.ID == computerChoice.ID {
 		result.Results = string(Tie)
-		rs.saveRoundResults(result)
+		result.Description = describeTie(phrases[TiePhrase], playerChoice)
 		return result, nil
 	}
 
-	simulatedRound, err := rs.roundStore.SimulateRound(playerChoice.ID, computerChoice.ID)
+	simulatedRound, err := roundStore.SimulateRound(playerChoice.ID, computerChoice.ID)
 	if err != nil {
 		return nil, err
 	}
 
+	result.Action = simulatedRound.Action
 	if simulatedRound.WinnerID == playerChoice.ID {
 		result.Results = string(Win)
+		result.Description = describeRound(playerChoice, simulatedRound.Action, computerChoice)
 	} else {
 		result.Results = string(Lose)
+		result.Description = describeRound(computerChoice, simulatedRound.Action, playerChoice)
 	}
-
-	rs.saveRoundResults(result)
 	return result, nil
 }
 
-func (rs RoundServiceImpl) saveRoundResults(results *RoundResults) {
-	err := rs.scoreboardService.Append(Config.DummyUserID, results)
+// saveRoundResults audits the round, and adds it to the scoreboard and the history of the player if authenticated.
+// A round that can't be audited isn't saved anywhere else, as it couldn't be settled in a dispute.
+func (rs RoundServiceImpl) saveRoundResults(settings *RoundSettings, results *RoundResults, move *ComputerMove) error {
+	audit := newRoundAudit(settings.RequestID, settings.UserID, rs.now(), results, move)
+	if err := rs.auditService.Record(audit); err != nil {
+		return err
+	}
+	if settings.UserID == "" {
+		return nil
+	}
+
+	err := rs.scoreboardService.Append(settings.UserID, results)
 	if err != nil {
 		log.Info().Msg("failed to save round to scoreboard")
 	}
+	recordHistory(rs.historyService, settings.UserID, results, "")
+	return nil
+}
+
+func describeRound(winner *Choice, action string, loser *Choice) string {
+	return fmt.Sprintf("%s %s %s", winner.Name, action, loser.Name)
+}
+
+// describeTie fills the tie phrase in with the choice both players played
+func describeTie(phrase string, choice *Choice) string {
+	return strings.ReplaceAll(phrase, "{choice}", choice.Name)
 }
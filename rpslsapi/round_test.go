@@ -1,6 +1,8 @@
 package rpslsapi
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -19,28 +21,68 @@ type ScoreboardServiceMock struct {
 	mock.Mock
 }
 
-func (rsm *RoundStoreMock) SimulateRound(choice1ID, choice2ID int64) (*Round, error) {
+func (rsm *RoundStoreMock) SimulateRound(choice1ID, choice2ID string) (*Round, error) {
 	args := rsm.Called(choice1ID, choice2ID)
 	return args.Get(0).(*Round), args.Error(1)
 }
 
-func (csm *ChoiceServiceMock) Choices() ([]Choice, error) {
-	args := csm.Called()
+func (csm *ChoiceServiceMock) Choices(ruleset string) ([]Choice, error) {
+	args := csm.Called(ruleset)
 	return args.Get(0).([]Choice), args.Error(1)
 }
 
-func (csm *ChoiceServiceMock) Choice(id int64) (*Choice, error) {
+func (csm *ChoiceServiceMock) Choice(id string) (*Choice, error) {
 	args := csm.Called(id)
 	return args.Get(0).(*Choice), args.Error(1)
 }
 
-func (csm *ChoiceServiceMock) RandomChoice() (*Choice, error) {
-	args := csm.Called()
+func (csm *ChoiceServiceMock) RandomChoice(ruleset string) (*Choice, error) {
+	args := csm.Called(ruleset)
 	return args.Get(0).(*Choice), args.Error(1)
 }
 
-func (ssm *ScoreboardServiceMock) Scoreboard(userID string) ([]RoundResults, error) {
-	args := ssm.Called(userID)
+func (csm *ChoiceServiceMock) CreateChoice(definition *ChoiceDefinition) (*Choice, error) {
+	args := csm.Called(definition)
+	return args.Get(0).(*Choice), args.Error(1)
+}
+
+func (csm *ChoiceServiceMock) UpdateChoice(choice *Choice) (*Choice, error) {
+	args := csm.Called(choice)
+	return args.Get(0).(*Choice), args.Error(1)
+}
+
+func (csm *ChoiceServiceMock) DeleteChoice(id string) error {
+	args := csm.Called(id)
+	return args.Error(0)
+}
+
+func (csm *ChoiceServiceMock) Rules(ruleset string) ([]Rule, error) {
+	args := csm.Called(ruleset)
+	return args.Get(0).([]Rule), args.Error(1)
+}
+
+func (csm *ChoiceServiceMock) SaveRule(rule *Rule) error {
+	args := csm.Called(rule)
+	return args.Error(0)
+}
+
+func (csm *ChoiceServiceMock) DeleteRule(winnerID, loserID string) error {
+	args := csm.Called(winnerID, loserID)
+	return args.Error(0)
+}
+
+func (csm *ChoiceServiceMock) ValidateRules(ruleset string) (*RuleGraphReport, error) {
+	args := csm.Called(ruleset)
+	return args.Get(0).(*RuleGraphReport), args.Error(1)
+}
+
+func (csm *ChoiceServiceMock) RenderRules(ruleset string, format GraphFormat) ([]byte, error) {
+	args := csm.Called(ruleset, format)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (ssm *ScoreboardServiceMock) Scoreboard(userID, ruleset string) ([]RoundResults, error) {
+	args := ssm.Called(userID, ruleset)
 	return args.Get(0).([]RoundResults), args.Error(1)
 }
 
@@ -49,21 +91,24 @@ func (ssm *ScoreboardServiceMock) Append(userID string, results *RoundResults) e
 	return args.Error(0)
 }
 
-func (ssm *ScoreboardServiceMock) Clear(userID string) error {
-	args := ssm.Called(userID)
+func (ssm *ScoreboardServiceMock) Clear(userID, ruleset string) error {
+	args := ssm.Called(userID, ruleset)
 	return args.Error(0)
 }
 
 func TestRoundService_Play(t *testing.T) {
-	const winnerChoiceID = int64(1)
-	const loserChoiceID = int64(2)
-	const missingChoiceID = int64(3)
+	const winnerChoiceID = "rpsls-paper"
+	const loserChoiceID = "rpsls-rock"
+	const missingChoiceID = "rpsls-well"
+	const otherRulesetChoiceID = "rps-rock"
 
 	testCases := []struct {
 		name                   string
-		playerChoiceID         int64
-		randomComputerChoiceID int64
+		playerChoiceID         string
+		randomComputerChoiceID string
 		expectedOutcome        string
+		expectedDescription    string
+		auditError             error
 		expectedError          error
 	}{
 		{
@@ -71,6 +116,7 @@ func TestRoundService_Play(t *testing.T) {
 			playerChoiceID:         winnerChoiceID,
 			randomComputerChoiceID: loserChoiceID,
 			expectedOutcome:        string(Win),
+			expectedDescription:    "Paper covers Rock",
 			expectedError:          nil,
 		},
 		{
@@ -78,6 +124,7 @@ func TestRoundService_Play(t *testing.T) {
 			playerChoiceID:         loserChoiceID,
 			randomComputerChoiceID: loserChoiceID,
 			expectedOutcome:        string(Tie),
+			expectedDescription:    "Both played Rock",
 			expectedError:          nil,
 		},
 		{
@@ -85,6 +132,7 @@ func TestRoundService_Play(t *testing.T) {
 			playerChoiceID:         loserChoiceID,
 			randomComputerChoiceID: winnerChoiceID,
 			expectedOutcome:        string(Lose),
+			expectedDescription:    "Paper covers Rock",
 			expectedError:          nil,
 		},
 		{
@@ -93,25 +141,54 @@ func TestRoundService_Play(t *testing.T) {
 			randomComputerChoiceID: winnerChoiceID,
 			expectedError:          ErrChoiceNotFound,
 		},
+		{
+			name:                   "failure: if choice belongs to another ruleset, return ErrChoiceNotFound",
+			playerChoiceID:         otherRulesetChoiceID,
+			randomComputerChoiceID: winnerChoiceID,
+			expectedError:          ErrChoiceNotFound,
+		},
+		{
+			name:                   "failure: if the round can't be audited, return the error",
+			playerChoiceID:         winnerChoiceID,
+			randomComputerChoiceID: loserChoiceID,
+			auditError:             errors.New("audit store down"),
+			expectedError:          errors.New("audit store down"),
+		},
 	}
 
 	storeMock := RoundStoreMock{}
 	choiceServiceMock := ChoiceServiceMock{}
 	scoreboardServiceMock := ScoreboardServiceMock{}
-	service := NewRoundService(&storeMock, &choiceServiceMock, &scoreboardServiceMock)
-	choiceServiceMock.On("Choice", winnerChoiceID).Return(&Choice{ID: winnerChoiceID}, nil)
-	choiceServiceMock.On("Choice", loserChoiceID).Return(&Choice{ID: loserChoiceID}, nil)
-	choiceServiceMock.On("Choice", int64(3)).Return((*Choice)(nil), ErrChoiceNotFound)
+	strategyMock := StrategyServiceMock{}
+	historyMock := HistoryServiceMock{}
+	winnerChoice := &Choice{ID: winnerChoiceID, Name: "Paper", Ruleset: "rpsls"}
+	loserChoice := &Choice{ID: loserChoiceID, Name: "Rock", Ruleset: "rpsls"}
+	choiceServiceMock.On("Choice", winnerChoiceID).Return(winnerChoice, nil)
+	choiceServiceMock.On("Choice", loserChoiceID).Return(loserChoice, nil)
+	choiceServiceMock.On("Choice", missingChoiceID).Return((*Choice)(nil), ErrChoiceNotFound)
+	choiceServiceMock.On("Choice", otherRulesetChoiceID).Return(&Choice{ID: otherRulesetChoiceID, Ruleset: "rps"}, nil)
 	storeMock.On("SimulateRound", winnerChoiceID, loserChoiceID).
-		Return(&Round{WinnerID: winnerChoiceID, LoserID: loserChoiceID}, nil)
+		Return(&Round{WinnerID: winnerChoiceID, LoserID: loserChoiceID, Action: "covers"}, nil)
 	storeMock.On("SimulateRound", loserChoiceID, winnerChoiceID).
-		Return(&Round{WinnerID: winnerChoiceID, LoserID: loserChoiceID}, nil)
+		Return(&Round{WinnerID: winnerChoiceID, LoserID: loserChoiceID, Action: "covers"}, nil)
 	scoreboardServiceMock.On("Append", mock.Anything, mock.Anything).Return(nil)
+	historyMock.On("Record", "user", mock.Anything, "").Return(nil)
 
 	for _, tc := range testCases {
-		choiceServiceMock.On("RandomChoice").Return(&Choice{ID: tc.randomComputerChoiceID}, nil).Once()
+		auditMock := AuditServiceMock{}
+		auditMock.On("Record", mock.Anything).Return(tc.auditError)
+		service := NewRoundService(&storeMock, &choiceServiceMock, &scoreboardServiceMock, &CommitmentStoreMock{},
+			&strategyMock, &historyMock, &auditMock)
+		computerChoice := loserChoice
+		if tc.randomComputerChoiceID == winnerChoiceID {
+			computerChoice = winnerChoice
+		}
+		move := &ComputerMove{Choice: computerChoice, Strategy: "win-stay-lose-shift", Choices: baseChoices,
+			RandomValues: []int{}}
+		strategyMock.On("ComputerChoice", "user", "rpsls", "hard").Return(move, nil).Once()
 
-		results, err := service.Play(&RoundSettings{Player: tc.playerChoiceID})
+		results, err := service.Play(&RoundSettings{Player: tc.playerChoiceID, Ruleset: "rpsls", Strategy: "hard",
+			RequestID: "request", UserID: "user"})
 
 		if tc.expectedError != nil {
 			require.NotNil(t, t, err)
@@ -119,12 +196,81 @@ func TestRoundService_Play(t *testing.T) {
 		} else {
 			require.NotNil(t, results)
 			require.Equal(t, tc.expectedOutcome, results.Results)
-			choiceServiceMock.AssertCalled(t, "RandomChoice")
+			require.Equal(t, "rpsls", results.Ruleset)
+			require.Equal(t, tc.expectedDescription, results.Description)
+			require.Equal(t, tc.playerChoiceID, results.PlayerChoice.ID)
+			require.Equal(t, tc.randomComputerChoiceID, results.ComputerChoice.ID)
+			strategyMock.AssertCalled(t, "ComputerChoice", "user", "rpsls", "hard")
 			choiceServiceMock.AssertCalled(t, "Choice", tc.playerChoiceID)
 			if results.Results != string(Tie) {
 				storeMock.AssertCalled(t, "SimulateRound", tc.playerChoiceID, tc.randomComputerChoiceID)
 			}
 			scoreboardServiceMock.AssertCalled(t, "Append", mock.Anything, mock.Anything)
+			historyMock.AssertCalled(t, "Record", "user", results, "")
+			audit := auditMock.Calls[len(auditMock.Calls)-1].Arguments.Get(0).(*RoundAudit)
+			require.Equal(t, results.ID, audit.ID)
+			require.Equal(t, "request", audit.RequestID)
+			require.Equal(t, "user", audit.UserID)
+			require.Equal(t, move.Choices, audit.Choices)
+			require.Equal(t, results.Results, audit.replayResults())
+		}
+	}
+}
+
+func TestRoundResults_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name            string
+		stored          string
+		expectedResults RoundResults
+		expectedError   bool
+	}{
+		{
+			name: "success: decode results with choice slugs",
+			stored: `{"results":"win","player":"rpsls-paper","computer":"rpsls-rock","ruleset":"rpsls",` +
+				`"action":"covers"}`,
+			expectedResults: RoundResults{Results: "win", Player: "rpsls-paper", Computer: "rpsls-rock",
+				Ruleset: "rpsls", Action: "covers"},
+		},
+		{
+			name: "success: derive the slugs of legacy numeric choice IDs from the stored choices",
+			stored: `{"results":"lose","player":1,"computer":2,"ruleset":"rpsls",` +
+				`"player_choice":{"id":1,"name":"Rock","ruleset":"rpsls"},` +
+				`"computer_choice":{"id":2,"name":"Paper","ruleset":"rpsls"},"description":"Paper covers Rock"}`,
+			expectedResults: RoundResults{Results: "lose", Player: "rpsls-rock", Computer: "rpsls-paper",
+				Ruleset: "rpsls", PlayerChoice: &Choice{ID: "rpsls-rock", Name: "Rock", Ruleset: "rpsls"},
+				ComputerChoice: &Choice{ID: "rpsls-paper", Name: "Paper", Ruleset: "rpsls"},
+				Description:    "Paper covers Rock"},
+		},
+		{
+			name: "success: derive the slugs of legacy numeric choice IDs like the migrations",
+			stored: `{"results":"win","player":4,"computer":5,"ruleset":"rpsls",` +
+				`"player_choice":{"id":4,"name":"Spock!","ruleset":"rpsls"},` +
+				`"computer_choice":{"id":5,"name":"Lizard  King","ruleset":"rpsls"}}`,
+			expectedResults: RoundResults{Results: "win", Player: "rpsls-spock!", Computer: "rpsls-lizard--king",
+				Ruleset: "rpsls", PlayerChoice: &Choice{ID: "rpsls-spock!", Name: "Spock!", Ruleset: "rpsls"},
+				ComputerChoice: &Choice{ID: "rpsls-lizard--king", Name: "Lizard  King", Ruleset: "rpsls"}},
+		},
+		{
+			name:            "success: keep legacy numeric choice IDs without stored choices",
+			stored:          `{"results":"tie","player":3,"computer":3,"ruleset":"rpsls"}`,
+			expectedResults: RoundResults{Results: "tie", Player: "3", Computer: "3", Ruleset: "rpsls"},
+		},
+		{
+			name:          "failure: if results is not a string, return error",
+			stored:        `{"results":1,"player":3,"computer":3}`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		var results RoundResults
+		err := json.Unmarshal([]byte(tc.stored), &results)
+
+		if tc.expectedError {
+			require.Error(t, err, tc.name)
+		} else {
+			require.NoError(t, err, tc.name)
+			require.Equal(t, tc.expectedResults, results, tc.name)
 		}
 	}
 }
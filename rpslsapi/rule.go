@@ -0,0 +1,19 @@
+package rpslsapi
+
+import "errors"
+
+var ErrRuleNotFound = errors.New("rule not found")
+var ErrInvalidRule = errors.New("invalid rule")
+
+// Rule is a BEATS relationship: the winner choice beats the loser choice with the given action.
+type Rule struct {
+	WinnerID string `json:"winner"`
+	LoserID  string `json:"loser"`
+	Action   string `json:"action"`
+}
+
+// Relationship is one side of a rule, as seen from the choice that owns it.
+type Relationship struct {
+	ChoiceID string `json:"choice"`
+	Action   string `json:"action"`
+}
@@ -0,0 +1,192 @@
+package rpslsapi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrInconsistentRuleGraph = errors.New("inconsistent rule graph")
+
+type RuleIssueKind string
+
+const (
+	MissingRule      RuleIssueKind = "missing_rule"
+	DuplicateRule    RuleIssueKind = "duplicate_rule"
+	MutualRule       RuleIssueKind = "mutual_rule"
+	SelfLoop         RuleIssueKind = "self_loop"
+	DanglingRule     RuleIssueKind = "dangling_rule"
+	UnbalancedChoice RuleIssueKind = "unbalanced_choice"
+)
+
+type RuleIssueSeverity string
+
+const (
+	SeverityError   RuleIssueSeverity = "error"
+	SeverityWarning RuleIssueSeverity = "warning"
+)
+
+type RuleIssue struct {
+	Kind      RuleIssueKind     `json:"kind"`
+	Severity  RuleIssueSeverity `json:"severity"`
+	ChoiceIDs []string          `json:"choices"`
+	Message   string            `json:"message"`
+}
+
+// RuleGraphReport is the outcome of analysing the whole choice/BEATS graph. The graph is valid when every pair of
+// choices is related by exactly one BEATS edge; warnings do not invalidate it.
+type RuleGraphReport struct {
+	Valid   bool        `json:"valid"`
+	Choices int         `json:"choices"`
+	Rules   int         `json:"rules"`
+	Issues  []RuleIssue `json:"issues"`
+}
+
+// RuleGraphError is returned when a write would introduce errors in the rule graph. It matches
+// ErrInconsistentRuleGraph through errors.Is.
+type RuleGraphError struct {
+	Report *RuleGraphReport
+}
+
+func (e *RuleGraphError) Error() string {
+	return fmt.Sprintf("%v: %d issue(s) found", ErrInconsistentRuleGraph, len(e.Report.Issues))
+}
+
+func (e *RuleGraphError) Is(target error) bool {
+	return target == ErrInconsistentRuleGraph
+}
+
+type choicePair struct {
+	first, second string
+}
+
+func newChoicePair(choice1ID, choice2ID string) choicePair {
+	if choice1ID > choice2ID {
+		return choicePair{choice2ID, choice1ID}
+	}
+	return choicePair{choice1ID, choice2ID}
+}
+
+// ValidateRuleGraph checks that every pair of choices has exactly one BEATS edge, reporting missing pairs,
+// duplicate or mutual edges, self-loops, edges to unknown choices and choices whose win count is unbalanced.
+func ValidateRuleGraph(choices []Choice, rules []Rule) *RuleGraphReport {
+	report := &RuleGraphReport{Choices: len(choices), Rules: len(rules), Issues: []RuleIssue{}}
+
+	sorted := make([]Choice, len(choices))
+	copy(sorted, choices)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	names := make(map[string]string, len(sorted))
+	for _, choice := range sorted {
+		names[choice.ID] = choice.Name
+	}
+
+	pairs := make(map[choicePair][]Rule)
+	wins := make(map[string]int, len(sorted))
+	for _, rule := range rules {
+		_, winnerFound := names[rule.WinnerID]
+		_, loserFound := names[rule.LoserID]
+		switch {
+		case rule.WinnerID == rule.LoserID:
+			report.addError(SelfLoop, fmt.Sprintf("%s beats itself", choiceName(names, rule.WinnerID)),
+				rule.WinnerID)
+		case !winnerFound || !loserFound:
+			report.addError(DanglingRule, fmt.Sprintf("rule %s -> %s references an unknown choice",
+				rule.WinnerID, rule.LoserID), rule.WinnerID, rule.LoserID)
+		default:
+			pair := newChoicePair(rule.WinnerID, rule.LoserID)
+			pairs[pair] = append(pairs[pair], rule)
+			wins[rule.WinnerID]++
+		}
+	}
+
+	for i := range sorted {
+		for j := i + 1; j < len(sorted); j++ {
+			first, second := sorted[i], sorted[j]
+			pairRules := pairs[newChoicePair(first.ID, second.ID)]
+			switch {
+			case len(pairRules) == 0:
+				report.addError(MissingRule, fmt.Sprintf("%s and %s have no BEATS relationship",
+					first.Name, second.Name), first.ID, second.ID)
+			case len(pairRules) > 1 && isMutual(pairRules):
+				report.addError(MutualRule, fmt.Sprintf("%s and %s beat each other", first.Name, second.Name),
+					first.ID, second.ID)
+			case len(pairRules) > 1:
+				report.addError(DuplicateRule, fmt.Sprintf("%s beats %s more than once",
+					choiceName(names, pairRules[0].WinnerID), choiceName(names, pairRules[0].LoserID)),
+					first.ID, second.ID)
+			}
+		}
+	}
+
+	// with an even number of choices, each one plays an odd number of others and can't beat exactly half of them:
+	// the closest to balanced is beating one more or one less than it loses to
+	minWins, maxWins := (len(sorted)-1)/2, len(sorted)/2
+	for _, choice := range sorted {
+		if wins[choice.ID] < minWins || wins[choice.ID] > maxWins {
+			expected := fmt.Sprint(minWins)
+			if minWins != maxWins {
+				expected = fmt.Sprintf("%d or %d", minWins, maxWins)
+			}
+			report.Issues = append(report.Issues, RuleIssue{
+				Kind:      UnbalancedChoice,
+				Severity:  SeverityWarning,
+				ChoiceIDs: []string{choice.ID},
+				Message: fmt.Sprintf("%s beats %d choice(s), a balanced game needs %s",
+					choice.Name, wins[choice.ID], expected),
+			})
+		}
+	}
+
+	report.Valid = len(report.errors()) == 0
+	return report
+}
+
+func (r *RuleGraphReport) addError(kind RuleIssueKind, message string, choiceIDs ...string) {
+	r.Issues = append(r.Issues, RuleIssue{Kind: kind, Severity: SeverityError, ChoiceIDs: choiceIDs, Message: message})
+}
+
+func (r *RuleGraphReport) errors() []RuleIssue {
+	var errs []RuleIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+// introducedErrors returns the errors in this report that were not already present in the previous one
+func (r *RuleGraphReport) introducedErrors(previous *RuleGraphReport) []RuleIssue {
+	existing := make(map[string]bool)
+	for _, issue := range previous.errors() {
+		existing[issueKey(issue)] = true
+	}
+
+	var introduced []RuleIssue
+	for _, issue := range r.errors() {
+		if !existing[issueKey(issue)] {
+			introduced = append(introduced, issue)
+		}
+	}
+	return introduced
+}
+
+func issueKey(issue RuleIssue) string {
+	return fmt.Sprintf("%s%v", issue.Kind, issue.ChoiceIDs)
+}
+
+func isMutual(rules []Rule) bool {
+	for _, rule := range rules[1:] {
+		if rule.WinnerID != rules[0].WinnerID {
+			return true
+		}
+	}
+	return false
+}
+
+func choiceName(names map[string]string, id string) string {
+	if name, found := names[id]; found {
+		return name
+	}
+	return fmt.Sprintf("choice %s", id)
+}
@@ -0,0 +1,164 @@
+package rpslsapi
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+type GraphFormat string
+
+const (
+	DOTFormat     GraphFormat = "dot"
+	MermaidFormat GraphFormat = "mermaid"
+	SVGFormat     GraphFormat = "svg"
+)
+
+// svg layout, in pixels: choices are drawn as circles evenly spread on a larger circle
+const (
+	svgNodeRadius   = 34.0
+	svgMargin       = 60.0
+	svgMinRadius    = 120.0
+	svgRadiusByNode = 24.0
+)
+
+// RenderRuleGraph draws the choices of a ruleset and their BEATS relationships, labelled with their verbs, in the given
+// format. Relationships to unknown choices are left out.
+func RenderRuleGraph(w io.Writer, ruleset string, choices []Choice, rules []Rule, format GraphFormat) error {
+	choices, rules = sortedGraph(choices, rules)
+
+	var buffer bytes.Buffer
+	switch format {
+	case DOTFormat:
+		renderDOT(&buffer, ruleset, choices, rules)
+	case MermaidFormat:
+		renderMermaid(&buffer, choices, rules)
+	case SVGFormat:
+		renderSVG(&buffer, ruleset, choices, rules)
+	default:
+		return fmt.Errorf("unknown graph format %q", format)
+	}
+
+	_, err := buffer.WriteTo(w)
+	return err
+}
+
+// sortedGraph returns the choices sorted by ID and the rules between them sorted by winner and loser, so renderings
+// are stable
+func sortedGraph(choices []Choice, rules []Rule) ([]Choice, []Rule) {
+	sortedChoices := append([]Choice{}, choices...)
+	sort.Slice(sortedChoices, func(i, j int) bool { return sortedChoices[i].ID < sortedChoices[j].ID })
+	known := make(map[string]bool, len(choices))
+	for _, choice := range choices {
+		known[choice.ID] = true
+	}
+
+	var sortedRules []Rule
+	for _, rule := range rules {
+		if known[rule.WinnerID] && known[rule.LoserID] {
+			sortedRules = append(sortedRules, rule)
+		}
+	}
+	sort.SliceStable(sortedRules, func(i, j int) bool {
+		if sortedRules[i].WinnerID != sortedRules[j].WinnerID {
+			return sortedRules[i].WinnerID < sortedRules[j].WinnerID
+		}
+		return sortedRules[i].LoserID < sortedRules[j].LoserID
+	})
+	return sortedChoices, sortedRules
+}
+
+// nodeNames names the node of every choice after its position, as choice IDs aren't valid identifiers in every format
+func nodeNames(choices []Choice) map[string]string {
+	names := make(map[string]string, len(choices))
+	for i, choice := range choices {
+		names[choice.ID] = fmt.Sprintf("c%d", i)
+	}
+	return names
+}
+
+func renderDOT(w io.Writer, ruleset string, choices []Choice, rules []Rule) {
+	nodes := nodeNames(choices)
+	fmt.Fprintf(w, "digraph %s {\n", dotQuote(ruleset))
+	fmt.Fprintln(w, "  node [shape=circle];")
+	for _, choice := range choices {
+		fmt.Fprintf(w, "  %s [label=%s];\n", nodes[choice.ID], dotQuote(choice.Name))
+	}
+	for _, rule := range rules {
+		fmt.Fprintf(w, "  %s -> %s [label=%s];\n", nodes[rule.WinnerID], nodes[rule.LoserID], dotQuote(rule.Action))
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func renderMermaid(w io.Writer, choices []Choice, rules []Rule) {
+	nodes := nodeNames(choices)
+	fmt.Fprintln(w, "flowchart LR")
+	for _, choice := range choices {
+		fmt.Fprintf(w, "  %s((%s))\n", nodes[choice.ID], mermaidQuote(choice.Name))
+	}
+	for _, rule := range rules {
+		fmt.Fprintf(w, "  %s -->|%s| %s\n", nodes[rule.WinnerID], mermaidQuote(rule.Action), nodes[rule.LoserID])
+	}
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s) + `"`
+}
+
+type svgPoint struct {
+	x, y float64
+}
+
+func renderSVG(w io.Writer, ruleset string, choices []Choice, rules []Rule) {
+	radius := math.Max(svgMinRadius, svgRadiusByNode*float64(len(choices)))
+	size := 2 * (radius + svgNodeRadius + svgMargin)
+	center := size / 2
+
+	positions := make(map[string]svgPoint, len(choices))
+	for i, choice := range choices {
+		angle := -math.Pi/2 + 2*math.Pi*float64(i)/float64(len(choices))
+		positions[choice.ID] = svgPoint{center + radius*math.Cos(angle), center + radius*math.Sin(angle)}
+	}
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" `+
+		`font-family="sans-serif" font-size="12">`+"\n", size, size, size, size)
+	fmt.Fprintf(w, "  <title>%s</title>\n", html.EscapeString(ruleset))
+	fmt.Fprintln(w, `  <defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" `+
+		`markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="#555"/></marker></defs>`)
+
+	for _, rule := range rules {
+		from, to := positions[rule.WinnerID], positions[rule.LoserID]
+		length := math.Hypot(to.x-from.x, to.y-from.y)
+		if length <= 2*svgNodeRadius {
+			continue
+		}
+		dx, dy := (to.x-from.x)/length, (to.y-from.y)/length
+		start := svgPoint{from.x + dx*svgNodeRadius, from.y + dy*svgNodeRadius}
+		end := svgPoint{to.x - dx*svgNodeRadius, to.y - dy*svgNodeRadius}
+		// labels sit closer to the winner, so the labels of crossing edges don't overlap at the center
+		label := svgPoint{start.x + (end.x-start.x)*0.4, start.y + (end.y-start.y)*0.4}
+
+		fmt.Fprintf(w, `  <line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#555" marker-end="url(#arrow)"/>`+"\n",
+			start.x, start.y, end.x, end.y)
+		fmt.Fprintf(w, `  <text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="middle" fill="#333" `+
+			`stroke="#fff" stroke-width="3" paint-order="stroke">%s</text>`+"\n",
+			label.x, label.y, html.EscapeString(rule.Action))
+	}
+
+	for _, choice := range choices {
+		position := positions[choice.ID]
+		fmt.Fprintf(w, `  <circle cx="%.1f" cy="%.1f" r="%.0f" fill="#e8f0fe" stroke="#1a73e8" stroke-width="2"/>`+"\n",
+			position.x, position.y, svgNodeRadius)
+		fmt.Fprintf(w, `  <text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n",
+			position.x, position.y, html.EscapeString(choice.Name))
+	}
+	fmt.Fprintln(w, "</svg>")
+}
@@ -0,0 +1,98 @@
+package rpslsapi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var rpsChoices = []Choice{
+	{ID: "rps-scissors", Name: "Scissors", Ruleset: "rps"},
+	{ID: "rps-rock", Name: "Rock", Ruleset: "rps"},
+	{ID: "rps-paper", Name: "Paper", Ruleset: "rps"},
+}
+
+var rpsRules = []Rule{
+	{WinnerID: "rps-scissors", LoserID: "rps-paper", Action: "cuts"},
+	{WinnerID: "rps-rock", LoserID: "rps-scissors", Action: "crushes"},
+	{WinnerID: "rps-paper", LoserID: "rps-rock", Action: "covers"},
+	{WinnerID: "rps-paper", LoserID: "rps-well", Action: "dangles"},
+}
+
+func TestRenderRuleGraph_DOT(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, RenderRuleGraph(&output, "rps", rpsChoices, rpsRules, DOTFormat))
+
+	require.Equal(t, `digraph "rps" {
+  node [shape=circle];
+  c0 [label="Paper"];
+  c1 [label="Rock"];
+  c2 [label="Scissors"];
+  c0 -> c1 [label="covers"];
+  c1 -> c2 [label="crushes"];
+  c2 -> c0 [label="cuts"];
+}
+`, output.String())
+}
+
+func TestRenderRuleGraph_Mermaid(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, RenderRuleGraph(&output, "rps", rpsChoices, rpsRules, MermaidFormat))
+
+	require.Equal(t, `flowchart LR
+  c0(("Paper"))
+  c1(("Rock"))
+  c2(("Scissors"))
+  c0 -->|"covers"| c1
+  c1 -->|"crushes"| c2
+  c2 -->|"cuts"| c0
+`, output.String())
+}
+
+func TestRenderRuleGraph_SVG(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, RenderRuleGraph(&output, "rps", rpsChoices, rpsRules, SVGFormat))
+
+	elements := make(map[string]int)
+	var texts []string
+	decoder := xml.NewDecoder(&output)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		switch element := token.(type) {
+		case xml.StartElement:
+			elements[element.Name.Local]++
+		case xml.CharData:
+			if text := strings.TrimSpace(string(element)); text != "" {
+				texts = append(texts, text)
+			}
+		}
+	}
+
+	require.Equal(t, 3, elements["circle"])
+	require.Equal(t, 3, elements["line"])
+	require.Equal(t, []string{"rps", "covers", "crushes", "cuts", "Paper", "Rock", "Scissors"}, texts)
+}
+
+func TestRenderRuleGraph_Escaping(t *testing.T) {
+	choices := []Choice{{ID: "rps-big-rock", Name: `Big "Rock"`}, {ID: "rps-paper-co", Name: "<Paper> & co"}}
+	rules := []Rule{{WinnerID: "rps-big-rock", LoserID: "rps-paper-co", Action: `says "no"`}}
+
+	var dot, mermaid, svg bytes.Buffer
+	require.NoError(t, RenderRuleGraph(&dot, "rps", choices, rules, DOTFormat))
+	require.NoError(t, RenderRuleGraph(&mermaid, "rps", choices, rules, MermaidFormat))
+	require.NoError(t, RenderRuleGraph(&svg, "rps", choices, rules, SVGFormat))
+
+	require.Contains(t, dot.String(), `c0 [label="Big \"Rock\""];`)
+	require.Contains(t, mermaid.String(), `c0 -->|"says #quot;no#quot;"| c1`)
+	require.Contains(t, svg.String(), "&lt;Paper&gt; &amp; co")
+
+	require.Error(t, RenderRuleGraph(&dot, "rps", choices, rules, "png"))
+}
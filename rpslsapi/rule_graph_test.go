@@ -0,0 +1,83 @@
+package rpslsapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// evenRules relate rock, paper, scissors and lizard as in RPSLS
+var evenRules = []Rule{
+	{WinnerID: "rpsls-paper", LoserID: "rpsls-rock", Action: "covers"},
+	{WinnerID: "rpsls-rock", LoserID: "rpsls-scissors", Action: "crushes"},
+	{WinnerID: "rpsls-rock", LoserID: "rpsls-lizard", Action: "crushes"},
+	{WinnerID: "rpsls-scissors", LoserID: "rpsls-paper", Action: "cuts"},
+	{WinnerID: "rpsls-scissors", LoserID: "rpsls-lizard", Action: "decapitates"},
+	{WinnerID: "rpsls-lizard", LoserID: "rpsls-paper", Action: "eats"},
+}
+
+func TestValidateRuleGraph(t *testing.T) {
+	testCases := []struct {
+		name           string
+		choices        []Choice
+		rules          []Rule
+		expectedValid  bool
+		expectedIssues []RuleIssueKind
+	}{
+		{
+			name:           "success: RPSLS graph is valid and balanced",
+			choices:        baseChoices,
+			rules:          baseRules,
+			expectedValid:  true,
+			expectedIssues: []RuleIssueKind{},
+		},
+		{
+			name:           "failure: report missing pairs",
+			choices:        baseChoices[:3],
+			rules:          []Rule{{WinnerID: "rpsls-rock", LoserID: "rpsls-scissors", Action: "crushes"}, {WinnerID: "rpsls-paper", LoserID: "rpsls-rock", Action: "covers"}},
+			expectedValid:  false,
+			expectedIssues: []RuleIssueKind{MissingRule, UnbalancedChoice},
+		},
+		{
+			name:    "failure: report duplicate, mutual, self-loop and dangling rules",
+			choices: baseChoices[:3],
+			rules: []Rule{
+				{WinnerID: "rpsls-rock", LoserID: "rpsls-scissors", Action: "crushes"},
+				{WinnerID: "rpsls-rock", LoserID: "rpsls-scissors", Action: "smashes"},
+				{WinnerID: "rpsls-paper", LoserID: "rpsls-rock", Action: "covers"},
+				{WinnerID: "rpsls-rock", LoserID: "rpsls-paper", Action: "tears"},
+				{WinnerID: "rpsls-scissors", LoserID: "rpsls-paper", Action: "cuts"},
+				{WinnerID: "rpsls-scissors", LoserID: "rpsls-scissors", Action: "cuts"},
+				{WinnerID: "rpsls-scissors", LoserID: "rpsls-well", Action: "cuts"},
+			},
+			expectedValid:  false,
+			expectedIssues: []RuleIssueKind{SelfLoop, DanglingRule, MutualRule, DuplicateRule, UnbalancedChoice},
+		},
+		{
+			name:           "success: even choices beating one more or one less than they lose to are balanced",
+			choices:        baseChoices[:4],
+			rules:          evenRules,
+			expectedValid:  true,
+			expectedIssues: []RuleIssueKind{},
+		},
+		{
+			name:    "success: even choices beating two more than they lose to are valid but unbalanced",
+			choices: baseChoices[:4],
+			rules: append([]Rule{{WinnerID: "rpsls-rock", LoserID: "rpsls-paper", Action: "tears"}},
+				evenRules[1:]...),
+			expectedValid:  true,
+			expectedIssues: []RuleIssueKind{UnbalancedChoice, UnbalancedChoice},
+		},
+	}
+
+	for _, tc := range testCases {
+		report := ValidateRuleGraph(tc.choices, tc.rules)
+
+		require.Equal(t, tc.expectedValid, report.Valid, tc.name)
+		kinds := make([]RuleIssueKind, len(report.Issues))
+		for i, issue := range report.Issues {
+			kinds[i] = issue.Kind
+		}
+		require.Equal(t, tc.expectedIssues, kinds, tc.name)
+	}
+}
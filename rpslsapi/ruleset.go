@@ -0,0 +1,116 @@
+package rpslsapi
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrRulesetNotFound = errors.New("ruleset not found")
+var ErrRulesetAlreadyExists = errors.New("ruleset already exists")
+var ErrInvalidRuleset = errors.New("invalid ruleset")
+
+var rulesetIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Ruleset scopes a game variant: its choices and the BEATS relationships between them, e.g. RPS or RPSLS
+type Ruleset struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type RulesetService interface {
+	Rulesets() ([]Ruleset, error)
+	Ruleset(id string) (*Ruleset, error)
+	CreateRuleset(ruleset *Ruleset) (*Ruleset, error)
+	DeleteRuleset(id string) error
+	ExportRuleset(id string) (*RulesetDocument, error)
+	// ImportRuleset validates the document and creates the ruleset it describes, along with its choices and rules, in
+	// a single transaction. An existing ruleset with the same ID is replaced if replace is set, otherwise
+	// ErrRulesetAlreadyExists is returned.
+	ImportRuleset(document *RulesetDocument, replace bool) (*Ruleset, error)
+}
+
+type RulesetStore interface {
+	Rulesets() ([]Ruleset, error)
+	Ruleset(id string) (*Ruleset, error)
+	CreateRuleset(ruleset *Ruleset) (*Ruleset, error)
+	// DeleteRuleset deletes the ruleset along with all of its choices
+	DeleteRuleset(id string) error
+}
+
+type RulesetServiceImpl struct {
+	store       RulesetStore
+	choiceStore ChoiceStore
+	lock        *RuleGraphLock
+}
+
+func NewRulesetService(store RulesetStore, choiceStore ChoiceStore, lock *RuleGraphLock) RulesetService {
+	return RulesetServiceImpl{store: store, choiceStore: choiceStore, lock: lock}
+}
+
+func (rs RulesetServiceImpl) Rulesets() ([]Ruleset, error) {
+	rulesets, err := rs.store.Rulesets()
+	if err == nil && rulesets == nil {
+		rulesets = []Ruleset{}
+	}
+	return rulesets, err
+}
+
+func (rs RulesetServiceImpl) Ruleset(id string) (*Ruleset, error) {
+	return rs.store.Ruleset(id)
+}
+
+func (rs RulesetServiceImpl) CreateRuleset(ruleset *Ruleset) (*Ruleset, error) {
+	ruleset.Name = strings.TrimSpace(ruleset.Name)
+	if !rulesetIDPattern.MatchString(ruleset.ID) || ruleset.Name == "" {
+		return nil, ErrInvalidRuleset
+	}
+
+	return rs.store.CreateRuleset(ruleset)
+}
+
+func (rs RulesetServiceImpl) DeleteRuleset(id string) error {
+	if id == Config.DefaultRuleset {
+		return ErrInvalidRuleset
+	}
+
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	return rs.store.DeleteRuleset(id)
+}
+
+func (rs RulesetServiceImpl) ExportRuleset(id string) (*RulesetDocument, error) {
+	ruleset, err := rs.store.Ruleset(id)
+	if err != nil {
+		return nil, err
+	}
+	choices, err := rs.choiceStore.Choices(id)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := rs.choiceStore.Rules(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRulesetDocument(ruleset, choices, rules), nil
+}
+
+func (rs RulesetServiceImpl) ImportRuleset(document *RulesetDocument, replace bool) (*Ruleset, error) {
+	document.normalize()
+	if err := document.validate(); err != nil {
+		return nil, err
+	}
+
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	return rs.choiceStore.ImportRuleset(document, replace)
+}
+
+// RulesetOrDefault returns the given ruleset ID, or the configured default one if it is empty
+func RulesetOrDefault(id string) string {
+	if id == "" {
+		return Config.DefaultRuleset
+	}
+	return id
+}
@@ -0,0 +1,192 @@
+package rpslsapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RulesetDocumentVersion is the version of the ruleset document format written by exports
+const RulesetDocumentVersion = 1
+
+type DocumentFormat string
+
+const (
+	JSONFormat DocumentFormat = "json"
+	YAMLFormat DocumentFormat = "yaml"
+)
+
+// RulesetDocument is the portable representation of a ruleset used to import and export it. Choices are referenced by
+// name, so a document doesn't depend on the IDs of the database it was exported from.
+type RulesetDocument struct {
+	Version int          `json:"version" yaml:"version"`
+	ID      string       `json:"id" yaml:"id"`
+	Name    string       `json:"name" yaml:"name"`
+	Choices []string     `json:"choices" yaml:"choices"`
+	Beats   []BeatsEntry `json:"beats" yaml:"beats"`
+}
+
+// BeatsEntry is a BEATS relationship of a ruleset document, e.g. Rock crushes Scissors
+type BeatsEntry struct {
+	Winner string `json:"winner" yaml:"winner"`
+	Loser  string `json:"loser" yaml:"loser"`
+	Verb   string `json:"verb" yaml:"verb"`
+}
+
+// RulesetDocumentError lists the problems found in a ruleset document. It matches ErrInvalidRuleset through errors.Is.
+type RulesetDocumentError struct {
+	Problems []string
+}
+
+func (e *RulesetDocumentError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrInvalidRuleset, strings.Join(e.Problems, "; "))
+}
+
+func (e *RulesetDocumentError) Is(target error) bool {
+	return target == ErrInvalidRuleset
+}
+
+// DecodeRulesetDocument reads a ruleset document in either JSON or YAML, rejecting unknown fields
+func DecodeRulesetDocument(r io.Reader) (*RulesetDocument, error) {
+	// YAML is a superset of JSON, so a single decoder handles both formats
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var document RulesetDocument
+	if err := decoder.Decode(&document); err != nil {
+		if err == io.EOF {
+			return nil, &RulesetDocumentError{Problems: []string{"the document is empty"}}
+		}
+		return nil, &RulesetDocumentError{Problems: []string{err.Error()}}
+	}
+	return &document, nil
+}
+
+// EncodeRulesetDocument writes the document in the given format
+func EncodeRulesetDocument(w io.Writer, document *RulesetDocument, format DocumentFormat) error {
+	switch format {
+	case JSONFormat:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(document)
+	case YAMLFormat:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return fmt.Errorf("unknown document format %q", format)
+	}
+}
+
+// normalize trims every name and verb of the document
+func (d *RulesetDocument) normalize() {
+	d.ID = strings.TrimSpace(d.ID)
+	d.Name = strings.TrimSpace(d.Name)
+	for i := range d.Choices {
+		d.Choices[i] = strings.TrimSpace(d.Choices[i])
+	}
+	for i := range d.Beats {
+		d.Beats[i].Winner = strings.TrimSpace(d.Beats[i].Winner)
+		d.Beats[i].Loser = strings.TrimSpace(d.Beats[i].Loser)
+		d.Beats[i].Verb = strings.TrimSpace(d.Beats[i].Verb)
+	}
+}
+
+// validate checks the document structure, returning a RulesetDocumentError listing every problem found, and then its
+// rule graph, returning a RuleGraphError if it has errors
+func (d *RulesetDocument) validate() error {
+	var problems []string
+	if d.Version != 0 && d.Version != RulesetDocumentVersion {
+		problems = append(problems, fmt.Sprintf("unsupported version %d", d.Version))
+	}
+	if !rulesetIDPattern.MatchString(d.ID) {
+		problems = append(problems, fmt.Sprintf("id %q must be a lowercase slug", d.ID))
+	}
+	if d.Name == "" {
+		problems = append(problems, "name is required")
+	}
+
+	ids := make(map[string]string, len(d.Choices))
+	names := make(map[string]string, len(d.Choices))
+	choices := make([]Choice, 0, len(d.Choices))
+	for i, name := range d.Choices {
+		if name == "" {
+			problems = append(problems, fmt.Sprintf("choice %d has no name", i+1))
+			continue
+		}
+		if _, found := ids[name]; found {
+			problems = append(problems, fmt.Sprintf("choice %q is listed more than once", name))
+			continue
+		}
+		id := NewChoiceID(d.ID, name)
+		if other, found := names[id]; found {
+			problems = append(problems, fmt.Sprintf("choices %q and %q have the same id %q", other, name, id))
+			continue
+		}
+		ids[name] = id
+		names[id] = name
+		choices = append(choices, Choice{ID: id, Name: name, Ruleset: d.ID})
+	}
+
+	rules := make([]Rule, 0, len(d.Beats))
+	for _, entry := range d.Beats {
+		winnerID, winnerFound := ids[entry.Winner]
+		loserID, loserFound := ids[entry.Loser]
+		switch {
+		case !winnerFound:
+			problems = append(problems, fmt.Sprintf("beats entry references unknown choice %q", entry.Winner))
+		case !loserFound:
+			problems = append(problems, fmt.Sprintf("beats entry references unknown choice %q", entry.Loser))
+		case entry.Verb == "":
+			problems = append(problems, fmt.Sprintf("%s -> %s has no verb", entry.Winner, entry.Loser))
+		default:
+			rules = append(rules, Rule{WinnerID: winnerID, LoserID: loserID, Action: entry.Verb})
+		}
+	}
+	if len(problems) > 0 {
+		return &RulesetDocumentError{Problems: problems}
+	}
+
+	if report := ValidateRuleGraph(choices, rules); !report.Valid {
+		return &RuleGraphError{Report: report}
+	}
+	return nil
+}
+
+// newRulesetDocument builds the document of a stored ruleset, listing its beats in the order of their choices
+func newRulesetDocument(ruleset *Ruleset, choices []Choice, rules []Rule) *RulesetDocument {
+	document := &RulesetDocument{
+		Version: RulesetDocumentVersion,
+		ID:      ruleset.ID,
+		Name:    ruleset.Name,
+		Choices: make([]string, 0, len(choices)),
+		Beats:   make([]BeatsEntry, 0, len(rules)),
+	}
+
+	names := make(map[string]string, len(choices))
+	for _, choice := range choices {
+		names[choice.ID] = choice.Name
+		document.Choices = append(document.Choices, choice.Name)
+	}
+	// rules indexed by winner and loser IDs
+	byPair := make(map[[2]string][]Rule, len(rules))
+	for _, rule := range rules {
+		pair := [2]string{rule.WinnerID, rule.LoserID}
+		byPair[pair] = append(byPair[pair], rule)
+	}
+	for _, winner := range choices {
+		for _, loser := range choices {
+			for _, rule := range byPair[[2]string{winner.ID, loser.ID}] {
+				document.Beats = append(document.Beats,
+					BeatsEntry{Winner: names[rule.WinnerID], Loser: names[rule.LoserID], Verb: rule.Action})
+			}
+		}
+	}
+	return document
+}
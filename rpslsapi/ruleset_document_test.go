@@ -0,0 +1,82 @@
+package rpslsapi
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const rpsYAML = `version: 1
+id: rps
+name: Rock Paper Scissors
+choices:
+  - Rock
+  - Paper
+  - Scissors
+beats:
+  - winner: Rock
+    loser: Scissors
+    verb: crushes
+  - winner: Paper
+    loser: Rock
+    verb: covers
+  - winner: Scissors
+    loser: Paper
+    verb: cuts
+`
+
+func TestEncodeRulesetDocument(t *testing.T) {
+	var yamlOutput bytes.Buffer
+	require.NoError(t, EncodeRulesetDocument(&yamlOutput, &rpsDocument, YAMLFormat))
+	require.Equal(t, rpsYAML, yamlOutput.String())
+
+	var jsonOutput bytes.Buffer
+	require.NoError(t, EncodeRulesetDocument(&jsonOutput, &rpsDocument, JSONFormat))
+	require.Contains(t, jsonOutput.String(), `"winner": "Rock"`)
+
+	require.Error(t, EncodeRulesetDocument(&jsonOutput, &rpsDocument, "xml"))
+}
+
+func TestDecodeRulesetDocument(t *testing.T) {
+	testCases := []struct {
+		name             string
+		input            string
+		expectedDocument *RulesetDocument
+	}{
+		{
+			name:             "success: decode YAML",
+			input:            rpsYAML,
+			expectedDocument: &rpsDocument,
+		},
+		{
+			name: "success: decode JSON",
+			input: `{"version": 1, "id": "rps", "name": "Rock Paper Scissors", "choices": ["Rock", "Paper", "Scissors"],
+				"beats": [{"winner": "Rock", "loser": "Scissors", "verb": "crushes"},
+				{"winner": "Paper", "loser": "Rock", "verb": "covers"},
+				{"winner": "Scissors", "loser": "Paper", "verb": "cuts"}]}`,
+			expectedDocument: &rpsDocument,
+		},
+		{
+			name:  "failure: reject unknown fields",
+			input: "id: rps\nrules: []\n",
+		},
+		{
+			name:  "failure: reject empty documents",
+			input: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		document, err := DecodeRulesetDocument(strings.NewReader(tc.input))
+
+		if tc.expectedDocument == nil {
+			require.True(t, errors.Is(err, ErrInvalidRuleset), tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedDocument, document, tc.name)
+	}
+}
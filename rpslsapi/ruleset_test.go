@@ -0,0 +1,226 @@
+package rpslsapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type RulesetStoreMock struct {
+	mock.Mock
+}
+
+func (rsm *RulesetStoreMock) Rulesets() ([]Ruleset, error) {
+	args := rsm.Called()
+	return args.Get(0).([]Ruleset), args.Error(1)
+}
+
+func (rsm *RulesetStoreMock) Ruleset(id string) (*Ruleset, error) {
+	args := rsm.Called(id)
+	return args.Get(0).(*Ruleset), args.Error(1)
+}
+
+func (rsm *RulesetStoreMock) CreateRuleset(ruleset *Ruleset) (*Ruleset, error) {
+	args := rsm.Called(ruleset)
+	return args.Get(0).(*Ruleset), args.Error(1)
+}
+
+func (rsm *RulesetStoreMock) DeleteRuleset(id string) error {
+	args := rsm.Called(id)
+	return args.Error(0)
+}
+
+func TestRulesetService_Rulesets(t *testing.T) {
+	storeMock := RulesetStoreMock{}
+	service := NewRulesetService(&storeMock, &ChoiceStoreMock{}, NewRuleGraphLock())
+	storeMock.On("Rulesets").Return([]Ruleset(nil), nil).Once()
+
+	rulesets, err := service.Rulesets()
+
+	require.NoError(t, err)
+	require.NotNil(t, rulesets)
+	require.Empty(t, rulesets)
+}
+
+func TestRulesetService_CreateRuleset(t *testing.T) {
+	testCases := []struct {
+		name          string
+		ruleset       *Ruleset
+		storeError    error
+		expectedError error
+	}{
+		{
+			name:    "success: create ruleset",
+			ruleset: &Ruleset{ID: "rps-101", Name: "RPS-101"},
+		},
+		{
+			name:          "failure: if the ID is not a slug, return ErrInvalidRuleset",
+			ruleset:       &Ruleset{ID: "RPS 101", Name: "RPS-101"},
+			expectedError: ErrInvalidRuleset,
+		},
+		{
+			name:          "failure: if the name is blank, return ErrInvalidRuleset",
+			ruleset:       &Ruleset{ID: "rps-101", Name: " "},
+			expectedError: ErrInvalidRuleset,
+		},
+		{
+			name:          "failure: if store returns ErrRulesetAlreadyExists, propagate it",
+			ruleset:       &Ruleset{ID: "rps", Name: "RPS"},
+			storeError:    ErrRulesetAlreadyExists,
+			expectedError: ErrRulesetAlreadyExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := RulesetStoreMock{}
+		service := NewRulesetService(&storeMock, &ChoiceStoreMock{}, NewRuleGraphLock())
+		storeMock.On("CreateRuleset", tc.ruleset).Return(tc.ruleset, tc.storeError).Once()
+
+		ruleset, err := service.CreateRuleset(tc.ruleset)
+
+		if tc.expectedError != nil {
+			require.NotNil(t, err)
+			require.EqualError(t, tc.expectedError, err.Error())
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, tc.ruleset, ruleset)
+		}
+	}
+}
+
+func TestRulesetService_DeleteRuleset(t *testing.T) {
+	Config.DefaultRuleset = "rpsls"
+	storeMock := RulesetStoreMock{}
+	service := NewRulesetService(&storeMock, &ChoiceStoreMock{}, NewRuleGraphLock())
+	storeMock.On("DeleteRuleset", "rps").Return(nil)
+
+	require.NoError(t, service.DeleteRuleset("rps"))
+	require.EqualError(t, service.DeleteRuleset("rpsls"), ErrInvalidRuleset.Error())
+	storeMock.AssertNotCalled(t, "DeleteRuleset", "rpsls")
+}
+
+var rpsDocument = RulesetDocument{
+	Version: RulesetDocumentVersion,
+	ID:      "rps",
+	Name:    "Rock Paper Scissors",
+	Choices: []string{"Rock", "Paper", "Scissors"},
+	Beats: []BeatsEntry{
+		{Winner: "Rock", Loser: "Scissors", Verb: "crushes"},
+		{Winner: "Paper", Loser: "Rock", Verb: "covers"},
+		{Winner: "Scissors", Loser: "Paper", Verb: "cuts"},
+	},
+}
+
+func TestRulesetService_ExportRuleset(t *testing.T) {
+	storeMock := RulesetStoreMock{}
+	choiceStoreMock := ChoiceStoreMock{}
+	service := NewRulesetService(&storeMock, &choiceStoreMock, NewRuleGraphLock())
+	storeMock.On("Ruleset", "rps").Return(&Ruleset{ID: "rps", Name: "Rock Paper Scissors"}, nil)
+	storeMock.On("Ruleset", "missing").Return((*Ruleset)(nil), ErrRulesetNotFound)
+	choiceStoreMock.On("Choices", "rps").Return([]Choice{
+		{ID: "rps-rock", Name: "Rock", Ruleset: "rps"},
+		{ID: "rps-paper", Name: "Paper", Ruleset: "rps"},
+		{ID: "rps-scissors", Name: "Scissors", Ruleset: "rps"},
+	}, nil)
+	choiceStoreMock.On("Rules", "rps").Return([]Rule{
+		{WinnerID: "rps-scissors", LoserID: "rps-paper", Action: "cuts"},
+		{WinnerID: "rps-rock", LoserID: "rps-scissors", Action: "crushes"},
+		{WinnerID: "rps-paper", LoserID: "rps-rock", Action: "covers"},
+	}, nil)
+
+	document, err := service.ExportRuleset("rps")
+	require.NoError(t, err)
+	require.Equal(t, &rpsDocument, document)
+
+	_, err = service.ExportRuleset("missing")
+	require.Equal(t, ErrRulesetNotFound, err)
+}
+
+func TestRulesetService_ImportRuleset(t *testing.T) {
+	testCases := []struct {
+		name             string
+		document         RulesetDocument
+		expectedProblems []string
+		expectGraphError bool
+	}{
+		{
+			name:     "success: import a valid document",
+			document: rpsDocument,
+		},
+		{
+			name: "success: names and verbs are trimmed",
+			document: RulesetDocument{
+				ID:      "duel",
+				Name:    " Duel ",
+				Choices: []string{"Sword ", " Shield"},
+				Beats:   []BeatsEntry{{Winner: " Shield", Loser: "Sword", Verb: " blocks "}},
+			},
+		},
+		{
+			name: "failure: if the document is malformed, list its problems",
+			document: RulesetDocument{
+				Version: 2,
+				ID:      "Duel",
+				Choices: []string{"Sword", "Sword", ""},
+				Beats: []BeatsEntry{
+					{Winner: "Shield", Loser: "Sword", Verb: "blocks"},
+					{Winner: "Sword", Loser: "Sword"},
+				},
+			},
+			expectedProblems: []string{
+				"unsupported version 2",
+				`id "Duel" must be a lowercase slug`,
+				"name is required",
+				`choice "Sword" is listed more than once`,
+				"choice 3 has no name",
+				`beats entry references unknown choice "Shield"`,
+				"Sword -> Sword has no verb",
+			},
+		},
+		{
+			name: "failure: if the rule graph has errors, return a RuleGraphError",
+			document: RulesetDocument{
+				ID:      "rps",
+				Name:    "Rock Paper Scissors",
+				Choices: []string{"Rock", "Paper", "Scissors"},
+				Beats:   rpsDocument.Beats[:2],
+			},
+			expectGraphError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		choiceStoreMock := ChoiceStoreMock{}
+		service := NewRulesetService(&RulesetStoreMock{}, &choiceStoreMock, NewRuleGraphLock())
+		document := tc.document
+		imported := &Ruleset{ID: document.ID, Name: strings.TrimSpace(document.Name)}
+		choiceStoreMock.On("ImportRuleset", &document, true).Return(imported, nil)
+
+		ruleset, err := service.ImportRuleset(&document, true)
+
+		var documentErr *RulesetDocumentError
+		var graphErr *RuleGraphError
+		switch {
+		case tc.expectedProblems != nil:
+			require.True(t, errors.As(err, &documentErr), tc.name)
+			require.True(t, errors.Is(err, ErrInvalidRuleset), tc.name)
+			require.Equal(t, tc.expectedProblems, documentErr.Problems, tc.name)
+			choiceStoreMock.AssertNotCalled(t, "ImportRuleset", mock.Anything, mock.Anything)
+		case tc.expectGraphError:
+			require.True(t, errors.As(err, &graphErr), tc.name)
+			require.Equal(t, MissingRule, graphErr.Report.Issues[0].Kind, tc.name)
+			choiceStoreMock.AssertNotCalled(t, "ImportRuleset", mock.Anything, mock.Anything)
+		default:
+			require.NoError(t, err, tc.name)
+			require.Equal(t, imported, ruleset, tc.name)
+			for _, entry := range document.Beats {
+				require.Contains(t, document.Choices, entry.Winner, tc.name)
+				require.Contains(t, document.Choices, entry.Loser, tc.name)
+				require.Equal(t, strings.TrimSpace(entry.Verb), entry.Verb, tc.name)
+			}
+		}
+	}
+}
@@ -4,13 +4,21 @@ import (
 	"fmt"
 )
 
-// first placeholder is for the userID
-const scoreboardKeyTemplate = "rpsls-scoreboard:%s"
+// first placeholder is for the userID, second one for the ruleset
+const scoreboardKeyTemplate = "rpsls-scoreboard:%s:%s"
 
+// legacyScoreboardKeyTemplate is the key of the scoreboards kept before rulesets existed, which only hold rounds of
+// legacyScoreboardRuleset. They are read after the results of the scoreboard of that ruleset until newer results push
+// them out, and cleared along with it.
+const legacyScoreboardKeyTemplate = "rpsls-scoreboard:%s"
+const legacyScoreboardRuleset = "rpsls"
+
+// ScoreboardService keeps one scoreboard per user and ruleset. Results are appended to the scoreboard of their own
+// ruleset, and an empty ruleset ID stands for the default one.
 type ScoreboardService interface {
-	Scoreboard(userID string) ([]RoundResults, error)
+	Scoreboard(userID, ruleset string) ([]RoundResults, error)
 	Append(userID string, results *RoundResults) error
-	Clear(userID string) error
+	Clear(userID, ruleset string) error
 }
 
 type ScoreboardStore interface {
@@ -28,14 +36,36 @@ func NewScoreboardService(scoreboardStore ScoreboardStore) ScoreboardService {
 	return ScoreboardServiceImpl{scoreboardStore, Config.ScoreboardSize}
 }
 
-func (ss ScoreboardServiceImpl) Scoreboard(userID string) ([]RoundResults, error) {
-	return ss.scoreboardStore.Scoreboard(fmt.Sprintf(scoreboardKeyTemplate, userID), int64(ss.boardSize))
+func (ss ScoreboardServiceImpl) Scoreboard(userID, ruleset string) ([]RoundResults, error) {
+	scoreboard, err := ss.scoreboardStore.Scoreboard(scoreboardKey(userID, ruleset), int64(ss.boardSize))
+	if err != nil || RulesetOrDefault(ruleset) != legacyScoreboardRuleset || len(scoreboard) >= ss.boardSize {
+		return scoreboard, err
+	}
+
+	legacy, err := ss.scoreboardStore.Scoreboard(fmt.Sprintf(legacyScoreboardKeyTemplate, userID),
+		int64(ss.boardSize-len(scoreboard)))
+	if err != nil {
+		return nil, err
+	}
+	for i := range legacy {
+		legacy[i].Ruleset = legacyScoreboardRuleset
+	}
+	return append(scoreboard, legacy...), nil
 }
 
 func (ss ScoreboardServiceImpl) Append(userID string, results *RoundResults) error {
-	return ss.scoreboardStore.Append(fmt.Sprintf(scoreboardKeyTemplate, userID), int64(ss.boardSize), results)
+	return ss.scoreboardStore.Append(scoreboardKey(userID, results.Ruleset), int64(ss.boardSize), results)
+}
+
+func (ss ScoreboardServiceImpl) Clear(userID, ruleset string) error {
+	if RulesetOrDefault(ruleset) == legacyScoreboardRuleset {
+		if err := ss.scoreboardStore.Clear(fmt.Sprintf(legacyScoreboardKeyTemplate, userID)); err != nil {
+			return err
+		}
+	}
+	return ss.scoreboardStore.Clear(scoreboardKey(userID, ruleset))
 }
 
-func (ss ScoreboardServiceImpl) Clear(userID string) error {
-	return ss.scoreboardStore.Clear(fmt.Sprintf(scoreboardKeyTemplate, userID))
+func scoreboardKey(userID, ruleset string) string {
+	return fmt.Sprintf(scoreboardKeyTemplate, userID, RulesetOrDefault(ruleset))
 }
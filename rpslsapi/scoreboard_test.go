@@ -32,13 +32,13 @@ func TestScoreboardServiceImpl_Scoreboard(t *testing.T) {
 	results := []RoundResults{
 		{
 			Results:  string(Win),
-			Player:   1,
-			Computer: 3,
+			Player:   "rpsls-rock",
+			Computer: "rpsls-scissors",
 		},
 		{
 			Results:  string(Lose),
-			Player:   4,
-			Computer: 2,
+			Player:   "rpsls-lizard",
+			Computer: "rpsls-paper",
 		},
 	}
 	testCases := []struct {
@@ -66,7 +66,7 @@ func TestScoreboardServiceImpl_Scoreboard(t *testing.T) {
 	for _, tc := range testCases {
 		storeMock.On("Scoreboard", mock.Anything, mock.Anything).Return(tc.resultsFromStore, tc.storeError).Once()
 
-		scoreboard, err := service.Scoreboard("dummyUserID")
+		scoreboard, err := service.Scoreboard("dummyUserID", "rpsls")
 
 		storeMock.AssertCalled(t, "Scoreboard", mock.Anything, mock.Anything)
 		if tc.expectedError != nil {
@@ -103,7 +103,7 @@ func TestScoreboardServiceImpl_Append(t *testing.T) {
 	for _, tc := range testCases {
 		storeMock.On("Append", mock.Anything, mock.Anything, mock.Anything).Return(tc.storeError).Once()
 
-		err := service.Append("dummyUserID", nil)
+		err := service.Append("dummyUserID", &RoundResults{Ruleset: "rpsls"})
 
 		storeMock.AssertCalled(t, "Append", mock.Anything, mock.Anything, mock.Anything)
 		if tc.expectedError != nil {
@@ -115,33 +115,125 @@ func TestScoreboardServiceImpl_Append(t *testing.T) {
 	}
 }
 
+func TestScoreboardServiceImpl_Scoreboard_Legacy(t *testing.T) {
+	scoreboardMockError := errors.New("store error")
+	recent := RoundResults{Results: string(Win), Ruleset: "rpsls", Player: "rpsls-rock", Computer: "rpsls-scissors"}
+	legacy := RoundResults{Results: string(Lose), Player: "rpsls-lizard", Computer: "rpsls-scissors"}
+	legacyWithRuleset := legacy
+	legacyWithRuleset.Ruleset = "rpsls"
+
+	testCases := []struct {
+		name            string
+		ruleset         string
+		current         []RoundResults
+		legacy          []RoundResults
+		legacyError     error
+		expectedLegacy  bool
+		expectedResults []RoundResults
+		expectedError   error
+	}{
+		{
+			name:            "success: fill the rpsls scoreboard with the results stored before rulesets existed",
+			ruleset:         "rpsls",
+			current:         []RoundResults{recent},
+			legacy:          []RoundResults{legacy, legacy},
+			expectedLegacy:  true,
+			expectedResults: []RoundResults{recent, legacyWithRuleset, legacyWithRuleset},
+		},
+		{
+			name:            "success: don't read the old scoreboard once the rpsls scoreboard is full",
+			ruleset:         "rpsls",
+			current:         []RoundResults{recent, recent, recent},
+			expectedResults: []RoundResults{recent, recent, recent},
+		},
+		{
+			name:            "success: don't read the old scoreboard for other rulesets",
+			ruleset:         "rps",
+			current:         []RoundResults{recent},
+			expectedResults: []RoundResults{recent},
+		},
+		{
+			name:           "failure: if reading the old scoreboard fails, propagate the error",
+			ruleset:        "rpsls",
+			legacyError:    scoreboardMockError,
+			expectedLegacy: true,
+			expectedError:  scoreboardMockError,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := ScoreboardStoreMock{}
+		service := ScoreboardServiceImpl{&storeMock, 3}
+		storeMock.On("Scoreboard", "rpsls-scoreboard:user:"+tc.ruleset, int64(3)).Return(tc.current, nil).Once()
+		storeMock.On("Scoreboard", "rpsls-scoreboard:user", int64(3-len(tc.current))).
+			Return(tc.legacy, tc.legacyError).Once()
+
+		scoreboard, err := service.Scoreboard("user", tc.ruleset)
+
+		if tc.expectedLegacy {
+			storeMock.AssertCalled(t, "Scoreboard", "rpsls-scoreboard:user", int64(3-len(tc.current)))
+		} else {
+			storeMock.AssertNotCalled(t, "Scoreboard", "rpsls-scoreboard:user", mock.Anything)
+		}
+		if tc.expectedError != nil {
+			require.EqualError(t, err, tc.expectedError.Error(), tc.name)
+		} else {
+			require.NoError(t, err, tc.name)
+			require.Equal(t, tc.expectedResults, scoreboard, tc.name)
+		}
+	}
+}
+
 func TestScoreboardServiceImpl_Clear(t *testing.T) {
 	scoreboardMockError := errors.New("store error")
 
 	testCases := []struct {
 		name          string
+		ruleset       string
+		legacyError   error
 		storeError    error
+		expectedKeys  []string
 		expectedError error
 	}{
 		{
-			name: "success: call store and return nil",
+			name:         "success: clear the rpsls scoreboard along with the one stored before rulesets existed",
+			ruleset:      "rpsls",
+			expectedKeys: []string{"rpsls-scoreboard:dummyUserID", "rpsls-scoreboard:dummyUserID:rpsls"},
+		},
+		{
+			name:         "success: only clear the scoreboard of other rulesets",
+			ruleset:      "rps",
+			expectedKeys: []string{"rpsls-scoreboard:dummyUserID:rps"},
+		},
+		{
+			name:          "failure: if clearing the old scoreboard fails, propagate the error",
+			ruleset:       "rpsls",
+			legacyError:   scoreboardMockError,
+			expectedKeys:  []string{"rpsls-scoreboard:dummyUserID"},
+			expectedError: scoreboardMockError,
 		},
 		{
 			name:          "failure: if store returns unknown error, propagate it",
+			ruleset:       "rps",
 			storeError:    scoreboardMockError,
+			expectedKeys:  []string{"rpsls-scoreboard:dummyUserID:rps"},
 			expectedError: scoreboardMockError,
 		},
 	}
 
-	storeMock := ScoreboardStoreMock{}
-	service := NewScoreboardService(&storeMock)
-
 	for _, tc := range testCases {
-		storeMock.On("Clear", mock.Anything).Return(tc.storeError).Once()
+		storeMock := ScoreboardStoreMock{}
+		service := NewScoreboardService(&storeMock)
+		storeMock.On("Clear", "rpsls-scoreboard:dummyUserID").Return(tc.legacyError).Once()
+		storeMock.On("Clear", "rpsls-scoreboard:dummyUserID:"+tc.ruleset).Return(tc.storeError).Once()
 
-		err := service.Clear("dummyUserID")
+		err := service.Clear("dummyUserID", tc.ruleset)
 
-		storeMock.AssertCalled(t, "Clear", mock.Anything)
+		var keys []string
+		for _, call := range storeMock.Calls {
+			keys = append(keys, call.Arguments.String(0))
+		}
+		require.Equal(t, tc.expectedKeys, keys, tc.name)
 		if tc.expectedError != nil {
 			require.NotNil(t, t, err)
 			require.EqualError(t, tc.expectedError, err.Error())
@@ -0,0 +1,250 @@
+package rpslsapi
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrSeriesNotFound = errors.New("series not found")
+var ErrSeriesFinished = errors.New("series already finished")
+var ErrInvalidSeries = errors.New("best_of must be an odd number between 1 and 99")
+var ErrNotSeriesOwner = errors.New("not the owner of the series")
+
+// maxSeriesLength bounds the number of rounds a series may be the best of
+const maxSeriesLength = 99
+
+// seriesRetention is how long series are kept, so players can come back to them
+const seriesRetention = 24 * time.Hour
+
+type SeriesSide string
+
+const (
+	SeriesPlayer   SeriesSide = "player"
+	SeriesComputer SeriesSide = "computer"
+)
+
+type SeriesSettings struct {
+	BestOf  int    `json:"best_of"`
+	Ruleset string `json:"ruleset"`
+	// ReplayTies makes tied rounds not count towards the rounds of the series
+	ReplayTies bool `json:"replay_ties"`
+	// Strategy is the name or difficulty of the strategy the computer plays the series with, the default one of the
+	// user if empty
+	Strategy string `json:"strategy,omitempty"`
+	// UserID is the ID of the authenticated user creating the series, who owns it, empty for anonymous players
+	UserID string `json:"-"`
+}
+
+type SeriesScore struct {
+	Player   int `json:"player"`
+	Computer int `json:"computer"`
+	Ties     int `json:"ties"`
+}
+
+// Series is a best-of-N contest between the player and the computer. It is won by the first side to win a majority
+// of the N rounds, or by the side ahead once N rounds were played, and ends in a draw if both are level by then.
+// Only its owner, the user who created it, may see and play it, series created anonymously being left to anonymous
+// players.
+type Series struct {
+	ID         string         `json:"id"`
+	Owner      string         `json:"owner,omitempty"`
+	Ruleset    string         `json:"ruleset"`
+	BestOf     int            `json:"best_of"`
+	ReplayTies bool           `json:"replay_ties"`
+	Strategy   string         `json:"strategy,omitempty"`
+	Finished   bool           `json:"finished"`
+	Score      SeriesScore    `json:"score"`
+	Winner     SeriesSide     `json:"winner,omitempty"`
+	Rounds     []RoundResults `json:"rounds"`
+}
+
+type SeriesService interface {
+	CreateSeries(settings *SeriesSettings) (*Series, error)
+	// Series returns the series to the user owning it, or ErrNotSeriesOwner
+	Series(id, userID string) (*Series, error)
+	// Play plays the choice of the settings in a round of the series, auditing the round once it is added to the
+	// series, and recording the series to the scoreboard of the user once it is finished. Rounds of anonymous players,
+	// whose user ID is empty, are only audited. The ruleset and strategy of the settings are the ones of the series,
+	// which only its owner may play.
+	Play(id string, settings *RoundSettings) (*Series, error)
+}
+
+type SeriesStore interface {
+	// CreateSeries stores a new series, which is dropped once the retention has passed
+	CreateSeries(series *Series, retention time.Duration) error
+	// Series returns the series or ErrSeriesNotFound
+	Series(id string) (*Series, error)
+	UpdateSeries(id string, update func(series *Series) error) (*Series, error)
+}
+
+type SeriesServiceImpl struct {
+	seriesStore       SeriesStore
+	roundStore        RoundStore
+	choiceService     ChoiceService
+	scoreboardService ScoreboardService
+	strategyService   StrategyService
+	historyService    HistoryService
+	auditService      AuditService
+	now               func() time.Time
+}
+
+func NewSeriesService(seriesStore SeriesStore, roundStore RoundStore, choiceService ChoiceService,
+	scoreboardService ScoreboardService, strategyService StrategyService, historyService HistoryService,
+	auditService AuditService) SeriesService {
+	return SeriesServiceImpl{
+		seriesStore:       seriesStore,
+		roundStore:        roundStore,
+		choiceService:     choiceService,
+		scoreboardService: scoreboardService,
+		strategyService:   strategyService,
+		historyService:    historyService,
+		auditService:      auditService,
+		now:               time.Now,
+	}
+}
+
+func (ss SeriesServiceImpl) CreateSeries(settings *SeriesSettings) (*Series, error) {
+	if settings.BestOf < 1 || settings.BestOf > maxSeriesLength || settings.BestOf%2 == 0 {
+		return nil, ErrInvalidSeries
+	}
+	ruleset := RulesetOrDefault(settings.Ruleset)
+	if _, err := ss.choiceService.Choices(ruleset); err != nil {
+		return nil, err
+	}
+	strategy := ""
+	if settings.Strategy != "" {
+		info, err := ss.strategyService.Strategy(settings.Strategy)
+		if err != nil {
+			return nil, err
+		}
+		strategy = info.Name
+	}
+
+	series := &Series{
+		ID:         newToken(8),
+		Owner:      settings.UserID,
+		Ruleset:    ruleset,
+		BestOf:     settings.BestOf,
+		ReplayTies: settings.ReplayTies,
+		Strategy:   strategy,
+		Rounds:     []RoundResults{},
+	}
+	if err := ss.seriesStore.CreateSeries(series, seriesRetention); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (ss SeriesServiceImpl) Series(id, userID string) (*Series, error) {
+	series, err := ss.seriesStore.Series(id)
+	if err != nil {
+		return nil, err
+	}
+	if series.Owner != userID {
+		return nil, ErrNotSeriesOwner
+	}
+	return series, nil
+}
+
+func (ss SeriesServiceImpl) Play(id string, settings *RoundSettings) (*Series, error) {
+	series, err := ss.Series(id, settings.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if series.Finished {
+		return nil, ErrSeriesFinished
+	}
+
+	// the round is decided before the update, which may be retried, and only recorded once the update added it to the
+	// series, so that a round dropped because the series ended meanwhile leaves no trace
+	userID := settings.UserID
+	settings = &RoundSettings{Player: settings.Player, Ruleset: series.Ruleset, Strategy: series.Strategy,
+		RequestID: settings.RequestID, UserID: userID}
+	round, move, err := playAgainstComputer(ss.roundStore, ss.choiceService, ss.strategyService, userID, settings)
+	if err != nil {
+		return nil, err
+	}
+	round.ID = newToken(8)
+
+	finishedNow := false
+	series, err = ss.seriesStore.UpdateSeries(id, func(series *Series) error {
+		if series.Finished {
+			return ErrSeriesFinished
+		}
+		series.record(round)
+		finishedNow = series.Finished
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.auditService.Record(newRoundAudit(settings.RequestID, userID, ss.now(), round, move)); err != nil {
+		return nil, err
+	}
+
+	if userID == "" {
+		return series, nil
+	}
+	recordHistory(ss.historyService, userID, round, series.ID)
+	if finishedNow {
+		if err := ss.scoreboardService.Append(userID, series.results()); err != nil {
+			log.Info().Msg("failed to save series to scoreboard")
+		}
+	}
+	return series, nil
+}
+
+// record adds the round to the score, finishing the series once the side behind can't catch up anymore
+func (s *Series) record(round *RoundResults) {
+	s.Rounds = append(s.Rounds, *round)
+	switch ResultsLabel(round.Results) {
+	case Win:
+		s.Score.Player++
+	case Lose:
+		s.Score.Computer++
+	case Tie:
+		s.Score.Ties++
+	}
+
+	played := s.Score.Player + s.Score.Computer
+	if !s.ReplayTies {
+		played += s.Score.Ties
+	}
+	remaining := s.BestOf - played
+	lead := s.Score.Player - s.Score.Computer
+	if remaining > 0 && lead <= remaining && -lead <= remaining {
+		return
+	}
+
+	s.Finished = true
+	switch {
+	case lead > 0:
+		s.Winner = SeriesPlayer
+	case lead < 0:
+		s.Winner = SeriesComputer
+	}
+}
+
+// results summarizes a finished series as a single scoreboard entry
+func (s *Series) results() *RoundResults {
+	score := s.Score
+	results := &RoundResults{Ruleset: s.Ruleset, Series: s.ID, Score: &score, Rounds: s.Rounds}
+	switch s.Winner {
+	case SeriesPlayer:
+		results.Results = string(Win)
+		results.Description = fmt.Sprintf("Player wins the best-of-%d series %d-%d", s.BestOf, score.Player,
+			score.Computer)
+	case SeriesComputer:
+		results.Results = string(Lose)
+		results.Description = fmt.Sprintf("Computer wins the best-of-%d series %d-%d", s.BestOf, score.Computer,
+			score.Player)
+	default:
+		results.Results = string(Tie)
+		results.Description = fmt.Sprintf("The best-of-%d series ends in a %d-%d draw", s.BestOf, score.Player,
+			score.Computer)
+	}
+	return results
+}
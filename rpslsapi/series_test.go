@@ -0,0 +1,271 @@
+package rpslsapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type SeriesStoreMock struct {
+	mock.Mock
+}
+
+func (ssm *SeriesStoreMock) CreateSeries(series *Series, retention time.Duration) error {
+	args := ssm.Called(series, retention)
+	return args.Error(0)
+}
+
+func (ssm *SeriesStoreMock) Series(id string) (*Series, error) {
+	args := ssm.Called(id)
+	return args.Get(0).(*Series), args.Error(1)
+}
+
+// UpdateSeries applies the update to the series the mock returns
+func (ssm *SeriesStoreMock) UpdateSeries(id string, update func(series *Series) error) (*Series, error) {
+	args := ssm.Called(id)
+	series := args.Get(0).(*Series)
+	if err := update(series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func TestSeries_record(t *testing.T) {
+	testCases := []struct {
+		name             string
+		bestOf           int
+		replayTies       bool
+		results          []ResultsLabel
+		expectedFinished bool
+		expectedWinner   SeriesSide
+	}{
+		{
+			name:    "the series goes on while both sides can win",
+			bestOf:  5,
+			results: []ResultsLabel{Win, Win, Lose},
+		},
+		{
+			name:             "the first side to win a majority wins the series",
+			bestOf:           5,
+			results:          []ResultsLabel{Lose, Win, Lose, Lose},
+			expectedFinished: true,
+			expectedWinner:   SeriesComputer,
+		},
+		{
+			name:             "ties count as rounds",
+			bestOf:           3,
+			results:          []ResultsLabel{Tie, Tie, Win},
+			expectedFinished: true,
+			expectedWinner:   SeriesPlayer,
+		},
+		{
+			name:             "the series is drawn if both sides are level after all rounds",
+			bestOf:           3,
+			results:          []ResultsLabel{Win, Lose, Tie},
+			expectedFinished: true,
+		},
+		{
+			name:       "replayed ties don't count as rounds",
+			bestOf:     3,
+			replayTies: true,
+			results:    []ResultsLabel{Win, Tie, Tie, Lose, Tie},
+		},
+		{
+			name:             "a series with replayed ties is won by a majority",
+			bestOf:           3,
+			replayTies:       true,
+			results:          []ResultsLabel{Tie, Win, Tie, Win},
+			expectedFinished: true,
+			expectedWinner:   SeriesPlayer,
+		},
+	}
+
+	for _, tc := range testCases {
+		series := &Series{BestOf: tc.bestOf, ReplayTies: tc.replayTies}
+		for _, results := range tc.results {
+			series.record(&RoundResults{Results: string(results)})
+		}
+
+		require.Equal(t, tc.expectedFinished, series.Finished, tc.name)
+		require.Equal(t, tc.expectedWinner, series.Winner, tc.name)
+		require.Len(t, series.Rounds, len(tc.results), tc.name)
+	}
+}
+
+func TestSeriesService_CreateSeries(t *testing.T) {
+	testCases := []struct {
+		name             string
+		settings         SeriesSettings
+		expectedStrategy string
+		expectedError    error
+	}{
+		{
+			name:     "success: create the series owned by the user",
+			settings: SeriesSettings{BestOf: 5, Ruleset: "rpsls", ReplayTies: true, UserID: "user"},
+		},
+		{
+			name:             "success: store the name of the strategy selected by difficulty",
+			settings:         SeriesSettings{BestOf: 3, Ruleset: "rpsls", Strategy: "expert"},
+			expectedStrategy: "markov",
+		},
+		{
+			name:          "failure: if the strategy is unknown, return ErrStrategyNotFound",
+			settings:      SeriesSettings{BestOf: 3, Ruleset: "rpsls", Strategy: "impossible"},
+			expectedError: ErrStrategyNotFound,
+		},
+		{
+			name:          "failure: if the series is the best of an even number, return ErrInvalidSeries",
+			settings:      SeriesSettings{BestOf: 4, Ruleset: "rpsls"},
+			expectedError: ErrInvalidSeries,
+		},
+		{
+			name:          "failure: if the series is the best of no rounds, return ErrInvalidSeries",
+			settings:      SeriesSettings{Ruleset: "rpsls"},
+			expectedError: ErrInvalidSeries,
+		},
+		{
+			name:          "failure: if the ruleset doesn't exist, return ErrRulesetNotFound",
+			settings:      SeriesSettings{BestOf: 3, Ruleset: "missing"},
+			expectedError: ErrRulesetNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := SeriesStoreMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		strategyMock := StrategyServiceMock{}
+		service := NewSeriesService(&storeMock, &RoundStoreMock{}, &choiceServiceMock, &ScoreboardServiceMock{},
+			&strategyMock, &HistoryServiceMock{}, &AuditServiceMock{})
+		strategyMock.On("Strategy", "expert").Return(&StrategyInfo{Name: "markov", Difficulty: "expert"}, nil)
+		strategyMock.On("Strategy", "impossible").Return((*StrategyInfo)(nil), ErrStrategyNotFound)
+		choiceServiceMock.On("Choices", "rpsls").Return(baseChoices, nil)
+		choiceServiceMock.On("Choices", "missing").Return([]Choice(nil), ErrRulesetNotFound)
+		storeMock.On("CreateSeries", mock.Anything, seriesRetention).Return(nil)
+
+		series, err := service.CreateSeries(&tc.settings)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			storeMock.AssertNotCalled(t, "CreateSeries", mock.Anything, mock.Anything)
+		} else {
+			require.NoError(t, err, tc.name)
+			require.Len(t, series.ID, 16, tc.name)
+			require.Equal(t, tc.settings.UserID, series.Owner, tc.name)
+			require.Equal(t, tc.settings.BestOf, series.BestOf, tc.name)
+			require.Equal(t, tc.settings.ReplayTies, series.ReplayTies, tc.name)
+			require.Equal(t, tc.expectedStrategy, series.Strategy, tc.name)
+			require.False(t, series.Finished, tc.name)
+		}
+	}
+}
+
+func TestSeriesService_Play(t *testing.T) {
+	rock, scissors := &baseChoices[0], &baseChoices[2]
+
+	testCases := []struct {
+		name               string
+		series             *Series
+		storedSeries       *Series
+		expectedScore      SeriesScore
+		expectedScoreboard *RoundResults
+		auditError         error
+		expectedError      error
+	}{
+		{
+			name:          "success: record the round played with the strategy of the series",
+			series:        &Series{ID: "series", Owner: "user", Ruleset: "rpsls", BestOf: 3, Strategy: "markov"},
+			expectedScore: SeriesScore{Player: 1},
+		},
+		{
+			name: "success: record the finished series to the scoreboard",
+			series: &Series{ID: "series", Owner: "user", Ruleset: "rpsls", BestOf: 3,
+				Score:  SeriesScore{Player: 1, Ties: 1},
+				Rounds: []RoundResults{{Results: string(Win)}, {Results: string(Tie)}}},
+			expectedScore: SeriesScore{Player: 2, Ties: 1},
+			expectedScoreboard: &RoundResults{Results: string(Win), Ruleset: "rpsls", Series: "series",
+				Description: "Player wins the best-of-3 series 2-0"},
+		},
+		{
+			name:          "failure: if the series is finished, return ErrSeriesFinished",
+			series:        &Series{ID: "series", Owner: "user", Ruleset: "rpsls", BestOf: 1, Finished: true},
+			expectedError: ErrSeriesFinished,
+		},
+		{
+			name:          "failure: if the series belongs to another user, return ErrNotSeriesOwner",
+			series:        &Series{ID: "series", Owner: "other", Ruleset: "rpsls", BestOf: 3},
+			expectedError: ErrNotSeriesOwner,
+		},
+		{
+			name:          "failure: if the series ended meanwhile, return ErrSeriesFinished without an audit",
+			series:        &Series{ID: "series", Owner: "user", Ruleset: "rpsls", BestOf: 1},
+			storedSeries:  &Series{ID: "series", Owner: "user", Ruleset: "rpsls", BestOf: 1, Finished: true},
+			expectedError: ErrSeriesFinished,
+		},
+		{
+			name:          "failure: if the round can't be audited, return the error without adding it to the history",
+			series:        &Series{ID: "series", Owner: "user", Ruleset: "rpsls", BestOf: 3},
+			auditError:    errors.New("audit store down"),
+			expectedError: errors.New("audit store down"),
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := SeriesStoreMock{}
+		roundStoreMock := RoundStoreMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		scoreboardMock := ScoreboardServiceMock{}
+		strategyMock := StrategyServiceMock{}
+		historyMock := HistoryServiceMock{}
+		auditMock := AuditServiceMock{}
+		service := NewSeriesService(&storeMock, &roundStoreMock, &choiceServiceMock, &scoreboardMock, &strategyMock,
+			&historyMock, &auditMock)
+		storeMock.On("Series", "series").Return(tc.series, nil)
+		storedSeries := tc.series
+		if tc.storedSeries != nil {
+			storedSeries = tc.storedSeries
+		}
+		storeMock.On("UpdateSeries", "series").Return(storedSeries, nil)
+		choiceServiceMock.On("Choice", rock.ID).Return(rock, nil)
+		strategyMock.On("ComputerChoice", mock.Anything, "rpsls", tc.series.Strategy).
+			Return(&ComputerMove{Choice: scissors}, nil)
+		roundStoreMock.On("SimulateRound", rock.ID, scissors.ID).
+			Return(&Round{WinnerID: rock.ID, LoserID: scissors.ID, Action: "crushes"}, nil)
+		scoreboardMock.On("Append", mock.Anything, mock.Anything).Return(nil)
+		historyMock.On("Record", mock.Anything, mock.Anything, "series").Return(nil)
+		auditMock.On("Record", mock.Anything).Return(tc.auditError)
+
+		series, err := service.Play("series", &RoundSettings{Player: rock.ID, RequestID: "request", UserID: "user"})
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			if tc.auditError == nil {
+				auditMock.AssertNotCalled(t, "Record", mock.Anything)
+			}
+			historyMock.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedScore, series.Score, tc.name)
+		round := series.Rounds[len(series.Rounds)-1]
+		require.Equal(t, "rock crushes scissors", round.Description, tc.name)
+		audit := auditMock.Calls[0].Arguments.Get(0).(*RoundAudit)
+		require.Equal(t, round.ID, audit.ID, tc.name)
+		require.Equal(t, "request", audit.RequestID, tc.name)
+		require.Equal(t, "user", audit.UserID, tc.name)
+		require.Equal(t, scissors.ID, audit.Computer, tc.name)
+		historyMock.AssertNumberOfCalls(t, "Record", 1)
+		if tc.expectedScoreboard == nil {
+			scoreboardMock.AssertNotCalled(t, "Append", mock.Anything, mock.Anything)
+			continue
+		}
+		entry := scoreboardMock.Calls[0].Arguments.Get(1).(*RoundResults)
+		require.Equal(t, tc.expectedScoreboard.Results, entry.Results, tc.name)
+		require.Equal(t, tc.expectedScoreboard.Series, entry.Series, tc.name)
+		require.Equal(t, tc.expectedScoreboard.Description, entry.Description, tc.name)
+		require.Equal(t, &tc.expectedScore, entry.Score, tc.name)
+		require.Len(t, entry.Rounds, 3, tc.name)
+	}
+}
@@ -0,0 +1,277 @@
+package rpslsapi
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidSimulation = errors.New("simulations play between 1 and 10000 rounds between built-in strategies")
+
+// defaultSimulationRounds and maxSimulationRounds bound the number of rounds of a simulation, which is played on the
+// goroutine of the request
+const (
+	defaultSimulationRounds = 1000
+	maxSimulationRounds     = 10000
+)
+
+// SimulationSettings selects the strategies playing both sides of a simulation, by name or difficulty. Both play
+// random, i.e. uniformly, if empty.
+type SimulationSettings struct {
+	Ruleset  string `json:"ruleset"`
+	Rounds   int    `json:"rounds"`
+	Player   string `json:"player"`
+	Computer string `json:"computer"`
+	// Seed seeds the random values drawn by the strategies, so a simulation can be run again. It is picked at random
+	// if zero.
+	Seed int64 `json:"seed"`
+}
+
+// SimulationStats counts the outcomes of rounds from the perspective of the side or choice they describe
+type SimulationStats struct {
+	Rounds   int     `json:"rounds"`
+	Wins     int     `json:"wins"`
+	Ties     int     `json:"ties"`
+	Losses   int     `json:"losses"`
+	WinRate  float64 `json:"win_rate"`
+	TieRate  float64 `json:"tie_rate"`
+	LossRate float64 `json:"loss_rate"`
+}
+
+// ChoiceStats counts the outcomes of the rounds a choice was played in by either side
+type ChoiceStats struct {
+	Choice string `json:"choice"`
+	SimulationStats
+}
+
+// MatchupStats counts the outcomes of the rounds the player side played a choice against one of the computer side
+type MatchupStats struct {
+	Player   string `json:"player"`
+	Computer string `json:"computer"`
+	SimulationStats
+}
+
+type SimulationReport struct {
+	Ruleset  string `json:"ruleset"`
+	Player   string `json:"player"`
+	Computer string `json:"computer"`
+	Seed     int64  `json:"seed"`
+	// Totals counts the outcomes from the perspective of the player side
+	Totals   SimulationStats `json:"totals"`
+	Choices  []ChoiceStats   `json:"choices"`
+	Matchups []MatchupStats  `json:"matchups"`
+}
+
+// SimulationService plays rounds between strategies in-process, to check the balance of rulesets. Rounds are neither
+// recorded nor drawn from the random number server.
+type SimulationService interface {
+	Simulate(settings *SimulationSettings) (*SimulationReport, error)
+}
+
+type SimulationServiceImpl struct {
+	choiceService ChoiceService
+	// historySize is how many previous rounds strategies learn from, as much as they do from scoreboards
+	historySize int
+	now         func() time.Time
+}
+
+func NewSimulationService(choiceService ChoiceService) SimulationService {
+	return SimulationServiceImpl{choiceService: choiceService, historySize: Config.ScoreboardSize, now: time.Now}
+}
+
+func (ss SimulationServiceImpl) Simulate(settings *SimulationSettings) (*SimulationReport, error) {
+	rounds := settings.Rounds
+	if rounds == 0 {
+		rounds = defaultSimulationRounds
+	}
+	if rounds < 0 || rounds > maxSimulationRounds {
+		return nil, ErrInvalidSimulation
+	}
+	player, err := simulatedStrategy(settings.Player)
+	if err != nil {
+		return nil, err
+	}
+	computer, err := simulatedStrategy(settings.Computer)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleset := RulesetOrDefault(settings.Ruleset)
+	choices, err := ss.choiceService.Choices(ruleset)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := ss.choiceService.Rules(ruleset)
+	if err != nil {
+		return nil, err
+	}
+	// every pair of choices must be decided by exactly one rule for the rates to mean anything
+	if report := ValidateRuleGraph(choices, rules); !report.Valid {
+		return nil, &RuleGraphError{Report: report}
+	}
+	if len(choices) == 0 {
+		return nil, ErrChoiceNotFound
+	}
+
+	seed := settings.Seed
+	if seed == 0 {
+		seed = ss.now().UnixNano()
+	}
+	simulation := newSimulation(choices, rules, NewSeededRandomizer(seed), ss.historySize)
+	for i := 0; i < rounds; i++ {
+		if err := simulation.playRound(player.strategy, computer.strategy); err != nil {
+			return nil, err
+		}
+	}
+	return simulation.report(ruleset, player.Name, computer.Name, seed), nil
+}
+
+// simulatedStrategy returns the built-in strategy with the name or difficulty, random if empty. Bots can't be
+// simulated, each of their moves being an HTTP call.
+func simulatedStrategy(nameOrDifficulty string) (*StrategyInfo, error) {
+	if nameOrDifficulty == "" {
+		nameOrDifficulty = defaultStrategy
+	}
+	info, err := findStrategy(nameOrDifficulty)
+	if err == ErrStrategyNotFound {
+		return nil, ErrInvalidSimulation
+	}
+	return info, err
+}
+
+// simulation plays rounds between two strategies, each of them seeing the history of its opponent as the one of a
+// player facing the computer
+type simulation struct {
+	choices     []Choice
+	rules       []Rule
+	beats       map[string]map[string]bool
+	randomizer  RandomizerService
+	historySize int
+	// playerHistory holds the rounds from the perspective of the player side, most recent first, computerHistory
+	// the same rounds from the perspective of the computer side
+	playerHistory   []RoundResults
+	computerHistory []RoundResults
+	totals          SimulationStats
+	choiceStats     map[string]*SimulationStats
+	matchupStats    map[choicePair]*SimulationStats
+}
+
+func newSimulation(choices []Choice, rules []Rule, randomizer RandomizerService, historySize int) *simulation {
+	return &simulation{
+		choices:      choices,
+		rules:        rules,
+		beats:        beatsByWinner(rules),
+		randomizer:   randomizer,
+		historySize:  historySize,
+		choiceStats:  map[string]*SimulationStats{},
+		matchupStats: map[choicePair]*SimulationStats{},
+	}
+}
+
+func (s *simulation) playRound(player, computer ComputerStrategy) error {
+	// the strategy of the computer side learns from the moves of the player side, and the other way around
+	computerChoice, err := computer.Choose(&StrategyInput{Choices: s.choices, Rules: s.rules,
+		History: s.playerHistory, Randomizer: s.randomizer})
+	if err != nil {
+		return err
+	}
+	playerChoice, err := player.Choose(&StrategyInput{Choices: s.choices, Rules: s.rules,
+		History: s.computerHistory, Randomizer: s.randomizer})
+	if err != nil {
+		return err
+	}
+
+	results := Tie
+	if s.beats[playerChoice.ID][computerChoice.ID] {
+		results = Win
+	} else if s.beats[computerChoice.ID][playerChoice.ID] {
+		results = Lose
+	}
+	s.totals.count(results)
+	s.statsOf(playerChoice.ID).count(results)
+	s.statsOf(computerChoice.ID).count(opposite(results))
+	matchup := choicePair{first: playerChoice.ID, second: computerChoice.ID}
+	if s.matchupStats[matchup] == nil {
+		s.matchupStats[matchup] = &SimulationStats{}
+	}
+	s.matchupStats[matchup].count(results)
+
+	s.playerHistory = s.remember(s.playerHistory, RoundResults{Results: string(results),
+		Player: playerChoice.ID, Computer: computerChoice.ID})
+	s.computerHistory = s.remember(s.computerHistory, RoundResults{Results: string(opposite(results)),
+		Player: computerChoice.ID, Computer: playerChoice.ID})
+	return nil
+}
+
+// remember adds the round to the history, only keeping the most recent rounds like scoreboards do
+func (s *simulation) remember(history []RoundResults, round RoundResults) []RoundResults {
+	history = append([]RoundResults{round}, history...)
+	if len(history) > s.historySize {
+		history = history[:s.historySize]
+	}
+	return history
+}
+
+func (s *simulation) statsOf(choiceID string) *SimulationStats {
+	if s.choiceStats[choiceID] == nil {
+		s.choiceStats[choiceID] = &SimulationStats{}
+	}
+	return s.choiceStats[choiceID]
+}
+
+// report lists the choices and the matchups that were played in the order of the choices of the ruleset
+func (s *simulation) report(ruleset, player, computer string, seed int64) *SimulationReport {
+	report := &SimulationReport{
+		Ruleset:  ruleset,
+		Player:   player,
+		Computer: computer,
+		Seed:     seed,
+		Totals:   s.totals.withRates(),
+		Choices:  []ChoiceStats{},
+		Matchups: []MatchupStats{},
+	}
+	for _, playerChoice := range s.choices {
+		if stats := s.choiceStats[playerChoice.ID]; stats != nil {
+			report.Choices = append(report.Choices, ChoiceStats{Choice: playerChoice.ID,
+				SimulationStats: stats.withRates()})
+		}
+		for _, computerChoice := range s.choices {
+			if stats := s.matchupStats[choicePair{first: playerChoice.ID, second: computerChoice.ID}]; stats != nil {
+				report.Matchups = append(report.Matchups, MatchupStats{Player: playerChoice.ID,
+					Computer: computerChoice.ID, SimulationStats: stats.withRates()})
+			}
+		}
+	}
+	return report
+}
+
+func (ss *SimulationStats) count(results ResultsLabel) {
+	ss.Rounds++
+	switch results {
+	case Win:
+		ss.Wins++
+	case Tie:
+		ss.Ties++
+	case Lose:
+		ss.Losses++
+	}
+}
+
+func (ss SimulationStats) withRates() SimulationStats {
+	if ss.Rounds > 0 {
+		ss.WinRate = float64(ss.Wins) / float64(ss.Rounds)
+		ss.TieRate = float64(ss.Ties) / float64(ss.Rounds)
+		ss.LossRate = float64(ss.Losses) / float64(ss.Rounds)
+	}
+	return ss
+}
+
+// opposite returns the results of a round from the perspective of the other side
+func opposite(results ResultsLabel) ResultsLabel {
+	switch results {
+	case Win:
+		return Lose
+	case Lose:
+		return Win
+	}
+	return results
+}
@@ -0,0 +1,110 @@
+package rpslsapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulationService_Simulate(t *testing.T) {
+	testCases := []struct {
+		name             string
+		settings         SimulationSettings
+		rules            []Rule
+		expectedPlayer   string
+		expectedComputer string
+		expectedRounds   int
+		expectedError    error
+	}{
+		{
+			name:             "success: play uniformly on both sides by default",
+			settings:         SimulationSettings{Ruleset: "rpsls", Seed: 42},
+			rules:            baseRules,
+			expectedPlayer:   "random",
+			expectedComputer: "random",
+			expectedRounds:   defaultSimulationRounds,
+		},
+		{
+			name:             "success: play strategies selected by name or difficulty",
+			settings:         SimulationSettings{Ruleset: "rpsls", Rounds: 200, Player: "frequency", Computer: "expert"},
+			rules:            baseRules,
+			expectedPlayer:   "frequency",
+			expectedComputer: "markov",
+			expectedRounds:   200,
+		},
+		{
+			name:          "failure: if there are too many rounds, return ErrInvalidSimulation",
+			settings:      SimulationSettings{Ruleset: "rpsls", Rounds: maxSimulationRounds + 1},
+			rules:         baseRules,
+			expectedError: ErrInvalidSimulation,
+		},
+		{
+			name:          "failure: if a strategy isn't built-in, return ErrInvalidSimulation",
+			settings:      SimulationSettings{Ruleset: "rpsls", Computer: "some-bot"},
+			rules:         baseRules,
+			expectedError: ErrInvalidSimulation,
+		},
+		{
+			name:          "failure: if a pair of choices isn't decided by a rule, return ErrInconsistentRuleGraph",
+			settings:      SimulationSettings{Ruleset: "rpsls"},
+			rules:         baseRules[1:],
+			expectedError: ErrInconsistentRuleGraph,
+		},
+	}
+
+	for _, tc := range testCases {
+		choiceServiceMock := ChoiceServiceMock{}
+		service := NewSimulationService(&choiceServiceMock).(SimulationServiceImpl)
+		service.historySize = 10
+		choiceServiceMock.On("Choices", "rpsls").Return(baseChoices, nil)
+		choiceServiceMock.On("Rules", "rpsls").Return(tc.rules, nil)
+
+		report, err := service.Simulate(&tc.settings)
+
+		if tc.expectedError != nil {
+			require.ErrorIs(t, err, tc.expectedError, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedPlayer, report.Player, tc.name)
+		require.Equal(t, tc.expectedComputer, report.Computer, tc.name)
+		require.NotZero(t, report.Seed, tc.name)
+		totals := report.Totals
+		require.Equal(t, tc.expectedRounds, totals.Rounds, tc.name)
+		require.Equal(t, totals.Rounds, totals.Wins+totals.Ties+totals.Losses, tc.name)
+		require.InDelta(t, 1, totals.WinRate+totals.TieRate+totals.LossRate, 1e-9, tc.name)
+
+		played := 0
+		for _, stats := range report.Choices {
+			played += stats.Rounds
+		}
+		require.Equal(t, 2*totals.Rounds, played, tc.name)
+		matchups := 0
+		for _, stats := range report.Matchups {
+			matchups += stats.Rounds
+			require.Equal(t, stats.Player == stats.Computer, stats.Ties == stats.Rounds, tc.name)
+		}
+		require.Equal(t, totals.Rounds, matchups, tc.name)
+	}
+}
+
+func TestSimulationService_SimulateIsReproducible(t *testing.T) {
+	choiceServiceMock := ChoiceServiceMock{}
+	service := NewSimulationService(&choiceServiceMock).(SimulationServiceImpl)
+	service.historySize = 10
+	choiceServiceMock.On("Choices", "rpsls").Return(baseChoices, nil)
+	choiceServiceMock.On("Rules", "rpsls").Return(baseRules, nil)
+	settings := &SimulationSettings{Ruleset: "rpsls", Rounds: 5000, Player: "random", Computer: "random", Seed: 7}
+
+	first, err := service.Simulate(settings)
+	require.NoError(t, err)
+	second, err := service.Simulate(settings)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	// every choice of a balanced ruleset wins and loses as often when both sides play uniformly
+	for _, stats := range first.Choices {
+		require.InDelta(t, 0.4, stats.WinRate, 0.03, stats.Choice)
+		require.InDelta(t, 0.4, stats.LossRate, 0.03, stats.Choice)
+	}
+}
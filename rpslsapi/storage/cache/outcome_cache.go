@@ -0,0 +1,393 @@
+package cache
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+type choicePair struct {
+	first, second string
+}
+
+func newChoicePair(choice1ID, choice2ID string) choicePair {
+	if choice1ID > choice2ID {
+		return choicePair{choice2ID, choice1ID}
+	}
+	return choicePair{choice1ID, choice2ID}
+}
+
+// outcomeMatrix holds the whole rule graph of a ruleset along with the outcome of every pair of choices
+type outcomeMatrix struct {
+	choices  []rpslsapi.Choice
+	rules    []rpslsapi.Rule
+	outcomes map[choicePair][]rpslsapi.Round
+	loadedAt time.Time
+}
+
+func newOutcomeMatrix(choices []rpslsapi.Choice, rules []rpslsapi.Rule, loadedAt time.Time) *outcomeMatrix {
+	matrix := &outcomeMatrix{
+		choices:  choices,
+		rules:    rules,
+		outcomes: make(map[choicePair][]rpslsapi.Round, len(rules)),
+		loadedAt: loadedAt,
+	}
+	for _, rule := range rules {
+		pair := newChoicePair(rule.WinnerID, rule.LoserID)
+		matrix.outcomes[pair] = append(matrix.outcomes[pair],
+			rpslsapi.Round{WinnerID: rule.WinnerID, LoserID: rule.LoserID, Action: rule.Action})
+	}
+	return matrix
+}
+
+// OutcomeMatrixCache loads the choices and rules of a ruleset from a ChoiceStore the first time they are needed and
+// answers reads and rounds from memory afterwards. Writes go through to the store and invalidate the affected ruleset;
+// entries older than the TTL are reloaded, so changes made by other server instances are eventually picked up. A zero
+// TTL keeps entries until they are invalidated.
+type OutcomeMatrixCache struct {
+	store rpslsapi.ChoiceStore
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu       sync.RWMutex
+	matrices map[string]*outcomeMatrix
+	// rulesets indexes the ruleset of every cached choice
+	rulesets map[string]string
+	// loads runs a single load per ruleset at a time, outside of mu
+	loads map[string]*sync.Mutex
+	// generation is incremented by every invalidation, so that matrices loaded meanwhile aren't cached
+	generation uint64
+	// translations is invalidated by the writes deleting choices, whose translations are deleted along with them
+	translations *TranslationCache
+
+	hits, misses, refreshes, invalidations uint64
+}
+
+func NewOutcomeMatrixCache(store rpslsapi.ChoiceStore, ttl time.Duration) *OutcomeMatrixCache {
+	return &OutcomeMatrixCache{
+		store:    store,
+		ttl:      ttl,
+		now:      time.Now,
+		matrices: make(map[string]*outcomeMatrix),
+		rulesets: make(map[string]string),
+		loads:    make(map[string]*sync.Mutex),
+	}
+}
+
+func (c *OutcomeMatrixCache) Stats() rpslsapi.OutcomeCacheStats {
+	stats := rpslsapi.OutcomeCacheStats{
+		Hits:           atomic.LoadUint64(&c.hits),
+		Misses:         atomic.LoadUint64(&c.misses),
+		Refreshes:      atomic.LoadUint64(&c.refreshes),
+		Invalidations:  atomic.LoadUint64(&c.invalidations),
+		CachedRulesets: []string{},
+		TTL:            c.ttl.String(),
+	}
+	if lookups := stats.Hits + stats.Misses + stats.Refreshes; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for ruleset := range c.matrices {
+		stats.CachedRulesets = append(stats.CachedRulesets, ruleset)
+	}
+	sort.Strings(stats.CachedRulesets)
+	return stats
+}
+
+func (c *OutcomeMatrixCache) Invalidate(ruleset string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if ruleset == "" {
+		for cached := range c.matrices {
+			c.invalidate(cached)
+		}
+		return
+	}
+	c.invalidate(ruleset)
+}
+
+// AttachTranslations makes the writes deleting choices invalidate the translation cache
+func (c *OutcomeMatrixCache) AttachTranslations(translations *TranslationCache) {
+	c.translations = translations
+}
+
+// ChoiceStore returns a ChoiceStore reading from the cache
+func (c *OutcomeMatrixCache) ChoiceStore() rpslsapi.ChoiceStore {
+	return ChoiceStore{c}
+}
+
+// RoundStore returns a RoundStore deciding rounds from the cache
+func (c *OutcomeMatrixCache) RoundStore() rpslsapi.RoundStore {
+	return RoundStore{c}
+}
+
+// RulesetStore wraps store so deleting a ruleset invalidates its cached matrix
+func (c *OutcomeMatrixCache) RulesetStore(store rpslsapi.RulesetStore) rpslsapi.RulesetStore {
+	return RulesetStore{RulesetStore: store, cache: c}
+}
+
+// matrix returns the cached matrix of the ruleset, loading it from the store if it is missing or expired
+func (c *OutcomeMatrixCache) matrix(ruleset string) (*outcomeMatrix, error) {
+	c.mu.RLock()
+	matrix, found := c.matrices[ruleset]
+	c.mu.RUnlock()
+	if found && !c.expired(matrix) {
+		atomic.AddUint64(&c.hits, 1)
+		return matrix, nil
+	}
+
+	load := c.load(ruleset)
+	load.Lock()
+	defer load.Unlock()
+	c.mu.RLock()
+	matrix, found = c.matrices[ruleset]
+	generation := c.generation
+	c.mu.RUnlock()
+	switch {
+	case found && !c.expired(matrix):
+		atomic.AddUint64(&c.hits, 1)
+		return matrix, nil
+	case found:
+		atomic.AddUint64(&c.refreshes, 1)
+	default:
+		atomic.AddUint64(&c.misses, 1)
+	}
+
+	choices, err := c.store.Choices(ruleset)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := c.store.Rules(ruleset)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix = newOutcomeMatrix(choices, rules, c.now())
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// a write invalidating the cache while the matrix was loaded may be missing from it, so the matrix is only
+	// returned to this read: a write committed before its invalidation never leaves a stale matrix behind
+	if c.generation != generation {
+		return matrix, nil
+	}
+	c.drop(ruleset)
+	c.matrices[ruleset] = matrix
+	for _, choice := range choices {
+		c.rulesets[choice.ID] = ruleset
+	}
+	return matrix, nil
+}
+
+// load returns the mutex serializing the loads of the ruleset
+func (c *OutcomeMatrixCache) load(ruleset string) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	load, found := c.loads[ruleset]
+	if !found {
+		load = &sync.Mutex{}
+		c.loads[ruleset] = load
+	}
+	return load
+}
+
+// choiceMatrix returns the matrix of the ruleset the choice belongs to, or ErrChoiceNotFound
+func (c *OutcomeMatrixCache) choiceMatrix(choiceID string) (*outcomeMatrix, error) {
+	ruleset, found := c.rulesetOf(choiceID)
+	if !found {
+		choice, err := c.store.Choice(choiceID)
+		if err != nil {
+			return nil, err
+		}
+		ruleset = choice.Ruleset
+	}
+
+	return c.matrix(ruleset)
+}
+
+func (c *OutcomeMatrixCache) rulesetOf(choiceID string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ruleset, found := c.rulesets[choiceID]
+	return ruleset, found
+}
+
+// invalidateChoiceRuleset invalidates the ruleset of the choice, if cached. It is meant to be called after writes
+// concerning an existing choice: a matrix loaded before the write was committed necessarily indexes the choice, and
+// the ones being loaded meanwhile aren't cached.
+func (c *OutcomeMatrixCache) invalidateChoiceRuleset(choiceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if ruleset, found := c.rulesets[choiceID]; found {
+		c.invalidate(ruleset)
+	}
+}
+
+// invalidate drops the matrix of the ruleset, counting the invalidation. The caller must hold the write lock.
+func (c *OutcomeMatrixCache) invalidate(ruleset string) {
+	if c.drop(ruleset) {
+		atomic.AddUint64(&c.invalidations, 1)
+	}
+}
+
+// drop removes the matrix of the ruleset and its choices from the index, returning whether it was cached. The caller
+// must hold the write lock.
+func (c *OutcomeMatrixCache) drop(ruleset string) bool {
+	matrix, found := c.matrices[ruleset]
+	if !found {
+		return false
+	}
+	delete(c.matrices, ruleset)
+	for _, choice := range matrix.choices {
+		if c.rulesets[choice.ID] == ruleset {
+			delete(c.rulesets, choice.ID)
+		}
+	}
+	return true
+}
+
+// invalidateTranslations drops the cached translations, if a translation cache is attached
+func (c *OutcomeMatrixCache) invalidateTranslations() {
+	if c.translations != nil {
+		c.translations.Invalidate()
+	}
+}
+
+func (c *OutcomeMatrixCache) expired(matrix *outcomeMatrix) bool {
+	return c.ttl > 0 && c.now().Sub(matrix.loadedAt) >= c.ttl
+}
+
+type ChoiceStore struct {
+	*OutcomeMatrixCache
+}
+
+func (cs ChoiceStore) Choices(ruleset string) ([]rpslsapi.Choice, error) {
+	matrix, err := cs.matrix(ruleset)
+	if err != nil {
+		return nil, err
+	}
+	if matrix.choices == nil {
+		return nil, nil
+	}
+	return append([]rpslsapi.Choice{}, matrix.choices...), nil
+}
+
+func (cs ChoiceStore) Choice(id string) (*rpslsapi.Choice, error) {
+	matrix, err := cs.choiceMatrix(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, choice := range matrix.choices {
+		if choice.ID == id {
+			return &choice, nil
+		}
+	}
+	// the choice was deleted between both lookups
+	return nil, rpslsapi.ErrChoiceNotFound
+}
+
+func (cs ChoiceStore) CreateChoice(definition *rpslsapi.ChoiceDefinition) (*rpslsapi.Choice, error) {
+	choice, err := cs.store.CreateChoice(definition)
+	cs.Invalidate(definition.Ruleset)
+	return choice, err
+}
+
+func (cs ChoiceStore) UpdateChoice(choice *rpslsapi.Choice) (*rpslsapi.Choice, error) {
+	updated, err := cs.store.UpdateChoice(choice)
+	cs.invalidateChoiceRuleset(choice.ID)
+	return updated, err
+}
+
+func (cs ChoiceStore) DeleteChoice(id string) error {
+	err := cs.store.DeleteChoice(id)
+	cs.invalidateChoiceRuleset(id)
+	cs.invalidateTranslations()
+	return err
+}
+
+func (cs ChoiceStore) Rules(ruleset string) ([]rpslsapi.Rule, error) {
+	matrix, err := cs.matrix(ruleset)
+	if err == rpslsapi.ErrRulesetNotFound {
+		// stores list no rules for unknown rulesets
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if matrix.rules == nil {
+		return nil, nil
+	}
+	return append([]rpslsapi.Rule{}, matrix.rules...), nil
+}
+
+func (cs ChoiceStore) SaveRule(rule *rpslsapi.Rule) error {
+	err := cs.store.SaveRule(rule)
+	cs.invalidateChoiceRuleset(rule.WinnerID)
+	cs.invalidateChoiceRuleset(rule.LoserID)
+	return err
+}
+
+func (cs ChoiceStore) DeleteRule(winnerID, loserID string) error {
+	err := cs.store.DeleteRule(winnerID, loserID)
+	cs.invalidateChoiceRuleset(winnerID)
+	return err
+}
+
+func (cs ChoiceStore) ImportRuleset(document *rpslsapi.RulesetDocument, replace bool) (*rpslsapi.Ruleset, error) {
+	ruleset, err := cs.store.ImportRuleset(document, replace)
+	cs.Invalidate(document.ID)
+	if replace {
+		cs.invalidateTranslations()
+	}
+	return ruleset, err
+}
+
+type RoundStore struct {
+	*OutcomeMatrixCache
+}
+
+func (rs RoundStore) SimulateRound(choice1ID, choice2ID string) (*rpslsapi.Round, error) {
+	matrix, err := rs.choiceMatrix(choice1ID)
+	if err == rpslsapi.ErrChoiceNotFound {
+		return nil, rpslsapi.ErrRuleNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	rounds := matrix.outcomes[newChoicePair(choice1ID, choice2ID)]
+	switch len(rounds) {
+	case 0:
+		return nil, rpslsapi.ErrRuleNotFound
+	case 1:
+		round := rounds[0]
+		return &round, nil
+	default:
+		return nil, errors.New("multiple BEATS associations")
+	}
+}
+
+type RulesetStore struct {
+	rpslsapi.RulesetStore
+	cache *OutcomeMatrixCache
+}
+
+func (rs RulesetStore) CreateRuleset(ruleset *rpslsapi.Ruleset) (*rpslsapi.Ruleset, error) {
+	created, err := rs.RulesetStore.CreateRuleset(ruleset)
+	rs.cache.Invalidate(ruleset.ID)
+	return created, err
+}
+
+func (rs RulesetStore) DeleteRuleset(id string) error {
+	err := rs.RulesetStore.DeleteRuleset(id)
+	rs.cache.Invalidate(id)
+	rs.cache.invalidateTranslations()
+	return err
+}
@@ -0,0 +1,242 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+	"rpsls/rpslsapi/storage/memory"
+)
+
+// countingChoiceStore counts the reads reaching the underlying store
+type countingChoiceStore struct {
+	rpslsapi.ChoiceStore
+	reads int
+}
+
+func (cs *countingChoiceStore) Choices(ruleset string) ([]rpslsapi.Choice, error) {
+	cs.reads++
+	return cs.ChoiceStore.Choices(ruleset)
+}
+
+func (cs *countingChoiceStore) Choice(id string) (*rpslsapi.Choice, error) {
+	cs.reads++
+	return cs.ChoiceStore.Choice(id)
+}
+
+func (cs *countingChoiceStore) Rules(ruleset string) ([]rpslsapi.Rule, error) {
+	cs.reads++
+	return cs.ChoiceStore.Rules(ruleset)
+}
+
+// blockingChoiceStore holds the loads of a ruleset, after reading its choices, until released
+type blockingChoiceStore struct {
+	rpslsapi.ChoiceStore
+	ruleset string
+	loading chan struct{}
+	release chan struct{}
+}
+
+func newBlockingChoiceStore(ruleset string) *blockingChoiceStore {
+	return &blockingChoiceStore{
+		ChoiceStore: memory.NewChoiceStore(memory.NewDB()),
+		ruleset:     ruleset,
+		loading:     make(chan struct{}, 1),
+		release:     make(chan struct{}),
+	}
+}
+
+func (cs *blockingChoiceStore) Choices(ruleset string) ([]rpslsapi.Choice, error) {
+	choices, err := cs.ChoiceStore.Choices(ruleset)
+	if ruleset == cs.ruleset {
+		select {
+		case cs.loading <- struct{}{}:
+		default:
+		}
+		<-cs.release
+	}
+	return choices, err
+}
+
+func newTestCache(ttl time.Duration) (*OutcomeMatrixCache, *countingChoiceStore) {
+	store := &countingChoiceStore{ChoiceStore: memory.NewChoiceStore(memory.NewDB())}
+	return NewOutcomeMatrixCache(store, ttl), store
+}
+
+func choiceIDs(t *testing.T, store rpslsapi.ChoiceStore, ruleset string) map[string]string {
+	choices, err := store.Choices(ruleset)
+	require.NoError(t, err)
+	ids := make(map[string]string, len(choices))
+	for _, choice := range choices {
+		ids[choice.Name] = choice.ID
+	}
+	return ids
+}
+
+func TestOutcomeMatrixCache_RoundsAreAnsweredFromMemory(t *testing.T) {
+	outcomeCache, store := newTestCache(0)
+	choiceStore, roundStore := outcomeCache.ChoiceStore(), outcomeCache.RoundStore()
+	ids := choiceIDs(t, choiceStore, "rpsls")
+	require.Equal(t, 2, store.reads)
+
+	for i := 0; i < 3; i++ {
+		choice, err := choiceStore.Choice(ids["Rock"])
+		require.NoError(t, err)
+		require.Equal(t, &rpslsapi.Choice{ID: ids["Rock"], Name: "Rock", Ruleset: "rpsls"}, choice)
+
+		round, err := roundStore.SimulateRound(ids["Rock"], ids["Spock"])
+		require.NoError(t, err)
+		require.Equal(t, &rpslsapi.Round{WinnerID: ids["Spock"], LoserID: ids["Rock"], Action: "vaporizes"}, round)
+	}
+	require.Equal(t, 2, store.reads)
+
+	_, err := roundStore.SimulateRound(ids["Rock"], ids["Rock"])
+	require.Equal(t, rpslsapi.ErrRuleNotFound, err)
+	_, err = choiceStore.Choice("rpsls-well")
+	require.Equal(t, rpslsapi.ErrChoiceNotFound, err)
+
+	stats := outcomeCache.Stats()
+	require.Equal(t, uint64(1), stats.Misses)
+	require.Equal(t, uint64(7), stats.Hits)
+	require.Equal(t, []string{"rpsls"}, stats.CachedRulesets)
+}
+
+func TestOutcomeMatrixCache_UnknownRuleset(t *testing.T) {
+	outcomeCache, _ := newTestCache(0)
+
+	_, err := outcomeCache.ChoiceStore().Choices("missing")
+	require.Equal(t, rpslsapi.ErrRulesetNotFound, err)
+	rules, err := outcomeCache.ChoiceStore().Rules("missing")
+	require.NoError(t, err)
+	require.Empty(t, rules)
+	require.Empty(t, outcomeCache.Stats().CachedRulesets)
+}
+
+func TestOutcomeMatrixCache_WritesInvalidate(t *testing.T) {
+	outcomeCache, _ := newTestCache(0)
+	choiceStore, roundStore := outcomeCache.ChoiceStore(), outcomeCache.RoundStore()
+	ids := choiceIDs(t, choiceStore, "rps")
+	choiceIDs(t, choiceStore, "rpsls")
+
+	rule := rpslsapi.Rule{WinnerID: ids["Rock"], LoserID: ids["Paper"], Action: "breaks"}
+	require.NoError(t, choiceStore.SaveRule(&rule))
+	require.Equal(t, []string{"rpsls"}, outcomeCache.Stats().CachedRulesets)
+	round, err := roundStore.SimulateRound(ids["Paper"], ids["Rock"])
+	require.NoError(t, err)
+	require.Equal(t, "breaks", round.Action)
+
+	_, err = choiceStore.UpdateChoice(&rpslsapi.Choice{ID: ids["Rock"], Name: "Boulder"})
+	require.NoError(t, err)
+	choice, err := choiceStore.Choice(ids["Rock"])
+	require.NoError(t, err)
+	require.Equal(t, "Boulder", choice.Name)
+
+	require.NoError(t, choiceStore.DeleteChoice(ids["Rock"]))
+	_, err = choiceStore.Choice(ids["Rock"])
+	require.Equal(t, rpslsapi.ErrChoiceNotFound, err)
+
+	_, err = choiceStore.CreateChoice(&rpslsapi.ChoiceDefinition{Name: "Well", Ruleset: "rps"})
+	require.NoError(t, err)
+	require.Contains(t, choiceIDs(t, choiceStore, "rps"), "Well")
+
+	rulesetStore := outcomeCache.RulesetStore(memory.NewRulesetStore(memory.NewDB()))
+	require.NoError(t, rulesetStore.DeleteRuleset("rpsls"))
+	require.Equal(t, []string{"rps"}, outcomeCache.Stats().CachedRulesets)
+	require.Equal(t, uint64(4), outcomeCache.Stats().Invalidations)
+}
+
+func TestOutcomeMatrixCache_WritesAreValidatedAgainstTheStore(t *testing.T) {
+	outcomeCache, store := newTestCache(time.Hour)
+	choiceService := rpslsapi.NewChoiceService(outcomeCache.ChoiceStore(), nil, rpslsapi.NewRuleGraphLock())
+	ids := choiceIDs(t, outcomeCache.ChoiceStore(), "rps")
+	// another instance deletes Paper: reversing the rule between Rock and Scissors only unbalances the cached graph
+	require.NoError(t, store.ChoiceStore.DeleteChoice(ids["Paper"]))
+	reads := store.reads
+
+	err := choiceService.SaveRule(&rpslsapi.Rule{WinnerID: ids["Scissors"], LoserID: ids["Rock"], Action: "cuts"})
+
+	require.NoError(t, err)
+	require.Equal(t, reads+2, store.reads)
+}
+
+func TestOutcomeMatrixCache_LoadsDontBlockOtherRulesets(t *testing.T) {
+	store := newBlockingChoiceStore("rpsls")
+	outcomeCache := NewOutcomeMatrixCache(store, 0)
+	loaded := make(chan error)
+	go func() {
+		_, err := outcomeCache.ChoiceStore().Choices("rpsls")
+		loaded <- err
+	}()
+	<-store.loading
+
+	require.Contains(t, choiceIDs(t, outcomeCache.ChoiceStore(), "rps"), "Rock")
+	require.Equal(t, []string{"rps"}, outcomeCache.Stats().CachedRulesets)
+
+	close(store.release)
+	require.NoError(t, <-loaded)
+	require.Equal(t, []string{"rps", "rpsls"}, outcomeCache.Stats().CachedRulesets)
+}
+
+func TestOutcomeMatrixCache_LoadsOverlappingWritesAreNotCached(t *testing.T) {
+	store := newBlockingChoiceStore("rps")
+	outcomeCache := NewOutcomeMatrixCache(store, 0)
+	choiceStore := outcomeCache.ChoiceStore()
+	ids := choiceIDs(t, store.ChoiceStore, "rps")
+	loaded := make(chan error)
+	go func() {
+		_, err := choiceStore.Choices("rps")
+		loaded <- err
+	}()
+	<-store.loading
+
+	_, err := choiceStore.UpdateChoice(&rpslsapi.Choice{ID: ids["Rock"], Name: "Boulder"})
+	require.NoError(t, err)
+	close(store.release)
+	require.NoError(t, <-loaded)
+
+	require.Empty(t, outcomeCache.Stats().CachedRulesets)
+	choice, err := choiceStore.Choice(ids["Rock"])
+	require.NoError(t, err)
+	require.Equal(t, "Boulder", choice.Name)
+}
+
+func TestOutcomeMatrixCache_TTL(t *testing.T) {
+	outcomeCache, store := newTestCache(time.Minute)
+	now := time.Now()
+	outcomeCache.now = func() time.Time { return now }
+
+	choiceIDs(t, outcomeCache.ChoiceStore(), "rps")
+	now = now.Add(59 * time.Second)
+	choiceIDs(t, outcomeCache.ChoiceStore(), "rps")
+	require.Equal(t, 2, store.reads)
+
+	now = now.Add(time.Second)
+	choiceIDs(t, outcomeCache.ChoiceStore(), "rps")
+	require.Equal(t, 4, store.reads)
+
+	stats := outcomeCache.Stats()
+	require.Equal(t, rpslsapi.OutcomeCacheStats{
+		Hits:           1,
+		Misses:         1,
+		Refreshes:      1,
+		Invalidations:  0,
+		HitRatio:       float64(1) / 3,
+		CachedRulesets: []string{"rps"},
+		TTL:            "1m0s",
+	}, stats)
+}
+
+func TestOutcomeMatrixCache_Invalidate(t *testing.T) {
+	outcomeCache, store := newTestCache(0)
+	choiceIDs(t, outcomeCache.ChoiceStore(), "rps")
+	choiceIDs(t, outcomeCache.ChoiceStore(), "rpsls")
+
+	outcomeCache.Invalidate("rps")
+	require.Equal(t, []string{"rpsls"}, outcomeCache.Stats().CachedRulesets)
+	outcomeCache.Invalidate("")
+	require.Empty(t, outcomeCache.Stats().CachedRulesets)
+
+	choiceIDs(t, outcomeCache.ChoiceStore(), "rps")
+	require.Equal(t, 6, store.reads)
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+// cachedTranslations holds the translations into a language, or into every language for the empty one
+type cachedTranslations struct {
+	translations []rpslsapi.Translation
+	loadedAt     time.Time
+}
+
+// TranslationCache loads the translations into a language from a TranslationStore the first time they are needed and
+// answers from memory afterwards, so localized rounds and choices don't query the database. Writes go through to the
+// store and drop every cached language, like the deletions of choices and rulesets through an OutcomeMatrixCache
+// attached to it, since their translations are deleted along with them. Entries older than the TTL are reloaded, a
+// zero TTL keeping them until they are invalidated.
+type TranslationCache struct {
+	store rpslsapi.TranslationStore
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu        sync.RWMutex
+	languages map[string]*cachedTranslations
+}
+
+func NewTranslationCache(store rpslsapi.TranslationStore, ttl time.Duration) *TranslationCache {
+	return &TranslationCache{
+		store:     store,
+		ttl:       ttl,
+		now:       time.Now,
+		languages: make(map[string]*cachedTranslations),
+	}
+}
+
+func (c *TranslationCache) Translations(language string) ([]rpslsapi.Translation, error) {
+	cached, err := c.cached(language)
+	if err != nil {
+		return nil, err
+	}
+	if cached.translations == nil {
+		return nil, nil
+	}
+	return append([]rpslsapi.Translation{}, cached.translations...), nil
+}
+
+func (c *TranslationCache) SaveTranslation(translation *rpslsapi.Translation) error {
+	err := c.store.SaveTranslation(translation)
+	c.Invalidate()
+	return err
+}
+
+func (c *TranslationCache) DeleteTranslation(kind rpslsapi.TranslationKind, key, language string) error {
+	err := c.store.DeleteTranslation(kind, key, language)
+	c.Invalidate()
+	return err
+}
+
+// Invalidate drops the translations of every language
+func (c *TranslationCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.languages = make(map[string]*cachedTranslations)
+}
+
+// cached returns the cached translations into the language, loading them from the store if they are missing or
+// expired
+func (c *TranslationCache) cached(language string) (*cachedTranslations, error) {
+	c.mu.RLock()
+	cached, found := c.languages[language]
+	c.mu.RUnlock()
+	if found && !c.expired(cached) {
+		return cached, nil
+	}
+
+	// loading under the write lock guarantees that a write invalidating the cache after it has been committed never
+	// leaves stale translations behind
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, found = c.languages[language]
+	if found && !c.expired(cached) {
+		return cached, nil
+	}
+	translations, err := c.store.Translations(language)
+	if err != nil {
+		return nil, err
+	}
+	cached = &cachedTranslations{translations: translations, loadedAt: c.now()}
+	c.languages[language] = cached
+	return cached, nil
+}
+
+func (c *TranslationCache) expired(cached *cachedTranslations) bool {
+	return c.ttl > 0 && c.now().Sub(cached.loadedAt) >= c.ttl
+}
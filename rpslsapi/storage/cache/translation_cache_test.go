@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+	"rpsls/rpslsapi/storage/memory"
+)
+
+// countingTranslationStore counts the reads reaching the underlying store
+type countingTranslationStore struct {
+	rpslsapi.TranslationStore
+	reads int
+}
+
+func (ts *countingTranslationStore) Translations(language string) ([]rpslsapi.Translation, error) {
+	ts.reads++
+	return ts.TranslationStore.Translations(language)
+}
+
+func newTestTranslationCache(ttl time.Duration) (*TranslationCache, *countingTranslationStore, *memory.DB) {
+	db := memory.NewDB()
+	store := &countingTranslationStore{TranslationStore: memory.NewTranslationStore(db)}
+	return NewTranslationCache(store, ttl), store, db
+}
+
+func TestTranslationCache_ReadsAreAnsweredFromMemory(t *testing.T) {
+	translationCache, store, _ := newTestTranslationCache(0)
+	vaporizes := rpslsapi.Translation{Kind: rpslsapi.VerbTranslation, Key: "vaporizes", Language: "pt",
+		Text: "vaporiza"}
+	require.NoError(t, translationCache.SaveTranslation(&vaporizes))
+
+	for i := 0; i < 3; i++ {
+		translations, err := translationCache.Translations("pt")
+		require.NoError(t, err)
+		require.Equal(t, []rpslsapi.Translation{vaporizes}, translations)
+		translations, err = translationCache.Translations("es")
+		require.NoError(t, err)
+		require.Empty(t, translations)
+	}
+	require.Equal(t, 2, store.reads)
+}
+
+func TestTranslationCache_WritesInvalidate(t *testing.T) {
+	translationCache, store, db := newTestTranslationCache(0)
+	outcomeCache := NewOutcomeMatrixCache(memory.NewChoiceStore(db), 0)
+	outcomeCache.AttachTranslations(translationCache)
+	ids := choiceIDs(t, outcomeCache.ChoiceStore(), "rpsls")
+	rock := rpslsapi.Translation{Kind: rpslsapi.ChoiceTranslation, Key: ids["Rock"], Language: "pt", Text: "Pedra"}
+	require.NoError(t, translationCache.SaveTranslation(&rock))
+
+	translations, err := translationCache.Translations("pt")
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.Translation{rock}, translations)
+
+	require.NoError(t, outcomeCache.ChoiceStore().DeleteChoice(ids["Rock"]))
+	translations, err = translationCache.Translations("pt")
+	require.NoError(t, err)
+	require.Empty(t, translations)
+
+	paper := rpslsapi.Translation{Kind: rpslsapi.ChoiceTranslation, Key: ids["Paper"], Language: "pt", Text: "Papel"}
+	require.NoError(t, translationCache.SaveTranslation(&paper))
+	translations, err = translationCache.Translations("pt")
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.Translation{paper}, translations)
+
+	require.NoError(t, translationCache.DeleteTranslation(paper.Kind, paper.Key, paper.Language))
+	translations, err = translationCache.Translations("pt")
+	require.NoError(t, err)
+	require.Empty(t, translations)
+	require.Equal(t, 4, store.reads)
+}
+
+func TestTranslationCache_TTL(t *testing.T) {
+	translationCache, store, _ := newTestTranslationCache(time.Minute)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	translationCache.now = func() time.Time { return now }
+
+	_, err := translationCache.Translations("pt")
+	require.NoError(t, err)
+	now = now.Add(59 * time.Second)
+	_, err = translationCache.Translations("pt")
+	require.NoError(t, err)
+	require.Equal(t, 1, store.reads)
+
+	now = now.Add(time.Second)
+	_, err = translationCache.Translations("pt")
+	require.NoError(t, err)
+	require.Equal(t, 2, store.reads)
+}
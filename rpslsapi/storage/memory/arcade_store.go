@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+// ArcadeStore keeps arcade sessions as expiring JSON values and the high scores of every ruleset in memory
+type ArcadeStore struct {
+	jsonValues
+	mu         *sync.RWMutex
+	highScores map[string][]rpslsapi.HighScore
+}
+
+func NewArcadeStore() ArcadeStore {
+	return ArcadeStore{
+		jsonValues: newJSONValues(),
+		mu:         &sync.RWMutex{},
+		highScores: make(map[string][]rpslsapi.HighScore),
+	}
+}
+
+func (as ArcadeStore) CreateSession(session *rpslsapi.ArcadeSession, retention time.Duration) error {
+	return as.create(session.ID, session, retention)
+}
+
+func (as ArcadeStore) Session(id string) (*rpslsapi.ArcadeSession, error) {
+	var session rpslsapi.ArcadeSession
+	if err := as.get(id, &session, rpslsapi.ErrArcadeSessionNotFound); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (as ArcadeStore) UpdateSession(id string,
+	update func(session *rpslsapi.ArcadeSession) error) (*rpslsapi.ArcadeSession, error) {
+	var session rpslsapi.ArcadeSession
+	err := as.update(id, &session, rpslsapi.ErrArcadeSessionNotFound, func() error { return update(&session) })
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (as ArcadeStore) SubmitHighScore(score *rpslsapi.HighScore, size int) (int, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	highScores, rank := rpslsapi.InsertHighScore(as.highScores[score.Ruleset], score, size)
+	as.highScores[score.Ruleset] = highScores
+	return rank, nil
+}
+
+func (as ArcadeStore) HighScores(ruleset string, size int) ([]rpslsapi.HighScore, error) {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	stored := as.highScores[ruleset]
+	if len(stored) > size {
+		stored = stored[:size]
+	}
+	highScores := make([]rpslsapi.HighScore, len(stored))
+	copy(highScores, stored)
+	return highScores, nil
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestArcadeStore(t *testing.T) {
+	store := NewArcadeStore()
+	session := &rpslsapi.ArcadeSession{ID: "arcade", Player: "ada", Ruleset: "rpsls", Lives: 3, Multiplier: 1}
+	require.NoError(t, store.CreateSession(session, time.Hour))
+	require.Error(t, store.CreateSession(session, time.Hour))
+
+	updated, err := store.UpdateSession("arcade", func(session *rpslsapi.ArcadeSession) error {
+		session.Lives--
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, updated.Lives)
+	stored, err := store.Session("arcade")
+	require.NoError(t, err)
+	require.Equal(t, updated, stored)
+	_, err = store.Session("missing")
+	require.Equal(t, rpslsapi.ErrArcadeSessionNotFound, err)
+
+	for i, score := range []int{300, 500, 100} {
+		rank, err := store.SubmitHighScore(&rpslsapi.HighScore{Player: "ada", Score: score, Ruleset: "rpsls"}, 2)
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 1, 0}[i], rank)
+	}
+	highScores, err := store.HighScores("rpsls", 10)
+	require.NoError(t, err)
+	require.Len(t, highScores, 2)
+	require.Equal(t, 500, highScores[0].Score)
+	require.Equal(t, 300, highScores[1].Score)
+	highScores, err = store.HighScores("rps", 10)
+	require.NoError(t, err)
+	require.Empty(t, highScores)
+}
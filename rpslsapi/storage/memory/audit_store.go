@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+
+	"rpsls/rpslsapi"
+)
+
+// AuditStore keeps round audits as JSON, so they can't be altered through the values it is given or returns
+type AuditStore struct {
+	*DB
+}
+
+func NewAuditStore(db *DB) AuditStore {
+	return AuditStore{db}
+}
+
+func (as AuditStore) SaveAudit(audit *rpslsapi.RoundAudit) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if _, found := as.audits[audit.ID]; found {
+		return errors.New("round audit already exists: " + audit.ID)
+	}
+	record, err := json.Marshal(audit)
+	if err != nil {
+		return err
+	}
+	as.audits[audit.ID] = record
+	return nil
+}
+
+func (as AuditStore) Audit(id string) (*rpslsapi.RoundAudit, error) {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	record, found := as.audits[id]
+	if !found {
+		return nil, rpslsapi.ErrAuditNotFound
+	}
+	var audit rpslsapi.RoundAudit
+	if err := json.Unmarshal(record, &audit); err != nil {
+		return nil, err
+	}
+	return &audit, nil
+}
@@ -0,0 +1,29 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestAuditStore(t *testing.T) {
+	store := NewAuditStore(NewDB())
+	audit := &rpslsapi.RoundAudit{ID: "round", RequestID: "request", UserID: "user",
+		PlayedAt: time.Date(2021, 6, 1, 12, 0, 0, 123456789, time.UTC), Ruleset: "rps", Player: "rps-rock",
+		Computer: "rps-paper", Strategy: "random", RandomValues: []int{7},
+		Choices: []rpslsapi.Choice{{ID: "rps-rock", Name: "Rock", Ruleset: "rps"}},
+		Rule:    &rpslsapi.Rule{WinnerID: "rps-paper", LoserID: "rps-rock", Action: "covers"}, Results: "lose"}
+
+	_, err := store.Audit("round")
+	require.Equal(t, rpslsapi.ErrAuditNotFound, err)
+
+	require.NoError(t, store.SaveAudit(audit))
+	stored, err := store.Audit("round")
+	require.NoError(t, err)
+	require.Equal(t, audit, stored)
+
+	// audits are never overwritten
+	require.Error(t, store.SaveAudit(&rpslsapi.RoundAudit{ID: "round", Results: "win"}))
+}
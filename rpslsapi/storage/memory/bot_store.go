@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"rpsls/rpslsapi"
+)
+
+// BotStore keeps the registered bots in memory
+type BotStore struct {
+	mu   *sync.RWMutex
+	bots map[string]rpslsapi.Bot
+}
+
+func NewBotStore() BotStore {
+	return BotStore{mu: &sync.RWMutex{}, bots: make(map[string]rpslsapi.Bot)}
+}
+
+func (bs BotStore) CreateBot(bot *rpslsapi.Bot) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if _, found := bs.bots[bot.Name]; found {
+		return rpslsapi.ErrBotAlreadyExists
+	}
+	bs.bots[bot.Name] = *bot
+	return nil
+}
+
+func (bs BotStore) Bots() ([]rpslsapi.Bot, error) {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	bots := make([]rpslsapi.Bot, 0, len(bs.bots))
+	for _, bot := range bs.bots {
+		bots = append(bots, bot)
+	}
+	sort.Slice(bots, func(i, j int) bool { return bots[i].Name < bots[j].Name })
+	return bots, nil
+}
+
+func (bs BotStore) Bot(name string) (*rpslsapi.Bot, error) {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	bot, found := bs.bots[name]
+	if !found {
+		return nil, rpslsapi.ErrBotNotFound
+	}
+	return &bot, nil
+}
+
+func (bs BotStore) DeleteBot(name string) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if _, found := bs.bots[name]; !found {
+		return rpslsapi.ErrBotNotFound
+	}
+	delete(bs.bots, name)
+	return nil
+}
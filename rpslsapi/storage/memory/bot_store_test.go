@@ -0,0 +1,26 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestBotStore(t *testing.T) {
+	store := NewBotStore()
+	zeta := &rpslsapi.Bot{Name: "zeta", URL: "http://localhost:3100"}
+	alpha := &rpslsapi.Bot{Name: "alpha", URL: "http://localhost:3101"}
+	require.NoError(t, store.CreateBot(zeta))
+	require.NoError(t, store.CreateBot(alpha))
+	require.Equal(t, rpslsapi.ErrBotAlreadyExists, store.CreateBot(zeta))
+
+	bots, err := store.Bots()
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.Bot{*alpha, *zeta}, bots)
+
+	require.NoError(t, store.DeleteBot("zeta"))
+	_, err = store.Bot("zeta")
+	require.Equal(t, rpslsapi.ErrBotNotFound, err)
+	require.Equal(t, rpslsapi.ErrBotNotFound, store.DeleteBot("zeta"))
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"sort"
+
+	"rpsls/rpslsapi"
+)
+
+type ChoiceStore struct {
+	*DB
+}
+
+func NewChoiceStore(db *DB) ChoiceStore {
+	return ChoiceStore{db}
+}
+
+func (cs ChoiceStore) Choices(ruleset string) ([]rpslsapi.Choice, error) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	if _, found := cs.rulesets[ruleset]; !found {
+		return nil, rpslsapi.ErrRulesetNotFound
+	}
+
+	var choices []rpslsapi.Choice
+	for _, choice := range cs.choices {
+		if choice.Ruleset == ruleset {
+			choices = append(choices, choice)
+		}
+	}
+	sort.Slice(choices, func(i, j int) bool { return cs.created[choices[i].ID] < cs.created[choices[j].ID] })
+	return choices, nil
+}
+
+func (cs ChoiceStore) Choice(id string) (*rpslsapi.Choice, error) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	choice, found := cs.choices[id]
+	if !found {
+		return nil, rpslsapi.ErrChoiceNotFound
+	}
+	return &choice, nil
+}
+
+func (cs ChoiceStore) CreateChoice(definition *rpslsapi.ChoiceDefinition) (*rpslsapi.Choice, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if _, found := cs.rulesets[definition.Ruleset]; !found {
+		return nil, rpslsapi.ErrRulesetNotFound
+	}
+	if _, found := cs.choices[definition.ID]; found || cs.nameTaken(definition.Ruleset, "", definition.Name) {
+		return nil, rpslsapi.ErrChoiceAlreadyExists
+	}
+	for _, relationships := range [][]rpslsapi.Relationship{definition.Beats, definition.BeatenBy} {
+		for _, relationship := range relationships {
+			if related, found := cs.choices[relationship.ChoiceID]; !found || related.Ruleset != definition.Ruleset {
+				return nil, rpslsapi.ErrChoiceNotFound
+			}
+		}
+	}
+
+	choice := cs.createChoice(definition.ID, definition.Name, definition.Ruleset)
+	for _, relationship := range definition.Beats {
+		cs.saveRule(rpslsapi.Rule{WinnerID: choice.ID, LoserID: relationship.ChoiceID, Action: relationship.Action})
+	}
+	for _, relationship := range definition.BeatenBy {
+		cs.saveRule(rpslsapi.Rule{WinnerID: relationship.ChoiceID, LoserID: choice.ID, Action: relationship.Action})
+	}
+	return &choice, nil
+}
+
+func (cs ChoiceStore) UpdateChoice(choice *rpslsapi.Choice) (*rpslsapi.Choice, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	existing, found := cs.choices[choice.ID]
+	if !found {
+		return nil, rpslsapi.ErrChoiceNotFound
+	}
+	if cs.nameTaken(existing.Ruleset, choice.ID, choice.Name) {
+		return nil, rpslsapi.ErrChoiceAlreadyExists
+	}
+
+	existing.Name = choice.Name
+	cs.choices[choice.ID] = existing
+	return &existing, nil
+}
+
+func (cs ChoiceStore) DeleteChoice(id string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if _, found := cs.choices[id]; !found {
+		return rpslsapi.ErrChoiceNotFound
+	}
+
+	cs.deleteChoice(id)
+	cs.deleteRules(func(rule rpslsapi.Rule) bool {
+		return rule.WinnerID == id || rule.LoserID == id
+	})
+	return nil
+}
+
+func (cs ChoiceStore) Rules(ruleset string) ([]rpslsapi.Rule, error) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	var rules []rpslsapi.Rule
+	for _, rule := range cs.rules {
+		if cs.choices[rule.WinnerID].Ruleset == ruleset {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (cs ChoiceStore) SaveRule(rule *rpslsapi.Rule) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	winner, winnerFound := cs.choices[rule.WinnerID]
+	loser, loserFound := cs.choices[rule.LoserID]
+	if !winnerFound || !loserFound || winner.Ruleset != loser.Ruleset {
+		return rpslsapi.ErrChoiceNotFound
+	}
+
+	cs.saveRule(*rule)
+	return nil
+}
+
+func (cs ChoiceStore) DeleteRule(winnerID, loserID string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	deleted := cs.deleteRules(func(rule rpslsapi.Rule) bool {
+		return rule.WinnerID == winnerID && rule.LoserID == loserID
+	})
+	if deleted == 0 {
+		return rpslsapi.ErrRuleNotFound
+	}
+	return nil
+}
+
+// nameTaken reports whether a choice of the ruleset other than the one with the given id uses the name
+func (cs ChoiceStore) nameTaken(ruleset, id, name string) bool {
+	for _, choice := range cs.choices {
+		if choice.Ruleset == ruleset && choice.Name == name && choice.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+func (cs ChoiceStore) ImportRuleset(document *rpslsapi.RulesetDocument, replace bool) (*rpslsapi.Ruleset, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if _, found := cs.rulesets[document.ID]; found && !replace {
+		return nil, rpslsapi.ErrRulesetAlreadyExists
+	}
+	ids := make(map[string]string, len(document.Choices))
+	for _, name := range document.Choices {
+		ids[name] = rpslsapi.NewChoiceID(document.ID, name)
+		if existing, found := cs.choices[ids[name]]; found && existing.Ruleset != document.ID {
+			return nil, rpslsapi.ErrChoiceAlreadyExists
+		}
+	}
+
+	cs.deleteRuleset(document.ID)
+	ruleset := rpslsapi.Ruleset{ID: document.ID, Name: document.Name}
+	cs.rulesets[ruleset.ID] = ruleset
+	for _, name := range document.Choices {
+		cs.createChoice(ids[name], name, ruleset.ID)
+	}
+	for _, entry := range document.Beats {
+		cs.saveRule(rpslsapi.Rule{WinnerID: ids[entry.Winner], LoserID: ids[entry.Loser], Action: entry.Verb})
+	}
+	return &ruleset, nil
+}
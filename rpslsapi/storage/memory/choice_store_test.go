@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestChoiceStore_Choices(t *testing.T) {
+	store := NewChoiceStore(NewDB())
+
+	rpslsChoices, err := store.Choices("rpsls")
+	require.NoError(t, err)
+	require.Len(t, rpslsChoices, 5)
+	require.Equal(t, rpslsapi.Choice{ID: "rpsls-rock", Name: "Rock", Ruleset: "rpsls"}, rpslsChoices[0])
+
+	rpsChoices, err := store.Choices("rps")
+	require.NoError(t, err)
+	require.Len(t, rpsChoices, 3)
+
+	_, err = store.Choices("missing")
+	require.Equal(t, rpslsapi.ErrRulesetNotFound, err)
+}
+
+func TestChoiceStore_SeededRulesAreValid(t *testing.T) {
+	store := NewChoiceStore(NewDB())
+
+	for _, seed := range seedRulesets {
+		choices, err := store.Choices(seed.ruleset.ID)
+		require.NoError(t, err)
+		rules, err := store.Rules(seed.ruleset.ID)
+		require.NoError(t, err)
+
+		report := rpslsapi.ValidateRuleGraph(choices, rules)
+		require.True(t, report.Valid, seed.ruleset.ID)
+		require.Empty(t, report.Issues, seed.ruleset.ID)
+	}
+}
+
+func TestChoiceStore_CreateChoice(t *testing.T) {
+	store := NewChoiceStore(NewDB())
+	choices, _ := store.Choices("rpsls")
+	ids := make(map[string]string, len(choices))
+	for _, choice := range choices {
+		ids[choice.Name] = choice.ID
+	}
+
+	kitten, err := store.CreateChoice(&rpslsapi.ChoiceDefinition{
+		ID:      "rpsls-kitten",
+		Name:    "Kitten",
+		Ruleset: "rpsls",
+		Beats: []rpslsapi.Relationship{
+			{ChoiceID: ids["Paper"], Action: "scratches"},
+			{ChoiceID: ids["Lizard"], Action: "eats"},
+			{ChoiceID: ids["Spock"], Action: "mesmerizes"},
+		},
+		BeatenBy: []rpslsapi.Relationship{
+			{ChoiceID: ids["Rock"], Action: "crushes"},
+			{ChoiceID: ids["Scissors"], Action: "cuts"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &rpslsapi.Choice{ID: "rpsls-kitten", Name: "Kitten", Ruleset: "rpsls"}, kitten)
+
+	rules, _ := store.Rules("rpsls")
+	require.Len(t, rules, 15)
+
+	_, err = store.CreateChoice(&rpslsapi.ChoiceDefinition{ID: "rpsls-cat", Name: "Kitten", Ruleset: "rpsls"})
+	require.Equal(t, rpslsapi.ErrChoiceAlreadyExists, err)
+	_, err = store.CreateChoice(&rpslsapi.ChoiceDefinition{ID: "rpsls-kitten", Name: "Cat", Ruleset: "rpsls"})
+	require.Equal(t, rpslsapi.ErrChoiceAlreadyExists, err)
+
+	rpsChoices, _ := store.Choices("rps")
+	_, err = store.CreateChoice(&rpslsapi.ChoiceDefinition{
+		ID:      "rpsls-well",
+		Name:    "Well",
+		Ruleset: "rpsls",
+		Beats:   []rpslsapi.Relationship{{ChoiceID: rpsChoices[0].ID, Action: "swallows"}},
+	})
+	require.Equal(t, rpslsapi.ErrChoiceNotFound, err)
+}
+
+func TestChoiceStore_DeleteChoice(t *testing.T) {
+	store := NewChoiceStore(NewDB())
+	choices, _ := store.Choices("rps")
+
+	require.NoError(t, store.DeleteChoice(choices[0].ID))
+	rules, _ := store.Rules("rps")
+	require.Len(t, rules, 1)
+
+	require.Equal(t, rpslsapi.ErrChoiceNotFound, store.DeleteChoice(choices[0].ID))
+}
+
+func TestChoiceStore_SaveRule(t *testing.T) {
+	store := NewChoiceStore(NewDB())
+	choices, _ := store.Choices("rps")
+	rock, paper := choices[0], choices[1]
+
+	require.NoError(t, store.SaveRule(&rpslsapi.Rule{WinnerID: rock.ID, LoserID: paper.ID, Action: "breaks"}))
+	rules, _ := store.Rules("rps")
+	require.Len(t, rules, 3)
+	require.Contains(t, rules, rpslsapi.Rule{WinnerID: rock.ID, LoserID: paper.ID, Action: "breaks"})
+
+	rpslsChoices, _ := store.Choices("rpsls")
+	err := store.SaveRule(&rpslsapi.Rule{WinnerID: rock.ID, LoserID: rpslsChoices[0].ID, Action: "crushes"})
+	require.Equal(t, rpslsapi.ErrChoiceNotFound, err)
+}
+
+func TestChoiceStore_ImportRuleset(t *testing.T) {
+	store := NewChoiceStore(NewDB())
+	document := &rpslsapi.RulesetDocument{
+		ID:      "rps",
+		Name:    "Duel",
+		Choices: []string{"Sword", "Shield"},
+		Beats:   []rpslsapi.BeatsEntry{{Winner: "Shield", Loser: "Sword", Verb: "blocks"}},
+	}
+
+	_, err := store.ImportRuleset(document, false)
+	require.Equal(t, rpslsapi.ErrRulesetAlreadyExists, err)
+
+	ruleset, err := store.ImportRuleset(document, true)
+	require.NoError(t, err)
+	require.Equal(t, &rpslsapi.Ruleset{ID: "rps", Name: "Duel"}, ruleset)
+
+	choices, _ := store.Choices("rps")
+	require.Equal(t, []rpslsapi.Choice{
+		{ID: "rps-sword", Name: "Sword", Ruleset: "rps"},
+		{ID: "rps-shield", Name: "Shield", Ruleset: "rps"},
+	}, choices)
+	rules, _ := store.Rules("rps")
+	require.Equal(t, []rpslsapi.Rule{{WinnerID: "rps-shield", LoserID: "rps-sword", Action: "blocks"}}, rules)
+
+	// choice IDs owned by another ruleset are not taken over
+	_, err = store.CreateChoice(&rpslsapi.ChoiceDefinition{ID: "rps-big-rock", Name: "Big Rock", Ruleset: "rps"})
+	require.NoError(t, err)
+	_, err = store.ImportRuleset(&rpslsapi.RulesetDocument{ID: "rps-big", Name: "Big", Choices: []string{"Rock"}}, false)
+	require.Equal(t, rpslsapi.ErrChoiceAlreadyExists, err)
+	_, err = NewRulesetStore(store.DB).Ruleset("rps-big")
+	require.Equal(t, rpslsapi.ErrRulesetNotFound, err)
+}
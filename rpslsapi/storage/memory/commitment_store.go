@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+type CommitmentStore struct {
+	jsonValues
+}
+
+func NewCommitmentStore() CommitmentStore {
+	return CommitmentStore{newJSONValues()}
+}
+
+func (cs CommitmentStore) SaveCommitment(round *rpslsapi.CommittedRound, ttl time.Duration) error {
+	return cs.create(round.Token, round, ttl)
+}
+
+func (cs CommitmentStore) TakeCommitment(token string) (*rpslsapi.CommittedRound, error) {
+	var round rpslsapi.CommittedRound
+	if err := cs.take(token, &round, rpslsapi.ErrCommitmentNotFound); err != nil {
+		return nil, err
+	}
+	return &round, nil
+}
@@ -0,0 +1,22 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestCommitmentStore(t *testing.T) {
+	store := NewCommitmentStore()
+	round := &rpslsapi.CommittedRound{RoundCommitment: rpslsapi.RoundCommitment{Token: "token"}, Salt: "salt"}
+	require.NoError(t, store.SaveCommitment(round, time.Minute))
+
+	taken, err := store.TakeCommitment("token")
+	require.NoError(t, err)
+	require.Equal(t, round, taken)
+
+	_, err = store.TakeCommitment("token")
+	require.Equal(t, rpslsapi.ErrCommitmentNotFound, err)
+}
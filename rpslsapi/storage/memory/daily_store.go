@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+// DailyStore keeps the attempts and leaderboards of daily challenges in memory, for good
+type DailyStore struct {
+	mu           *sync.RWMutex
+	attempts     map[string]rpslsapi.DailyAttempt
+	leaderboards map[string][]rpslsapi.DailyScore
+}
+
+func NewDailyStore() DailyStore {
+	return DailyStore{
+		mu:           &sync.RWMutex{},
+		attempts:     make(map[string]rpslsapi.DailyAttempt),
+		leaderboards: make(map[string][]rpslsapi.DailyScore),
+	}
+}
+
+func (ds DailyStore) SaveAttempt(attempt *rpslsapi.DailyAttempt, size int, _ time.Duration) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	key := attempt.Date + ":" + attempt.UserID
+	if _, played := ds.attempts[key]; played {
+		return rpslsapi.ErrAlreadyPlayedDaily
+	}
+	ds.leaderboards[attempt.Date] = rpslsapi.InsertDailyScore(ds.leaderboards[attempt.Date], attempt, size)
+	ds.attempts[key] = *attempt
+	return nil
+}
+
+func (ds DailyStore) Attempt(date, userID string) (*rpslsapi.DailyAttempt, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	attempt, played := ds.attempts[date+":"+userID]
+	if !played {
+		return nil, rpslsapi.ErrDailyAttemptNotFound
+	}
+	return &attempt, nil
+}
+
+func (ds DailyStore) Leaderboard(date string, size int) ([]rpslsapi.DailyScore, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	stored := ds.leaderboards[date]
+	if len(stored) > size {
+		stored = stored[:size]
+	}
+	leaderboard := make([]rpslsapi.DailyScore, len(stored))
+	copy(leaderboard, stored)
+	return leaderboard, nil
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestDailyStore(t *testing.T) {
+	store := NewDailyStore()
+	first := &rpslsapi.DailyAttempt{Date: "2026-10-18", UserID: "first", Player: "ada", Score: 8}
+	second := &rpslsapi.DailyAttempt{Date: "2026-10-18", UserID: "second", Player: "grace", Score: 14}
+	require.NoError(t, store.SaveAttempt(first, 10, time.Hour))
+	require.NoError(t, store.SaveAttempt(second, 10, time.Hour))
+	require.Equal(t, 1, first.Rank)
+	require.Equal(t, 1, second.Rank)
+	require.Equal(t, rpslsapi.ErrAlreadyPlayedDaily, store.SaveAttempt(first, 10, time.Hour))
+
+	attempt, err := store.Attempt("2026-10-18", "first")
+	require.NoError(t, err)
+	require.Equal(t, first, attempt)
+	_, err = store.Attempt("2026-10-17", "first")
+	require.Equal(t, rpslsapi.ErrDailyAttemptNotFound, err)
+
+	leaderboard, err := store.Leaderboard("2026-10-18", 10)
+	require.NoError(t, err)
+	require.Len(t, leaderboard, 2)
+	require.Equal(t, "grace", leaderboard[0].Player)
+	require.Equal(t, "ada", leaderboard[1].Player)
+	leaderboard, err = store.Leaderboard("2026-10-17", 10)
+	require.NoError(t, err)
+	require.Empty(t, leaderboard)
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"sync"
+
+	"rpsls/rpslsapi"
+)
+
+// DB holds rulesets, choices, rules, translations, the round history, audits and users in memory. It is safe for concurrent
+// use and starts seeded with the same rulesets as the database migrations.
+type DB struct {
+	mu       sync.RWMutex
+	rulesets map[string]rpslsapi.Ruleset
+	choices  map[string]rpslsapi.Choice
+	// created numbers choices in creation order, in which they are listed
+	created      map[string]int64
+	nextCreated  int64
+	rules        []rpslsapi.Rule
+	translations map[translationKey]rpslsapi.Translation
+	history      []rpslsapi.HistoryRound
+	audits       map[string][]byte
+	// users are keyed by username
+	users map[string]rpslsapi.User
+}
+
+// translationKey identifies a translation: there is at most one per kind, key and language
+type translationKey struct {
+	kind     rpslsapi.TranslationKind
+	key      string
+	language string
+}
+
+func NewDB() *DB {
+	db := &DB{
+		rulesets:     make(map[string]rpslsapi.Ruleset),
+		choices:      make(map[string]rpslsapi.Choice),
+		created:      make(map[string]int64),
+		translations: make(map[translationKey]rpslsapi.Translation),
+		audits:       make(map[string][]byte),
+		users:        make(map[string]rpslsapi.User),
+	}
+	db.seed()
+	return db
+}
+
+func (db *DB) createChoice(id, name, ruleset string) rpslsapi.Choice {
+	choice := rpslsapi.Choice{ID: id, Name: name, Ruleset: ruleset}
+	db.choices[choice.ID] = choice
+	db.created[choice.ID] = db.nextCreated
+	db.nextCreated++
+	return choice
+}
+
+// deleteChoice deletes the choice along with the translations of its name, but not its rules
+func (db *DB) deleteChoice(id string) {
+	delete(db.choices, id)
+	delete(db.created, id)
+	db.deleteChoiceTranslations(id)
+}
+
+// saveRule replaces any rule between both choices with the given one
+func (db *DB) saveRule(rule rpslsapi.Rule) {
+	db.deleteRules(func(existing rpslsapi.Rule) bool {
+		return (existing.WinnerID == rule.WinnerID && existing.LoserID == rule.LoserID) ||
+			(existing.WinnerID == rule.LoserID && existing.LoserID == rule.WinnerID)
+	})
+	db.rules = append(db.rules, rule)
+}
+
+// deleteRules deletes the rules matching the predicate, returning how many were deleted
+func (db *DB) deleteRules(matches func(rule rpslsapi.Rule) bool) int {
+	var kept []rpslsapi.Rule
+	for _, rule := range db.rules {
+		if !matches(rule) {
+			kept = append(kept, rule)
+		}
+	}
+	deleted := len(db.rules) - len(kept)
+	db.rules = kept
+	return deleted
+}
+
+// deleteChoiceTranslations deletes the translations of the name of the choice
+func (db *DB) deleteChoiceTranslations(choiceID string) {
+	for index := range db.translations {
+		if index.kind == rpslsapi.ChoiceTranslation && index.key == choiceID {
+			delete(db.translations, index)
+		}
+	}
+}
+
+// deleteRuleset deletes the ruleset along with its choices, their rules and translations
+func (db *DB) deleteRuleset(id string) {
+	delete(db.rulesets, id)
+	for choiceID, choice := range db.choices {
+		if choice.Ruleset == id {
+			db.deleteChoice(choiceID)
+		}
+	}
+	db.deleteRules(func(rule rpslsapi.Rule) bool {
+		_, winnerFound := db.choices[rule.WinnerID]
+		return !winnerFound
+	})
+}
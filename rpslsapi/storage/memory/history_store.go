@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"sort"
+
+	"rpsls/rpslsapi"
+)
+
+type HistoryStore struct {
+	*DB
+}
+
+func NewHistoryStore(db *DB) HistoryStore {
+	return HistoryStore{db}
+}
+
+func (hs HistoryStore) SaveRound(round *rpslsapi.HistoryRound) error {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	hs.history = append(hs.history, *round)
+	return nil
+}
+
+func (hs HistoryStore) Rounds(query *rpslsapi.HistoryQuery) ([]rpslsapi.HistoryRound, error) {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	var rounds []rpslsapi.HistoryRound
+	for _, round := range hs.history {
+		if round.UserID != query.UserID || (query.Ruleset != "" && round.Ruleset != query.Ruleset) ||
+			(!query.From.IsZero() && round.PlayedAt.Before(query.From)) ||
+			(!query.To.IsZero() && !round.PlayedAt.Before(query.To)) ||
+			(query.Before != nil && !playedBefore(&round, query.Before)) {
+			continue
+		}
+		rounds = append(rounds, round)
+	}
+	sort.Slice(rounds, func(i, j int) bool {
+		return playedBefore(&rounds[j], &rounds[i])
+	})
+	if len(rounds) > query.Limit {
+		rounds = rounds[:query.Limit]
+	}
+	return rounds, nil
+}
+
+// playedBefore tells whether the round comes before the other one, rounds played at the same time being sorted by ID
+func playedBefore(round, other *rpslsapi.HistoryRound) bool {
+	if !round.PlayedAt.Equal(other.PlayedAt) {
+		return round.PlayedAt.Before(other.PlayedAt)
+	}
+	return round.ID < other.ID
+}
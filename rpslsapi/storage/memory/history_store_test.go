@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestHistoryStore(t *testing.T) {
+	store := NewHistoryStore(NewDB())
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	first := rpslsapi.HistoryRound{ID: "b", UserID: "user", PlayedAt: start, Ruleset: "rpsls", Player: "rpsls-rock",
+		Computer: "rpsls-spock", Results: "lose", Action: "vaporizes"}
+	second := rpslsapi.HistoryRound{ID: "a", UserID: "user", PlayedAt: start.Add(time.Hour), Ruleset: "rps",
+		Player: "rps-rock", Computer: "rps-rock", Results: "tie"}
+	// played at the same time as the second round, but sorted before it by ID
+	third := rpslsapi.HistoryRound{ID: "c", UserID: "user", PlayedAt: start.Add(time.Hour), Ruleset: "rpsls",
+		Player: "rpsls-paper", Computer: "rpsls-rock", Results: "win", Action: "covers", Series: "series"}
+	other := rpslsapi.HistoryRound{ID: "d", UserID: "other", PlayedAt: start, Ruleset: "rpsls",
+		Player: "rpsls-rock", Computer: "rpsls-rock", Results: "tie"}
+	for _, round := range []rpslsapi.HistoryRound{first, second, third, other} {
+		round := round
+		require.NoError(t, store.SaveRound(&round))
+	}
+
+	testCases := []struct {
+		name     string
+		query    rpslsapi.HistoryQuery
+		expected []rpslsapi.HistoryRound
+	}{
+		{
+			name:     "every round of the user, most recent first",
+			query:    rpslsapi.HistoryQuery{UserID: "user", Limit: 10},
+			expected: []rpslsapi.HistoryRound{third, second, first},
+		},
+		{
+			name:     "up to the limit",
+			query:    rpslsapi.HistoryQuery{UserID: "user", Limit: 2},
+			expected: []rpslsapi.HistoryRound{third, second},
+		},
+		{
+			name:     "after the last round of the previous page",
+			query:    rpslsapi.HistoryQuery{UserID: "user", Before: &third, Limit: 10},
+			expected: []rpslsapi.HistoryRound{second, first},
+		},
+		{
+			name:     "of the ruleset",
+			query:    rpslsapi.HistoryQuery{UserID: "user", Ruleset: "rpsls", Limit: 10},
+			expected: []rpslsapi.HistoryRound{third, first},
+		},
+		{
+			name:     "from a time, inclusive",
+			query:    rpslsapi.HistoryQuery{UserID: "user", From: start.Add(time.Hour), Limit: 10},
+			expected: []rpslsapi.HistoryRound{third, second},
+		},
+		{
+			name:     "to a time, exclusive",
+			query:    rpslsapi.HistoryQuery{UserID: "user", To: start.Add(time.Hour), Limit: 10},
+			expected: []rpslsapi.HistoryRound{first},
+		},
+		{
+			name:  "none for an unknown user",
+			query: rpslsapi.HistoryQuery{UserID: "unknown", Limit: 10},
+		},
+	}
+
+	for _, tc := range testCases {
+		rounds, err := store.Rounds(&tc.query)
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expected, rounds, tc.name)
+	}
+}
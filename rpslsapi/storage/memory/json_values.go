@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// jsonValues keeps values in memory as JSON, mimicking the expiring keys of the Redis implementation
+type jsonValues struct {
+	mu     *sync.Mutex
+	values map[string]storedValue
+	now    func() time.Time
+}
+
+// storedValue never expires if expires is zero
+type storedValue struct {
+	value   []byte
+	expires time.Time
+}
+
+func newJSONValues() jsonValues {
+	return jsonValues{mu: &sync.Mutex{}, values: make(map[string]storedValue), now: time.Now}
+}
+
+// create stores value under key, which expires once the retention has passed, unless the key is in use. Like Redis,
+// a zero retention keeps the key until it is deleted.
+func (jv jsonValues) create(key string, value interface{}, retention time.Duration) error {
+	jv.mu.Lock()
+	defer jv.mu.Unlock()
+
+	jv.dropExpired()
+	if _, found := jv.values[key]; found {
+		return errors.New("key already in use: " + key)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var expires time.Time
+	if retention > 0 {
+		expires = jv.now().Add(retention)
+	}
+	jv.values[key] = storedValue{value: encoded, expires: expires}
+	return nil
+}
+
+// get decodes the value under key into value, returning notFound if the key doesn't exist
+func (jv jsonValues) get(key string, value interface{}, notFound error) error {
+	jv.mu.Lock()
+	defer jv.mu.Unlock()
+
+	return jv.decode(key, value, notFound)
+}
+
+// update decodes the value under key into value, applies update and saves the result, keeping the expiry of the key.
+// Updates of the same values don't interleave, and errors returned by update are returned as is, leaving the value
+// untouched.
+func (jv jsonValues) update(key string, value interface{}, notFound error, update func() error) error {
+	jv.mu.Lock()
+	defer jv.mu.Unlock()
+
+	if err := jv.decode(key, value, notFound); err != nil {
+		return err
+	}
+	if err := update(); err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	jv.values[key] = storedValue{value: encoded, expires: jv.values[key].expires}
+	return nil
+}
+
+// take decodes the value under key into value and deletes the key, returning notFound if the key doesn't exist
+func (jv jsonValues) take(key string, value interface{}, notFound error) error {
+	jv.mu.Lock()
+	defer jv.mu.Unlock()
+
+	if err := jv.decode(key, value, notFound); err != nil {
+		return err
+	}
+	delete(jv.values, key)
+	return nil
+}
+
+func (jv jsonValues) decode(key string, value interface{}, notFound error) error {
+	stored, found := jv.values[key]
+	if !found || jv.expired(stored) {
+		return notFound
+	}
+	return json.Unmarshal(stored.value, value)
+}
+
+func (jv jsonValues) dropExpired() {
+	for key, stored := range jv.values {
+		if jv.expired(stored) {
+			delete(jv.values, key)
+		}
+	}
+}
+
+func (jv jsonValues) expired(stored storedValue) bool {
+	return !stored.expires.IsZero() && jv.now().After(stored.expires)
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+type MatchStore struct {
+	jsonValues
+}
+
+func NewMatchStore() MatchStore {
+	return MatchStore{newJSONValues()}
+}
+
+func (ms MatchStore) CreateMatch(match *rpslsapi.Match, retention time.Duration) error {
+	return ms.create(match.ID, match, retention)
+}
+
+func (ms MatchStore) Match(id string) (*rpslsapi.Match, error) {
+	var match rpslsapi.Match
+	if err := ms.get(id, &match, rpslsapi.ErrMatchNotFound); err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+func (ms MatchStore) UpdateMatch(id string, update func(match *rpslsapi.Match) error) (*rpslsapi.Match, error) {
+	var match rpslsapi.Match
+	if err := ms.update(id, &match, rpslsapi.ErrMatchNotFound, func() error { return update(&match) }); err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
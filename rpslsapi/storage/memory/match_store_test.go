@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestMatchStore(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMatchStore()
+	store.now = func() time.Time { return now }
+
+	match := &rpslsapi.Match{ID: "match", Ruleset: "rpsls", Status: rpslsapi.MatchWaiting}
+	require.NoError(t, store.CreateMatch(match, time.Hour))
+	require.Error(t, store.CreateMatch(match, time.Hour))
+
+	stored, err := store.Match("match")
+	require.NoError(t, err)
+	require.Equal(t, match, stored)
+
+	updated, err := store.UpdateMatch("match", func(match *rpslsapi.Match) error {
+		match.Status = rpslsapi.MatchPlaying
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, rpslsapi.MatchPlaying, updated.Status)
+
+	updateError := errors.New("update error")
+	_, err = store.UpdateMatch("match", func(match *rpslsapi.Match) error {
+		match.Status = rpslsapi.MatchFinished
+		return updateError
+	})
+	require.Equal(t, updateError, err)
+	stored, err = store.Match("match")
+	require.NoError(t, err)
+	require.Equal(t, rpslsapi.MatchPlaying, stored.Status)
+
+	_, err = store.Match("missing")
+	require.Equal(t, rpslsapi.ErrMatchNotFound, err)
+
+	now = now.Add(2 * time.Hour)
+	_, err = store.Match("match")
+	require.Equal(t, rpslsapi.ErrMatchNotFound, err)
+	_, err = store.UpdateMatch("match", func(*rpslsapi.Match) error { return nil })
+	require.Equal(t, rpslsapi.ErrMatchNotFound, err)
+}
+
+func TestMatchStore_ZeroRetention(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMatchStore()
+	store.now = func() time.Time { return now }
+
+	match := &rpslsapi.Match{ID: "match", Ruleset: "rpsls", Status: rpslsapi.MatchWaiting}
+	require.NoError(t, store.CreateMatch(match, 0))
+
+	now = now.Add(24 * time.Hour)
+	stored, err := store.Match("match")
+	require.NoError(t, err)
+	require.Equal(t, match, stored)
+}
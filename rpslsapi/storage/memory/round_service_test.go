@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+	"rpsls/rpslsapi/storage/cache"
+)
+
+type fixedRandomizer int
+
+func (fr fixedRandomizer) RandomInt() (int, error) {
+	return int(fr), nil
+}
+
+func TestRoundService_PlayWithMemoryStores(t *testing.T) {
+	rpslsapi.Config.DefaultRuleset = "rpsls"
+	rpslsapi.Config.ScoreboardSize = 10
+	db := NewDB()
+	// rounds are only decided by the outcome matrix cache
+	outcomeCache := cache.NewOutcomeMatrixCache(NewChoiceStore(db), 0)
+	choiceStore, roundStore := outcomeCache.ChoiceStore(), outcomeCache.RoundStore()
+	choices, _ := choiceStore.Choices("rps")
+	rock := choices[0]
+
+	// the computer picks the second choice of the ruleset: Paper
+	choiceService := rpslsapi.NewChoiceService(choiceStore, fixedRandomizer(4), rpslsapi.NewRuleGraphLock())
+	scoreboardService := rpslsapi.NewScoreboardService(NewScoreboardStore())
+	strategyService := rpslsapi.NewStrategyService(NewStrategyStore(), NewBotStore(), nil, choiceService,
+		scoreboardService, fixedRandomizer(4))
+	auditService := rpslsapi.NewAuditService(NewAuditStore(db), roundStore)
+	roundService := rpslsapi.NewRoundService(roundStore, choiceService, scoreboardService,
+		NewCommitmentStore(), strategyService, rpslsapi.NewHistoryService(NewHistoryStore(db)), auditService)
+
+	results, err := roundService.Play(&rpslsapi.RoundSettings{Player: rock.ID, Ruleset: "rps", UserID: "user"})
+	require.NoError(t, err)
+	require.Equal(t, string(rpslsapi.Lose), results.Results)
+	require.Equal(t, "Paper covers Rock", results.Description)
+
+	scoreboard, err := scoreboardService.Scoreboard("user", "rps")
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.RoundResults{*results}, scoreboard)
+
+	replay, err := auditService.Replay(results.ID)
+	require.NoError(t, err)
+	require.True(t, replay.Confirmed)
+	require.Equal(t, results.Computer, replay.Computer)
+
+	_, err = roundService.Play(&rpslsapi.RoundSettings{Player: rock.ID})
+	require.Equal(t, rpslsapi.ErrChoiceNotFound, err)
+}
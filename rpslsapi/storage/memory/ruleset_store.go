@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"sort"
+
+	"rpsls/rpslsapi"
+)
+
+type RulesetStore struct {
+	*DB
+}
+
+func NewRulesetStore(db *DB) RulesetStore {
+	return RulesetStore{db}
+}
+
+func (rs RulesetStore) Rulesets() ([]rpslsapi.Ruleset, error) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	var rulesets []rpslsapi.Ruleset
+	for _, ruleset := range rs.rulesets {
+		rulesets = append(rulesets, ruleset)
+	}
+	sort.Slice(rulesets, func(i, j int) bool { return rulesets[i].ID < rulesets[j].ID })
+	return rulesets, nil
+}
+
+func (rs RulesetStore) Ruleset(id string) (*rpslsapi.Ruleset, error) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	ruleset, found := rs.rulesets[id]
+	if !found {
+		return nil, rpslsapi.ErrRulesetNotFound
+	}
+	return &ruleset, nil
+}
+
+func (rs RulesetStore) CreateRuleset(ruleset *rpslsapi.Ruleset) (*rpslsapi.Ruleset, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if _, found := rs.rulesets[ruleset.ID]; found {
+		return nil, rpslsapi.ErrRulesetAlreadyExists
+	}
+	rs.rulesets[ruleset.ID] = *ruleset
+	return ruleset, nil
+}
+
+func (rs RulesetStore) DeleteRuleset(id string) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if _, found := rs.rulesets[id]; !found {
+		return rpslsapi.ErrRulesetNotFound
+	}
+
+	rs.deleteRuleset(id)
+	return nil
+}
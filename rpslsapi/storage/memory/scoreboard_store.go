@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"sync"
+
+	"rpsls/rpslsapi"
+)
+
+// ScoreboardStore keeps scoreboards in memory, mimicking the capped lists of the Redis implementation
+type ScoreboardStore struct {
+	mu     *sync.RWMutex
+	boards map[string][]rpslsapi.RoundResults
+}
+
+func NewScoreboardStore() ScoreboardStore {
+	return ScoreboardStore{mu: &sync.RWMutex{}, boards: make(map[string][]rpslsapi.RoundResults)}
+}
+
+func (ss ScoreboardStore) Scoreboard(key string, size int64) ([]rpslsapi.RoundResults, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	board := ss.boards[key]
+	if int64(len(board)) > size {
+		board = board[:size]
+	}
+	scoreboard := make([]rpslsapi.RoundResults, len(board))
+	copy(scoreboard, board)
+	return scoreboard, nil
+}
+
+func (ss ScoreboardStore) Append(key string, size int64, results *rpslsapi.RoundResults) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	board := append([]rpslsapi.RoundResults{*results}, ss.boards[key]...)
+	if int64(len(board)) > size {
+		board = board[:size]
+	}
+	ss.boards[key] = board
+	return nil
+}
+
+func (ss ScoreboardStore) Clear(key string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	delete(ss.boards, key)
+	return nil
+}
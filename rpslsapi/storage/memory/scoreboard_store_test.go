@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestScoreboardStore(t *testing.T) {
+	store := NewScoreboardStore()
+
+	for _, player := range []string{"rpsls-rock", "rpsls-paper", "rpsls-scissors"} {
+		require.NoError(t, store.Append("key", 2, &rpslsapi.RoundResults{Player: player}))
+	}
+
+	scoreboard, err := store.Scoreboard("key", 2)
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.RoundResults{{Player: "rpsls-scissors"}, {Player: "rpsls-paper"}}, scoreboard)
+
+	require.NoError(t, store.Clear("key"))
+	scoreboard, err = store.Scoreboard("key", 2)
+	require.NoError(t, err)
+	require.Empty(t, scoreboard)
+}
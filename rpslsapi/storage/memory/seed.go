@@ -0,0 +1,56 @@
+package memory
+
+import "rpsls/rpslsapi"
+
+type seedRule struct {
+	winner, loser, action string
+}
+
+type seedRuleset struct {
+	ruleset rpslsapi.Ruleset
+	choices []string
+	rules   []seedRule
+}
+
+// seedRulesets mirror the rulesets created by the database migrations in db/migrations
+var seedRulesets = []seedRuleset{
+	{
+		ruleset: rpslsapi.Ruleset{ID: "rpsls", Name: "Rock Paper Scissors Lizard Spock"},
+		choices: []string{"Rock", "Paper", "Scissors", "Lizard", "Spock"},
+		rules: []seedRule{
+			{"Rock", "Scissors", "crushes"},
+			{"Rock", "Lizard", "crushes"},
+			{"Paper", "Rock", "covers"},
+			{"Paper", "Spock", "disproves"},
+			{"Scissors", "Paper", "cuts"},
+			{"Scissors", "Lizard", "decapitates"},
+			{"Lizard", "Paper", "eats"},
+			{"Lizard", "Spock", "poisons"},
+			{"Spock", "Scissors", "smashes"},
+			{"Spock", "Rock", "vaporizes"},
+		},
+	},
+	{
+		ruleset: rpslsapi.Ruleset{ID: "rps", Name: "Rock Paper Scissors"},
+		choices: []string{"Rock", "Paper", "Scissors"},
+		rules: []seedRule{
+			{"Rock", "Scissors", "crushes"},
+			{"Paper", "Rock", "covers"},
+			{"Scissors", "Paper", "cuts"},
+		},
+	},
+}
+
+func (db *DB) seed() {
+	for _, seed := range seedRulesets {
+		db.rulesets[seed.ruleset.ID] = seed.ruleset
+
+		ids := make(map[string]string, len(seed.choices))
+		for _, name := range seed.choices {
+			ids[name] = db.createChoice(rpslsapi.NewChoiceID(seed.ruleset.ID, name), name, seed.ruleset.ID).ID
+		}
+		for _, rule := range seed.rules {
+			db.saveRule(rpslsapi.Rule{WinnerID: ids[rule.winner], LoserID: ids[rule.loser], Action: rule.action})
+		}
+	}
+}
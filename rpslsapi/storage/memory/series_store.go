@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+type SeriesStore struct {
+	jsonValues
+}
+
+func NewSeriesStore() SeriesStore {
+	return SeriesStore{newJSONValues()}
+}
+
+func (ss SeriesStore) CreateSeries(series *rpslsapi.Series, retention time.Duration) error {
+	return ss.create(series.ID, series, retention)
+}
+
+func (ss SeriesStore) Series(id string) (*rpslsapi.Series, error) {
+	var series rpslsapi.Series
+	if err := ss.get(id, &series, rpslsapi.ErrSeriesNotFound); err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (ss SeriesStore) UpdateSeries(id string, update func(series *rpslsapi.Series) error) (*rpslsapi.Series, error) {
+	var series rpslsapi.Series
+	if err := ss.update(id, &series, rpslsapi.ErrSeriesNotFound, func() error { return update(&series) }); err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"sync"
+)
+
+// StrategyStore keeps the default strategy of each user in memory
+type StrategyStore struct {
+	mu         *sync.RWMutex
+	strategies map[string]string
+}
+
+func NewStrategyStore() StrategyStore {
+	return StrategyStore{mu: &sync.RWMutex{}, strategies: make(map[string]string)}
+}
+
+func (ss StrategyStore) DefaultStrategy(userID string) (string, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	return ss.strategies[userID], nil
+}
+
+func (ss StrategyStore) SetDefaultStrategy(userID, name string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.strategies[userID] = name
+	return nil
+}
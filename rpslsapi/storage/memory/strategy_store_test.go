@@ -0,0 +1,19 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrategyStore(t *testing.T) {
+	store := NewStrategyStore()
+	name, err := store.DefaultStrategy("user")
+	require.NoError(t, err)
+	require.Empty(t, name)
+
+	require.NoError(t, store.SetDefaultStrategy("user", "markov"))
+	name, err = store.DefaultStrategy("user")
+	require.NoError(t, err)
+	require.Equal(t, "markov", name)
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+type TournamentStore struct {
+	jsonValues
+}
+
+func NewTournamentStore() TournamentStore {
+	return TournamentStore{newJSONValues()}
+}
+
+func (ts TournamentStore) CreateTournament(tournament *rpslsapi.Tournament, retention time.Duration) error {
+	return ts.create(tournament.ID, tournament, retention)
+}
+
+func (ts TournamentStore) Tournament(id string) (*rpslsapi.Tournament, error) {
+	var tournament rpslsapi.Tournament
+	if err := ts.get(id, &tournament, rpslsapi.ErrTournamentNotFound); err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+func (ts TournamentStore) UpdateTournament(id string, update func(tournament *rpslsapi.Tournament) error) (
+	*rpslsapi.Tournament, error) {
+	var tournament rpslsapi.Tournament
+	err := ts.update(id, &tournament, rpslsapi.ErrTournamentNotFound, func() error { return update(&tournament) })
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
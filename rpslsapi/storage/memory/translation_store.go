@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"sort"
+
+	"rpsls/rpslsapi"
+)
+
+type TranslationStore struct {
+	*DB
+}
+
+func NewTranslationStore(db *DB) TranslationStore {
+	return TranslationStore{db}
+}
+
+func (ts TranslationStore) Translations(language string) ([]rpslsapi.Translation, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	var translations []rpslsapi.Translation
+	for _, translation := range ts.translations {
+		if language == "" || translation.Language == language {
+			translations = append(translations, translation)
+		}
+	}
+	sort.Slice(translations, func(i, j int) bool {
+		a, b := translations[i], translations[j]
+		if a.Language != b.Language {
+			return a.Language < b.Language
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Key < b.Key
+	})
+	return translations, nil
+}
+
+func (ts TranslationStore) SaveTranslation(translation *rpslsapi.Translation) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.translations[translationKey{translation.Kind, translation.Key, translation.Language}] = *translation
+	return nil
+}
+
+func (ts TranslationStore) DeleteTranslation(kind rpslsapi.TranslationKind, key, language string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	index := translationKey{kind, key, language}
+	if _, found := ts.translations[index]; !found {
+		return rpslsapi.ErrTranslationNotFound
+	}
+	delete(ts.translations, index)
+	return nil
+}
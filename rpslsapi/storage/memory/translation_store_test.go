@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestTranslationStore(t *testing.T) {
+	db := NewDB()
+	store := NewTranslationStore(db)
+
+	rock := rpslsapi.Translation{Kind: rpslsapi.ChoiceTranslation, Key: "rpsls-rock", Language: "pt", Text: "Pedra"}
+	crushes := rpslsapi.Translation{Kind: rpslsapi.VerbTranslation, Key: "crushes", Language: "es", Text: "aplasta"}
+	require.NoError(t, store.SaveTranslation(&rock))
+	require.NoError(t, store.SaveTranslation(&crushes))
+	rock.Text = "Rocha"
+	require.NoError(t, store.SaveTranslation(&rock))
+
+	translations, err := store.Translations("pt")
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.Translation{rock}, translations)
+	translations, err = store.Translations("")
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.Translation{crushes, rock}, translations)
+
+	require.NoError(t, store.DeleteTranslation(rpslsapi.VerbTranslation, "crushes", "es"))
+	require.Equal(t, rpslsapi.ErrTranslationNotFound,
+		store.DeleteTranslation(rpslsapi.VerbTranslation, "crushes", "es"))
+
+	// translations of a choice go away with it
+	require.NoError(t, NewChoiceStore(db).DeleteChoice("rpsls-rock"))
+	translations, err = store.Translations("")
+	require.NoError(t, err)
+	require.Empty(t, translations)
+}
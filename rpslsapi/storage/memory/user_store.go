@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"rpsls/rpslsapi"
+)
+
+type UserStore struct {
+	*DB
+}
+
+func NewUserStore(db *DB) UserStore {
+	return UserStore{db}
+}
+
+func (us UserStore) CreateUser(user *rpslsapi.User) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	if _, found := us.users[user.Username]; found {
+		return rpslsapi.ErrUsernameTaken
+	}
+	us.users[user.Username] = *user
+	return nil
+}
+
+func (us UserStore) UserByUsername(username string) (*rpslsapi.User, error) {
+	us.mu.RLock()
+	defer us.mu.RUnlock()
+
+	user, found := us.users[username]
+	if !found {
+		return nil, rpslsapi.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (us UserStore) SetRole(username string, role rpslsapi.Role) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	user, found := us.users[username]
+	if !found {
+		return rpslsapi.ErrUserNotFound
+	}
+	user.Role = role
+	us.users[username] = user
+	return nil
+}
@@ -0,0 +1,67 @@
+package neo4j
+
+import (
+	"encoding/json"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"rpsls/rpslsapi"
+)
+
+const saveAuditQuery = "CREATE (:RoundAudit { id: $id, request_id: $requestID, user_id: $userID, " +
+	"played_at: $playedAt, record: $record })"
+const auditQuery = "MATCH (a:RoundAudit { id: $id }) RETURN a.record as record"
+
+// AuditStore keeps each round audit as a RoundAudit node holding its JSON record
+type AuditStore struct {
+	DbClient
+}
+
+func NewAuditStore(dbClient DbClient) AuditStore {
+	return AuditStore{dbClient}
+}
+
+func (as AuditStore) SaveAudit(audit *rpslsapi.RoundAudit) error {
+	record, err := json.Marshal(audit)
+	if err != nil {
+		return err
+	}
+	session := as.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: as.databaseName})
+	defer CloseDBResource(session)
+
+	_, err = session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		return transaction.Run(saveAuditQuery, map[string]interface{}{
+			"id":        audit.ID,
+			"requestID": audit.RequestID,
+			"userID":    audit.UserID,
+			"playedAt":  audit.PlayedAt.UnixNano(),
+			"record":    string(record),
+		})
+	})
+	return err
+}
+
+func (as AuditStore) Audit(id string) (*rpslsapi.RoundAudit, error) {
+	session := as.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: as.databaseName})
+	defer CloseDBResource(session)
+
+	record, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		records, err := transaction.Run(auditQuery, map[string]interface{}{"id": id})
+		if err != nil {
+			return nil, err
+		}
+		if !records.Next() {
+			return nil, rpslsapi.ErrAuditNotFound
+		}
+		record, _ := records.Record().Get("record")
+		return record, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var audit rpslsapi.RoundAudit
+	if err := json.Unmarshal([]byte(record.(string)), &audit); err != nil {
+		return nil, err
+	}
+	return &audit, nil
+}
@@ -5,8 +5,31 @@ import (
 	"rpsls/rpslsapi"
 )
 
-const allChoicesQuery = "MATCH (c:Choice) RETURN id(c) as id, c.name as name"
-const choiceByIdQuery = "MATCH (c:Choice) WHERE id(c) = $id RETURN id(c) as id, c.name as name"
+const allChoicesQuery = "MATCH (r:Ruleset) WHERE r.id = $ruleset " +
+	"OPTIONAL MATCH (c:Choice)-[:PART_OF]->(r) RETURN c.id as id, c.name as name"
+const choiceByIdQuery = "MATCH (c:Choice)-[:PART_OF]->(r:Ruleset) WHERE c.id = $id " +
+	"RETURN c.id as id, c.name as name, r.id as ruleset"
+const choiceByNameQuery = "MATCH (c:Choice)-[:PART_OF]->(r:Ruleset) " +
+	"WHERE r.id = $ruleset AND c.name = $name AND c.id <> $id RETURN c.id as id"
+const createChoiceQuery = "MATCH (r:Ruleset) WHERE r.id = $ruleset " +
+	"CREATE (c:Choice { id: $id, name: $name })-[:PART_OF]->(r) RETURN c.id as id"
+const updateChoiceQuery = "MATCH (c:Choice) WHERE c.id = $id SET c.name = $name RETURN c.id as id"
+const deleteChoiceQuery = "MATCH (c:Choice) WHERE c.id = $id " +
+	"OPTIONAL MATCH (t:Translation { kind: 'choice', key: c.id }) " +
+	"DETACH DELETE c, t RETURN count(*) as deleted"
+const allRulesQuery = "MATCH (r:Ruleset)<-[:PART_OF]-(winner:Choice)-[BEATS:BEATS]->(loser:Choice) " +
+	"WHERE r.id = $ruleset " +
+	"RETURN winner.id as winnerChoiceID, loser.id as loserChoiceID, BEATS.with as action"
+const saveRuleQuery = "MATCH (winner:Choice)-[:PART_OF]->(:Ruleset)<-[:PART_OF]-(loser:Choice) " +
+	"WHERE winner.id = $winnerID AND loser.id = $loserID " +
+	"OPTIONAL MATCH (winner)-[existing:BEATS]-(loser) " +
+	"WITH winner, loser, collect(existing) as existingRules " +
+	"FOREACH (rule IN existingRules | DELETE rule) " +
+	"CREATE (winner)-[:BEATS {with: $action}]->(loser) " +
+	"RETURN winner.id as winnerChoiceID"
+const deleteRuleQuery = "MATCH (winner:Choice)-[BEATS:BEATS]->(loser:Choice) " +
+	"WHERE winner.id = $winnerID AND loser.id = $loserID " +
+	"DELETE BEATS RETURN count(*) as deleted"
 
 type ChoiceStore struct {
 	DbClient
@@ -16,22 +39,31 @@ func NewChoiceStore(dbClient DbClient) ChoiceStore {
 	return ChoiceStore{dbClient}
 }
 
-func (cs ChoiceStore) Choices() ([]rpslsapi.Choice, error) {
+func (cs ChoiceStore) Choices(ruleset string) ([]rpslsapi.Choice, error) {
 	session := cs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: cs.databaseName})
 	defer CloseDBResource(session)
 
 	choices, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
-		records, err := transaction.Run(allChoicesQuery, nil)
+		records, err := transaction.Run(allChoicesQuery, map[string]interface{}{"ruleset": ruleset})
 		if err != nil {
 			return nil, err
 		}
 		var result []rpslsapi.Choice
+		rulesetFound := false
 
 		for records.Next() {
+			rulesetFound = true
 			record := records.Record()
 			id, _ := record.Get("id")
+			if id == nil {
+				// the ruleset has no choices
+				continue
+			}
 			name, _ := record.Get("name")
-			result = append(result, rpslsapi.Choice{ID: id.(int64), Name: name.(string)})
+			result = append(result, rpslsapi.Choice{ID: id.(string), Name: name.(string), Ruleset: ruleset})
+		}
+		if !rulesetFound {
+			return nil, rpslsapi.ErrRulesetNotFound
 		}
 		return result, nil
 	})
@@ -42,27 +74,271 @@ func (cs ChoiceStore) Choices() ([]rpslsapi.Choice, error) {
 	return choices.([]rpslsapi.Choice), nil
 }
 
-func (cs ChoiceStore) Choice(id int64) (*rpslsapi.Choice, error) {
+func (cs ChoiceStore) Choice(id string) (*rpslsapi.Choice, error) {
 	session := cs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: cs.databaseName})
 	defer CloseDBResource(session)
 
 	choices, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
-		result, err := transaction.Run(choiceByIdQuery,
-			map[string]interface{}{"id": id})
+		return choiceByID(transaction, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return choices.(*rpslsapi.Choice), nil
+}
+
+func (cs ChoiceStore) CreateChoice(definition *rpslsapi.ChoiceDefinition) (*rpslsapi.Choice, error) {
+	session := cs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: cs.databaseName})
+	defer CloseDBResource(session)
+
+	choice, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		if err := checkIDAvailable(transaction, definition.ID); err != nil {
+			return nil, err
+		}
+		if err := checkNameAvailable(transaction, definition.Ruleset, "", definition.Name); err != nil {
+			return nil, err
+		}
+
+		result, err := transaction.Run(createChoiceQuery,
+			map[string]interface{}{"id": definition.ID, "name": definition.Name, "ruleset": definition.Ruleset})
 		if err != nil {
 			return nil, err
 		}
+		if !result.Next() {
+			return nil, rpslsapi.ErrRulesetNotFound
+		}
+		choice := &rpslsapi.Choice{ID: definition.ID, Name: definition.Name, Ruleset: definition.Ruleset}
 
-		if result.Next() {
-			record := result.Record()
-			name, _ := record.Get("name")
-			return &rpslsapi.Choice{ID: id, Name: name.(string)}, nil
+		for _, relationship := range definition.Beats {
+			rule := rpslsapi.Rule{WinnerID: choice.ID, LoserID: relationship.ChoiceID, Action: relationship.Action}
+			if err := saveRule(transaction, &rule); err != nil {
+				return nil, err
+			}
+		}
+		for _, relationship := range definition.BeatenBy {
+			rule := rpslsapi.Rule{WinnerID: relationship.ChoiceID, LoserID: choice.ID, Action: relationship.Action}
+			if err := saveRule(transaction, &rule); err != nil {
+				return nil, err
+			}
 		}
-		return nil, rpslsapi.ErrChoiceNotFound
+		return choice, nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return choices.(*rpslsapi.Choice), nil
+	return choice.(*rpslsapi.Choice), nil
+}
+
+func (cs ChoiceStore) UpdateChoice(choice *rpslsapi.Choice) (*rpslsapi.Choice, error) {
+	session := cs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: cs.databaseName})
+	defer CloseDBResource(session)
+
+	updated, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		existing, err := choiceByID(transaction, choice.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkNameAvailable(transaction, existing.Ruleset, choice.ID, choice.Name); err != nil {
+			return nil, err
+		}
+
+		_, err = transaction.Run(updateChoiceQuery,
+			map[string]interface{}{"id": choice.ID, "name": choice.Name})
+		if err != nil {
+			return nil, err
+		}
+		return &rpslsapi.Choice{ID: choice.ID, Name: choice.Name, Ruleset: existing.Ruleset}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.(*rpslsapi.Choice), nil
+}
+
+func (cs ChoiceStore) DeleteChoice(id string) error {
+	session := cs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: cs.databaseName})
+	defer CloseDBResource(session)
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		return nil, runDelete(transaction, deleteChoiceQuery, map[string]interface{}{"id": id},
+			rpslsapi.ErrChoiceNotFound)
+	})
+	return err
+}
+
+func (cs ChoiceStore) Rules(ruleset string) ([]rpslsapi.Rule, error) {
+	session := cs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: cs.databaseName})
+	defer CloseDBResource(session)
+
+	rules, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		records, err := transaction.Run(allRulesQuery, map[string]interface{}{"ruleset": ruleset})
+		if err != nil {
+			return nil, err
+		}
+		var result []rpslsapi.Rule
+
+		for records.Next() {
+			record := records.Record()
+			winnerChoiceID, _ := record.Get("winnerChoiceID")
+			loserChoiceID, _ := record.Get("loserChoiceID")
+			action, _ := record.Get("action")
+			result = append(result, rpslsapi.Rule{
+				WinnerID: winnerChoiceID.(string),
+				LoserID:  loserChoiceID.(string),
+				Action:   action.(string),
+			})
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rules.([]rpslsapi.Rule), nil
+}
+
+func (cs ChoiceStore) SaveRule(rule *rpslsapi.Rule) error {
+	session := cs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: cs.databaseName})
+	defer CloseDBResource(session)
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		return nil, saveRule(transaction, rule)
+	})
+	return err
+}
+
+func (cs ChoiceStore) DeleteRule(winnerID, loserID string) error {
+	session := cs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: cs.databaseName})
+	defer CloseDBResource(session)
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		return nil, runDelete(transaction, deleteRuleQuery,
+			map[string]interface{}{"winnerID": winnerID, "loserID": loserID}, rpslsapi.ErrRuleNotFound)
+	})
+	return err
+}
+
+func choiceByID(transaction neo4j.Transaction, id string) (*rpslsapi.Choice, error) {
+	result, err := transaction.Run(choiceByIdQuery, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Next() {
+		record := result.Record()
+		name, _ := record.Get("name")
+		ruleset, _ := record.Get("ruleset")
+		return &rpslsapi.Choice{ID: id, Name: name.(string), Ruleset: ruleset.(string)}, nil
+	}
+	return nil, rpslsapi.ErrChoiceNotFound
+}
+
+// checkNameAvailable fails with ErrChoiceAlreadyExists if a choice of the ruleset other than the one with the given id
+// uses the name
+func checkNameAvailable(transaction neo4j.Transaction, ruleset, id, name string) error {
+	result, err := transaction.Run(choiceByNameQuery,
+		map[string]interface{}{"ruleset": ruleset, "id": id, "name": name})
+	if err != nil {
+		return err
+	}
+	if result.Next() {
+		return rpslsapi.ErrChoiceAlreadyExists
+	}
+	return nil
+}
+
+// checkIDAvailable fails with ErrChoiceAlreadyExists if a choice already uses the id
+func checkIDAvailable(transaction neo4j.Transaction, id string) error {
+	if _, err := choiceByID(transaction, id); err == nil {
+		return rpslsapi.ErrChoiceAlreadyExists
+	} else if err != rpslsapi.ErrChoiceNotFound {
+		return err
+	}
+	return nil
+}
+
+func saveRule(transaction neo4j.Transaction, rule *rpslsapi.Rule) error {
+	result, err := transaction.Run(saveRuleQuery, map[string]interface{}{
+		"winnerID": rule.WinnerID,
+		"loserID":  rule.LoserID,
+		"action":   rule.Action,
+	})
+	if err != nil {
+		return err
+	}
+	if !result.Next() {
+		return rpslsapi.ErrChoiceNotFound
+	}
+	return nil
+}
+
+// runDelete runs a query returning the number of deleted entities, failing with notFoundErr if nothing was deleted
+func runDelete(transaction neo4j.Transaction, query string, params map[string]interface{}, notFoundErr error) error {
+	result, err := transaction.Run(query, params)
+	if err != nil {
+		return err
+	}
+	record, err := result.Single()
+	if err != nil {
+		return err
+	}
+	deleted, _ := record.Get("deleted")
+	if deleted.(int64) == 0 {
+		return notFoundErr
+	}
+	return nil
+}
+
+func (cs ChoiceStore) ImportRuleset(document *rpslsapi.RulesetDocument, replace bool) (*rpslsapi.Ruleset, error) {
+	session := cs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: cs.databaseName})
+	defer CloseDBResource(session)
+
+	ruleset, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		if _, err := rulesetByID(transaction, document.ID); err == nil {
+			if !replace {
+				return nil, rpslsapi.ErrRulesetAlreadyExists
+			}
+			if _, err := transaction.Run(deleteRulesetQuery, map[string]interface{}{"id": document.ID}); err != nil {
+				return nil, err
+			}
+		} else if err != rpslsapi.ErrRulesetNotFound {
+			return nil, err
+		}
+
+		_, err := transaction.Run(createRulesetQuery, map[string]interface{}{"id": document.ID, "name": document.Name})
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make(map[string]string, len(document.Choices))
+		for _, name := range document.Choices {
+			ids[name] = rpslsapi.NewChoiceID(document.ID, name)
+			if err := checkIDAvailable(transaction, ids[name]); err != nil {
+				return nil, err
+			}
+			result, err := transaction.Run(createChoiceQuery,
+				map[string]interface{}{"id": ids[name], "name": name, "ruleset": document.ID})
+			if err != nil {
+				return nil, err
+			}
+			if _, err := result.Single(); err != nil {
+				return nil, err
+			}
+		}
+		for _, entry := range document.Beats {
+			rule := rpslsapi.Rule{WinnerID: ids[entry.Winner], LoserID: ids[entry.Loser], Action: entry.Verb}
+			if err := saveRule(transaction, &rule); err != nil {
+				return nil, err
+			}
+		}
+		return &rpslsapi.Ruleset{ID: document.ID, Name: document.Name}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ruleset.(*rpslsapi.Ruleset), nil
 }
@@ -0,0 +1,98 @@
+package neo4j
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"rpsls/rpslsapi"
+)
+
+const saveHistoryRoundQuery = "CREATE (:HistoryRound { id: $id, user_id: $userID, played_at: $playedAt, " +
+	"ruleset: $ruleset, player: $player, computer: $computer, results: $results, action: $action, series: $series })"
+const historyRoundsQuery = "MATCH (r:HistoryRound) WHERE r.user_id = $userID " +
+	"AND ($ruleset = '' OR r.ruleset = $ruleset) AND r.played_at >= $from AND r.played_at < $to " +
+	"AND (r.played_at < $before OR (r.played_at = $before AND r.id < $beforeID)) " +
+	"RETURN r.id as id, r.user_id as userID, r.played_at as playedAt, r.ruleset as ruleset, r.player as player, " +
+	"r.computer as computer, r.results as results, r.action as action, r.series as series " +
+	"ORDER BY r.played_at DESC, r.id DESC LIMIT $limit"
+
+// HistoryStore keeps rounds as HistoryRound nodes, with their time in nanoseconds since the epoch
+type HistoryStore struct {
+	DbClient
+}
+
+func NewHistoryStore(dbClient DbClient) HistoryStore {
+	return HistoryStore{dbClient}
+}
+
+func (hs HistoryStore) SaveRound(round *rpslsapi.HistoryRound) error {
+	session := hs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: hs.databaseName})
+	defer CloseDBResource(session)
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		return transaction.Run(saveHistoryRoundQuery, map[string]interface{}{
+			"id":       round.ID,
+			"userID":   round.UserID,
+			"playedAt": round.PlayedAt.UnixNano(),
+			"ruleset":  round.Ruleset,
+			"player":   round.Player,
+			"computer": round.Computer,
+			"results":  round.Results,
+			"action":   round.Action,
+			"series":   round.Series,
+		})
+	})
+	return err
+}
+
+func (hs HistoryStore) Rounds(query *rpslsapi.HistoryQuery) ([]rpslsapi.HistoryRound, error) {
+	session := hs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: hs.databaseName})
+	defer CloseDBResource(session)
+
+	from, to, before, beforeID := query.UnixNanoBounds()
+	rounds, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		records, err := transaction.Run(historyRoundsQuery, map[string]interface{}{
+			"userID":   query.UserID,
+			"ruleset":  query.Ruleset,
+			"from":     from,
+			"to":       to,
+			"before":   before,
+			"beforeID": beforeID,
+			"limit":    query.Limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		var result []rpslsapi.HistoryRound
+
+		for records.Next() {
+			record := records.Record()
+			id, _ := record.Get("id")
+			userID, _ := record.Get("userID")
+			playedAt, _ := record.Get("playedAt")
+			ruleset, _ := record.Get("ruleset")
+			player, _ := record.Get("player")
+			computer, _ := record.Get("computer")
+			results, _ := record.Get("results")
+			action, _ := record.Get("action")
+			series, _ := record.Get("series")
+			result = append(result, rpslsapi.HistoryRound{
+				ID:       id.(string),
+				UserID:   userID.(string),
+				PlayedAt: time.Unix(0, playedAt.(int64)).UTC(),
+				Ruleset:  ruleset.(string),
+				Player:   player.(string),
+				Computer: computer.(string),
+				Results:  results.(string),
+				Action:   action.(string),
+				Series:   series.(string),
+			})
+		}
+		return result, records.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rounds.([]rpslsapi.HistoryRound), nil
+}
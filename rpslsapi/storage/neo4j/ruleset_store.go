@@ -0,0 +1,107 @@
+package neo4j
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"rpsls/rpslsapi"
+)
+
+const allRulesetsQuery = "MATCH (r:Ruleset) RETURN r.id as id, r.name as name ORDER BY r.id"
+const rulesetByIdQuery = "MATCH (r:Ruleset) WHERE r.id = $id RETURN r.id as id, r.name as name"
+const createRulesetQuery = "CREATE (r:Ruleset { id: $id, name: $name })"
+const deleteRulesetQuery = "MATCH (r:Ruleset) WHERE r.id = $id " +
+	"OPTIONAL MATCH (c:Choice)-[:PART_OF]->(r) " +
+	"OPTIONAL MATCH (t:Translation { kind: 'choice', key: c.id }) " +
+	"DETACH DELETE c, r, t RETURN count(*) as deleted"
+
+type RulesetStore struct {
+	DbClient
+}
+
+func NewRulesetStore(dbClient DbClient) RulesetStore {
+	return RulesetStore{dbClient}
+}
+
+func (rs RulesetStore) Rulesets() ([]rpslsapi.Ruleset, error) {
+	session := rs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: rs.databaseName})
+	defer CloseDBResource(session)
+
+	rulesets, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		records, err := transaction.Run(allRulesetsQuery, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result []rpslsapi.Ruleset
+
+		for records.Next() {
+			record := records.Record()
+			id, _ := record.Get("id")
+			name, _ := record.Get("name")
+			result = append(result, rpslsapi.Ruleset{ID: id.(string), Name: name.(string)})
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rulesets.([]rpslsapi.Ruleset), nil
+}
+
+func (rs RulesetStore) Ruleset(id string) (*rpslsapi.Ruleset, error) {
+	session := rs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: rs.databaseName})
+	defer CloseDBResource(session)
+
+	ruleset, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		return rulesetByID(transaction, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ruleset.(*rpslsapi.Ruleset), nil
+}
+
+func (rs RulesetStore) CreateRuleset(ruleset *rpslsapi.Ruleset) (*rpslsapi.Ruleset, error) {
+	session := rs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: rs.databaseName})
+	defer CloseDBResource(session)
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		if _, err := rulesetByID(transaction, ruleset.ID); err == nil {
+			return nil, rpslsapi.ErrRulesetAlreadyExists
+		} else if err != rpslsapi.ErrRulesetNotFound {
+			return nil, err
+		}
+
+		_, err := transaction.Run(createRulesetQuery, map[string]interface{}{"id": ruleset.ID, "name": ruleset.Name})
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ruleset, nil
+}
+
+func (rs RulesetStore) DeleteRuleset(id string) error {
+	session := rs.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: rs.databaseName})
+	defer CloseDBResource(session)
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		return nil, runDelete(transaction, deleteRulesetQuery, map[string]interface{}{"id": id},
+			rpslsapi.ErrRulesetNotFound)
+	})
+	return err
+}
+
+func rulesetByID(transaction neo4j.Transaction, id string) (*rpslsapi.Ruleset, error) {
+	result, err := transaction.Run(rulesetByIdQuery, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Next() {
+		name, _ := result.Record().Get("name")
+		return &rpslsapi.Ruleset{ID: id, Name: name.(string)}, nil
+	}
+	return nil, rpslsapi.ErrRulesetNotFound
+}
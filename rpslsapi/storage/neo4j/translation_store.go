@@ -0,0 +1,82 @@
+package neo4j
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"rpsls/rpslsapi"
+)
+
+const translationsQuery = "MATCH (t:Translation) WHERE $language = '' OR t.language = $language " +
+	"RETURN t.kind as kind, t.key as key, t.language as language, t.text as text " +
+	"ORDER BY t.language, t.kind, t.key"
+const saveTranslationQuery = "MERGE (t:Translation { kind: $kind, key: $key, language: $language }) " +
+	"SET t.text = $text"
+const deleteTranslationQuery = "MATCH (t:Translation { kind: $kind, key: $key, language: $language }) " +
+	"DELETE t RETURN count(*) as deleted"
+
+type TranslationStore struct {
+	DbClient
+}
+
+func NewTranslationStore(dbClient DbClient) TranslationStore {
+	return TranslationStore{dbClient}
+}
+
+func (ts TranslationStore) Translations(language string) ([]rpslsapi.Translation, error) {
+	session := ts.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: ts.databaseName})
+	defer CloseDBResource(session)
+
+	translations, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		records, err := transaction.Run(translationsQuery, map[string]interface{}{"language": language})
+		if err != nil {
+			return nil, err
+		}
+		var result []rpslsapi.Translation
+
+		for records.Next() {
+			record := records.Record()
+			kind, _ := record.Get("kind")
+			key, _ := record.Get("key")
+			translationLanguage, _ := record.Get("language")
+			text, _ := record.Get("text")
+			result = append(result, rpslsapi.Translation{
+				Kind:     rpslsapi.TranslationKind(kind.(string)),
+				Key:      key.(string),
+				Language: translationLanguage.(string),
+				Text:     text.(string),
+			})
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return translations.([]rpslsapi.Translation), nil
+}
+
+func (ts TranslationStore) SaveTranslation(translation *rpslsapi.Translation) error {
+	session := ts.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: ts.databaseName})
+	defer CloseDBResource(session)
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		return transaction.Run(saveTranslationQuery, map[string]interface{}{
+			"kind":     string(translation.Kind),
+			"key":      translation.Key,
+			"language": translation.Language,
+			"text":     translation.Text,
+		})
+	})
+	return err
+}
+
+func (ts TranslationStore) DeleteTranslation(kind rpslsapi.TranslationKind, key, language string) error {
+	session := ts.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: ts.databaseName})
+	defer CloseDBResource(session)
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		return nil, runDelete(transaction, deleteTranslationQuery,
+			map[string]interface{}{"kind": string(kind), "key": key, "language": language},
+			rpslsapi.ErrTranslationNotFound)
+	})
+	return err
+}
@@ -0,0 +1,95 @@
+package neo4j
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"rpsls/rpslsapi"
+)
+
+const createUserQuery = "CREATE (:User { id: $id, username: $username, password_hash: $passwordHash, role: $role, " +
+	"created_at: $createdAt })"
+const userByUsernameQuery = "MATCH (u:User { username: $username }) RETURN u.id as id, u.username as username, " +
+	"u.password_hash as passwordHash, coalesce(u.role, 'player') as role, u.created_at as createdAt"
+const setRoleQuery = "MATCH (u:User { username: $username }) SET u.role = $role RETURN u.id"
+
+// UserStore keeps users as User nodes, with their creation time in nanoseconds since the epoch. Users created before
+// roles were stored have none, and are players.
+type UserStore struct {
+	DbClient
+}
+
+func NewUserStore(dbClient DbClient) UserStore {
+	return UserStore{dbClient}
+}
+
+func (us UserStore) CreateUser(user *rpslsapi.User) error {
+	session := us.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: us.databaseName})
+	defer CloseDBResource(session)
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		if _, err := userByUsername(transaction, user.Username); err == nil {
+			return nil, rpslsapi.ErrUsernameTaken
+		} else if err != rpslsapi.ErrUserNotFound {
+			return nil, err
+		}
+
+		return transaction.Run(createUserQuery, map[string]interface{}{
+			"id":           user.ID,
+			"username":     user.Username,
+			"passwordHash": user.PasswordHash,
+			"role":         string(user.Role),
+			"createdAt":    user.CreatedAt.UnixNano(),
+		})
+	})
+	return err
+}
+
+func (us UserStore) UserByUsername(username string) (*rpslsapi.User, error) {
+	session := us.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: us.databaseName})
+	defer CloseDBResource(session)
+
+	user, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		return userByUsername(transaction, username)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user.(*rpslsapi.User), nil
+}
+
+func (us UserStore) SetRole(username string, role rpslsapi.Role) error {
+	session := us.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: us.databaseName})
+	defer CloseDBResource(session)
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		records, err := transaction.Run(setRoleQuery,
+			map[string]interface{}{"username": username, "role": string(role)})
+		if err != nil {
+			return nil, err
+		}
+		if !records.Next() {
+			return nil, rpslsapi.ErrUserNotFound
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func userByUsername(transaction neo4j.Transaction, username string) (*rpslsapi.User, error) {
+	records, err := transaction.Run(userByUsernameQuery, map[string]interface{}{"username": username})
+	if err != nil {
+		return nil, err
+	}
+	if !records.Next() {
+		return nil, rpslsapi.ErrUserNotFound
+	}
+	record := records.Record()
+	id, _ := record.Get("id")
+	name, _ := record.Get("username")
+	passwordHash, _ := record.Get("passwordHash")
+	role, _ := record.Get("role")
+	createdAt, _ := record.Get("createdAt")
+	return &rpslsapi.User{ID: id.(string), Username: name.(string), PasswordHash: passwordHash.(string),
+		Role: rpslsapi.Role(role.(string)), CreatedAt: time.Unix(0, createdAt.(int64)).UTC()}, nil
+}
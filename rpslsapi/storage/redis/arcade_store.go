@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"rpsls/rpslsapi"
+)
+
+// arcade sessions and high scores are kept next to the scoreboards
+const (
+	arcadeKeyPrefix     = "rpsls-arcade:"
+	highScoresKeyPrefix = "rpsls-arcade-high-scores:"
+)
+
+// errNoHighScores tells that a ruleset has no high scores yet
+var errNoHighScores = errors.New("no high scores")
+
+type ArcadeStore struct {
+	Client
+}
+
+func NewArcadeStore(client Client) ArcadeStore {
+	return ArcadeStore{client}
+}
+
+func (as ArcadeStore) CreateSession(session *rpslsapi.ArcadeSession, retention time.Duration) error {
+	return createJSON(as.Client, arcadeKeyPrefix+session.ID, session, retention)
+}
+
+func (as ArcadeStore) Session(id string) (*rpslsapi.ArcadeSession, error) {
+	var session rpslsapi.ArcadeSession
+	if err := getJSON(as.Client, arcadeKeyPrefix+id, &session, rpslsapi.ErrArcadeSessionNotFound); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (as ArcadeStore) UpdateSession(id string,
+	update func(session *rpslsapi.ArcadeSession) error) (*rpslsapi.ArcadeSession, error) {
+	var session rpslsapi.ArcadeSession
+	err := updateJSON(as.Client, arcadeKeyPrefix+id, &session, rpslsapi.ErrArcadeSessionNotFound, func() error {
+		return update(&session)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// SubmitHighScore saves the high scores of the ruleset as a single JSON value which never expires, watching it while
+// inserting the score like updateJSON does
+func (as ArcadeStore) SubmitHighScore(score *rpslsapi.HighScore, size int) (int, error) {
+	ctx := context.Background()
+	key := highScoresKeyPrefix + score.Ruleset
+	rank := 0
+	apply := func(tx *redis.Tx) error {
+		highScores, err := loadHighScores(tx, key)
+		if err != nil {
+			return err
+		}
+		highScores, rank = rpslsapi.InsertHighScore(highScores, score, size)
+		if rank == 0 {
+			return nil
+		}
+		encoded, err := json.Marshal(highScores)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, encoded, 0)
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		err := as.Watch(ctx, apply, key)
+		if err != redis.TxFailedErr {
+			return rank, err
+		}
+	}
+	return 0, errors.New("value kept changing while being updated: " + key)
+}
+
+func (as ArcadeStore) HighScores(ruleset string, size int) ([]rpslsapi.HighScore, error) {
+	highScores, err := loadHighScores(as.Client, highScoresKeyPrefix+ruleset)
+	if err != nil {
+		return nil, err
+	}
+	if len(highScores) > size {
+		highScores = highScores[:size]
+	}
+	return highScores, nil
+}
+
+func loadHighScores(client redis.Cmdable, key string) ([]rpslsapi.HighScore, error) {
+	highScores := []rpslsapi.HighScore{}
+	err := getJSON(client, key, &highScores, errNoHighScores)
+	if err != nil && err != errNoHighScores {
+		return nil, err
+	}
+	return highScores, nil
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/go-redis/redis/v8"
+	"rpsls/rpslsapi"
+)
+
+// botsKey is the hash holding the JSON of every registered bot under its name
+const botsKey = "bots"
+
+type BotStore struct {
+	Client
+}
+
+func NewBotStore(client Client) BotStore {
+	return BotStore{client}
+}
+
+func (bs BotStore) CreateBot(bot *rpslsapi.Bot) error {
+	encoded, err := json.Marshal(bot)
+	if err != nil {
+		return err
+	}
+
+	created, err := bs.HSetNX(context.Background(), botsKey, bot.Name, encoded).Result()
+	if err != nil {
+		return err
+	}
+	if !created {
+		return rpslsapi.ErrBotAlreadyExists
+	}
+	return nil
+}
+
+func (bs BotStore) Bots() ([]rpslsapi.Bot, error) {
+	encoded, err := bs.HGetAll(context.Background(), botsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	bots := make([]rpslsapi.Bot, 0, len(encoded))
+	for _, value := range encoded {
+		var bot rpslsapi.Bot
+		if err := json.Unmarshal([]byte(value), &bot); err != nil {
+			return nil, err
+		}
+		bots = append(bots, bot)
+	}
+	sort.Slice(bots, func(i, j int) bool { return bots[i].Name < bots[j].Name })
+	return bots, nil
+}
+
+func (bs BotStore) Bot(name string) (*rpslsapi.Bot, error) {
+	encoded, err := bs.HGet(context.Background(), botsKey, name).Bytes()
+	if err == redis.Nil {
+		return nil, rpslsapi.ErrBotNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var bot rpslsapi.Bot
+	if err := json.Unmarshal(encoded, &bot); err != nil {
+		return nil, err
+	}
+	return &bot, nil
+}
+
+func (bs BotStore) DeleteBot(name string) error {
+	deleted, err := bs.HDel(context.Background(), botsKey, name).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return rpslsapi.ErrBotNotFound
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"rpsls/rpslsapi"
+)
+
+const commitmentKeyPrefix = "commitment:"
+
+type CommitmentStore struct {
+	Client
+}
+
+func NewCommitmentStore(client Client) CommitmentStore {
+	return CommitmentStore{client}
+}
+
+func (cs CommitmentStore) SaveCommitment(round *rpslsapi.CommittedRound, ttl time.Duration) error {
+	return createJSON(cs.Client, commitmentKeyPrefix+round.Token, round, ttl)
+}
+
+// TakeCommitment reads and deletes the commitment in a single transaction, so it can't be played twice
+func (cs CommitmentStore) TakeCommitment(token string) (*rpslsapi.CommittedRound, error) {
+	ctx := context.Background()
+	key := commitmentKeyPrefix + token
+
+	var get *redis.StringCmd
+	_, err := cs.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err == redis.Nil {
+		return nil, rpslsapi.ErrCommitmentNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var round rpslsapi.CommittedRound
+	if err := json.Unmarshal([]byte(get.Val()), &round); err != nil {
+		return nil, err
+	}
+	return &round, nil
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"rpsls/rpslsapi"
+)
+
+// daily challenge attempts and leaderboards are kept next to the scoreboards
+const (
+	dailyKeyPrefix            = "rpsls-daily:"
+	dailyLeaderboardKeyPrefix = "rpsls-daily-leaderboard:"
+)
+
+// errNoLeaderboard tells that nobody played the challenge of a day yet
+var errNoLeaderboard = errors.New("no leaderboard")
+
+type DailyStore struct {
+	Client
+}
+
+func NewDailyStore(client Client) DailyStore {
+	return DailyStore{client}
+}
+
+// SaveAttempt watches both the attempt and the leaderboard while saving them, like updateJSON does, so concurrent
+// attempts neither overwrite each other nor get lost from the leaderboard
+func (ds DailyStore) SaveAttempt(attempt *rpslsapi.DailyAttempt, size int, retention time.Duration) error {
+	ctx := context.Background()
+	attemptKey := dailyAttemptKey(attempt.Date, attempt.UserID)
+	leaderboardKey := dailyLeaderboardKeyPrefix + attempt.Date
+	apply := func(tx *redis.Tx) error {
+		played, err := tx.Exists(ctx, attemptKey).Result()
+		if err != nil {
+			return err
+		}
+		if played > 0 {
+			return rpslsapi.ErrAlreadyPlayedDaily
+		}
+		leaderboard, err := loadLeaderboard(tx, leaderboardKey)
+		if err != nil {
+			return err
+		}
+		leaderboard = rpslsapi.InsertDailyScore(leaderboard, attempt, size)
+		encodedAttempt, err := json.Marshal(attempt)
+		if err != nil {
+			return err
+		}
+		encodedLeaderboard, err := json.Marshal(leaderboard)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, attemptKey, encodedAttempt, retention)
+			pipe.Set(ctx, leaderboardKey, encodedLeaderboard, retention)
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < maxUpdateAttempts; i++ {
+		attempt.Rank = 0
+		err := ds.Watch(ctx, apply, attemptKey, leaderboardKey)
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+	return errors.New("value kept changing while being updated: " + leaderboardKey)
+}
+
+func (ds DailyStore) Attempt(date, userID string) (*rpslsapi.DailyAttempt, error) {
+	var attempt rpslsapi.DailyAttempt
+	err := getJSON(ds.Client, dailyAttemptKey(date, userID), &attempt, rpslsapi.ErrDailyAttemptNotFound)
+	if err != nil {
+		return nil, err
+	}
+	attempt.UserID = userID
+	return &attempt, nil
+}
+
+func (ds DailyStore) Leaderboard(date string, size int) ([]rpslsapi.DailyScore, error) {
+	leaderboard, err := loadLeaderboard(ds.Client, dailyLeaderboardKeyPrefix+date)
+	if err != nil {
+		return nil, err
+	}
+	if len(leaderboard) > size {
+		leaderboard = leaderboard[:size]
+	}
+	return leaderboard, nil
+}
+
+func dailyAttemptKey(date, userID string) string {
+	return dailyKeyPrefix + date + ":" + userID
+}
+
+func loadLeaderboard(client redis.Cmdable, key string) ([]rpslsapi.DailyScore, error) {
+	leaderboard := []rpslsapi.DailyScore{}
+	err := getJSON(client, key, &leaderboard, errNoLeaderboard)
+	if err != nil && err != errNoLeaderboard {
+		return nil, err
+	}
+	return leaderboard, nil
+}
@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// maxUpdateAttempts bounds how many times an update is retried when the value changes while it is being applied
+const maxUpdateAttempts = 10
+
+// createJSON stores value as JSON under key, which expires once the retention has passed, unless the key is in use
+func createJSON(client Client, key string, value interface{}, retention time.Duration) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	created, err := client.SetNX(context.Background(), key, encoded, retention).Result()
+	if err != nil {
+		return err
+	}
+	if !created {
+		return errors.New("key already in use: " + key)
+	}
+	return nil
+}
+
+// getJSON decodes the value under key into value, returning notFound if the key doesn't exist
+func getJSON(client redis.Cmdable, key string, value interface{}, notFound error) error {
+	encoded, err := client.Get(context.Background(), key).Bytes()
+	if err == redis.Nil {
+		return notFound
+	} else if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, value)
+}
+
+// updateJSON decodes the value under key into value, applies update and saves the result, keeping the expiry of the
+// key. The key is watched while doing so, and the update is retried if another update saved it in between, so update
+// may run several times and must not have side effects. Errors returned by update are returned as is, leaving the
+// value untouched.
+func updateJSON(client Client, key string, value interface{}, notFound error, update func() error) error {
+	ctx := context.Background()
+	apply := func(tx *redis.Tx) error {
+		// decoding doesn't clear the fields missing from the JSON, so every attempt starts from the zero value
+		reset := reflect.ValueOf(value).Elem()
+		reset.Set(reflect.Zero(reset.Type()))
+		if err := getJSON(tx, key, value, notFound); err != nil {
+			return err
+		}
+		if err := update(); err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, encoded, redis.KeepTTL)
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		err := client.Watch(ctx, apply, key)
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+	return errors.New("value kept changing while being updated: " + key)
+}
@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+const matchKeyPrefix = "match:"
+
+type MatchStore struct {
+	Client
+}
+
+func NewMatchStore(client Client) MatchStore {
+	return MatchStore{client}
+}
+
+func (ms MatchStore) CreateMatch(match *rpslsapi.Match, retention time.Duration) error {
+	return createJSON(ms.Client, matchKeyPrefix+match.ID, match, retention)
+}
+
+func (ms MatchStore) Match(id string) (*rpslsapi.Match, error) {
+	var match rpslsapi.Match
+	if err := getJSON(ms.Client, matchKeyPrefix+id, &match, rpslsapi.ErrMatchNotFound); err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+func (ms MatchStore) UpdateMatch(id string, update func(match *rpslsapi.Match) error) (*rpslsapi.Match, error) {
+	var match rpslsapi.Match
+	err := updateJSON(ms.Client, matchKeyPrefix+id, &match, rpslsapi.ErrMatchNotFound, func() error {
+		return update(&match)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+const seriesKeyPrefix = "series:"
+
+type SeriesStore struct {
+	Client
+}
+
+func NewSeriesStore(client Client) SeriesStore {
+	return SeriesStore{client}
+}
+
+func (ss SeriesStore) CreateSeries(series *rpslsapi.Series, retention time.Duration) error {
+	return createJSON(ss.Client, seriesKeyPrefix+series.ID, series, retention)
+}
+
+func (ss SeriesStore) Series(id string) (*rpslsapi.Series, error) {
+	var series rpslsapi.Series
+	if err := getJSON(ss.Client, seriesKeyPrefix+id, &series, rpslsapi.ErrSeriesNotFound); err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (ss SeriesStore) UpdateSeries(id string, update func(series *rpslsapi.Series) error) (*rpslsapi.Series, error) {
+	var series rpslsapi.Series
+	err := updateJSON(ss.Client, seriesKeyPrefix+id, &series, rpslsapi.ErrSeriesNotFound, func() error {
+		return update(&series)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
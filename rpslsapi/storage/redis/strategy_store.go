@@ -0,0 +1,30 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const strategyKeyPrefix = "strategy:"
+
+// StrategyStore keeps the default strategy of each user, which is kept until replaced
+type StrategyStore struct {
+	Client
+}
+
+func NewStrategyStore(client Client) StrategyStore {
+	return StrategyStore{client}
+}
+
+func (ss StrategyStore) DefaultStrategy(userID string) (string, error) {
+	name, err := ss.Get(context.Background(), strategyKeyPrefix+userID).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return name, err
+}
+
+func (ss StrategyStore) SetDefaultStrategy(userID, name string) error {
+	return ss.Set(context.Background(), strategyKeyPrefix+userID, name, 0).Err()
+}
@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+const tournamentKeyPrefix = "tournament:"
+
+type TournamentStore struct {
+	Client
+}
+
+func NewTournamentStore(client Client) TournamentStore {
+	return TournamentStore{client}
+}
+
+func (ts TournamentStore) CreateTournament(tournament *rpslsapi.Tournament, retention time.Duration) error {
+	return createJSON(ts.Client, tournamentKeyPrefix+tournament.ID, tournament, retention)
+}
+
+func (ts TournamentStore) Tournament(id string) (*rpslsapi.Tournament, error) {
+	var tournament rpslsapi.Tournament
+	err := getJSON(ts.Client, tournamentKeyPrefix+id, &tournament, rpslsapi.ErrTournamentNotFound)
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+func (ts TournamentStore) UpdateTournament(id string, update func(tournament *rpslsapi.Tournament) error) (
+	*rpslsapi.Tournament, error) {
+	var tournament rpslsapi.Tournament
+	err := updateJSON(ts.Client, tournamentKeyPrefix+id, &tournament, rpslsapi.ErrTournamentNotFound, func() error {
+		return update(&tournament)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
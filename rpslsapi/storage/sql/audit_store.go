@@ -0,0 +1,47 @@
+package sql
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"rpsls/rpslsapi"
+)
+
+const saveAuditQuery = "INSERT INTO round_audits (id, request_id, user_id, played_at, record) " +
+	"VALUES ($1, $2, $3, $4, $5)"
+const auditQuery = "SELECT record FROM round_audits WHERE id = $1"
+
+// AuditStore keeps each round audit as a JSON record in the round_audits table, along with the columns to look it up
+type AuditStore struct {
+	DbClient
+}
+
+func NewAuditStore(dbClient DbClient) AuditStore {
+	return AuditStore{dbClient}
+}
+
+func (as AuditStore) SaveAudit(audit *rpslsapi.RoundAudit) error {
+	record, err := json.Marshal(audit)
+	if err != nil {
+		return err
+	}
+	_, err = as.db.Exec(saveAuditQuery, audit.ID, audit.RequestID, audit.UserID, audit.PlayedAt.UnixNano(),
+		string(record))
+	return err
+}
+
+func (as AuditStore) Audit(id string) (*rpslsapi.RoundAudit, error) {
+	var record string
+	err := as.db.QueryRow(auditQuery, id).Scan(&record)
+	if err == sql.ErrNoRows {
+		return nil, rpslsapi.ErrAuditNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var audit rpslsapi.RoundAudit
+	if err := json.Unmarshal([]byte(record), &audit); err != nil {
+		return nil, err
+	}
+	return &audit, nil
+}
@@ -0,0 +1,292 @@
+package sql
+
+import (
+	"database/sql"
+
+	"rpsls/rpslsapi"
+)
+
+const rulesetExistsQuery = "SELECT id FROM rulesets WHERE id = $1"
+const allChoicesQuery = "SELECT slug, name FROM choices WHERE ruleset = $1 ORDER BY id"
+const choiceByIdQuery = "SELECT slug, name, ruleset FROM choices WHERE slug = $1"
+const choiceByNameQuery = "SELECT slug FROM choices WHERE ruleset = $1 AND name = $2 AND slug <> $3"
+const createChoiceQuery = "INSERT INTO choices (slug, name, ruleset) VALUES ($1, $2, $3)"
+const updateChoiceQuery = "UPDATE choices SET name = $1 WHERE slug = $2"
+const deleteChoiceRulesQuery = "DELETE FROM beats WHERE winner = (SELECT id FROM choices WHERE slug = $1) " +
+	"OR loser = (SELECT id FROM choices WHERE slug = $1)"
+const deleteChoiceQuery = "DELETE FROM choices WHERE slug = $1"
+const allRulesQuery = "SELECT winner.slug, loser.slug, beats.verb FROM beats " +
+	"JOIN choices winner ON winner.id = beats.winner JOIN choices loser ON loser.id = beats.loser " +
+	"WHERE winner.ruleset = $1 ORDER BY beats.winner, beats.loser"
+const deletePairRulesQuery = "DELETE FROM beats WHERE winner IN (SELECT id FROM choices WHERE slug IN ($1, $2)) " +
+	"AND loser IN (SELECT id FROM choices WHERE slug IN ($1, $2))"
+const createRuleQuery = "INSERT INTO beats (winner, loser, verb) SELECT winner.id, loser.id, $1 " +
+	"FROM choices winner, choices loser WHERE winner.slug = $2 AND loser.slug = $3"
+const deleteRuleQuery = "DELETE FROM beats WHERE winner = (SELECT id FROM choices WHERE slug = $1) " +
+	"AND loser = (SELECT id FROM choices WHERE slug = $2)"
+
+type ChoiceStore struct {
+	DbClient
+}
+
+func NewChoiceStore(dbClient DbClient) ChoiceStore {
+	return ChoiceStore{dbClient}
+}
+
+func (cs ChoiceStore) Choices(ruleset string) ([]rpslsapi.Choice, error) {
+	var choices []rpslsapi.Choice
+
+	err := cs.inTransaction(func(tx *sql.Tx) error {
+		if err := checkRulesetExists(tx, ruleset); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(allChoicesQuery, ruleset)
+		if err != nil {
+			return err
+		}
+		defer CloseDBResource(rows)
+
+		for rows.Next() {
+			choice := rpslsapi.Choice{Ruleset: ruleset}
+			if err := rows.Scan(&choice.ID, &choice.Name); err != nil {
+				return err
+			}
+			choices = append(choices, choice)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return choices, nil
+}
+
+func (cs ChoiceStore) Choice(id string) (*rpslsapi.Choice, error) {
+	var choice *rpslsapi.Choice
+
+	err := cs.inTransaction(func(tx *sql.Tx) (err error) {
+		choice, err = choiceByID(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return choice, nil
+}
+
+func (cs ChoiceStore) CreateChoice(definition *rpslsapi.ChoiceDefinition) (*rpslsapi.Choice, error) {
+	choice := &rpslsapi.Choice{ID: definition.ID, Name: definition.Name, Ruleset: definition.Ruleset}
+
+	err := cs.inTransaction(func(tx *sql.Tx) error {
+		if err := checkRulesetExists(tx, definition.Ruleset); err != nil {
+			return err
+		}
+		if err := checkIDAvailable(tx, definition.ID); err != nil {
+			return err
+		}
+		if err := checkNameAvailable(tx, definition.Ruleset, "", definition.Name); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(createChoiceQuery, definition.ID, definition.Name, definition.Ruleset); err != nil {
+			return err
+		}
+
+		for _, relationship := range definition.Beats {
+			rule := rpslsapi.Rule{WinnerID: choice.ID, LoserID: relationship.ChoiceID, Action: relationship.Action}
+			if err := saveRule(tx, &rule); err != nil {
+				return err
+			}
+		}
+		for _, relationship := range definition.BeatenBy {
+			rule := rpslsapi.Rule{WinnerID: relationship.ChoiceID, LoserID: choice.ID, Action: relationship.Action}
+			if err := saveRule(tx, &rule); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return choice, nil
+}
+
+func (cs ChoiceStore) UpdateChoice(choice *rpslsapi.Choice) (*rpslsapi.Choice, error) {
+	var updated *rpslsapi.Choice
+
+	err := cs.inTransaction(func(tx *sql.Tx) error {
+		existing, err := choiceByID(tx, choice.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkNameAvailable(tx, existing.Ruleset, choice.ID, choice.Name); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(updateChoiceQuery, choice.Name, choice.ID); err != nil {
+			return err
+		}
+		updated = &rpslsapi.Choice{ID: choice.ID, Name: choice.Name, Ruleset: existing.Ruleset}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (cs ChoiceStore) DeleteChoice(id string) error {
+	return cs.inTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(deleteChoiceRulesQuery, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(deleteChoiceTranslationsQuery, id); err != nil {
+			return err
+		}
+		return runDelete(tx, rpslsapi.ErrChoiceNotFound, deleteChoiceQuery, id)
+	})
+}
+
+func (cs ChoiceStore) Rules(ruleset string) ([]rpslsapi.Rule, error) {
+	rows, err := cs.db.Query(allRulesQuery, ruleset)
+	if err != nil {
+		return nil, err
+	}
+	defer CloseDBResource(rows)
+
+	var rules []rpslsapi.Rule
+	for rows.Next() {
+		var rule rpslsapi.Rule
+		if err := rows.Scan(&rule.WinnerID, &rule.LoserID, &rule.Action); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (cs ChoiceStore) SaveRule(rule *rpslsapi.Rule) error {
+	return cs.inTransaction(func(tx *sql.Tx) error {
+		return saveRule(tx, rule)
+	})
+}
+
+func (cs ChoiceStore) DeleteRule(winnerID, loserID string) error {
+	return cs.inTransaction(func(tx *sql.Tx) error {
+		return runDelete(tx, rpslsapi.ErrRuleNotFound, deleteRuleQuery, winnerID, loserID)
+	})
+}
+
+func checkRulesetExists(tx *sql.Tx, ruleset string) error {
+	var id string
+	err := tx.QueryRow(rulesetExistsQuery, ruleset).Scan(&id)
+	if err == sql.ErrNoRows {
+		return rpslsapi.ErrRulesetNotFound
+	}
+	return err
+}
+
+func choiceByID(tx *sql.Tx, id string) (*rpslsapi.Choice, error) {
+	var choice rpslsapi.Choice
+	err := tx.QueryRow(choiceByIdQuery, id).Scan(&choice.ID, &choice.Name, &choice.Ruleset)
+	if err == sql.ErrNoRows {
+		return nil, rpslsapi.ErrChoiceNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &choice, nil
+}
+
+// checkNameAvailable fails with ErrChoiceAlreadyExists if a choice of the ruleset other than the one with the given id
+// uses the name
+func checkNameAvailable(tx *sql.Tx, ruleset, id, name string) error {
+	var existingID string
+	err := tx.QueryRow(choiceByNameQuery, ruleset, name, id).Scan(&existingID)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return rpslsapi.ErrChoiceAlreadyExists
+}
+
+// checkIDAvailable fails with ErrChoiceAlreadyExists if a choice already uses the id
+func checkIDAvailable(tx *sql.Tx, id string) error {
+	if _, err := choiceByID(tx, id); err == nil {
+		return rpslsapi.ErrChoiceAlreadyExists
+	} else if err != rpslsapi.ErrChoiceNotFound {
+		return err
+	}
+	return nil
+}
+
+func saveRule(tx *sql.Tx, rule *rpslsapi.Rule) error {
+	winner, err := choiceByID(tx, rule.WinnerID)
+	if err != nil {
+		return err
+	}
+	loser, err := choiceByID(tx, rule.LoserID)
+	if err != nil {
+		return err
+	}
+	if winner.Ruleset != loser.Ruleset {
+		return rpslsapi.ErrChoiceNotFound
+	}
+
+	if _, err := tx.Exec(deletePairRulesQuery, rule.WinnerID, rule.LoserID); err != nil {
+		return err
+	}
+	_, err = tx.Exec(createRuleQuery, rule.Action, rule.WinnerID, rule.LoserID)
+	return err
+}
+
+func (cs ChoiceStore) ImportRuleset(document *rpslsapi.RulesetDocument, replace bool) (*rpslsapi.Ruleset, error) {
+	err := cs.inTransaction(func(tx *sql.Tx) error {
+		if err := checkRulesetExists(tx, document.ID); err == nil {
+			if !replace {
+				return rpslsapi.ErrRulesetAlreadyExists
+			}
+			if err := deleteRuleset(tx, document.ID); err != nil {
+				return err
+			}
+		} else if err != rpslsapi.ErrRulesetNotFound {
+			return err
+		}
+
+		if _, err := tx.Exec(createRulesetQuery, document.ID, document.Name); err != nil {
+			return err
+		}
+
+		ids := make(map[string]string, len(document.Choices))
+		for _, name := range document.Choices {
+			ids[name] = rpslsapi.NewChoiceID(document.ID, name)
+			if err := checkIDAvailable(tx, ids[name]); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(createChoiceQuery, ids[name], name, document.ID); err != nil {
+				return err
+			}
+		}
+		for _, entry := range document.Beats {
+			if _, err := tx.Exec(createRuleQuery, entry.Verb, ids[entry.Winner], ids[entry.Loser]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpslsapi.Ruleset{ID: document.ID, Name: document.Name}, nil
+}
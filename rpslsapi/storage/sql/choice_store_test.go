@@ -0,0 +1,174 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+// newTestDbClient opens a migrated in-memory SQLite database
+func newTestDbClient(t *testing.T) DbClient {
+	client, err := open(dialects["sqlite"], ":memory:", "file://../../../db/sql/sqlite")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		CloseDBResource(client.db)
+	})
+	return client
+}
+
+func choiceIDs(t *testing.T, store ChoiceStore, ruleset string) map[string]string {
+	choices, err := store.Choices(ruleset)
+	require.NoError(t, err)
+	ids := make(map[string]string, len(choices))
+	for _, choice := range choices {
+		ids[choice.Name] = choice.ID
+	}
+	return ids
+}
+
+func TestChoiceStore_Choices(t *testing.T) {
+	store := NewChoiceStore(newTestDbClient(t))
+
+	rpslsChoices, err := store.Choices("rpsls")
+	require.NoError(t, err)
+	require.Len(t, rpslsChoices, 5)
+	require.Equal(t, rpslsapi.Choice{ID: "rpsls-rock", Name: "Rock", Ruleset: "rpsls"}, rpslsChoices[0])
+
+	rpsChoices, err := store.Choices("rps")
+	require.NoError(t, err)
+	require.Len(t, rpsChoices, 3)
+
+	_, err = store.Choices("missing")
+	require.Equal(t, rpslsapi.ErrRulesetNotFound, err)
+}
+
+func TestChoiceStore_MigratedRulesAreValid(t *testing.T) {
+	store := NewChoiceStore(newTestDbClient(t))
+
+	for _, ruleset := range []string{"rpsls", "rps"} {
+		choices, err := store.Choices(ruleset)
+		require.NoError(t, err)
+		rules, err := store.Rules(ruleset)
+		require.NoError(t, err)
+
+		report := rpslsapi.ValidateRuleGraph(choices, rules)
+		require.True(t, report.Valid, ruleset)
+		require.Empty(t, report.Issues, ruleset)
+	}
+}
+
+func TestChoiceStore_CreateChoice(t *testing.T) {
+	store := NewChoiceStore(newTestDbClient(t))
+	ids := choiceIDs(t, store, "rpsls")
+
+	kitten, err := store.CreateChoice(&rpslsapi.ChoiceDefinition{
+		ID:      "rpsls-kitten",
+		Name:    "Kitten",
+		Ruleset: "rpsls",
+		Beats: []rpslsapi.Relationship{
+			{ChoiceID: ids["Paper"], Action: "scratches"},
+			{ChoiceID: ids["Lizard"], Action: "eats"},
+		},
+		BeatenBy: []rpslsapi.Relationship{
+			{ChoiceID: ids["Rock"], Action: "crushes"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &rpslsapi.Choice{ID: "rpsls-kitten", Name: "Kitten", Ruleset: "rpsls"}, kitten)
+
+	stored, err := store.Choice(kitten.ID)
+	require.NoError(t, err)
+	require.Equal(t, kitten, stored)
+	rules, _ := store.Rules("rpsls")
+	require.Len(t, rules, 13)
+
+	_, err = store.CreateChoice(&rpslsapi.ChoiceDefinition{ID: "rpsls-cat", Name: "Kitten", Ruleset: "rpsls"})
+	require.Equal(t, rpslsapi.ErrChoiceAlreadyExists, err)
+	_, err = store.CreateChoice(&rpslsapi.ChoiceDefinition{ID: "rpsls-kitten", Name: "Cat", Ruleset: "rpsls"})
+	require.Equal(t, rpslsapi.ErrChoiceAlreadyExists, err)
+
+	_, err = store.CreateChoice(&rpslsapi.ChoiceDefinition{ID: "missing-kitten", Name: "Kitten", Ruleset: "missing"})
+	require.Equal(t, rpslsapi.ErrRulesetNotFound, err)
+
+	// relationships to choices of other rulesets roll the whole creation back
+	rpsIDs := choiceIDs(t, store, "rps")
+	_, err = store.CreateChoice(&rpslsapi.ChoiceDefinition{
+		ID:      "rpsls-well",
+		Name:    "Well",
+		Ruleset: "rpsls",
+		Beats:   []rpslsapi.Relationship{{ChoiceID: rpsIDs["Rock"], Action: "swallows"}},
+	})
+	require.Equal(t, rpslsapi.ErrChoiceNotFound, err)
+	require.NotContains(t, choiceIDs(t, store, "rpsls"), "Well")
+}
+
+func TestChoiceStore_UpdateChoice(t *testing.T) {
+	store := NewChoiceStore(newTestDbClient(t))
+	ids := choiceIDs(t, store, "rps")
+
+	updated, err := store.UpdateChoice(&rpslsapi.Choice{ID: ids["Rock"], Name: "Boulder"})
+	require.NoError(t, err)
+	require.Equal(t, &rpslsapi.Choice{ID: ids["Rock"], Name: "Boulder", Ruleset: "rps"}, updated)
+
+	_, err = store.UpdateChoice(&rpslsapi.Choice{ID: ids["Paper"], Name: "Boulder"})
+	require.Equal(t, rpslsapi.ErrChoiceAlreadyExists, err)
+
+	_, err = store.UpdateChoice(&rpslsapi.Choice{ID: "rps-pebble", Name: "Pebble"})
+	require.Equal(t, rpslsapi.ErrChoiceNotFound, err)
+}
+
+func TestChoiceStore_DeleteChoice(t *testing.T) {
+	store := NewChoiceStore(newTestDbClient(t))
+	ids := choiceIDs(t, store, "rps")
+
+	require.NoError(t, store.DeleteChoice(ids["Rock"]))
+	rules, _ := store.Rules("rps")
+	require.Equal(t, []rpslsapi.Rule{{WinnerID: ids["Scissors"], LoserID: ids["Paper"], Action: "cuts"}}, rules)
+
+	require.Equal(t, rpslsapi.ErrChoiceNotFound, store.DeleteChoice(ids["Rock"]))
+}
+
+func TestChoiceStore_SaveAndDeleteRule(t *testing.T) {
+	store := NewChoiceStore(newTestDbClient(t))
+	ids := choiceIDs(t, store, "rps")
+
+	rule := rpslsapi.Rule{WinnerID: ids["Rock"], LoserID: ids["Paper"], Action: "breaks"}
+	require.NoError(t, store.SaveRule(&rule))
+	rules, _ := store.Rules("rps")
+	require.Len(t, rules, 3)
+	require.Contains(t, rules, rule)
+
+	rpslsIDs := choiceIDs(t, store, "rpsls")
+	err := store.SaveRule(&rpslsapi.Rule{WinnerID: ids["Rock"], LoserID: rpslsIDs["Spock"], Action: "crushes"})
+	require.Equal(t, rpslsapi.ErrChoiceNotFound, err)
+
+	require.NoError(t, store.DeleteRule(ids["Rock"], ids["Paper"]))
+	require.Equal(t, rpslsapi.ErrRuleNotFound, store.DeleteRule(ids["Rock"], ids["Paper"]))
+}
+
+func TestChoiceStore_ImportRuleset(t *testing.T) {
+	client := newTestDbClient(t)
+	store := NewChoiceStore(client)
+	document := &rpslsapi.RulesetDocument{
+		ID:      "rps",
+		Name:    "Duel",
+		Choices: []string{"Sword", "Shield"},
+		Beats:   []rpslsapi.BeatsEntry{{Winner: "Shield", Loser: "Sword", Verb: "blocks"}},
+	}
+
+	_, err := store.ImportRuleset(document, false)
+	require.Equal(t, rpslsapi.ErrRulesetAlreadyExists, err)
+
+	ruleset, err := store.ImportRuleset(document, true)
+	require.NoError(t, err)
+	require.Equal(t, &rpslsapi.Ruleset{ID: "rps", Name: "Duel"}, ruleset)
+
+	stored, err := NewRulesetStore(client).Ruleset("rps")
+	require.NoError(t, err)
+	require.Equal(t, ruleset, stored)
+	ids := choiceIDs(t, store, "rps")
+	require.Equal(t, map[string]string{"Sword": "rps-sword", "Shield": "rps-shield"}, ids)
+	rules, _ := store.Rules("rps")
+	require.Equal(t, []rpslsapi.Rule{{WinnerID: ids["Shield"], LoserID: ids["Sword"], Action: "blocks"}}, rules)
+}
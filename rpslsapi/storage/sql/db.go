@@ -0,0 +1,134 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rs/zerolog/log"
+	"rpsls/rpslsapi"
+)
+
+// dialect describes how to open and migrate one of the supported relational databases
+type dialect struct {
+	driverName    string
+	migrationsDir string
+	migrateDriver func(db *sql.DB) (database.Driver, error)
+}
+
+var dialects = map[string]dialect{
+	"sqlite": {
+		driverName:    "sqlite3",
+		migrationsDir: "db/sql/sqlite",
+		migrateDriver: func(db *sql.DB) (database.Driver, error) {
+			return sqlite3.WithInstance(db, &sqlite3.Config{})
+		},
+	},
+	"postgres": {
+		driverName:    "postgres",
+		migrationsDir: "db/sql/postgres",
+		migrateDriver: func(db *sql.DB) (database.Driver, error) {
+			return postgres.WithInstance(db, &postgres.Config{})
+		},
+	},
+}
+
+type DbClient struct {
+	db *sql.DB
+}
+
+// NewDbClient connects to the database selected by DB_DRIVER (sqlite or postgres), using DB_URI as the data source
+// name, and runs the migrations of db/sql.
+func NewDbClient() (DbClient, func()) {
+	dbDialect, found := dialects[rpslsapi.Config.DB.Driver]
+	if !found {
+		panic(fmt.Errorf("unknown SQL driver %q", rpslsapi.Config.DB.Driver))
+	}
+
+	client, err := open(dbDialect, rpslsapi.Config.DB.Uri, "file://"+dbDialect.migrationsDir)
+	if err != nil {
+		panic(err)
+	}
+
+	cleanup := func() {
+		CloseDBResource(client.db)
+	}
+
+	return client, cleanup
+}
+
+func open(dbDialect dialect, dataSourceName, migrationsURL string) (DbClient, error) {
+	db, err := sql.Open(dbDialect.driverName, dataSourceName)
+	if err != nil {
+		return DbClient{}, fmt.Errorf("failed to connect to the database: %v", err)
+	}
+	if dbDialect.driverName == "sqlite3" {
+		// SQLite allows a single writer, and every connection to an in-memory database opens a different one
+		db.SetMaxOpenConns(1)
+	}
+
+	if err = migrateDB(db, dbDialect, migrationsURL); err != nil && err != migrate.ErrNoChange {
+		CloseDBResource(db)
+		return DbClient{}, fmt.Errorf("failed to migrate database: %v", err)
+	}
+
+	return DbClient{db: db}, nil
+}
+
+func CloseDBResource(closer io.Closer) {
+	if err := closer.Close(); err != nil {
+		log.Fatal().Err(fmt.Errorf("could not close resource: %w", err))
+	}
+}
+
+func migrateDB(db *sql.DB, dbDialect dialect, migrationsURL string) error {
+	driver, err := dbDialect.migrateDriver(db)
+	if err != nil {
+		return err
+	}
+	m, err := migrate.NewWithDatabaseInstance(migrationsURL, dbDialect.driverName, driver)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// inTransaction runs work in a transaction, committing it if work succeeds and rolling it back otherwise
+func (c DbClient) inTransaction(work func(tx *sql.Tx) error) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := work(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// runDelete runs a delete statement, failing with notFoundErr if no row was deleted
+func runDelete(tx *sql.Tx, notFoundErr error, query string, args ...interface{}) error {
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return notFoundErr
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package sql
+
+import (
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+const saveHistoryRoundQuery = "INSERT INTO round_history " +
+	"(id, user_id, played_at, ruleset, player, computer, results, action, series) " +
+	"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
+const historyRoundsQuery = "SELECT id, user_id, played_at, ruleset, player, computer, results, action, series " +
+	"FROM round_history WHERE user_id = $1 AND ($2 = '' OR ruleset = $2) AND played_at >= $3 AND played_at < $4 " +
+	"AND (played_at < $5 OR (played_at = $5 AND id < $6)) ORDER BY played_at DESC, id DESC LIMIT $7"
+
+// HistoryStore keeps rounds in the round_history table, with their time in nanoseconds since the epoch
+type HistoryStore struct {
+	DbClient
+}
+
+func NewHistoryStore(dbClient DbClient) HistoryStore {
+	return HistoryStore{dbClient}
+}
+
+func (hs HistoryStore) SaveRound(round *rpslsapi.HistoryRound) error {
+	_, err := hs.db.Exec(saveHistoryRoundQuery, round.ID, round.UserID, round.PlayedAt.UnixNano(), round.Ruleset,
+		round.Player, round.Computer, round.Results, round.Action, round.Series)
+	return err
+}
+
+func (hs HistoryStore) Rounds(query *rpslsapi.HistoryQuery) ([]rpslsapi.HistoryRound, error) {
+	from, to, before, beforeID := query.UnixNanoBounds()
+	rows, err := hs.db.Query(historyRoundsQuery, query.UserID, query.Ruleset, from, to, before, beforeID,
+		query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer CloseDBResource(rows)
+
+	var rounds []rpslsapi.HistoryRound
+	for rows.Next() {
+		var round rpslsapi.HistoryRound
+		var playedAt int64
+		err := rows.Scan(&round.ID, &round.UserID, &playedAt, &round.Ruleset, &round.Player, &round.Computer,
+			&round.Results, &round.Action, &round.Series)
+		if err != nil {
+			return nil, err
+		}
+		round.PlayedAt = time.Unix(0, playedAt).UTC()
+		rounds = append(rounds, round)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rounds, nil
+}
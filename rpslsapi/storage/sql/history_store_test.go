@@ -0,0 +1,42 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestHistoryStore(t *testing.T) {
+	store := NewHistoryStore(newTestDbClient(t))
+	start := time.Date(2021, 6, 1, 12, 0, 0, 123456789, time.UTC)
+	first := rpslsapi.HistoryRound{ID: "b", UserID: "user", PlayedAt: start, Ruleset: "rpsls", Player: "rpsls-rock",
+		Computer: "rpsls-spock", Results: "lose", Action: "vaporizes"}
+	second := rpslsapi.HistoryRound{ID: "a", UserID: "user", PlayedAt: start.Add(time.Hour), Ruleset: "rps",
+		Player: "rps-rock", Computer: "rps-rock", Results: "tie"}
+	// played at the same time as the second round, but sorted before it by ID
+	third := rpslsapi.HistoryRound{ID: "c", UserID: "user", PlayedAt: start.Add(time.Hour), Ruleset: "rpsls",
+		Player: "rpsls-paper", Computer: "rpsls-rock", Results: "win", Action: "covers", Series: "series"}
+	other := rpslsapi.HistoryRound{ID: "d", UserID: "other", PlayedAt: start, Ruleset: "rpsls",
+		Player: "rpsls-rock", Computer: "rpsls-rock", Results: "tie"}
+	for _, round := range []rpslsapi.HistoryRound{first, second, third, other} {
+		round := round
+		require.NoError(t, store.SaveRound(&round))
+	}
+
+	rounds, err := store.Rounds(&rpslsapi.HistoryQuery{UserID: "user", Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.HistoryRound{third, second}, rounds)
+	rounds, err = store.Rounds(&rpslsapi.HistoryQuery{UserID: "user", Before: &second, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.HistoryRound{first}, rounds)
+
+	rounds, err = store.Rounds(&rpslsapi.HistoryQuery{UserID: "user", Ruleset: "rpsls", To: start.Add(time.Hour),
+		Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.HistoryRound{first}, rounds)
+	rounds, err = store.Rounds(&rpslsapi.HistoryQuery{UserID: "user", From: start.Add(time.Hour), Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.HistoryRound{third, second}, rounds)
+}
@@ -0,0 +1,95 @@
+package sql
+
+import (
+	"database/sql"
+
+	"rpsls/rpslsapi"
+)
+
+const allRulesetsQuery = "SELECT id, name FROM rulesets ORDER BY id"
+const rulesetByIdQuery = "SELECT id, name FROM rulesets WHERE id = $1"
+const createRulesetQuery = "INSERT INTO rulesets (id, name) VALUES ($1, $2)"
+const deleteRulesetRulesQuery = "DELETE FROM beats WHERE winner IN (SELECT id FROM choices WHERE ruleset = $1)"
+const deleteRulesetChoicesQuery = "DELETE FROM choices WHERE ruleset = $1"
+const deleteRulesetQuery = "DELETE FROM rulesets WHERE id = $1"
+
+type RulesetStore struct {
+	DbClient
+}
+
+func NewRulesetStore(dbClient DbClient) RulesetStore {
+	return RulesetStore{dbClient}
+}
+
+func (rs RulesetStore) Rulesets() ([]rpslsapi.Ruleset, error) {
+	rows, err := rs.db.Query(allRulesetsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer CloseDBResource(rows)
+
+	var rulesets []rpslsapi.Ruleset
+	for rows.Next() {
+		var ruleset rpslsapi.Ruleset
+		if err := rows.Scan(&ruleset.ID, &ruleset.Name); err != nil {
+			return nil, err
+		}
+		rulesets = append(rulesets, ruleset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rulesets, nil
+}
+
+func (rs RulesetStore) Ruleset(id string) (*rpslsapi.Ruleset, error) {
+	var ruleset rpslsapi.Ruleset
+	err := rs.db.QueryRow(rulesetByIdQuery, id).Scan(&ruleset.ID, &ruleset.Name)
+	if err == sql.ErrNoRows {
+		return nil, rpslsapi.ErrRulesetNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &ruleset, nil
+}
+
+func (rs RulesetStore) CreateRuleset(ruleset *rpslsapi.Ruleset) (*rpslsapi.Ruleset, error) {
+	err := rs.inTransaction(func(tx *sql.Tx) error {
+		if err := checkRulesetExists(tx, ruleset.ID); err == nil {
+			return rpslsapi.ErrRulesetAlreadyExists
+		} else if err != rpslsapi.ErrRulesetNotFound {
+			return err
+		}
+
+		_, err := tx.Exec(createRulesetQuery, ruleset.ID, ruleset.Name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ruleset, nil
+}
+
+func (rs RulesetStore) DeleteRuleset(id string) error {
+	return rs.inTransaction(func(tx *sql.Tx) error {
+		return deleteRuleset(tx, id)
+	})
+}
+
+// deleteRuleset deletes the ruleset along with its choices, their rules and translations, or fails with
+// ErrRulesetNotFound
+func deleteRuleset(tx *sql.Tx, id string) error {
+	if _, err := tx.Exec(deleteRulesetRulesQuery, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(deleteRulesetTranslationsQuery, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(deleteRulesetChoicesQuery, id); err != nil {
+		return err
+	}
+	return runDelete(tx, rpslsapi.ErrRulesetNotFound, deleteRulesetQuery, id)
+}
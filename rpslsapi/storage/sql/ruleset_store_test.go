@@ -0,0 +1,33 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestRulesetStore(t *testing.T) {
+	client := newTestDbClient(t)
+	store := NewRulesetStore(client)
+
+	rulesets, err := store.Rulesets()
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.Ruleset{
+		{ID: "rps", Name: "Rock Paper Scissors"},
+		{ID: "rpsls", Name: "Rock Paper Scissors Lizard Spock"},
+	}, rulesets)
+
+	created, err := store.CreateRuleset(&rpslsapi.Ruleset{ID: "rps-101", Name: "RPS-101"})
+	require.NoError(t, err)
+	require.Equal(t, "rps-101", created.ID)
+	_, err = store.CreateRuleset(&rpslsapi.Ruleset{ID: "rps-101", Name: "RPS-101"})
+	require.Equal(t, rpslsapi.ErrRulesetAlreadyExists, err)
+
+	require.NoError(t, store.DeleteRuleset("rps"))
+	_, err = store.Ruleset("rps")
+	require.Equal(t, rpslsapi.ErrRulesetNotFound, err)
+	_, err = NewChoiceStore(client).Choices("rps")
+	require.Equal(t, rpslsapi.ErrRulesetNotFound, err)
+	require.Equal(t, rpslsapi.ErrRulesetNotFound, store.DeleteRuleset("rps"))
+}
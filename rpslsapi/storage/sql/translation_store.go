@@ -0,0 +1,59 @@
+package sql
+
+import (
+	"database/sql"
+
+	"rpsls/rpslsapi"
+)
+
+const translationsQuery = "SELECT kind, key, language, text FROM translations " +
+	"WHERE $1 = '' OR language = $1 ORDER BY language, kind, key"
+const saveTranslationQuery = "INSERT INTO translations (kind, key, language, text) VALUES ($1, $2, $3, $4) " +
+	"ON CONFLICT (kind, key, language) DO UPDATE SET text = excluded.text"
+const deleteTranslationQuery = "DELETE FROM translations WHERE kind = $1 AND key = $2 AND language = $3"
+const deleteChoiceTranslationsQuery = "DELETE FROM translations WHERE kind = 'choice' AND key = $1"
+const deleteRulesetTranslationsQuery = "DELETE FROM translations WHERE kind = 'choice' " +
+	"AND key IN (SELECT slug FROM choices WHERE ruleset = $1)"
+
+type TranslationStore struct {
+	DbClient
+}
+
+func NewTranslationStore(dbClient DbClient) TranslationStore {
+	return TranslationStore{dbClient}
+}
+
+func (ts TranslationStore) Translations(language string) ([]rpslsapi.Translation, error) {
+	rows, err := ts.db.Query(translationsQuery, language)
+	if err != nil {
+		return nil, err
+	}
+	defer CloseDBResource(rows)
+
+	var translations []rpslsapi.Translation
+	for rows.Next() {
+		var translation rpslsapi.Translation
+		err := rows.Scan(&translation.Kind, &translation.Key, &translation.Language, &translation.Text)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+func (ts TranslationStore) SaveTranslation(translation *rpslsapi.Translation) error {
+	_, err := ts.db.Exec(saveTranslationQuery,
+		translation.Kind, translation.Key, translation.Language, translation.Text)
+	return err
+}
+
+func (ts TranslationStore) DeleteTranslation(kind rpslsapi.TranslationKind, key, language string) error {
+	return ts.inTransaction(func(tx *sql.Tx) error {
+		return runDelete(tx, rpslsapi.ErrTranslationNotFound, deleteTranslationQuery, kind, key, language)
+	})
+}
@@ -0,0 +1,40 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestTranslationStore(t *testing.T) {
+	client := newTestDbClient(t)
+	store := NewTranslationStore(client)
+	translations, err := store.Translations("")
+	require.NoError(t, err)
+	require.Empty(t, translations)
+
+	rock := rpslsapi.Translation{Kind: rpslsapi.ChoiceTranslation, Key: "rps-rock", Language: "pt", Text: "Pedra"}
+	crushes := rpslsapi.Translation{Kind: rpslsapi.VerbTranslation, Key: "crushes", Language: "es", Text: "aplasta"}
+	require.NoError(t, store.SaveTranslation(&rock))
+	require.NoError(t, store.SaveTranslation(&crushes))
+	rock.Text = "Rocha"
+	require.NoError(t, store.SaveTranslation(&rock))
+
+	translations, err = store.Translations("pt")
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.Translation{rock}, translations)
+	translations, err = store.Translations("")
+	require.NoError(t, err)
+	require.Equal(t, []rpslsapi.Translation{crushes, rock}, translations)
+
+	require.NoError(t, store.DeleteTranslation(rpslsapi.VerbTranslation, "crushes", "es"))
+	require.Equal(t, rpslsapi.ErrTranslationNotFound,
+		store.DeleteTranslation(rpslsapi.VerbTranslation, "crushes", "es"))
+
+	// translations of a choice go away with it
+	require.NoError(t, NewRulesetStore(client).DeleteRuleset("rps"))
+	translations, err = store.Translations("")
+	require.NoError(t, err)
+	require.Empty(t, translations)
+}
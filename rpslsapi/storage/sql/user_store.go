@@ -0,0 +1,75 @@
+package sql
+
+import (
+	"database/sql"
+	"time"
+
+	"rpsls/rpslsapi"
+)
+
+const createUserQuery = "INSERT INTO users (id, username, password_hash, role, created_at) VALUES ($1, $2, $3, $4, $5)"
+const userByUsernameQuery = "SELECT id, username, password_hash, role, created_at FROM users WHERE username = $1"
+const usernameTakenQuery = "SELECT id FROM users WHERE username = $1"
+const setRoleQuery = "UPDATE users SET role = $1 WHERE username = $2"
+
+// UserStore keeps users in the users table, their creation time in nanoseconds
+type UserStore struct {
+	DbClient
+}
+
+func NewUserStore(dbClient DbClient) UserStore {
+	return UserStore{dbClient}
+}
+
+func (us UserStore) CreateUser(user *rpslsapi.User) error {
+	return us.inTransaction(func(tx *sql.Tx) error {
+		if err := checkUsernameAvailable(tx, user.Username); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(createUserQuery, user.ID, user.Username, user.PasswordHash, user.Role,
+			user.CreatedAt.UnixNano())
+		return err
+	})
+}
+
+func (us UserStore) UserByUsername(username string) (*rpslsapi.User, error) {
+	var user rpslsapi.User
+	var createdAt int64
+	err := us.db.QueryRow(userByUsernameQuery, username).Scan(&user.ID, &user.Username, &user.PasswordHash,
+		&user.Role, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, rpslsapi.ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	user.CreatedAt = time.Unix(0, createdAt).UTC()
+	return &user, nil
+}
+
+func (us UserStore) SetRole(username string, role rpslsapi.Role) error {
+	result, err := us.db.Exec(setRoleQuery, role, username)
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return rpslsapi.ErrUserNotFound
+	}
+	return nil
+}
+
+// checkUsernameAvailable fails with ErrUsernameTaken if a user already has the username
+func checkUsernameAvailable(tx *sql.Tx, username string) error {
+	var id string
+	err := tx.QueryRow(usernameTakenQuery, username).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return rpslsapi.ErrUsernameTaken
+}
@@ -0,0 +1,31 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"rpsls/rpslsapi"
+)
+
+func TestUserStore(t *testing.T) {
+	store := NewUserStore(newTestDbClient(t))
+	user := &rpslsapi.User{ID: "user", Username: "sheldon", PasswordHash: "hash", Role: rpslsapi.PlayerRole,
+		CreatedAt: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)}
+
+	_, err := store.UserByUsername("sheldon")
+	require.Equal(t, rpslsapi.ErrUserNotFound, err)
+
+	require.NoError(t, store.CreateUser(user))
+	stored, err := store.UserByUsername("sheldon")
+	require.NoError(t, err)
+	require.Equal(t, user, stored)
+
+	require.Equal(t, rpslsapi.ErrUsernameTaken, store.CreateUser(&rpslsapi.User{ID: "other", Username: "sheldon"}))
+
+	require.NoError(t, store.SetRole("sheldon", rpslsapi.AdminRole))
+	stored, err = store.UserByUsername("sheldon")
+	require.NoError(t, err)
+	require.Equal(t, rpslsapi.AdminRole, stored.Role)
+	require.Equal(t, rpslsapi.ErrUserNotFound, store.SetRole("leonard", rpslsapi.AdminRole))
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"fmt"
+
+	"rpsls/rpslsapi"
+	"rpsls/rpslsapi/storage/cache"
+	"rpsls/rpslsapi/storage/memory"
+	"rpsls/rpslsapi/storage/neo4j"
+	"rpsls/rpslsapi/storage/redis"
+	"rpsls/rpslsapi/storage/sql"
+)
+
+// Stores holds the store implementations selected through the DB_DRIVER and CACHE_DRIVER settings. Choices, rules
+// and rounds are read through an in-process outcome matrix cache whatever the database, translations through an
+// in-process translation cache.
+type Stores struct {
+	Choice       rpslsapi.ChoiceStore
+	Round        rpslsapi.RoundStore
+	Ruleset      rpslsapi.RulesetStore
+	Scoreboard   rpslsapi.ScoreboardStore
+	OutcomeCache rpslsapi.OutcomeCache
+	Translation  rpslsapi.TranslationStore
+	Match        rpslsapi.MatchStore
+	Series       rpslsapi.SeriesStore
+	Tournament   rpslsapi.TournamentStore
+	Commitment   rpslsapi.CommitmentStore
+	Strategy     rpslsapi.StrategyStore
+	Bot          rpslsapi.BotStore
+	History      rpslsapi.HistoryStore
+	Audit        rpslsapi.AuditStore
+	Arcade       rpslsapi.ArcadeStore
+	Daily        rpslsapi.DailyStore
+	User         rpslsapi.UserStore
+}
+
+func NewStores() (Stores, func()) {
+	stores, cleanup := NewDatabaseStores()
+
+	switch rpslsapi.Config.Redis.Driver {
+	case "", "redis":
+		client := redis.NewClient()
+		stores.Scoreboard = redis.NewScoreboardStore(client)
+		stores.Match = redis.NewMatchStore(client)
+		stores.Series = redis.NewSeriesStore(client)
+		stores.Tournament = redis.NewTournamentStore(client)
+		stores.Commitment = redis.NewCommitmentStore(client)
+		stores.Strategy = redis.NewStrategyStore(client)
+		stores.Bot = redis.NewBotStore(client)
+		stores.Arcade = redis.NewArcadeStore(client)
+		stores.Daily = redis.NewDailyStore(client)
+	case "memory":
+		stores.Scoreboard = memory.NewScoreboardStore()
+		stores.Match = memory.NewMatchStore()
+		stores.Series = memory.NewSeriesStore()
+		stores.Tournament = memory.NewTournamentStore()
+		stores.Commitment = memory.NewCommitmentStore()
+		stores.Strategy = memory.NewStrategyStore()
+		stores.Bot = memory.NewBotStore()
+		stores.Arcade = memory.NewArcadeStore()
+		stores.Daily = memory.NewDailyStore()
+	default:
+		panic(fmt.Errorf("unknown cache driver %q", rpslsapi.Config.Redis.Driver))
+	}
+
+	return stores, cleanup
+}
+
+// NewDatabaseStores returns the stores selected through the DB_DRIVER setting, leaving the ones of the CACHE_DRIVER
+// unset, for tools that only work on the database
+func NewDatabaseStores() (Stores, func()) {
+	var stores Stores
+	cleanup := func() {}
+
+	switch rpslsapi.Config.DB.Driver {
+	case "", "neo4j":
+		var dbClient neo4j.DbClient
+		dbClient, cleanup = neo4j.NewDbClient()
+		stores.Choice = neo4j.NewChoiceStore(dbClient)
+		stores.Ruleset = neo4j.NewRulesetStore(dbClient)
+		stores.Translation = neo4j.NewTranslationStore(dbClient)
+		stores.History = neo4j.NewHistoryStore(dbClient)
+		stores.Audit = neo4j.NewAuditStore(dbClient)
+		stores.User = neo4j.NewUserStore(dbClient)
+	case "sqlite", "postgres":
+		var dbClient sql.DbClient
+		dbClient, cleanup = sql.NewDbClient()
+		stores.Choice = sql.NewChoiceStore(dbClient)
+		stores.Ruleset = sql.NewRulesetStore(dbClient)
+		stores.Translation = sql.NewTranslationStore(dbClient)
+		stores.History = sql.NewHistoryStore(dbClient)
+		stores.Audit = sql.NewAuditStore(dbClient)
+		stores.User = sql.NewUserStore(dbClient)
+	case "memory":
+		db := memory.NewDB()
+		stores.Choice = memory.NewChoiceStore(db)
+		stores.Ruleset = memory.NewRulesetStore(db)
+		stores.Translation = memory.NewTranslationStore(db)
+		stores.History = memory.NewHistoryStore(db)
+		stores.Audit = memory.NewAuditStore(db)
+		stores.User = memory.NewUserStore(db)
+	default:
+		panic(fmt.Errorf("unknown DB driver %q", rpslsapi.Config.DB.Driver))
+	}
+
+	outcomeCache := cache.NewOutcomeMatrixCache(stores.Choice, rpslsapi.Config.OutcomeCacheTTL)
+	stores.Choice = outcomeCache.ChoiceStore()
+	stores.Round = outcomeCache.RoundStore()
+	stores.Ruleset = outcomeCache.RulesetStore(stores.Ruleset)
+	stores.OutcomeCache = outcomeCache
+	translationCache := cache.NewTranslationCache(stores.Translation, rpslsapi.Config.OutcomeCacheTTL)
+	outcomeCache.AttachTranslations(translationCache)
+	stores.Translation = translationCache
+
+	return stores, cleanup
+}
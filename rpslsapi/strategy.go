@@ -0,0 +1,291 @@
+package rpslsapi
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrStrategyNotFound = errors.New("strategy not found")
+
+// defaultStrategy is played by users who didn't pick one, keeping the computer unpredictable
+const defaultStrategy = "random"
+
+// StrategyInput is what strategies know when picking the computer choice of a round
+type StrategyInput struct {
+	Choices []Choice
+	// Rules are the BEATS relationships between the choices
+	Rules []Rule
+	// History holds the previous rounds of the player in the ruleset, most recent first
+	History    []RoundResults
+	Randomizer RandomizerService
+}
+
+// ComputerStrategy picks the choice the computer plays
+type ComputerStrategy interface {
+	Choose(input *StrategyInput) (*Choice, error)
+}
+
+// ComputerMove is the computer choice of a round along with what it was picked from, as recorded in round audits
+type ComputerMove struct {
+	Choice   *Choice `json:"choice"`
+	Strategy string  `json:"strategy"`
+	// Choices is the snapshot of the choices of the ruleset the computer choice was picked among
+	Choices []Choice `json:"choices"`
+	// RandomValues holds the raw values drawn from the randomizer while picking, in order
+	RandomValues []int `json:"random_values"`
+}
+
+// StrategyInfo describes a strategy. Built-in ones can be selected by name or by difficulty level, bots by name.
+type StrategyInfo struct {
+	Name        string `json:"name"`
+	Difficulty  string `json:"difficulty,omitempty"`
+	Description string `json:"description"`
+	Bot         bool   `json:"bot,omitempty"`
+	strategy    ComputerStrategy
+	// needsRules and needsHistory tell whether the strategy reads the rules and the history of its input, which are
+	// only loaded for the strategies that do
+	needsRules   bool
+	needsHistory bool
+}
+
+// builtinStrategies returns the built-in strategies, the Markov one looking for the last RPSLS_MARKOV_ORDER moves of
+// the player in their history
+func builtinStrategies() []StrategyInfo {
+	return []StrategyInfo{
+		{
+			Name:        "random",
+			Difficulty:  "easy",
+			Description: "Plays every choice with the same probability",
+			strategy:    RandomStrategy{},
+		},
+		{
+			Name:         "frequency",
+			Difficulty:   "medium",
+			Description:  "Counters the choices the player plays the most",
+			strategy:     FrequencyStrategy{},
+			needsRules:   true,
+			needsHistory: true,
+		},
+		{
+			Name:         "win-stay-lose-shift",
+			Difficulty:   "hard",
+			Description:  "Expects the player to repeat a winning choice and to switch to a counter of what beat them",
+			strategy:     WinStayLoseShiftStrategy{},
+			needsRules:   true,
+			needsHistory: true,
+		},
+		{
+			Name:         "markov",
+			Difficulty:   "expert",
+			Description:  "Counters what the player played after their last moves in the past",
+			strategy:     MarkovStrategy{Order: Config.MarkovOrder},
+			needsRules:   true,
+			needsHistory: true,
+		},
+	}
+}
+
+// StrategyService picks the computer choices of the rounds played by users, with the strategy selected for the round
+// or, if none is, with the default strategy of the user. Strategies learn from the scoreboard of the user.
+type StrategyService interface {
+	// Strategies lists the built-in strategies followed by the registered bots
+	Strategies() ([]StrategyInfo, error)
+	// Strategy returns the built-in strategy with the name or difficulty, or else the bot with the name
+	Strategy(nameOrDifficulty string) (*StrategyInfo, error)
+	// ComputerChoice picks the computer choice of a round of the ruleset, the strategy being a name or a difficulty
+	ComputerChoice(userID, ruleset, strategy string) (*ComputerMove, error)
+	DefaultStrategy(userID string) (*StrategyInfo, error)
+	SetDefaultStrategy(userID, strategy string) (*StrategyInfo, error)
+}
+
+type StrategyStore interface {
+	// DefaultStrategy returns the name of the strategy picked by the user, or an empty string if there is none
+	DefaultStrategy(userID string) (string, error)
+	SetDefaultStrategy(userID, name string) error
+}
+
+type StrategyServiceImpl struct {
+	strategyStore     StrategyStore
+	botStore          BotStore
+	botClient         BotClient
+	botTimeout        time.Duration
+	choiceService     ChoiceService
+	scoreboardService ScoreboardService
+	randomizer        RandomizerService
+}
+
+func NewStrategyService(strategyStore StrategyStore, botStore BotStore, botClient BotClient,
+	choiceService ChoiceService, scoreboardService ScoreboardService, randomizer RandomizerService) StrategyService {
+	return StrategyServiceImpl{
+		strategyStore:     strategyStore,
+		botStore:          botStore,
+		botClient:         botClient,
+		botTimeout:        Config.BotTimeout,
+		choiceService:     choiceService,
+		scoreboardService: scoreboardService,
+		randomizer:        randomizer,
+	}
+}
+
+func (ss StrategyServiceImpl) Strategies() ([]StrategyInfo, error) {
+	bots, err := ss.botStore.Bots()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := builtinStrategies()
+	for i := range bots {
+		infos = append(infos, *ss.botStrategy(&bots[i]))
+	}
+	return infos, nil
+}
+
+func (ss StrategyServiceImpl) Strategy(nameOrDifficulty string) (*StrategyInfo, error) {
+	if info, err := findStrategy(nameOrDifficulty); err == nil {
+		return info, nil
+	}
+
+	bot, err := ss.botStore.Bot(strings.ToLower(nameOrDifficulty))
+	if err == ErrBotNotFound {
+		return nil, ErrStrategyNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return ss.botStrategy(bot), nil
+}
+
+func (ss StrategyServiceImpl) ComputerChoice(userID, ruleset, strategy string) (*ComputerMove, error) {
+	var info *StrategyInfo
+	var err error
+	if strategy == "" {
+		info, err = ss.DefaultStrategy(userID)
+	} else {
+		info, err = ss.Strategy(strategy)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ruleset = RulesetOrDefault(ruleset)
+	choices, err := ss.choiceService.Choices(ruleset)
+	if err != nil {
+		return nil, err
+	}
+	if len(choices) == 0 {
+		return nil, ErrChoiceNotFound
+	}
+	var rules []Rule
+	if info.needsRules {
+		rules, err = ss.choiceService.Rules(ruleset)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// anonymous players have no scoreboard to learn from
+	var scoreboard []RoundResults
+	if info.needsHistory && userID != "" {
+		scoreboard, err = ss.scoreboardService.Scoreboard(userID, ruleset)
+		if err != nil {
+			log.Info().Err(err).Msg("failed to load the history of the player, playing without it")
+		}
+	}
+
+	randomizer := &recordingRandomizer{randomizer: ss.randomizer, values: []int{}}
+	choice, err := info.strategy.Choose(&StrategyInput{
+		Choices:    choices,
+		Rules:      rules,
+		History:    playerRounds(scoreboard),
+		Randomizer: randomizer,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ComputerMove{Choice: choice, Strategy: info.Name, Choices: choices, RandomValues: randomizer.values}, nil
+}
+
+// recordingRandomizer keeps the values drawn from a randomizer, so they can be audited
+type recordingRandomizer struct {
+	randomizer RandomizerService
+	values     []int
+}
+
+func (rr *recordingRandomizer) RandomInt() (int, error) {
+	value, err := rr.randomizer.RandomInt()
+	if err != nil {
+		return 0, err
+	}
+	rr.values = append(rr.values, value)
+	return value, nil
+}
+
+func (ss StrategyServiceImpl) DefaultStrategy(userID string) (*StrategyInfo, error) {
+	// anonymous players can't pick a default strategy
+	if userID == "" {
+		return findStrategy(defaultStrategy)
+	}
+	name, err := ss.strategyStore.DefaultStrategy(userID)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return findStrategy(defaultStrategy)
+	}
+
+	info, err := ss.Strategy(name)
+	if err == ErrStrategyNotFound {
+		log.Info().Str("strategy", name).Msg("default strategy of the user is gone, playing the default one")
+		return findStrategy(defaultStrategy)
+	}
+	return info, err
+}
+
+func (ss StrategyServiceImpl) SetDefaultStrategy(userID, strategy string) (*StrategyInfo, error) {
+	info, err := ss.Strategy(strategy)
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.strategyStore.SetDefaultStrategy(userID, info.Name); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (ss StrategyServiceImpl) botStrategy(bot *Bot) *StrategyInfo {
+	return &StrategyInfo{
+		Name:         bot.Name,
+		Description:  bot.Description,
+		Bot:          true,
+		strategy:     BotStrategy{Bot: bot, Client: ss.botClient, Timeout: ss.botTimeout},
+		needsHistory: true,
+	}
+}
+
+// findStrategy returns the built-in strategy with the name or difficulty level
+func findStrategy(nameOrDifficulty string) (*StrategyInfo, error) {
+	strategies := builtinStrategies()
+	for i := range strategies {
+		if strings.EqualFold(strategies[i].Name, nameOrDifficulty) ||
+			strings.EqualFold(strategies[i].Difficulty, nameOrDifficulty) {
+			return &strategies[i], nil
+		}
+	}
+	return nil, ErrStrategyNotFound
+}
+
+// playerRounds returns the rounds recorded in a scoreboard, most recent first, replacing series by their rounds
+func playerRounds(scoreboard []RoundResults) []RoundResults {
+	rounds := []RoundResults{}
+	for _, entry := range scoreboard {
+		if entry.Series == "" {
+			rounds = append(rounds, entry)
+			continue
+		}
+		for i := len(entry.Rounds) - 1; i >= 0; i-- {
+			rounds = append(rounds, entry.Rounds[i])
+		}
+	}
+	return rounds
+}
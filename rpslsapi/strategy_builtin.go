@@ -0,0 +1,145 @@
+package rpslsapi
+
+// RandomStrategy plays uniformly at random, ignoring the history of the player
+type RandomStrategy struct{}
+
+// FrequencyStrategy expects the player to play their choices as often as they did before
+type FrequencyStrategy struct{}
+
+// MarkovStrategy expects the player to follow their last Order moves the way they did before. It looks for shorter
+// sequences when the last ones were never followed, down to counting every choice like FrequencyStrategy.
+type MarkovStrategy struct {
+	Order int
+}
+
+// WinStayLoseShiftStrategy expects the player to play again a choice that won, and to switch to one that beats the
+// computer choice that beat them. After a tie, any other choice is expected.
+type WinStayLoseShiftStrategy struct{}
+
+func (RandomStrategy) Choose(input *StrategyInput) (*Choice, error) {
+	return pickRandom(input.Choices, input.Randomizer)
+}
+
+func (FrequencyStrategy) Choose(input *StrategyInput) (*Choice, error) {
+	predicted := map[string]float64{}
+	for _, round := range input.History {
+		predicted[round.Player]++
+	}
+	return counter(input, predicted)
+}
+
+func (ms MarkovStrategy) Choose(input *StrategyInput) (*Choice, error) {
+	moves := make([]string, len(input.History))
+	for i, round := range input.History {
+		moves[len(moves)-1-i] = round.Player
+	}
+
+	for order := ms.Order; order > 0; order-- {
+		if order >= len(moves) {
+			continue
+		}
+		last := moves[len(moves)-order:]
+		predicted := map[string]float64{}
+		for next := order; next < len(moves); next++ {
+			if sameMoves(moves[next-order:next], last) {
+				predicted[moves[next]]++
+			}
+		}
+		if len(predicted) > 0 {
+			return counter(input, predicted)
+		}
+	}
+	return FrequencyStrategy{}.Choose(input)
+}
+
+func (WinStayLoseShiftStrategy) Choose(input *StrategyInput) (*Choice, error) {
+	if len(input.History) == 0 {
+		return pickRandom(input.Choices, input.Randomizer)
+	}
+
+	last := input.History[0]
+	predicted := map[string]float64{}
+	switch ResultsLabel(last.Results) {
+	case Win:
+		predicted[last.Player] = 1
+	case Lose:
+		beats := beatsByWinner(input.Rules)
+		for _, choice := range input.Choices {
+			if beats[choice.ID][last.Computer] {
+				predicted[choice.ID] = 1
+			}
+		}
+	default:
+		for _, choice := range input.Choices {
+			if choice.ID != last.Player {
+				predicted[choice.ID] = 1
+			}
+		}
+	}
+	return counter(input, predicted)
+}
+
+// counter picks the choice that scores best against the predicted choices of the player, weighted by how likely they
+// are, breaking ties at random. It picks any choice if nothing is predicted.
+func counter(input *StrategyInput, predicted map[string]float64) (*Choice, error) {
+	beats := beatsByWinner(input.Rules)
+	var best []Choice
+	var bestScore float64
+	for _, choice := range input.Choices {
+		score := 0.0
+		for playerChoiceID, weight := range predicted {
+			if beats[choice.ID][playerChoiceID] {
+				score += weight
+			} else if beats[playerChoiceID][choice.ID] {
+				score -= weight
+			}
+		}
+		if len(best) == 0 || score > bestScore {
+			best, bestScore = []Choice{choice}, score
+		} else if score == bestScore {
+			best = append(best, choice)
+		}
+	}
+	return pickRandom(best, input.Randomizer)
+}
+
+// pickRandom picks a choice uniformly with the randomizers drawing indexes, like the seeded ones of simulations, and
+// with a value of RandomInt modulo the number of choices otherwise, so that rounds can be replayed from their audit
+func pickRandom(choices []Choice, randomizer RandomizerService) (*Choice, error) {
+	if len(choices) == 0 {
+		return nil, ErrChoiceNotFound
+	}
+	if indexRandomizer, ok := randomizer.(IndexRandomizer); ok {
+		index, err := indexRandomizer.RandomIndex(len(choices))
+		if err != nil {
+			return nil, err
+		}
+		return &choices[index], nil
+	}
+	randomInt, err := randomizer.RandomInt()
+	if err != nil {
+		return nil, err
+	}
+	return &choices[randomInt%len(choices)], nil
+}
+
+// beatsByWinner indexes the rules by winner then loser
+func beatsByWinner(rules []Rule) map[string]map[string]bool {
+	beats := map[string]map[string]bool{}
+	for _, rule := range rules {
+		if beats[rule.WinnerID] == nil {
+			beats[rule.WinnerID] = map[string]bool{}
+		}
+		beats[rule.WinnerID][rule.LoserID] = true
+	}
+	return beats
+}
+
+func sameMoves(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
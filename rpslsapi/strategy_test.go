@@ -0,0 +1,340 @@
+package rpslsapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type StrategyServiceMock struct {
+	mock.Mock
+}
+
+type StrategyStoreMock struct {
+	mock.Mock
+}
+
+func (ssm *StrategyServiceMock) Strategies() ([]StrategyInfo, error) {
+	args := ssm.Called()
+	return args.Get(0).([]StrategyInfo), args.Error(1)
+}
+
+func (ssm *StrategyServiceMock) Strategy(nameOrDifficulty string) (*StrategyInfo, error) {
+	args := ssm.Called(nameOrDifficulty)
+	return args.Get(0).(*StrategyInfo), args.Error(1)
+}
+
+func (ssm *StrategyServiceMock) ComputerChoice(userID, ruleset, strategy string) (*ComputerMove, error) {
+	args := ssm.Called(userID, ruleset, strategy)
+	return args.Get(0).(*ComputerMove), args.Error(1)
+}
+
+func (ssm *StrategyServiceMock) DefaultStrategy(userID string) (*StrategyInfo, error) {
+	args := ssm.Called(userID)
+	return args.Get(0).(*StrategyInfo), args.Error(1)
+}
+
+func (ssm *StrategyServiceMock) SetDefaultStrategy(userID, strategy string) (*StrategyInfo, error) {
+	args := ssm.Called(userID, strategy)
+	return args.Get(0).(*StrategyInfo), args.Error(1)
+}
+
+func (ssm *StrategyStoreMock) DefaultStrategy(userID string) (string, error) {
+	args := ssm.Called(userID)
+	return args.String(0), args.Error(1)
+}
+
+func (ssm *StrategyStoreMock) SetDefaultStrategy(userID, name string) error {
+	args := ssm.Called(userID, name)
+	return args.Error(0)
+}
+
+// playedRounds returns the history of a player who played the choices in order, with the given results
+func playedRounds(results ResultsLabel, computerChoiceID string, playerChoiceIDs ...string) []RoundResults {
+	history := make([]RoundResults, len(playerChoiceIDs))
+	for i, id := range playerChoiceIDs {
+		history[len(history)-1-i] = RoundResults{Results: string(results), Player: id, Computer: computerChoiceID}
+	}
+	return history
+}
+
+func TestComputerStrategy_Choose(t *testing.T) {
+	testCases := []struct {
+		name             string
+		strategy         ComputerStrategy
+		history          []RoundResults
+		randomInt        int
+		expectedChoiceID string
+	}{
+		{
+			name:             "random: pick the choice drawn by the randomizer",
+			strategy:         RandomStrategy{},
+			history:          playedRounds(Win, "rpsls-scissors", "rpsls-rock"),
+			randomInt:        7,
+			expectedChoiceID: "rpsls-scissors",
+		},
+		{
+			name:             "frequency: counter the choices played the most",
+			strategy:         FrequencyStrategy{},
+			history:          playedRounds(Tie, "rpsls-rock", "rpsls-rock", "rpsls-paper", "rpsls-rock"),
+			expectedChoiceID: "rpsls-paper",
+		},
+		{
+			name:             "frequency: play at random without history",
+			strategy:         FrequencyStrategy{},
+			randomInt:        7,
+			expectedChoiceID: "rpsls-scissors",
+		},
+		{
+			name:     "markov: counter what followed the last moves, breaking ties at random",
+			strategy: MarkovStrategy{Order: 2},
+			history: playedRounds(Tie, "rpsls-rock", "rpsls-rock", "rpsls-paper", "rpsls-scissors", "rpsls-rock",
+				"rpsls-paper"),
+			randomInt:        1,
+			expectedChoiceID: "rpsls-spock",
+		},
+		{
+			name:             "markov: fall back to the frequency of the choices if the last moves never occurred",
+			strategy:         MarkovStrategy{Order: 2},
+			history:          playedRounds(Tie, "rpsls-rock", "rpsls-rock", "rpsls-paper", "rpsls-scissors"),
+			expectedChoiceID: "rpsls-spock",
+		},
+		{
+			name:             "win-stay-lose-shift: expect a winning choice to be played again",
+			strategy:         WinStayLoseShiftStrategy{},
+			history:          playedRounds(Win, "rpsls-scissors", "rpsls-rock"),
+			expectedChoiceID: "rpsls-paper",
+		},
+		{
+			name:             "win-stay-lose-shift: expect a switch to the choices beating the winning one",
+			strategy:         WinStayLoseShiftStrategy{},
+			history:          playedRounds(Lose, "rpsls-paper", "rpsls-rock"),
+			expectedChoiceID: "rpsls-rock",
+		},
+		{
+			name:             "win-stay-lose-shift: expect a switch to any other choice after a tie",
+			strategy:         WinStayLoseShiftStrategy{},
+			history:          playedRounds(Tie, "rpsls-rock", "rpsls-rock"),
+			expectedChoiceID: "rpsls-scissors",
+		},
+	}
+
+	for _, tc := range testCases {
+		randomizerMock := RandomizerMock{}
+		randomizerMock.On("RandomInt").Return(tc.randomInt, nil)
+
+		choice, err := tc.strategy.Choose(&StrategyInput{Choices: baseChoices, Rules: baseRules,
+			History: tc.history, Randomizer: &randomizerMock})
+
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedChoiceID, choice.ID, tc.name)
+	}
+}
+
+func TestStrategyService_ComputerChoice(t *testing.T) {
+	seriesEntry := RoundResults{Results: string(Win), Series: "series",
+		Rounds: playedRounds(Win, "rpsls-scissors", "rpsls-rock", "rpsls-rock")}
+
+	testCases := []struct {
+		name             string
+		userID           string
+		strategy         string
+		defaultStrategy  string
+		scoreboard       []RoundResults
+		scoreboardError  error
+		expectedChoiceID string
+		expectedStrategy string
+		// expectedRules and expectedHistory tell whether the rules and the scoreboard are loaded for the strategy
+		expectedRules   bool
+		expectedHistory bool
+		// expectedRandomValues are the values drawn by the strategy, the randomizer always returning 8
+		expectedRandomValues []int
+		expectedError        error
+	}{
+		{
+			name:                 "success: play the strategy selected by difficulty, learning from the series rounds",
+			userID:               "user",
+			strategy:             "medium",
+			scoreboard:           []RoundResults{seriesEntry},
+			expectedChoiceID:     "rpsls-paper",
+			expectedStrategy:     "frequency",
+			expectedRandomValues: []int{8},
+			expectedRules:        true,
+			expectedHistory:      true,
+		},
+		{
+			name:                 "success: play the default strategy of the user if none is selected",
+			userID:               "user",
+			defaultStrategy:      "frequency",
+			scoreboard:           playedRounds(Win, "rpsls-scissors", "rpsls-rock"),
+			expectedChoiceID:     "rpsls-paper",
+			expectedStrategy:     "frequency",
+			expectedRandomValues: []int{8},
+			expectedRules:        true,
+			expectedHistory:      true,
+		},
+		{
+			name:                 "success: play at random, loading nothing else, if the user has no default strategy",
+			userID:               "user",
+			scoreboard:           playedRounds(Win, "rpsls-scissors", "rpsls-rock"),
+			expectedChoiceID:     "rpsls-lizard",
+			expectedStrategy:     "random",
+			expectedRandomValues: []int{8},
+		},
+		{
+			name:                 "success: play without history if the scoreboard can't be read",
+			userID:               "user",
+			strategy:             "frequency",
+			scoreboardError:      unknownDBError,
+			expectedChoiceID:     "rpsls-lizard",
+			expectedStrategy:     "frequency",
+			expectedRandomValues: []int{8},
+			expectedRules:        true,
+			expectedHistory:      true,
+		},
+		{
+			name:                 "success: ask the bot with the name",
+			userID:               "user",
+			strategy:             "Stub",
+			expectedChoiceID:     "rpsls-spock",
+			expectedStrategy:     "stub",
+			expectedRandomValues: []int{},
+			expectedHistory:      true,
+		},
+		{
+			name:                 "success: play at random if the default strategy of the user is a deleted bot",
+			userID:               "user",
+			defaultStrategy:      "deleted",
+			scoreboard:           playedRounds(Win, "rpsls-scissors", "rpsls-rock"),
+			expectedChoiceID:     "rpsls-lizard",
+			expectedStrategy:     "random",
+			expectedRandomValues: []int{8},
+		},
+		{
+			name:                 "success: play without history for anonymous players",
+			userID:               "",
+			strategy:             "frequency",
+			expectedChoiceID:     "rpsls-lizard",
+			expectedStrategy:     "frequency",
+			expectedRandomValues: []int{8},
+			expectedRules:        true,
+		},
+		{
+			name:          "failure: if the strategy is unknown, return ErrStrategyNotFound",
+			userID:        "user",
+			strategy:      "impossible",
+			expectedError: ErrStrategyNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := StrategyStoreMock{}
+		botStoreMock := BotStoreMock{}
+		botClientMock := BotClientMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		scoreboardMock := ScoreboardServiceMock{}
+		randomizerMock := RandomizerMock{}
+		service := NewStrategyService(&storeMock, &botStoreMock, &botClientMock, &choiceServiceMock, &scoreboardMock,
+			&randomizerMock)
+		storeMock.On("DefaultStrategy", "user").Return(tc.defaultStrategy, nil)
+		botStoreMock.On("Bot", "stub").Return(stubBot, nil)
+		botStoreMock.On("Bot", mock.Anything).Return((*Bot)(nil), ErrBotNotFound)
+		botClientMock.On("Choose", stubBot, mock.Anything).Return(&BotResponse{Choice: "rpsls-spock"}, nil)
+		choiceServiceMock.On("Choices", "rpsls").Return(baseChoices, nil)
+		choiceServiceMock.On("Rules", "rpsls").Return(baseRules, nil)
+		scoreboardMock.On("Scoreboard", "user", "rpsls").Return(tc.scoreboard, tc.scoreboardError)
+		randomizerMock.On("RandomInt").Return(8, nil)
+
+		move, err := service.ComputerChoice(tc.userID, "rpsls", tc.strategy)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedChoiceID, move.Choice.ID, tc.name)
+		require.Equal(t, tc.expectedStrategy, move.Strategy, tc.name)
+		require.Equal(t, tc.expectedRandomValues, move.RandomValues, tc.name)
+		require.Equal(t, baseChoices, move.Choices, tc.name)
+		if !tc.expectedRules {
+			choiceServiceMock.AssertNotCalled(t, "Rules", mock.Anything)
+		}
+		if !tc.expectedHistory {
+			scoreboardMock.AssertNotCalled(t, "Scoreboard", mock.Anything, mock.Anything)
+		}
+	}
+}
+
+func TestStrategyService_Strategy_MarkovOrder(t *testing.T) {
+	defer func() { Config.MarkovOrder = 0 }()
+	Config.MarkovOrder = 3
+	service := NewStrategyService(&StrategyStoreMock{}, &BotStoreMock{}, &BotClientMock{}, &ChoiceServiceMock{},
+		&ScoreboardServiceMock{}, &RandomizerMock{})
+
+	info, err := service.Strategy("expert")
+
+	require.NoError(t, err)
+	require.Equal(t, MarkovStrategy{Order: 3}, info.strategy)
+}
+
+func TestStrategyService_SetDefaultStrategy(t *testing.T) {
+	testCases := []struct {
+		name          string
+		strategy      string
+		storeError    error
+		expectedName  string
+		expectedError error
+	}{
+		{
+			name:         "success: store the name of the strategy selected by difficulty",
+			strategy:     "Expert",
+			expectedName: "markov",
+		},
+		{
+			name:         "success: store the strategy selected by name",
+			strategy:     "win-stay-lose-shift",
+			expectedName: "win-stay-lose-shift",
+		},
+		{
+			name:         "success: store the bot selected by name",
+			strategy:     "stub",
+			expectedName: "stub",
+		},
+		{
+			name:          "failure: if the strategy is unknown, return ErrStrategyNotFound",
+			strategy:      "impossible",
+			expectedError: ErrStrategyNotFound,
+		},
+		{
+			name:          "failure: if the store fails, return its error",
+			strategy:      "easy",
+			storeError:    errors.New("unknown store error"),
+			expectedError: errors.New("unknown store error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := StrategyStoreMock{}
+		botStoreMock := BotStoreMock{}
+		service := NewStrategyService(&storeMock, &botStoreMock, &BotClientMock{}, &ChoiceServiceMock{},
+			&ScoreboardServiceMock{}, &RandomizerMock{})
+		storeMock.On("SetDefaultStrategy", "user", mock.Anything).Return(tc.storeError)
+		botStoreMock.On("Bot", "stub").Return(stubBot, nil)
+		botStoreMock.On("Bot", mock.Anything).Return((*Bot)(nil), ErrBotNotFound)
+
+		info, err := service.SetDefaultStrategy("user", tc.strategy)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			if tc.storeError == nil {
+				storeMock.AssertNotCalled(t, "SetDefaultStrategy", mock.Anything, mock.Anything)
+			}
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedName, info.Name, tc.name)
+		storeMock.AssertCalled(t, "SetDefaultStrategy", "user", tc.expectedName)
+	}
+}
@@ -0,0 +1,374 @@
+package rpslsapi
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrTournamentNotFound = errors.New("tournament not found")
+var ErrInvalidTournament = errors.New("invalid tournament")
+var ErrTournamentClosed = errors.New("tournament does not accept this request in its current state")
+var ErrNotEnoughPlayers = errors.New("tournament needs at least two players")
+var ErrPlayerNameTaken = errors.New("player name already taken")
+var ErrNothingToPlay = errors.New("no pairing left to play in the current round")
+var ErrNotTournamentPlayer = errors.New("not a player of the tournament")
+var ErrNotTournamentOrganizer = errors.New("not the organizer of the tournament")
+
+// tournamentRetention is how long tournaments are kept after being created
+const tournamentRetention = 30 * 24 * time.Hour
+
+const maxTournamentPlayers = 128
+const maxPlayerNameLength = 50
+
+type TournamentFormat string
+
+const (
+	// RoundRobin tournaments pair every player with every other once, ranking them by points
+	RoundRobin TournamentFormat = "round_robin"
+	// SingleElimination tournaments knock losers out of a bracket until a single player is left
+	SingleElimination TournamentFormat = "single_elimination"
+)
+
+type TournamentStatus string
+
+const (
+	// TournamentRegistration tournaments accept players until the organizer starts them
+	TournamentRegistration TournamentStatus = "registration"
+	TournamentRunning      TournamentStatus = "running"
+	TournamentFinished     TournamentStatus = "finished"
+)
+
+type TournamentSettings struct {
+	Name    string           `json:"name"`
+	Ruleset string           `json:"ruleset"`
+	Format  TournamentFormat `json:"format"`
+}
+
+// TournamentPlayer is a registered player. Seeds follow the registration order, and Token authenticates the moves of
+// the player, only being shown to them.
+type TournamentPlayer struct {
+	Name  string `json:"name"`
+	Seed  int    `json:"seed"`
+	Token string `json:"token,omitempty"`
+}
+
+type TournamentRound struct {
+	Number   int                 `json:"number"`
+	Pairings []TournamentPairing `json:"pairings"`
+}
+
+// TournamentPairing is a game between two players of a round, or a bye when Away is empty. The choices stay hidden
+// from the opponent until the pairing is decided, and Winner is empty on draws.
+type TournamentPairing struct {
+	Home       string  `json:"home"`
+	Away       string  `json:"away,omitempty"`
+	HomeMoved  bool    `json:"home_moved"`
+	AwayMoved  bool    `json:"away_moved"`
+	HomeChoice *Choice `json:"home_choice,omitempty"`
+	AwayChoice *Choice `json:"away_choice,omitempty"`
+	// Ties counts the tied games replayed to decide an elimination pairing
+	Ties        int    `json:"ties,omitempty"`
+	Decided     bool   `json:"decided"`
+	Winner      string `json:"winner,omitempty"`
+	Bye         bool   `json:"bye,omitempty"`
+	Forfeit     bool   `json:"forfeit,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type TournamentStanding struct {
+	Rank   int    `json:"rank"`
+	Player string `json:"player"`
+	Played int    `json:"played"`
+	Wins   int    `json:"wins"`
+	Draws  int    `json:"draws"`
+	Losses int    `json:"losses"`
+	// Points are only awarded in round-robin tournaments
+	Points     int  `json:"points,omitempty"`
+	Eliminated bool `json:"eliminated,omitempty"`
+}
+
+// Tournament holds the players, the scheduled rounds and the standings of a tournament. CurrentRound is the number of
+// the round being played, and OrganizerToken authenticates the requests of the organizer, only being shown to them.
+type Tournament struct {
+	ID             string               `json:"id"`
+	Name           string               `json:"name"`
+	Ruleset        string               `json:"ruleset"`
+	Format         TournamentFormat     `json:"format"`
+	Status         TournamentStatus     `json:"status"`
+	OrganizerToken string               `json:"organizer_token,omitempty"`
+	Players        []TournamentPlayer   `json:"players"`
+	Rounds         []TournamentRound    `json:"rounds"`
+	CurrentRound   int                  `json:"current_round"`
+	Champion       string               `json:"champion,omitempty"`
+	Standings      []TournamentStanding `json:"standings"`
+}
+
+type TournamentService interface {
+	// CreateTournament opens the registration of a tournament, returning it with the token of the organizer
+	CreateTournament(settings *TournamentSettings) (*Tournament, error)
+	// Tournament returns the tournament as seen by the organizer or player with the token, which may be empty
+	Tournament(id, token string) (*Tournament, error)
+	// JoinTournament registers a player, returning the tournament with the token of the player
+	JoinTournament(id, name string) (*Tournament, error)
+	// Move submits the choice of the player with the token for their pairing of the current round
+	Move(id, token, choiceID string) (*Tournament, error)
+	// Advance starts the tournament, or closes the current round and schedules the next one. Only the organizer
+	// may advance a tournament.
+	Advance(id, token string) (*Tournament, error)
+}
+
+type TournamentStore interface {
+	// CreateTournament stores a new tournament, which is dropped once the retention has passed
+	CreateTournament(tournament *Tournament, retention time.Duration) error
+	// Tournament returns the tournament or ErrTournamentNotFound
+	Tournament(id string) (*Tournament, error)
+	UpdateTournament(id string, update func(tournament *Tournament) error) (*Tournament, error)
+}
+
+type TournamentServiceImpl struct {
+	tournamentStore TournamentStore
+	roundStore      RoundStore
+	choiceService   ChoiceService
+}
+
+func NewTournamentService(tournamentStore TournamentStore, roundStore RoundStore,
+	choiceService ChoiceService) TournamentService {
+	return TournamentServiceImpl{tournamentStore: tournamentStore, roundStore: roundStore, choiceService: choiceService}
+}
+
+func (ts TournamentServiceImpl) CreateTournament(settings *TournamentSettings) (*Tournament, error) {
+	name := strings.TrimSpace(settings.Name)
+	if name == "" || (settings.Format != RoundRobin && settings.Format != SingleElimination) {
+		return nil, ErrInvalidTournament
+	}
+	ruleset := RulesetOrDefault(settings.Ruleset)
+	if _, err := ts.choiceService.Choices(ruleset); err != nil {
+		return nil, err
+	}
+
+	tournament := &Tournament{
+		ID:             newToken(8),
+		Name:           name,
+		Ruleset:        ruleset,
+		Format:         settings.Format,
+		Status:         TournamentRegistration,
+		OrganizerToken: newToken(16),
+		Players:        []TournamentPlayer{},
+		Rounds:         []TournamentRound{},
+		Standings:      []TournamentStanding{},
+	}
+	if err := ts.tournamentStore.CreateTournament(tournament, tournamentRetention); err != nil {
+		return nil, err
+	}
+	return tournament.viewFor(tournament.OrganizerToken), nil
+}
+
+func (ts TournamentServiceImpl) Tournament(id, token string) (*Tournament, error) {
+	tournament, err := ts.tournamentStore.Tournament(id)
+	if err != nil {
+		return nil, err
+	}
+	return tournament.viewFor(token), nil
+}
+
+func (ts TournamentServiceImpl) JoinTournament(id, name string) (*Tournament, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxPlayerNameLength {
+		return nil, ErrInvalidTournament
+	}
+
+	token := newToken(16)
+	tournament, err := ts.tournamentStore.UpdateTournament(id, func(tournament *Tournament) error {
+		switch {
+		case tournament.Status != TournamentRegistration || len(tournament.Players) >= maxTournamentPlayers:
+			return ErrTournamentClosed
+		case tournament.playerNamed(name) != nil:
+			return ErrPlayerNameTaken
+		}
+
+		tournament.Players = append(tournament.Players,
+			TournamentPlayer{Name: name, Seed: len(tournament.Players) + 1, Token: token})
+		tournament.Standings = tournament.standings()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tournament.viewFor(token), nil
+}
+
+func (ts TournamentServiceImpl) Move(id, token, choiceID string) (*Tournament, error) {
+	choice, err := ts.choiceService.Choice(choiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	tournament, err := ts.tournamentStore.UpdateTournament(id, func(tournament *Tournament) error {
+		player := tournament.playerWithToken(token)
+		switch {
+		case player == nil:
+			return ErrNotTournamentPlayer
+		case tournament.Status != TournamentRunning:
+			return ErrTournamentClosed
+		case choice.Ruleset != tournament.Ruleset:
+			return ErrChoiceNotFound
+		}
+
+		pairing := tournament.pendingPairing(player.Name)
+		if pairing == nil {
+			return ErrNothingToPlay
+		}
+		if pairing.Home == player.Name {
+			pairing.HomeMoved, pairing.HomeChoice = true, choice
+		} else {
+			pairing.AwayMoved, pairing.AwayChoice = true, choice
+		}
+		if pairing.HomeMoved && pairing.AwayMoved {
+			if err := ts.resolve(tournament, pairing); err != nil {
+				return err
+			}
+		}
+		tournament.Standings = tournament.standings()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tournament.viewFor(token), nil
+}
+
+func (ts TournamentServiceImpl) Advance(id, token string) (*Tournament, error) {
+	tournament, err := ts.tournamentStore.UpdateTournament(id, func(tournament *Tournament) error {
+		if token == "" || token != tournament.OrganizerToken {
+			return ErrNotTournamentOrganizer
+		}
+
+		switch tournament.Status {
+		case TournamentRegistration:
+			if len(tournament.Players) < 2 {
+				return ErrNotEnoughPlayers
+			}
+			tournament.start()
+		case TournamentRunning:
+			tournament.closeCurrentRound()
+			tournament.scheduleNextRound()
+		default:
+			return ErrTournamentClosed
+		}
+		tournament.Standings = tournament.standings()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tournament.viewFor(token), nil
+}
+
+// resolve decides a pairing both players moved in through the BEATS relationship between their choices. Ties are
+// draws in round-robin tournaments, and are replayed in elimination ones.
+func (ts TournamentServiceImpl) resolve(tournament *Tournament, pairing *TournamentPairing) error {
+	home, away := pairing.HomeChoice, pairing.AwayChoice
+	if home.ID == away.ID {
+		pairing.Description = describeTie(phrases[TiePhrase], home)
+		if tournament.Format == SingleElimination {
+			pairing.Ties++
+			pairing.HomeMoved, pairing.AwayMoved = false, false
+			pairing.HomeChoice, pairing.AwayChoice = nil, nil
+			return nil
+		}
+		pairing.Decided = true
+		return nil
+	}
+
+	round, err := ts.roundStore.SimulateRound(home.ID, away.ID)
+	if err != nil {
+		return err
+	}
+	pairing.Decided = true
+	if round.WinnerID == home.ID {
+		pairing.Winner = pairing.Home
+		pairing.Description = describeRound(home, round.Action, away)
+	} else {
+		pairing.Winner = pairing.Away
+		pairing.Description = describeRound(away, round.Action, home)
+	}
+	return nil
+}
+
+func (t *Tournament) playerNamed(name string) *TournamentPlayer {
+	for i := range t.Players {
+		if strings.EqualFold(t.Players[i].Name, name) {
+			return &t.Players[i]
+		}
+	}
+	return nil
+}
+
+func (t *Tournament) playerWithToken(token string) *TournamentPlayer {
+	if token == "" {
+		return nil
+	}
+	for i := range t.Players {
+		if t.Players[i].Token == token {
+			return &t.Players[i]
+		}
+	}
+	return nil
+}
+
+// pendingPairing returns the undecided pairing of the player in the current round, if they still have to move in it
+func (t *Tournament) pendingPairing(name string) *TournamentPairing {
+	if t.CurrentRound < 1 || t.CurrentRound > len(t.Rounds) {
+		return nil
+	}
+	pairings := t.Rounds[t.CurrentRound-1].Pairings
+	for i := range pairings {
+		pairing := &pairings[i]
+		if pairing.Decided {
+			continue
+		}
+		if (pairing.Home == name && !pairing.HomeMoved) || (pairing.Away == name && !pairing.AwayMoved) {
+			return pairing
+		}
+	}
+	return nil
+}
+
+// viewFor returns a copy of the tournament as the organizer or player with the token may see it: without the tokens
+// of the others, and without the choices of the opponents in undecided pairings
+func (t *Tournament) viewFor(token string) *Tournament {
+	view := *t
+	if token != t.OrganizerToken {
+		view.OrganizerToken = ""
+	}
+
+	viewer := ""
+	view.Players = make([]TournamentPlayer, len(t.Players))
+	for i, player := range t.Players {
+		if token != "" && player.Token == token {
+			viewer = player.Name
+		} else {
+			player.Token = ""
+		}
+		view.Players[i] = player
+	}
+
+	view.Rounds = make([]TournamentRound, len(t.Rounds))
+	for i, round := range t.Rounds {
+		pairings := make([]TournamentPairing, len(round.Pairings))
+		for j, pairing := range round.Pairings {
+			if !pairing.Decided {
+				if pairing.Home != viewer {
+					pairing.HomeChoice = nil
+				}
+				if pairing.Away != viewer {
+					pairing.AwayChoice = nil
+				}
+			}
+			pairings[j] = pairing
+		}
+		view.Rounds[i] = TournamentRound{Number: round.Number, Pairings: pairings}
+	}
+	return &view
+}
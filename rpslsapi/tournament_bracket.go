@@ -0,0 +1,236 @@
+package rpslsapi
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	roundRobinWinPoints  = 3
+	roundRobinDrawPoints = 1
+)
+
+// start closes the registration and schedules the first round, or every round of a round-robin tournament
+func (t *Tournament) start() {
+	t.Status = TournamentRunning
+	t.CurrentRound = 1
+	if t.Format == RoundRobin {
+		t.Rounds = roundRobinRounds(t.Players)
+	} else {
+		t.Rounds = []TournamentRound{eliminationRound(1, bracketOrder(t.Players))}
+	}
+}
+
+// closeCurrentRound decides the pairings still undecided once the organizer moves on. A player who moved wins by
+// forfeit. If neither did, both lose in round-robin tournaments, while the higher seed goes through in elimination
+// ones.
+func (t *Tournament) closeCurrentRound() {
+	pairings := t.Rounds[t.CurrentRound-1].Pairings
+	for i := range pairings {
+		pairing := &pairings[i]
+		if pairing.Decided {
+			continue
+		}
+
+		pairing.Decided, pairing.Forfeit = true, true
+		switch {
+		case pairing.HomeMoved:
+			pairing.Winner = pairing.Home
+			pairing.Description = fmt.Sprintf("%s did not move in time", pairing.Away)
+		case pairing.AwayMoved:
+			pairing.Winner = pairing.Away
+			pairing.Description = fmt.Sprintf("%s did not move in time", pairing.Home)
+		case t.Format == SingleElimination:
+			pairing.Winner = t.higherSeed(pairing.Home, pairing.Away)
+			pairing.Description = fmt.Sprintf("Neither player moved in time, %s goes through as the higher seed",
+				pairing.Winner)
+		default:
+			pairing.Description = "Neither player moved in time"
+		}
+	}
+}
+
+// scheduleNextRound moves on to the next round, pairing the winners of the current one in elimination tournaments,
+// and finishes the tournament once there is none left
+func (t *Tournament) scheduleNextRound() {
+	if t.Format == SingleElimination {
+		winners := []string{}
+		for _, pairing := range t.Rounds[t.CurrentRound-1].Pairings {
+			winners = append(winners, pairing.Winner)
+		}
+		if len(winners) > 1 {
+			t.Rounds = append(t.Rounds, eliminationRound(t.CurrentRound+1, winners))
+		}
+	}
+
+	if t.CurrentRound < len(t.Rounds) {
+		t.CurrentRound++
+		return
+	}
+	t.Status = TournamentFinished
+	if standings := t.standings(); len(standings) > 0 {
+		t.Champion = standings[0].Player
+	}
+}
+
+func (t *Tournament) higherSeed(name1, name2 string) string {
+	if t.playerNamed(name2).Seed < t.playerNamed(name1).Seed {
+		return name2
+	}
+	return name1
+}
+
+// roundRobinRounds schedules every round of a round-robin tournament through the circle method: the first player
+// stays in place while the others rotate around them. With an odd number of players, one of them sits out each round.
+func roundRobinRounds(players []TournamentPlayer) []TournamentRound {
+	names := make([]string, 0, len(players)+1)
+	for _, player := range players {
+		names = append(names, player.Name)
+	}
+	if len(names)%2 == 1 {
+		names = append(names, "")
+	}
+
+	count := len(names)
+	rounds := make([]TournamentRound, 0, count-1)
+	for number := 1; number < count; number++ {
+		round := TournamentRound{Number: number, Pairings: []TournamentPairing{}}
+		for i := 0; i < count/2; i++ {
+			home, away := names[i], names[count-1-i]
+			// alternate the side of the fixed player so they don't always play home
+			if i == 0 && number%2 == 0 {
+				home, away = away, home
+			}
+			round.Pairings = append(round.Pairings, newPairing(home, away))
+		}
+		rounds = append(rounds, round)
+
+		last := names[count-1]
+		copy(names[2:], names[1:count-1])
+		names[1] = last
+	}
+	return rounds
+}
+
+// bracketOrder returns the names of the players in the order of the first round of a bracket sized to the next power
+// of two, with empty names standing for byes. Seeds are placed so that the best ones meet as late as possible, and
+// byes go to the best seeds.
+func bracketOrder(players []TournamentPlayer) []string {
+	seeds := []int{1}
+	for len(seeds) < len(players) {
+		size := len(seeds) * 2
+		next := make([]int, 0, size)
+		for _, seed := range seeds {
+			next = append(next, seed, size+1-seed)
+		}
+		seeds = next
+	}
+
+	names := make([]string, len(seeds))
+	for i, seed := range seeds {
+		if seed <= len(players) {
+			names[i] = players[seed-1].Name
+		}
+	}
+	return names
+}
+
+// eliminationRound pairs the players two by two in the given order
+func eliminationRound(number int, names []string) TournamentRound {
+	round := TournamentRound{Number: number, Pairings: []TournamentPairing{}}
+	for i := 0; i+1 < len(names); i += 2 {
+		round.Pairings = append(round.Pairings, newPairing(names[i], names[i+1]))
+	}
+	return round
+}
+
+// newPairing returns a pairing between both players, or a bye decided in advance if one of them is missing
+func newPairing(home, away string) TournamentPairing {
+	if home == "" {
+		home, away = away, home
+	}
+	if away == "" {
+		return TournamentPairing{Home: home, Decided: true, Winner: home, Bye: true,
+			Description: fmt.Sprintf("%s has a bye", home)}
+	}
+	return TournamentPairing{Home: home, Away: away}
+}
+
+// standings ranks the players. Round-robin tournaments rank them by points, then wins, then seed. Elimination ones
+// rank the players still in first, then the others by how late they were knocked out, then seed.
+func (t *Tournament) standings() []TournamentStanding {
+	standings := make([]TournamentStanding, len(t.Players))
+	index := make(map[string]int, len(t.Players))
+	for i, player := range t.Players {
+		standings[i] = TournamentStanding{Player: player.Name}
+		index[player.Name] = i
+	}
+
+	knockedOut := make([]int, len(t.Players))
+	for _, round := range t.Rounds {
+		for _, pairing := range round.Pairings {
+			if !pairing.Decided || pairing.Bye {
+				continue
+			}
+			home, away := &standings[index[pairing.Home]], &standings[index[pairing.Away]]
+			home.Played++
+			away.Played++
+			switch pairing.Winner {
+			case pairing.Home:
+				home.Wins++
+				away.Losses++
+				knockedOut[index[pairing.Away]] = round.Number
+			case pairing.Away:
+				away.Wins++
+				home.Losses++
+				knockedOut[index[pairing.Home]] = round.Number
+			default:
+				if pairing.Forfeit {
+					home.Losses++
+					away.Losses++
+				} else {
+					home.Draws++
+					away.Draws++
+				}
+			}
+		}
+	}
+
+	for i := range standings {
+		standing := &standings[i]
+		if t.Format == RoundRobin {
+			standing.Points = standing.Wins*roundRobinWinPoints + standing.Draws*roundRobinDrawPoints
+		} else {
+			standing.Eliminated = standing.Losses > 0
+		}
+	}
+
+	seeds := make(map[string]int, len(t.Players))
+	for _, player := range t.Players {
+		seeds[player.Name] = player.Seed
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if t.Format == RoundRobin {
+			if a.Points != b.Points {
+				return a.Points > b.Points
+			}
+			if a.Wins != b.Wins {
+				return a.Wins > b.Wins
+			}
+		} else {
+			if a.Eliminated != b.Eliminated {
+				return !a.Eliminated
+			}
+			outA, outB := knockedOut[index[a.Player]], knockedOut[index[b.Player]]
+			if outA != outB {
+				return outA > outB
+			}
+		}
+		return seeds[a.Player] < seeds[b.Player]
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
@@ -0,0 +1,161 @@
+package rpslsapi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func tournamentPlayers(count int) []TournamentPlayer {
+	players := make([]TournamentPlayer, count)
+	for i := range players {
+		players[i] = TournamentPlayer{Name: fmt.Sprintf("p%d", i+1), Seed: i + 1}
+	}
+	return players
+}
+
+func TestRoundRobinRounds(t *testing.T) {
+	for _, count := range []int{2, 3, 4, 5, 8} {
+		players := tournamentPlayers(count)
+		rounds := roundRobinRounds(players)
+
+		met := map[string]int{}
+		for _, round := range rounds {
+			playing := map[string]bool{}
+			for _, pairing := range round.Pairings {
+				require.False(t, playing[pairing.Home], "%d players: %s plays twice in a round", count, pairing.Home)
+				playing[pairing.Home] = true
+				if pairing.Bye {
+					require.Empty(t, pairing.Away)
+					continue
+				}
+				require.False(t, playing[pairing.Away], "%d players: %s plays twice in a round", count, pairing.Away)
+				playing[pairing.Away] = true
+				met[pairing.Home+"-"+pairing.Away]++
+				met[pairing.Away+"-"+pairing.Home]++
+			}
+			require.Len(t, playing, count, "%d players: everybody plays or sits out each round", count)
+		}
+
+		for _, a := range players {
+			for _, b := range players {
+				if a.Name != b.Name {
+					require.Equal(t, 1, met[a.Name+"-"+b.Name], "%d players: %s meets %s once", count, a.Name,
+						b.Name)
+				}
+			}
+		}
+	}
+}
+
+func TestBracketOrder(t *testing.T) {
+	require.Equal(t, []string{"p1", "p2"}, bracketOrder(tournamentPlayers(2)))
+	require.Equal(t, []string{"p1", "", "p2", "p3"}, bracketOrder(tournamentPlayers(3)))
+	require.Equal(t, []string{"p1", "", "p4", "p5", "p2", "", "p3", ""}, bracketOrder(tournamentPlayers(5)))
+}
+
+func TestTournament_closeCurrentRound(t *testing.T) {
+	rock := &baseChoices[0]
+	testCases := []struct {
+		name            string
+		format          TournamentFormat
+		pairing         TournamentPairing
+		expectedWinner  string
+		expectedForfeit bool
+	}{
+		{
+			name:           "decided pairings stay as they are",
+			format:         RoundRobin,
+			pairing:        TournamentPairing{Home: "p1", Away: "p2", Decided: true, Winner: "p2"},
+			expectedWinner: "p2",
+		},
+		{
+			name:            "a player who moved wins by forfeit",
+			format:          RoundRobin,
+			pairing:         TournamentPairing{Home: "p1", Away: "p2", AwayMoved: true, AwayChoice: rock},
+			expectedWinner:  "p2",
+			expectedForfeit: true,
+		},
+		{
+			name:            "both players lose a round-robin pairing if neither moved",
+			format:          RoundRobin,
+			pairing:         TournamentPairing{Home: "p1", Away: "p2"},
+			expectedForfeit: true,
+		},
+		{
+			name:            "the higher seed goes through an elimination pairing if neither moved",
+			format:          SingleElimination,
+			pairing:         TournamentPairing{Home: "p2", Away: "p1"},
+			expectedWinner:  "p1",
+			expectedForfeit: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tournament := &Tournament{Format: tc.format, Players: tournamentPlayers(2), CurrentRound: 1,
+			Rounds: []TournamentRound{{Number: 1, Pairings: []TournamentPairing{tc.pairing}}}}
+
+		tournament.closeCurrentRound()
+
+		pairing := tournament.Rounds[0].Pairings[0]
+		require.True(t, pairing.Decided, tc.name)
+		require.Equal(t, tc.expectedWinner, pairing.Winner, tc.name)
+		require.Equal(t, tc.expectedForfeit, pairing.Forfeit, tc.name)
+	}
+}
+
+func TestTournament_scheduleNextRound(t *testing.T) {
+	tournament := &Tournament{Format: SingleElimination, Players: tournamentPlayers(3)}
+	tournament.start()
+	require.Equal(t, TournamentRunning, tournament.Status)
+	require.Equal(t, []TournamentPairing{
+		{Home: "p1", Decided: true, Winner: "p1", Bye: true, Description: "p1 has a bye"},
+		{Home: "p2", Away: "p3"},
+	}, tournament.Rounds[0].Pairings)
+
+	tournament.Rounds[0].Pairings[1].Decided = true
+	tournament.Rounds[0].Pairings[1].Winner = "p3"
+	tournament.scheduleNextRound()
+	require.Equal(t, 2, tournament.CurrentRound)
+	require.Equal(t, []TournamentPairing{{Home: "p1", Away: "p3"}}, tournament.Rounds[1].Pairings)
+
+	tournament.Rounds[1].Pairings[0].Decided = true
+	tournament.Rounds[1].Pairings[0].Winner = "p3"
+	tournament.scheduleNextRound()
+	require.Equal(t, TournamentFinished, tournament.Status)
+	require.Equal(t, "p3", tournament.Champion)
+}
+
+func TestTournament_standings(t *testing.T) {
+	roundRobin := &Tournament{Format: RoundRobin, Players: tournamentPlayers(4), Rounds: []TournamentRound{
+		{Number: 1, Pairings: []TournamentPairing{
+			{Home: "p1", Away: "p4", Decided: true, Winner: "p4"},
+			{Home: "p2", Away: "p3", Decided: true},
+		}},
+		{Number: 2, Pairings: []TournamentPairing{
+			{Home: "p4", Away: "p3", Decided: true, Winner: "p3"},
+			{Home: "p1", Away: "p2", Decided: true, Forfeit: true},
+		}},
+	}}
+	standings := roundRobin.standings()
+	require.Equal(t, TournamentStanding{Rank: 1, Player: "p3", Played: 2, Wins: 1, Draws: 1, Points: 4}, standings[0])
+	// p4 and p2 are level on points, p4 having more wins
+	require.Equal(t, TournamentStanding{Rank: 2, Player: "p4", Played: 2, Wins: 1, Losses: 1, Points: 3}, standings[1])
+	require.Equal(t, TournamentStanding{Rank: 3, Player: "p2", Played: 2, Draws: 1, Losses: 1, Points: 1}, standings[2])
+	require.Equal(t, TournamentStanding{Rank: 4, Player: "p1", Played: 2, Losses: 2}, standings[3])
+
+	elimination := &Tournament{Format: SingleElimination, Players: tournamentPlayers(4), Rounds: []TournamentRound{
+		{Number: 1, Pairings: []TournamentPairing{
+			{Home: "p1", Away: "p4", Decided: true, Winner: "p1"},
+			{Home: "p2", Away: "p3", Decided: true, Winner: "p3"},
+		}},
+		{Number: 2, Pairings: []TournamentPairing{{Home: "p1", Away: "p3"}}},
+	}}
+	var ranking []string
+	for _, standing := range elimination.standings() {
+		ranking = append(ranking, standing.Player)
+	}
+	// p2 and p4 were both knocked out in the first round, p2 being the higher seed
+	require.Equal(t, []string{"p1", "p3", "p2", "p4"}, ranking)
+}
@@ -0,0 +1,308 @@
+package rpslsapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type TournamentStoreMock struct {
+	mock.Mock
+}
+
+func (tsm *TournamentStoreMock) CreateTournament(tournament *Tournament, retention time.Duration) error {
+	args := tsm.Called(tournament, retention)
+	return args.Error(0)
+}
+
+func (tsm *TournamentStoreMock) Tournament(id string) (*Tournament, error) {
+	args := tsm.Called(id)
+	return args.Get(0).(*Tournament), args.Error(1)
+}
+
+// UpdateTournament applies the update to the tournament the mock returns
+func (tsm *TournamentStoreMock) UpdateTournament(id string, update func(tournament *Tournament) error) (*Tournament,
+	error) {
+	args := tsm.Called(id)
+	tournament := args.Get(0).(*Tournament)
+	if err := update(tournament); err != nil {
+		return nil, err
+	}
+	return tournament, nil
+}
+
+// runningTournament returns a tournament of two players, p1 and p2, whose tokens are their names
+func runningTournament(format TournamentFormat) *Tournament {
+	tournament := &Tournament{ID: "tournament", Ruleset: "rpsls", Format: format, Status: TournamentRegistration,
+		OrganizerToken: "organizer", Players: tournamentPlayers(2)}
+	for i := range tournament.Players {
+		tournament.Players[i].Token = tournament.Players[i].Name
+	}
+	tournament.start()
+	return tournament
+}
+
+func TestTournamentService_CreateTournament(t *testing.T) {
+	testCases := []struct {
+		name          string
+		settings      TournamentSettings
+		expectedError error
+	}{
+		{
+			name:     "success: create the tournament",
+			settings: TournamentSettings{Name: " Office cup ", Ruleset: "rpsls", Format: SingleElimination},
+		},
+		{
+			name:          "failure: if the format is unknown, return ErrInvalidTournament",
+			settings:      TournamentSettings{Name: "Office cup", Ruleset: "rpsls", Format: "swiss"},
+			expectedError: ErrInvalidTournament,
+		},
+		{
+			name:          "failure: if the name is blank, return ErrInvalidTournament",
+			settings:      TournamentSettings{Name: " ", Ruleset: "rpsls", Format: RoundRobin},
+			expectedError: ErrInvalidTournament,
+		},
+		{
+			name:          "failure: if the ruleset doesn't exist, return ErrRulesetNotFound",
+			settings:      TournamentSettings{Name: "Office cup", Ruleset: "missing", Format: RoundRobin},
+			expectedError: ErrRulesetNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := TournamentStoreMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		service := NewTournamentService(&storeMock, &RoundStoreMock{}, &choiceServiceMock)
+		choiceServiceMock.On("Choices", "rpsls").Return(baseChoices, nil)
+		choiceServiceMock.On("Choices", "missing").Return([]Choice(nil), ErrRulesetNotFound)
+		storeMock.On("CreateTournament", mock.Anything, tournamentRetention).Return(nil)
+
+		tournament, err := service.CreateTournament(&tc.settings)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+		} else {
+			require.NoError(t, err, tc.name)
+			require.Equal(t, "Office cup", tournament.Name, tc.name)
+			require.Equal(t, TournamentRegistration, tournament.Status, tc.name)
+			require.Len(t, tournament.OrganizerToken, 32, tc.name)
+		}
+	}
+}
+
+func TestTournamentService_JoinTournament(t *testing.T) {
+	testCases := []struct {
+		name          string
+		tournament    *Tournament
+		playerName    string
+		expectedError error
+	}{
+		{
+			name:       "success: register the player",
+			tournament: &Tournament{Status: TournamentRegistration, Players: tournamentPlayers(1)},
+			playerName: "p2",
+		},
+		{
+			name:          "failure: if the name is taken, return ErrPlayerNameTaken",
+			tournament:    &Tournament{Status: TournamentRegistration, Players: tournamentPlayers(1)},
+			playerName:    "P1",
+			expectedError: ErrPlayerNameTaken,
+		},
+		{
+			name:          "failure: if the tournament started, return ErrTournamentClosed",
+			tournament:    runningTournament(RoundRobin),
+			playerName:    "p3",
+			expectedError: ErrTournamentClosed,
+		},
+		{
+			name:          "failure: if the name is blank, return ErrInvalidTournament",
+			tournament:    &Tournament{Status: TournamentRegistration},
+			playerName:    "",
+			expectedError: ErrInvalidTournament,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := TournamentStoreMock{}
+		service := NewTournamentService(&storeMock, &RoundStoreMock{}, &ChoiceServiceMock{})
+		storeMock.On("UpdateTournament", "tournament").Return(tc.tournament, nil)
+
+		tournament, err := service.JoinTournament("tournament", tc.playerName)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+		} else {
+			require.NoError(t, err, tc.name)
+			player := tournament.Players[len(tournament.Players)-1]
+			require.Equal(t, tc.playerName, player.Name, tc.name)
+			require.Equal(t, 2, player.Seed, tc.name)
+			require.Len(t, player.Token, 32, tc.name)
+			require.Empty(t, tournament.Players[0].Token, tc.name)
+			require.Len(t, tournament.Standings, 2, tc.name)
+		}
+	}
+}
+
+func TestTournamentService_Move(t *testing.T) {
+	rock, paper := &baseChoices[0], &baseChoices[1]
+
+	testCases := []struct {
+		name            string
+		tournament      *Tournament
+		opponentChoice  *Choice
+		choice          *Choice
+		token           string
+		expectedDecided bool
+		expectedWinner  string
+		expectedTies    int
+		expectedError   error
+	}{
+		{
+			name:       "success: the choice stays hidden until the opponent moves",
+			tournament: runningTournament(RoundRobin),
+			choice:     rock,
+			token:      "p1",
+		},
+		{
+			name:            "success: the second move decides the pairing",
+			tournament:      runningTournament(SingleElimination),
+			opponentChoice:  rock,
+			choice:          paper,
+			token:           "p1",
+			expectedDecided: true,
+			expectedWinner:  "p1",
+		},
+		{
+			name:            "success: a tie is a draw in round-robin tournaments",
+			tournament:      runningTournament(RoundRobin),
+			opponentChoice:  rock,
+			choice:          rock,
+			token:           "p1",
+			expectedDecided: true,
+		},
+		{
+			name:           "success: a tie is replayed in elimination tournaments",
+			tournament:     runningTournament(SingleElimination),
+			opponentChoice: rock,
+			choice:         rock,
+			token:          "p1",
+			expectedTies:   1,
+		},
+		{
+			name:          "failure: if the token is not one of a player, return ErrNotTournamentPlayer",
+			tournament:    runningTournament(RoundRobin),
+			choice:        rock,
+			token:         "organizer",
+			expectedError: ErrNotTournamentPlayer,
+		},
+		{
+			name:          "failure: if the tournament didn't start, return ErrTournamentClosed",
+			tournament:    &Tournament{Status: TournamentRegistration, Players: []TournamentPlayer{{Token: "p1"}}},
+			choice:        rock,
+			token:         "p1",
+			expectedError: ErrTournamentClosed,
+		},
+		{
+			name:          "failure: if the choice belongs to another ruleset, return ErrChoiceNotFound",
+			tournament:    runningTournament(RoundRobin),
+			choice:        &Choice{ID: "rps-rock", Name: "Rock", Ruleset: "rps"},
+			token:         "p1",
+			expectedError: ErrChoiceNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := TournamentStoreMock{}
+		roundStoreMock := RoundStoreMock{}
+		choiceServiceMock := ChoiceServiceMock{}
+		service := NewTournamentService(&storeMock, &roundStoreMock, &choiceServiceMock)
+		if tc.opponentChoice != nil {
+			pairing := &tc.tournament.Rounds[0].Pairings[0]
+			pairing.AwayMoved, pairing.AwayChoice = true, tc.opponentChoice
+		}
+		storeMock.On("UpdateTournament", "tournament").Return(tc.tournament, nil)
+		choiceServiceMock.On("Choice", tc.choice.ID).Return(tc.choice, nil)
+		roundStoreMock.On("SimulateRound", paper.ID, rock.ID).
+			Return(&Round{WinnerID: paper.ID, LoserID: rock.ID, Action: "covers"}, nil)
+
+		tournament, err := service.Move("tournament", tc.token, tc.choice.ID)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		pairing := tournament.Rounds[0].Pairings[0]
+		require.Equal(t, tc.expectedDecided, pairing.Decided, tc.name)
+		require.Equal(t, tc.expectedWinner, pairing.Winner, tc.name)
+		require.Equal(t, tc.expectedTies, pairing.Ties, tc.name)
+		if !pairing.Decided && pairing.Ties == 0 {
+			require.Equal(t, tc.choice, pairing.HomeChoice, tc.name)
+			require.Nil(t, pairing.AwayChoice, tc.name)
+		}
+
+		_, err = service.Move("tournament", tc.token, tc.choice.ID)
+		if pairing.Ties == 0 {
+			require.Equal(t, ErrNothingToPlay, err, tc.name)
+		}
+	}
+}
+
+func TestTournamentService_Advance(t *testing.T) {
+	testCases := []struct {
+		name           string
+		tournament     *Tournament
+		token          string
+		expectedStatus TournamentStatus
+		expectedRound  int
+		expectedError  error
+	}{
+		{
+			name: "success: start the tournament",
+			tournament: &Tournament{Format: RoundRobin, Status: TournamentRegistration, OrganizerToken: "organizer",
+				Players: tournamentPlayers(3)},
+			token:          "organizer",
+			expectedStatus: TournamentRunning,
+			expectedRound:  1,
+		},
+		{
+			name:           "success: finish the tournament after its last round",
+			tournament:     runningTournament(SingleElimination),
+			token:          "organizer",
+			expectedStatus: TournamentFinished,
+			expectedRound:  1,
+		},
+		{
+			name: "failure: if less than two players joined, return ErrNotEnoughPlayers",
+			tournament: &Tournament{Format: RoundRobin, Status: TournamentRegistration, OrganizerToken: "organizer",
+				Players: tournamentPlayers(1)},
+			token:         "organizer",
+			expectedError: ErrNotEnoughPlayers,
+		},
+		{
+			name:          "failure: if the token is not the one of the organizer, return ErrNotTournamentOrganizer",
+			tournament:    runningTournament(RoundRobin),
+			token:         "p1",
+			expectedError: ErrNotTournamentOrganizer,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := TournamentStoreMock{}
+		service := NewTournamentService(&storeMock, &RoundStoreMock{}, &ChoiceServiceMock{})
+		storeMock.On("UpdateTournament", "tournament").Return(tc.tournament, nil)
+
+		tournament, err := service.Advance("tournament", tc.token)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedStatus, tournament.Status, tc.name)
+		require.Equal(t, tc.expectedRound, tournament.CurrentRound, tc.name)
+		require.Equal(t, "organizer", tournament.OrganizerToken, tc.name)
+	}
+}
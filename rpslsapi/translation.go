@@ -0,0 +1,237 @@
+package rpslsapi
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrTranslationNotFound = errors.New("translation not found")
+var ErrInvalidTranslation = errors.New("invalid translation")
+
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+type TranslationKind string
+
+const (
+	// ChoiceTranslation translates the name of the choice whose ID is the key
+	ChoiceTranslation TranslationKind = "choice"
+	// VerbTranslation translates the verb of a BEATS relationship, the key being the original verb, e.g. "crushes"
+	VerbTranslation TranslationKind = "verb"
+	// PhraseTranslation translates one of the sentences used to describe rounds, the key being one of the phrases below
+	PhraseTranslation TranslationKind = "phrase"
+)
+
+// TiePhrase is the key of the sentence describing tied rounds, in which {choice} stands for the choice both players
+// played, e.g. "Ambos jogaram {choice}"
+const TiePhrase = "tie"
+
+var phrases = map[string]string{
+	TiePhrase: "Both played {choice}",
+}
+
+// Translation is the text of a choice name, verb or phrase in a language, identified by a lowercase BCP 47 tag such
+// as "pt" or "pt-br"
+type Translation struct {
+	Kind     TranslationKind `json:"kind"`
+	Key      string          `json:"key"`
+	Language string          `json:"language"`
+	Text     string          `json:"text"`
+}
+
+type TranslationService interface {
+	Translations(language string) ([]Translation, error)
+	SaveTranslation(translation *Translation) (*Translation, error)
+	DeleteTranslation(kind TranslationKind, key, language string) error
+	// Translator returns a Translator for the languages, given in order of preference
+	Translator(languages []string) (*Translator, error)
+}
+
+type TranslationStore interface {
+	// Translations returns the translations into the language, or into every language if it is empty
+	Translations(language string) ([]Translation, error)
+	// SaveTranslation creates the translation, replacing any existing one of the same key into the same language
+	SaveTranslation(translation *Translation) error
+	DeleteTranslation(kind TranslationKind, key, language string) error
+}
+
+type TranslationServiceImpl struct {
+	store       TranslationStore
+	choiceStore ChoiceStore
+}
+
+func NewTranslationService(store TranslationStore, choiceStore ChoiceStore) TranslationService {
+	return TranslationServiceImpl{store: store, choiceStore: choiceStore}
+}
+
+func (ts TranslationServiceImpl) Translations(language string) ([]Translation, error) {
+	translations, err := ts.store.Translations(strings.ToLower(language))
+	if err == nil && translations == nil {
+		translations = []Translation{}
+	}
+	return translations, err
+}
+
+func (ts TranslationServiceImpl) SaveTranslation(translation *Translation) (*Translation, error) {
+	translation.Key = strings.TrimSpace(translation.Key)
+	translation.Language = strings.ToLower(strings.TrimSpace(translation.Language))
+	translation.Text = strings.TrimSpace(translation.Text)
+	if !languagePattern.MatchString(translation.Language) || translation.Text == "" || translation.Key == "" {
+		return nil, ErrInvalidTranslation
+	}
+
+	switch translation.Kind {
+	case ChoiceTranslation:
+		if _, err := ts.choiceStore.Choice(translation.Key); err != nil {
+			return nil, err
+		}
+	case VerbTranslation:
+	case PhraseTranslation:
+		if _, found := phrases[translation.Key]; !found {
+			return nil, ErrInvalidTranslation
+		}
+	default:
+		return nil, ErrInvalidTranslation
+	}
+
+	if err := ts.store.SaveTranslation(translation); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+func (ts TranslationServiceImpl) DeleteTranslation(kind TranslationKind, key, language string) error {
+	return ts.store.DeleteTranslation(kind, key, strings.ToLower(language))
+}
+
+func (ts TranslationServiceImpl) Translator(languages []string) (*Translator, error) {
+	var translations []Translation
+	for _, language := range fallbackChain(languages) {
+		languageTranslations, err := ts.store.Translations(language)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, languageTranslations...)
+	}
+	return NewTranslator(translations), nil
+}
+
+// fallbackChain lists the languages in order of preference, each one followed by its more generic forms, e.g.
+// [pt-br es] gives [pt-br pt es]
+func fallbackChain(languages []string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+	for _, language := range languages {
+		language = strings.ToLower(language)
+		for language != "" {
+			if !seen[language] && languagePattern.MatchString(language) {
+				seen[language] = true
+				chain = append(chain, language)
+			}
+			cut := strings.LastIndex(language, "-")
+			if cut < 0 {
+				break
+			}
+			language = language[:cut]
+		}
+	}
+	return chain
+}
+
+// Translator localizes choices and rounds, falling back to the original text of anything that has no translation in
+// any of its languages. A nil Translator leaves everything untranslated.
+type Translator struct {
+	// language is the most preferred language with translations, if any
+	language string
+	texts    map[TranslationKind]map[string]string
+}
+
+// NewTranslator returns a Translator using the translations, which come in order of preference: when several translate
+// the same text, the first one wins
+func NewTranslator(translations []Translation) *Translator {
+	translator := &Translator{texts: make(map[TranslationKind]map[string]string)}
+	for _, translation := range translations {
+		if translator.language == "" {
+			translator.language = translation.Language
+		}
+		texts, found := translator.texts[translation.Kind]
+		if !found {
+			texts = make(map[string]string)
+			translator.texts[translation.Kind] = texts
+		}
+		if _, translated := texts[translation.Key]; !translated {
+			texts[translation.Key] = translation.Text
+		}
+	}
+	return translator
+}
+
+// Language returns the most preferred language that has translations, or an empty string if none has
+func (t *Translator) Language() string {
+	if t == nil {
+		return ""
+	}
+	return t.language
+}
+
+func (t *Translator) Choice(choice *Choice) *Choice {
+	if t == nil || choice == nil {
+		return choice
+	}
+	translated := *choice
+	translated.Name = t.text(ChoiceTranslation, choice.ID, choice.Name)
+	return &translated
+}
+
+func (t *Translator) Choices(choices []Choice) []Choice {
+	if t == nil {
+		return choices
+	}
+	translated := make([]Choice, len(choices))
+	for i := range choices {
+		translated[i] = *t.Choice(&choices[i])
+	}
+	return translated
+}
+
+func (t *Translator) Verb(verb string) string {
+	if t == nil {
+		return verb
+	}
+	return t.text(VerbTranslation, verb, verb)
+}
+
+// Round returns a copy of the results with translated choices, action and description
+func (t *Translator) Round(results *RoundResults) *RoundResults {
+	if t == nil || results.PlayerChoice == nil || results.ComputerChoice == nil {
+		return results
+	}
+
+	translated := *results
+	translated.PlayerChoice = t.Choice(results.PlayerChoice)
+	translated.ComputerChoice = t.Choice(results.ComputerChoice)
+	translated.Action = t.Verb(results.Action)
+	switch ResultsLabel(results.Results) {
+	case Tie:
+		translated.Description = describeTie(t.phrase(TiePhrase), translated.PlayerChoice)
+	case Win:
+		translated.Description = describeRound(translated.PlayerChoice, translated.Action, translated.ComputerChoice)
+	case Lose:
+		translated.Description = describeRound(translated.ComputerChoice, translated.Action, translated.PlayerChoice)
+	}
+	return &translated
+}
+
+func (t *Translator) phrase(key string) string {
+	if t == nil {
+		return phrases[key]
+	}
+	return t.text(PhraseTranslation, key, phrases[key])
+}
+
+func (t *Translator) text(kind TranslationKind, key, original string) string {
+	if text, found := t.texts[kind][key]; found {
+		return text
+	}
+	return original
+}
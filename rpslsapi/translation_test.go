@@ -0,0 +1,139 @@
+package rpslsapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type TranslationStoreMock struct {
+	mock.Mock
+}
+
+func (tsm *TranslationStoreMock) Translations(language string) ([]Translation, error) {
+	args := tsm.Called(language)
+	return args.Get(0).([]Translation), args.Error(1)
+}
+
+func (tsm *TranslationStoreMock) SaveTranslation(translation *Translation) error {
+	args := tsm.Called(translation)
+	return args.Error(0)
+}
+
+func (tsm *TranslationStoreMock) DeleteTranslation(kind TranslationKind, key, language string) error {
+	args := tsm.Called(kind, key, language)
+	return args.Error(0)
+}
+
+func TestTranslationService_SaveTranslation(t *testing.T) {
+	testCases := []struct {
+		name                string
+		translation         Translation
+		expectedTranslation *Translation
+		expectedError       error
+	}{
+		{
+			name:                "success: save a normalized choice translation",
+			translation:         Translation{Kind: ChoiceTranslation, Key: "rpsls-rock", Language: " PT-BR ", Text: " Pedra "},
+			expectedTranslation: &Translation{Kind: ChoiceTranslation, Key: "rpsls-rock", Language: "pt-br", Text: "Pedra"},
+		},
+		{
+			name:                "success: save a verb translation",
+			translation:         Translation{Kind: VerbTranslation, Key: "crushes", Language: "es", Text: "aplasta"},
+			expectedTranslation: &Translation{Kind: VerbTranslation, Key: "crushes", Language: "es", Text: "aplasta"},
+		},
+		{
+			name:        "success: save a phrase translation",
+			translation: Translation{Kind: PhraseTranslation, Key: TiePhrase, Language: "pt", Text: "Empate: {choice}"},
+			expectedTranslation: &Translation{Kind: PhraseTranslation, Key: TiePhrase, Language: "pt",
+				Text: "Empate: {choice}"},
+		},
+		{
+			name:          "failure: if the choice doesn't exist, return ErrChoiceNotFound",
+			translation:   Translation{Kind: ChoiceTranslation, Key: "rpsls-well", Language: "pt", Text: "Pedra"},
+			expectedError: ErrChoiceNotFound,
+		},
+		{
+			name:          "failure: if the phrase is unknown, return ErrInvalidTranslation",
+			translation:   Translation{Kind: PhraseTranslation, Key: "win", Language: "pt", Text: "Ganhou"},
+			expectedError: ErrInvalidTranslation,
+		},
+		{
+			name:          "failure: if the kind is unknown, return ErrInvalidTranslation",
+			translation:   Translation{Kind: "ruleset", Key: "rps", Language: "pt", Text: "Pedra Papel Tesoura"},
+			expectedError: ErrInvalidTranslation,
+		},
+		{
+			name:          "failure: if the language isn't a language tag, return ErrInvalidTranslation",
+			translation:   Translation{Kind: VerbTranslation, Key: "crushes", Language: "pt_BR", Text: "esmaga"},
+			expectedError: ErrInvalidTranslation,
+		},
+		{
+			name:          "failure: if the text is blank, return ErrInvalidTranslation",
+			translation:   Translation{Kind: VerbTranslation, Key: "crushes", Language: "pt", Text: "  "},
+			expectedError: ErrInvalidTranslation,
+		},
+	}
+
+	storeMock := TranslationStoreMock{}
+	choiceStoreMock := ChoiceStoreMock{}
+	service := NewTranslationService(&storeMock, &choiceStoreMock)
+	choiceStoreMock.On("Choice", "rpsls-rock").Return(&baseChoices[0], nil)
+	choiceStoreMock.On("Choice", "rpsls-well").Return((*Choice)(nil), ErrChoiceNotFound)
+	storeMock.On("SaveTranslation", mock.Anything).Return(nil)
+
+	for _, tc := range testCases {
+		translation := tc.translation
+		saved, err := service.SaveTranslation(&translation)
+
+		require.Equal(t, tc.expectedError, err, tc.name)
+		require.Equal(t, tc.expectedTranslation, saved, tc.name)
+	}
+}
+
+func TestTranslationService_Translator(t *testing.T) {
+	storeMock := TranslationStoreMock{}
+	service := NewTranslationService(&storeMock, &ChoiceStoreMock{})
+	storeMock.On("Translations", "pt-br").Return([]Translation{
+		{Kind: ChoiceTranslation, Key: "rpsls-rock", Language: "pt-br", Text: "Pedra"},
+	}, nil)
+	storeMock.On("Translations", "pt").Return([]Translation{
+		{Kind: ChoiceTranslation, Key: "rpsls-rock", Language: "pt", Text: "Rocha"},
+		{Kind: ChoiceTranslation, Key: "rpsls-paper", Language: "pt", Text: "Papel"},
+		{Kind: VerbTranslation, Key: "covers", Language: "pt", Text: "cobre"},
+	}, nil)
+	storeMock.On("Translations", "es").Return([]Translation{
+		{Kind: ChoiceTranslation, Key: "rpsls-scissors", Language: "es", Text: "Tijeras"},
+		{Kind: PhraseTranslation, Key: TiePhrase, Language: "es", Text: "Ambos jugaron {choice}"},
+	}, nil)
+	storeMock.On("Translations", "fr").Return([]Translation{}, nil)
+
+	translator, err := service.Translator([]string{"fr", "pt-BR", "es"})
+	require.NoError(t, err)
+	storeMock.AssertCalled(t, "Translations", "pt")
+	require.Equal(t, "pt-br", translator.Language())
+
+	// pt-br wins over pt, which wins over es, and untranslated choices keep their name
+	require.Equal(t, []Choice{
+		{ID: "rpsls-rock", Name: "Pedra", Ruleset: "rpsls"},
+		{ID: "rpsls-paper", Name: "Papel", Ruleset: "rpsls"},
+		{ID: "rpsls-scissors", Name: "Tijeras", Ruleset: "rpsls"},
+		{ID: "rpsls-lizard", Name: "lizard", Ruleset: "rpsls"},
+	}, translator.Choices(baseChoices[:4]))
+	require.Equal(t, "rock", baseChoices[0].Name)
+
+	lost := &RoundResults{PlayerChoice: &baseChoices[0], ComputerChoice: &baseChoices[1], Results: string(Lose),
+		Action: "covers", Description: "paper covers rock"}
+	translated := translator.Round(lost)
+	require.Equal(t, "Papel cobre Pedra", translated.Description)
+	require.Equal(t, "cobre", translated.Action)
+	require.Equal(t, "paper covers rock", lost.Description)
+
+	tied := &RoundResults{PlayerChoice: &baseChoices[2], ComputerChoice: &baseChoices[2], Results: string(Tie)}
+	require.Equal(t, "Ambos jugaron Tijeras", translator.Round(tied).Description)
+
+	var untranslated *Translator
+	require.Equal(t, lost, untranslated.Round(lost))
+	require.Equal(t, "", untranslated.Language())
+}
@@ -0,0 +1,206 @@
+package rpslsapi
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+var ErrUsernameTaken = errors.New("username already taken")
+var ErrInvalidUser = errors.New("users need a username of 3 to 50 letters, digits, dashes or underscores and a " +
+	"password of 8 to 72 characters")
+var ErrInvalidCredentials = errors.New("invalid username or password")
+var ErrInvalidToken = errors.New("invalid or expired token")
+var ErrUnauthenticated = errors.New("authentication required")
+var ErrNotAdmin = errors.New("admin role required")
+var ErrInvalidRole = errors.New("roles are player or admin")
+
+// minPasswordLength and maxPasswordLength bound the length of passwords, bcrypt ignoring anything past 72 bytes
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+// tokenIssuer identifies the tokens issued by the API
+const tokenIssuer = "rpsls"
+
+// unknownUserPasswordHash is a bcrypt hash with the default cost, which logins of unknown users are checked against
+// so that they take as long as the ones of existing users and don't tell which usernames are taken
+const unknownUserPasswordHash = "$2a$10$9tKSu16XnY5pMInA6BYJ1ecIzPmiT4B/MH6FJRBjW5y.Q4oE7X4zu"
+
+var usernamePattern = regexp.MustCompile(`^[a-z0-9_-]{3,50}$`)
+
+// Role is granted to users in their tokens. Admins, appointed with the rpsls-users command, may also edit the game and
+// bots, run simulations and read audits.
+type Role string
+
+const (
+	PlayerRole Role = "player"
+	AdminRole  Role = "admin"
+)
+
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	// PasswordHash is the bcrypt hash of the password, never returned
+	PasswordHash string `json:"-"`
+	// Role is the role granted in the tokens of the user, returned along with them. Users registering get PlayerRole.
+	Role      Role      `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Credentials identify a user when registering and logging in. Usernames are case-insensitive.
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// AuthToken is a signed JWT whose subject is the ID of the user it was issued to
+type AuthToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Role      Role      `json:"role"`
+	User      *User     `json:"user"`
+}
+
+// Identity is the user a token was issued to, along with their role
+type Identity struct {
+	UserID string
+	Role   Role
+}
+
+// tokenClaims are the registered claims of tokens along with the role of the user
+type tokenClaims struct {
+	Role Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+type UserService interface {
+	Register(credentials *Credentials) (*User, error)
+	// Login checks the credentials and issues a token, or returns ErrInvalidCredentials
+	Login(credentials *Credentials) (*AuthToken, error)
+	// Authenticate returns the identity the token was issued to, or ErrInvalidToken
+	Authenticate(token string) (*Identity, error)
+	// SetRole changes the role of the user, which applies to the tokens issued afterwards, or returns ErrUserNotFound
+	SetRole(username string, role Role) error
+}
+
+type UserStore interface {
+	// CreateUser stores a new user, or returns ErrUsernameTaken
+	CreateUser(user *User) error
+	// UserByUsername returns the user or ErrUserNotFound
+	UserByUsername(username string) (*User, error)
+	// SetRole changes the role of the user, or returns ErrUserNotFound
+	SetRole(username string, role Role) error
+}
+
+type UserServiceImpl struct {
+	userStore UserStore
+	secret    []byte
+	tokenTTL  time.Duration
+	now       func() time.Time
+}
+
+// NewUserService signs tokens with RPSLS_JWT_SECRET, panicking without one unless in dev mode, where a random secret
+// is used so tokens don't outlive the process
+func NewUserService(userStore UserStore) UserService {
+	secret := Config.JWTSecret
+	if secret == "" {
+		if !Config.DevMode {
+			panic(errors.New("env var RPSLS_JWT_SECRET must be set outside of dev mode"))
+		}
+		log.Warn().Msg("RPSLS_JWT_SECRET is not set, tokens will be invalid once the server restarts")
+		secret = newToken(32)
+	}
+	return UserServiceImpl{userStore: userStore, secret: []byte(secret), tokenTTL: Config.TokenTTL, now: time.Now}
+}
+
+func (us UserServiceImpl) Register(credentials *Credentials) (*User, error) {
+	username := strings.ToLower(credentials.Username)
+	if !usernamePattern.MatchString(username) || len(credentials.Password) < minPasswordLength ||
+		len(credentials.Password) > maxPasswordLength {
+		return nil, ErrInvalidUser
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(credentials.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	user := &User{ID: newToken(16), Username: username, PasswordHash: string(hash), Role: PlayerRole,
+		CreatedAt: us.now().UTC()}
+	if err := us.userStore.CreateUser(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (us UserServiceImpl) Login(credentials *Credentials) (*AuthToken, error) {
+	user, err := us.userStore.UserByUsername(strings.ToLower(credentials.Username))
+	if err == ErrUserNotFound {
+		_ = bcrypt.CompareHashAndPassword([]byte(unknownUserPasswordHash), []byte(credentials.Password))
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(credentials.Password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	// users registered before roles were stored are players
+	role := user.Role
+	if role != AdminRole {
+		role = PlayerRole
+	}
+	// JWT times have a precision of a second
+	now := us.now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(us.tokenTTL)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}).SignedString(us.secret)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthToken{Token: token, ExpiresAt: expiresAt, Role: role, User: user}, nil
+}
+
+func (us UserServiceImpl) Authenticate(token string) (*Identity, error) {
+	var claims tokenClaims
+	// claims are checked against the clock of the service rather than the one of the jwt package
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithoutClaimsValidation())
+	_, err := parser.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return us.secret, nil
+	})
+	if err != nil {
+		log.Debug().Err(err).Msg("rejected token")
+		return nil, ErrInvalidToken
+	}
+	if !claims.VerifyIssuer(tokenIssuer, true) || !claims.VerifyExpiresAt(us.now(), true) || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	// tokens issued before roles existed are player tokens
+	role := claims.Role
+	if role != AdminRole {
+		role = PlayerRole
+	}
+	return &Identity{UserID: claims.Subject, Role: role}, nil
+}
+
+func (us UserServiceImpl) SetRole(username string, role Role) error {
+	if role != PlayerRole && role != AdminRole {
+		return ErrInvalidRole
+	}
+	return us.userStore.SetRole(strings.ToLower(username), role)
+}
@@ -0,0 +1,309 @@
+package rpslsapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserStoreMock struct {
+	mock.Mock
+}
+
+func (usm *UserStoreMock) CreateUser(user *User) error {
+	args := usm.Called(user)
+	return args.Error(0)
+}
+
+func (usm *UserStoreMock) UserByUsername(username string) (*User, error) {
+	args := usm.Called(username)
+	return args.Get(0).(*User), args.Error(1)
+}
+
+func (usm *UserStoreMock) SetRole(username string, role Role) error {
+	args := usm.Called(username, role)
+	return args.Error(0)
+}
+
+var userNow = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestUserService(storeMock *UserStoreMock) UserServiceImpl {
+	return UserServiceImpl{userStore: storeMock, secret: []byte("secret"), tokenTTL: time.Hour,
+		now: func() time.Time { return userNow }}
+}
+
+func TestNewUserService(t *testing.T) {
+	testCases := []struct {
+		name          string
+		secret        string
+		devMode       bool
+		expectedPanic bool
+	}{
+		{
+			name:   "success: sign tokens with the configured secret",
+			secret: "secret",
+		},
+		{
+			name:    "success: sign tokens with a random secret in dev mode",
+			devMode: true,
+		},
+		{
+			name:          "failure: if the secret is missing outside of dev mode, panic",
+			expectedPanic: true,
+		},
+	}
+
+	defer func() { Config.JWTSecret, Config.DevMode = "", false }()
+	for _, tc := range testCases {
+		Config.JWTSecret, Config.DevMode = tc.secret, tc.devMode
+
+		if tc.expectedPanic {
+			require.Panics(t, func() { NewUserService(&UserStoreMock{}) }, tc.name)
+			continue
+		}
+		service := NewUserService(&UserStoreMock{}).(UserServiceImpl)
+		require.NotEmpty(t, service.secret, tc.name)
+		if tc.secret != "" {
+			require.Equal(t, []byte(tc.secret), service.secret, tc.name)
+		}
+	}
+}
+
+func TestUserService_Register(t *testing.T) {
+	testCases := []struct {
+		name             string
+		credentials      Credentials
+		storeError       error
+		expectedUsername string
+		expectedError    error
+	}{
+		{
+			name:             "success: store the player with a lowercase username and a hashed password",
+			credentials:      Credentials{Username: "Sheldon", Password: "bazinga!"},
+			expectedUsername: "sheldon",
+		},
+		{
+			name:          "failure: if the username is too short, return ErrInvalidUser",
+			credentials:   Credentials{Username: "sh", Password: "bazinga!"},
+			expectedError: ErrInvalidUser,
+		},
+		{
+			name:          "failure: if the username has other characters, return ErrInvalidUser",
+			credentials:   Credentials{Username: "sheldon cooper", Password: "bazinga!"},
+			expectedError: ErrInvalidUser,
+		},
+		{
+			name:          "failure: if the password is too short, return ErrInvalidUser",
+			credentials:   Credentials{Username: "sheldon", Password: "bazinga"},
+			expectedError: ErrInvalidUser,
+		},
+		{
+			name:          "failure: if the username is taken, return ErrUsernameTaken",
+			credentials:   Credentials{Username: "sheldon", Password: "bazinga!"},
+			storeError:    ErrUsernameTaken,
+			expectedError: ErrUsernameTaken,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := UserStoreMock{}
+		service := newTestUserService(&storeMock)
+		storeMock.On("CreateUser", mock.Anything).Return(tc.storeError)
+
+		user, err := service.Register(&tc.credentials)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.NotEmpty(t, user.ID, tc.name)
+		require.Equal(t, tc.expectedUsername, user.Username, tc.name)
+		require.Equal(t, PlayerRole, user.Role, tc.name)
+		require.Equal(t, userNow, user.CreatedAt, tc.name)
+		require.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(tc.credentials.Password)),
+			tc.name)
+		storeMock.AssertCalled(t, "CreateUser", user)
+	}
+}
+
+func TestUserService_Login(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("bazinga!"), bcrypt.MinCost)
+	require.NoError(t, err)
+	sheldon := &User{ID: "user", Username: "sheldon", PasswordHash: string(hash)}
+	amy := &User{ID: "admin", Username: "amy", PasswordHash: string(hash), Role: AdminRole}
+
+	testCases := []struct {
+		name          string
+		credentials   Credentials
+		storeUser     *User
+		storeError    error
+		expectedRole  Role
+		expectedError error
+	}{
+		{
+			name:         "success: issue a token whose subject is the user, as a player without a role",
+			credentials:  Credentials{Username: "SHELDON", Password: "bazinga!"},
+			storeUser:    sheldon,
+			expectedRole: PlayerRole,
+		},
+		{
+			name:         "success: grant the role of the user",
+			credentials:  Credentials{Username: "amy", Password: "bazinga!"},
+			storeUser:    amy,
+			expectedRole: AdminRole,
+		},
+		{
+			name:          "failure: if the password is wrong, return ErrInvalidCredentials",
+			credentials:   Credentials{Username: "sheldon", Password: "knock knock"},
+			storeUser:     sheldon,
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "failure: if the user doesn't exist, return ErrInvalidCredentials",
+			credentials:   Credentials{Username: "leonard", Password: "bazinga!"},
+			storeError:    ErrUserNotFound,
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "failure: if the store fails, return its error",
+			credentials:   Credentials{Username: "sheldon", Password: "bazinga!"},
+			storeError:    unknownDBError,
+			expectedError: unknownDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := UserStoreMock{}
+		service := newTestUserService(&storeMock)
+		storeMock.On("UserByUsername", "sheldon").Return(tc.storeUser, tc.storeError)
+		storeMock.On("UserByUsername", "amy").Return(tc.storeUser, tc.storeError)
+		storeMock.On("UserByUsername", mock.Anything).Return((*User)(nil), tc.storeError)
+
+		token, err := service.Login(&tc.credentials)
+
+		if tc.expectedError != nil {
+			require.Equal(t, tc.expectedError, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.storeUser, token.User, tc.name)
+		require.Equal(t, userNow.Add(time.Hour), token.ExpiresAt, tc.name)
+		require.Equal(t, tc.expectedRole, token.Role, tc.name)
+		identity, err := service.Authenticate(token.Token)
+		require.NoError(t, err, tc.name)
+		require.Equal(t, &Identity{UserID: tc.storeUser.ID, Role: tc.expectedRole}, identity, tc.name)
+	}
+}
+
+func TestUnknownUserPasswordHash(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(unknownUserPasswordHash))
+	require.NoError(t, err)
+	require.Equal(t, bcrypt.DefaultCost, cost, "unknown users are checked as long as the registered ones")
+	require.Equal(t, bcrypt.ErrMismatchedHashAndPassword,
+		bcrypt.CompareHashAndPassword([]byte(unknownUserPasswordHash), []byte("bazinga!")))
+}
+
+func TestUserService_SetRole(t *testing.T) {
+	testCases := []struct {
+		name          string
+		username      string
+		role          Role
+		storeError    error
+		expectedError error
+	}{
+		{
+			name:     "success: store the role of the user with a lowercase username",
+			username: "Amy",
+			role:     AdminRole,
+		},
+		{
+			name:          "failure: if the role is unknown, return ErrInvalidRole",
+			username:      "amy",
+			role:          "overlord",
+			expectedError: ErrInvalidRole,
+		},
+		{
+			name:          "failure: if the user doesn't exist, return ErrUserNotFound",
+			username:      "amy",
+			role:          PlayerRole,
+			storeError:    ErrUserNotFound,
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		storeMock := UserStoreMock{}
+		service := newTestUserService(&storeMock)
+		storeMock.On("SetRole", "amy", tc.role).Return(tc.storeError)
+
+		err := service.SetRole(tc.username, tc.role)
+
+		require.Equal(t, tc.expectedError, err, tc.name)
+		if tc.expectedError == ErrInvalidRole {
+			storeMock.AssertNotCalled(t, "SetRole", mock.Anything, mock.Anything)
+		}
+	}
+}
+
+func TestUserService_Authenticate(t *testing.T) {
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.RegisteredClaims) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		require.NoError(t, err)
+		return token
+	}
+	validClaims := jwt.RegisteredClaims{Issuer: tokenIssuer, Subject: "user",
+		ExpiresAt: jwt.NewNumericDate(userNow.Add(time.Minute))}
+
+	testCases := []struct {
+		name             string
+		token            string
+		expectedIdentity *Identity
+		expectedError    error
+	}{
+		{
+			name:             "success: return the subject of a valid token, as a player without a role",
+			token:            sign(jwt.SigningMethodHS256, []byte("secret"), validClaims),
+			expectedIdentity: &Identity{UserID: "user", Role: PlayerRole},
+		},
+		{
+			name: "failure: if the token expired, return ErrInvalidToken",
+			token: sign(jwt.SigningMethodHS256, []byte("secret"), jwt.RegisteredClaims{Issuer: tokenIssuer,
+				Subject: "user", ExpiresAt: jwt.NewNumericDate(userNow.Add(-time.Minute))}),
+			expectedError: ErrInvalidToken,
+		},
+		{
+			name: "failure: if the token never expires, return ErrInvalidToken",
+			token: sign(jwt.SigningMethodHS256, []byte("secret"), jwt.RegisteredClaims{Issuer: tokenIssuer,
+				Subject: "user"}),
+			expectedError: ErrInvalidToken,
+		},
+		{
+			name:          "failure: if the token is signed with another secret, return ErrInvalidToken",
+			token:         sign(jwt.SigningMethodHS256, []byte("guess"), validClaims),
+			expectedError: ErrInvalidToken,
+		},
+		{
+			name:          "failure: if the token is signed with another method, return ErrInvalidToken",
+			token:         sign(jwt.SigningMethodHS512, []byte("secret"), validClaims),
+			expectedError: ErrInvalidToken,
+		},
+		{
+			name:          "failure: if the token is malformed, return ErrInvalidToken",
+			token:         "bazinga",
+			expectedError: ErrInvalidToken,
+		},
+	}
+
+	service := newTestUserService(&UserStoreMock{})
+	for _, tc := range testCases {
+		identity, err := service.Authenticate(tc.token)
+
+		require.Equal(t, tc.expectedError, err, tc.name)
+		require.Equal(t, tc.expectedIdentity, identity, tc.name)
+	}
+}
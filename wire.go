@@ -6,32 +6,61 @@ import (
 	"github.com/google/wire"
 	"rpsls/rpslsapi"
 	"rpsls/rpslsapi/http"
-	"rpsls/rpslsapi/storage/neo4j"
-	"rpsls/rpslsapi/storage/redis"
+	"rpsls/rpslsapi/storage"
 )
 
 func InitServer() (http.Server, func()) {
 	wire.Build(
 		http.NewServer,
 		http.NewRouter,
+		wire.Struct(new(http.Handlers), "*"),
 		http.NewChoiceHandler,
 		http.NewRoundHandler,
 		http.NewScoreboardHandler,
+		http.NewRulesetHandler,
+		http.NewCacheHandler,
+		http.NewTranslationHandler,
+		http.NewMatchHandler,
+		http.NewSeriesHandler,
+		http.NewTournamentHandler,
+		http.NewStrategyHandler,
+		http.NewBotHandler,
+		http.NewBotClient,
+		http.NewFreeForAllHandler,
+		http.NewHistoryHandler,
+		http.NewAuditHandler,
+		http.NewArcadeHandler,
+		http.NewDailyHandler,
+		http.NewSimulationHandler,
+		http.NewMatchmakingHandler,
+		http.NewAuthHandler,
 		http.NewRandomizerClient,
-		rpslsapi.NewExternalRandomizerService,
+		rpslsapi.NewRandomizerService,
+		rpslsapi.NewRuleGraphLock,
 		rpslsapi.NewChoiceService,
 		rpslsapi.NewRoundService,
 		rpslsapi.NewScoreboardService,
-		neo4j.NewDbClient,
-		neo4j.NewChoiceStore,
-		neo4j.NewRoundStore,
-		redis.NewClient,
-		redis.NewScoreboardStore,
-		wire.Bind(new(rpslsapi.ChoiceStore), new(neo4j.ChoiceStore)),
-		wire.Bind(new(rpslsapi.RoundStore), new(neo4j.RoundStore)),
-		wire.Bind(new(rpslsapi.ScoreboardStore), new(redis.ScoreboardStore)),
-		wire.Bind(new(rpslsapi.RandomizerService), new(rpslsapi.ExternalRandomizerService)),
-		wire.Bind(new(rpslsapi.RandomizerClient), new(http.RandomizerClient)))
+		rpslsapi.NewRulesetService,
+		rpslsapi.NewTranslationService,
+		rpslsapi.NewMatchService,
+		rpslsapi.NewSeriesService,
+		rpslsapi.NewTournamentService,
+		rpslsapi.NewStrategyService,
+		rpslsapi.NewBotService,
+		rpslsapi.NewFreeForAllService,
+		rpslsapi.NewHistoryService,
+		rpslsapi.NewAuditService,
+		rpslsapi.NewArcadeService,
+		rpslsapi.NewDailyService,
+		rpslsapi.NewSimulationService,
+		rpslsapi.NewMatchmakingService,
+		rpslsapi.NewUserService,
+		storage.NewStores,
+		wire.FieldsOf(new(storage.Stores), "Choice", "Round", "Ruleset", "Scoreboard", "OutcomeCache",
+			"Translation", "Match", "Series", "Tournament", "Commitment",
+			"Strategy", "Bot", "History", "Audit", "Arcade", "Daily", "User"),
+		wire.Bind(new(rpslsapi.RandomizerClient), new(http.RandomizerClient)),
+		wire.Bind(new(rpslsapi.BotClient), new(http.BotClient)))
 
 	return http.Server{}, func() {}
 }
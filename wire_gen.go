@@ -8,28 +8,98 @@ package rpsls_gen
 import (
 	"rpsls/rpslsapi"
 	"rpsls/rpslsapi/http"
-	"rpsls/rpslsapi/storage/neo4j"
-	"rpsls/rpslsapi/storage/redis"
+	"rpsls/rpslsapi/storage"
 )
 
 // Injectors from wire.go:
 
 func InitServer() (http.Server, func()) {
-	dbClient, cleanup := neo4j.NewDbClient()
-	choiceStore := neo4j.NewChoiceStore(dbClient)
+	stores, cleanup := storage.NewStores()
+	choiceStore := stores.Choice
 	randomizerClient := http.NewRandomizerClient()
-	externalRandomizerService := rpslsapi.NewExternalRandomizerService(randomizerClient)
-	choiceService := rpslsapi.NewChoiceService(choiceStore, externalRandomizerService)
-	choiceHandler := http.NewChoiceHandler(choiceService)
-	roundStore := neo4j.NewRoundStore(dbClient)
-	client := redis.NewClient()
-	scoreboardStore := redis.NewScoreboardStore(client)
+	randomizerService := rpslsapi.NewRandomizerService(randomizerClient)
+	ruleGraphLock := rpslsapi.NewRuleGraphLock()
+	choiceService := rpslsapi.NewChoiceService(choiceStore, randomizerService, ruleGraphLock)
+	translationStore := stores.Translation
+	translationService := rpslsapi.NewTranslationService(translationStore, choiceStore)
+	choiceHandler := http.NewChoiceHandler(choiceService, translationService)
+	roundStore := stores.Round
+	scoreboardStore := stores.Scoreboard
 	scoreboardService := rpslsapi.NewScoreboardService(scoreboardStore)
-	roundService := rpslsapi.NewRoundService(roundStore, choiceService, scoreboardService)
-	roundHandler := http.NewRoundHandler(roundService)
+	commitmentStore := stores.Commitment
+	strategyStore := stores.Strategy
+	botStore := stores.Bot
+	botClient := http.NewBotClient()
+	strategyService := rpslsapi.NewStrategyService(strategyStore, botStore, botClient, choiceService,
+		scoreboardService, randomizerService)
+	historyStore := stores.History
+	historyService := rpslsapi.NewHistoryService(historyStore)
+	auditStore := stores.Audit
+	auditService := rpslsapi.NewAuditService(auditStore, roundStore)
+	roundService := rpslsapi.NewRoundService(roundStore, choiceService, scoreboardService, commitmentStore,
+		strategyService, historyService, auditService)
+	roundHandler := http.NewRoundHandler(roundService, translationService)
 	scoreboardHandler := http.NewScoreboardHandler(scoreboardService)
-	router := http.NewRouter(choiceHandler, roundHandler, scoreboardHandler)
-	server := http.NewServer(router)
+	rulesetStore := stores.Ruleset
+	rulesetService := rpslsapi.NewRulesetService(rulesetStore, choiceStore, ruleGraphLock)
+	rulesetHandler := http.NewRulesetHandler(rulesetService)
+	outcomeCache := stores.OutcomeCache
+	cacheHandler := http.NewCacheHandler(outcomeCache)
+	translationHandler := http.NewTranslationHandler(translationService)
+	matchStore := stores.Match
+	matchService := rpslsapi.NewMatchService(matchStore, roundStore, choiceService)
+	matchHandler := http.NewMatchHandler(matchService)
+	seriesStore := stores.Series
+	seriesService := rpslsapi.NewSeriesService(seriesStore, roundStore, choiceService, scoreboardService,
+		strategyService, historyService, auditService)
+	seriesHandler := http.NewSeriesHandler(seriesService)
+	tournamentStore := stores.Tournament
+	tournamentService := rpslsapi.NewTournamentService(tournamentStore, roundStore, choiceService)
+	tournamentHandler := http.NewTournamentHandler(tournamentService)
+	strategyHandler := http.NewStrategyHandler(strategyService)
+	botService := rpslsapi.NewBotService(botStore)
+	botHandler := http.NewBotHandler(botService)
+	freeForAllService := rpslsapi.NewFreeForAllService(roundStore, choiceService)
+	freeForAllHandler := http.NewFreeForAllHandler(freeForAllService)
+	historyHandler := http.NewHistoryHandler(historyService)
+	auditHandler := http.NewAuditHandler(auditService)
+	arcadeStore := stores.Arcade
+	arcadeService := rpslsapi.NewArcadeService(arcadeStore, roundStore, choiceService, scoreboardService,
+		strategyService, historyService, auditService)
+	arcadeHandler := http.NewArcadeHandler(arcadeService)
+	dailyStore := stores.Daily
+	dailyService := rpslsapi.NewDailyService(dailyStore, roundStore, choiceService, auditService)
+	dailyHandler := http.NewDailyHandler(dailyService)
+	simulationService := rpslsapi.NewSimulationService(choiceService)
+	simulationHandler := http.NewSimulationHandler(simulationService)
+	matchmakingService := rpslsapi.NewMatchmakingService(matchService, choiceService)
+	matchmakingHandler := http.NewMatchmakingHandler(matchmakingService)
+	userStore := stores.User
+	userService := rpslsapi.NewUserService(userStore)
+	authHandler := http.NewAuthHandler(userService)
+	handlers := http.Handlers{
+		Choice:      choiceHandler,
+		Round:       roundHandler,
+		Scoreboard:  scoreboardHandler,
+		Ruleset:     rulesetHandler,
+		Cache:       cacheHandler,
+		Translation: translationHandler,
+		Match:       matchHandler,
+		Series:      seriesHandler,
+		Tournament:  tournamentHandler,
+		Strategy:    strategyHandler,
+		Bot:         botHandler,
+		FreeForAll:  freeForAllHandler,
+		History:     historyHandler,
+		Audit:       auditHandler,
+		Arcade:      arcadeHandler,
+		Daily:       dailyHandler,
+		Simulation:  simulationHandler,
+		Matchmaking: matchmakingHandler,
+		Auth:        authHandler,
+	}
+	router := http.NewRouter(handlers)
+	server := http.NewServer(router, choiceService, rulesetService)
 	return server, func() {
 		cleanup()
 	}